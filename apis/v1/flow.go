@@ -133,6 +133,38 @@ type FlowKubernetes struct {
 	EgressUid      string `json:"egressUid,omitempty"`
 }
 
+// FlowApp is the application-layer information Antrea detected for the flow, when L7 flow
+// export is enabled.
+type FlowApp struct {
+	// ProtocolName is the L7 protocol, e.g. "http".
+	ProtocolName string `json:"protocolName"`
+	// HTTPVals is the JSON-encoded HTTP transaction data exported by the agent, verbatim.
+	HTTPVals string `json:"httpVals,omitempty"`
+}
+
+// FlowIPFIX is the metadata of the IPFIX record the flow was exported in.
+type FlowIPFIX struct {
+	ExportTime          string `json:"exportTime,omitempty"`
+	SequenceNumber      uint32 `json:"sequenceNumber"`
+	ObservationDomainId uint32 `json:"observationDomainId"`
+	ExporterIp          string `json:"exporterIp"`
+}
+
+// FlowAggregation is what the Flow Aggregator recorded when correlating the records exported by
+// the source and destination Nodes of an inter-Node flow.
+type FlowAggregation struct {
+	EndTsFromSource                  string    `json:"endTsFromSource,omitempty"`
+	EndTsFromDestination             string    `json:"endTsFromDestination,omitempty"`
+	StatsFromSource                  FlowStats `json:"statsFromSource"`
+	ReverseStatsFromSource           FlowStats `json:"reverseStatsFromSource"`
+	StatsFromDestination             FlowStats `json:"statsFromDestination"`
+	ReverseStatsFromDestination      FlowStats `json:"reverseStatsFromDestination"`
+	ThroughputFromSource             uint64    `json:"throughputFromSource"`
+	ReverseThroughputFromSource      uint64    `json:"reverseThroughputFromSource"`
+	ThroughputFromDestination        uint64    `json:"throughputFromDestination"`
+	ReverseThroughputFromDestination uint64    `json:"reverseThroughputFromDestination"`
+}
+
 type Flow struct {
 	ID           string         `json:"id"`
 	StartTs      string         `json:"startTs"`
@@ -143,6 +175,10 @@ type Flow struct {
 	K8s          FlowKubernetes `json:"k8s"`
 	Stats        FlowStats      `json:"stats"`
 	ReverseStats FlowStats      `json:"reverseStats"`
+	// App, IPFIX and Aggregation are only set when the Flow Aggregator exported them.
+	App         *FlowApp         `json:"app,omitempty"`
+	IPFIX       *FlowIPFIX       `json:"ipfix,omitempty"`
+	Aggregation *FlowAggregation `json:"aggregation,omitempty"`
 }
 
 // FlowStreamEvent carries flow data and/or a dropped count from the stream.
@@ -28,6 +28,30 @@ type FrontendAuthSettings struct {
 
 type FrontendFeatureSettings struct {
 	FlowVisibilityEnabled bool `json:"flowVisibilityEnabled"`
+	// PacketCaptureEnabled is set when a file server is configured for PacketCaptures.
+	PacketCaptureEnabled bool `json:"packetCaptureEnabled"`
+	// SupportBundleEnabled is set when a file server is configured for support bundles.
+	SupportBundleEnabled bool `json:"supportBundleEnabled"`
+}
+
+// AntreaResourceVersion is the version of an Antrea CRD used by antrea-ui.
+type AntreaResourceVersion struct {
+	Resource string `json:"resource"`
+	// Version is the version used by antrea-ui, which is its latest supported version if the
+	// resource is not served in any supported version.
+	Version string `json:"version"`
+	// ServedVersions are the versions served by the cluster, the preferred one first.
+	ServedVersions []string `json:"servedVersions,omitempty"`
+	Supported      bool     `json:"supported"`
+}
+
+// AntreaAPISettings report whether antrea-ui supports the version of Antrea running in the cluster.
+type AntreaAPISettings struct {
+	// Discovered is false until the Antrea CRDs served by the cluster have been discovered.
+	Discovered bool                    `json:"discovered"`
+	Supported  bool                    `json:"supported"`
+	Message    string                  `json:"message,omitempty"`
+	Resources  []AntreaResourceVersion `json:"resources"`
 }
 
 // FrontendSettings are global settings exposed to the frontend, which can be
@@ -38,4 +62,5 @@ type FrontendSettings struct {
 	Version  string                  `json:"version"`
 	Auth     FrontendAuthSettings    `json:"auth"`
 	Features FrontendFeatureSettings `json:"features"`
+	Antrea   *AntreaAPISettings      `json:"antrea,omitempty"`
 }
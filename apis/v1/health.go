@@ -0,0 +1,93 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// AntreaHealth is the response of GET /api/v1/health/antrea. It aggregates the
+// AntreaControllerInfo and AntreaAgentInfo objects that Antrea updates periodically.
+type AntreaHealth struct {
+	// Status is "Healthy", "Degraded" (some agents are not healthy, or run another version than
+	// the controller) or "Unhealthy" (the controller is not healthy).
+	Status string `json:"status"`
+	// Issues describe, in plain words, everything that keeps Status from being "Healthy".
+	Issues []string `json:"issues"`
+	// Controller is nil when there is no AntreaControllerInfo.
+	Controller *AntreaControllerHealth `json:"controller"`
+	// Agents are sorted by Node name.
+	Agents        []AntreaAgentHealth `json:"agents"`
+	HealthyAgents int32               `json:"healthyAgents"`
+	// Versions counts the components running each Antrea version, the controller included.
+	Versions map[string]int32 `json:"versions"`
+	// VersionSkew is set when at least one agent does not run the version of the controller.
+	VersionSkew bool `json:"versionSkew"`
+	// StaleHeartbeatThreshold is the age past which a heartbeat is stale, e.g. "3m0s".
+	StaleHeartbeatThreshold string `json:"staleHeartbeatThreshold"`
+}
+
+// AntreaControllerHealth summarizes the AntreaControllerInfo.
+type AntreaControllerHealth struct {
+	Version string `json:"version"`
+	// Pod is "<namespace>/<name>".
+	Pod      string `json:"pod"`
+	NodeName string `json:"nodeName"`
+	// Healthy is the status of the ControllerHealthy condition.
+	Healthy bool `json:"healthy"`
+	// LastHeartbeatTime is the latest heartbeat of the conditions, in RFC 3339 format.
+	LastHeartbeatTime string `json:"lastHeartbeatTime"`
+	// StaleHeartbeat is set when the controller did not update its AntreaControllerInfo for
+	// longer than the stale heartbeat threshold, in which case Healthy cannot be trusted.
+	StaleHeartbeat  bool                       `json:"staleHeartbeat"`
+	ConnectedAgents int32                      `json:"connectedAgents"`
+	NetworkPolicies AntreaNetworkPolicyCounts  `json:"networkPolicies"`
+	Conditions      []AntreaComponentCondition `json:"conditions"`
+}
+
+// AntreaAgentHealth summarizes an AntreaAgentInfo.
+type AntreaAgentHealth struct {
+	Name       string `json:"name"`
+	NodeName   string `json:"nodeName"`
+	Pod        string `json:"pod"`
+	Version    string `json:"version"`
+	OVSVersion string `json:"ovsVersion"`
+	// Healthy is the status of the AgentHealthy condition.
+	Healthy bool `json:"healthy"`
+	// ControllerConnected is the status of the ControllerConnectionUp condition.
+	ControllerConnected bool   `json:"controllerConnected"`
+	LastHeartbeatTime   string `json:"lastHeartbeatTime"`
+	StaleHeartbeat      bool   `json:"staleHeartbeat"`
+	// VersionSkew is set when the agent does not run the version of the controller.
+	VersionSkew bool  `json:"versionSkew"`
+	LocalPods   int32 `json:"localPods"`
+	// NetworkPolicies are the policies realized by the agent, which only receives those applied
+	// to its Pods.
+	NetworkPolicies AntreaNetworkPolicyCounts  `json:"networkPolicies"`
+	Conditions      []AntreaComponentCondition `json:"conditions"`
+}
+
+// AntreaNetworkPolicyCounts are the numbers of internal NetworkPolicy objects known to an Antrea
+// component.
+type AntreaNetworkPolicyCounts struct {
+	NetworkPolicies int32 `json:"networkPolicies"`
+	AddressGroups   int32 `json:"addressGroups"`
+	AppliedToGroups int32 `json:"appliedToGroups"`
+}
+
+// AntreaComponentCondition is a condition of an Antrea component, as reported by Antrea.
+type AntreaComponentCondition struct {
+	Type              string `json:"type"`
+	Status            string `json:"status"`
+	LastHeartbeatTime string `json:"lastHeartbeatTime"`
+	Reason            string `json:"reason,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// NetworkPolicyEvaluationRequest is the body of POST /api/v1/networkpolicyevaluation. The
+// antrea-controller evaluates which NetworkPolicy rule applies to traffic from the source Pod to
+// the destination Pod, as "antctl query networkpolicyevaluation" does. Antrea does not take ports
+// and protocols into account.
+type NetworkPolicyEvaluationRequest struct {
+	Source      NetworkPolicyEvaluationPod `json:"source"`
+	Destination NetworkPolicyEvaluationPod `json:"destination"`
+}
+
+// NetworkPolicyEvaluationPod is a Pod.
+type NetworkPolicyEvaluationPod struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+}
+
+// NetworkPolicyEvaluationResult is the response of POST /api/v1/networkpolicyevaluation.
+type NetworkPolicyEvaluationResult struct {
+	// Matched is false when no NetworkPolicy rule applies, in which case Policy and Rule are
+	// nil.
+	Matched bool                           `json:"matched"`
+	Policy  *NetworkPolicyEvaluationPolicy `json:"policy,omitempty"`
+	Rule    *NetworkPolicyEvaluationRule   `json:"rule,omitempty"`
+}
+
+// NetworkPolicyEvaluationPolicy is the NetworkPolicy of the effective rule.
+type NetworkPolicyEvaluationPolicy struct {
+	// Type is one of "K8sNetworkPolicy", "AntreaClusterNetworkPolicy", "AntreaNetworkPolicy",
+	// "AdminNetworkPolicy" or "BaselineAdminNetworkPolicy".
+	Type string `json:"type"`
+	// Namespace is empty for a cluster-scoped policy.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+	// Tier is only set for Antrea-native policies, when the caller can get the policy.
+	Tier string `json:"tier,omitempty"`
+}
+
+// NetworkPolicyEvaluationRule is the effective rule, within its policy.
+type NetworkPolicyEvaluationRule struct {
+	// Index is the index of the rule in the ingress or egress rules of the policy.
+	Index int32 `json:"index"`
+	// Name is empty for the rules of Kubernetes NetworkPolicies.
+	Name string `json:"name,omitempty"`
+	// Direction is "In" or "Out".
+	Direction string `json:"direction"`
+	// Action is e.g. "Allow", "Drop", "Reject" or "Pass".
+	Action string `json:"action"`
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// OVSTraceRequest is the body of POST /api/v1/ovstracing. The backend validates it and asks the
+// antrea-agent on the Node of the source Pod to trace a packet through OVS with
+// "ovs-appctl ofproto/trace", as "antctl trace-packet" does.
+type OVSTraceRequest struct {
+	// Source is the Pod the packet is sent from. It is required, and selects the Node.
+	Source OVSTraceSource `json:"source"`
+	// Destination is optional: without it, the destination addresses come from Flow.
+	Destination OVSTraceDestination `json:"destination"`
+	// Protocol is one of "TCP" (the default), "UDP" or "ICMP".
+	Protocol string `json:"protocol,omitempty"`
+	// SourcePort and DestinationPort only apply to TCP and UDP. Zero leaves the port unset.
+	SourcePort      int32 `json:"sourcePort,omitempty"`
+	DestinationPort int32 `json:"destinationPort,omitempty"`
+	// IPv6 traces an IPv6 packet when the destination is not given as an IPv6 address.
+	IPv6 bool `json:"ipv6,omitempty"`
+	// Flow holds additional packet fields, in the flow syntax of ovs-ofctl, e.g.
+	// "tcp_flags=+syn,nw_ttl=1".
+	Flow string `json:"flow,omitempty"`
+}
+
+// OVSTraceSource is a Pod.
+type OVSTraceSource struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+}
+
+// OVSTraceDestination is a Pod (Namespace and Pod) or an IP address.
+type OVSTraceDestination struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// OVSTraceResult is the response of POST /api/v1/ovstracing: the output of ofproto/trace, and
+// what could be parsed from it.
+type OVSTraceResult struct {
+	// Node is the Node of the source Pod, on which the packet was traced.
+	Node string `json:"node"`
+	// Output is the output of ofproto/trace, as is.
+	Output string `json:"output"`
+	// Flow is the packet as given to OVS.
+	Flow string `json:"flow,omitempty"`
+	// Steps are the flows matched by the packet, in order, with their actions.
+	Steps []OVSTraceStep `json:"steps"`
+	// FinalFlow is the packet after the pipeline, when it was modified.
+	FinalFlow string `json:"finalFlow,omitempty"`
+	Megaflow  string `json:"megaflow,omitempty"`
+	// DatapathActions is what the datapath does with the packet, e.g. an output port or "drop".
+	DatapathActions string `json:"datapathActions,omitempty"`
+}
+
+// OVSTraceStep is a flow matched by a traced packet, in one OpenFlow table.
+type OVSTraceStep struct {
+	Table int32 `json:"table"`
+	// TableName is only set when OVS knows the names of the tables, which Antrea sets.
+	TableName string `json:"tableName,omitempty"`
+	// Match is empty for a flow which matches every packet, and "No match." when no flow
+	// matched.
+	Match    string `json:"match"`
+	Priority int32  `json:"priority"`
+	Cookie   string `json:"cookie,omitempty"`
+	// Actions are the actions of the flow, as executed, with their outcome, e.g.
+	// "goto_table:10" or "-> NXM_NX_REG0[0..3] is now 0x2".
+	Actions []string `json:"actions"`
+}
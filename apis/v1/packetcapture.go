@@ -0,0 +1,62 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// PacketCaptureRequest is the body of POST /api/v1/packetcapture. The backend validates it and
+// translates it into the spec of an Antrea PacketCapture CR, which captures the first Number
+// packets matching it on the Node of its Pod. At least one of Source and Destination must be a
+// Pod.
+type PacketCaptureRequest struct {
+	Source      PacketCaptureEndpoint `json:"source"`
+	Destination PacketCaptureEndpoint `json:"destination"`
+	// Protocol is one of "TCP" (the default), "UDP" or "ICMP".
+	Protocol string `json:"protocol,omitempty"`
+	// SourcePort and DestinationPort only apply to TCP and UDP. Zero matches any port.
+	SourcePort      int32 `json:"sourcePort,omitempty"`
+	DestinationPort int32 `json:"destinationPort,omitempty"`
+	// IPv6 selects IPv6 packets when no endpoint is given as an IPv6 address.
+	IPv6 bool `json:"ipv6,omitempty"`
+	// Number is the number of packets to capture, between 1 and 1000. Zero means 10.
+	Number int32 `json:"number,omitempty"`
+	// Timeout is in seconds, between 1 and 300. Zero means Antrea's default (60s).
+	Timeout int32 `json:"timeout,omitempty"`
+	// Title is an optional, free-form description shown in the list of captures.
+	Title string `json:"title,omitempty"`
+}
+
+// PacketCaptureEndpoint is a Pod (Namespace and Pod) or an IP address.
+type PacketCaptureEndpoint struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// PacketCaptureSummary describes one PacketCapture created by antrea-ui, in the list returned by
+// GET /api/v1/packetcapture.
+type PacketCaptureSummary struct {
+	// ID is the request ID, used in the other /api/v1/packetcapture/:id endpoints.
+	ID        string `json:"id"`
+	Title     string `json:"title,omitempty"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	// CreationTimestamp is in RFC 3339 format.
+	CreationTimestamp string `json:"creationTimestamp"`
+	// Phase is "Running" until the capture is over, then "Succeeded" once its file is
+	// uploaded, or "Failed". Message explains a failure.
+	Phase          string                `json:"phase"`
+	Message        string                `json:"message,omitempty"`
+	NumberCaptured int32                 `json:"numberCaptured"`
+	Source         PacketCaptureEndpoint `json:"source"`
+	Destination    PacketCaptureEndpoint `json:"destination"`
+}
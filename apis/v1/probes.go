@@ -0,0 +1,62 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ProbeStatus is the current status of a connectivity probe, as returned by GET /api/v1/probes.
+type ProbeStatus struct {
+	Name            string                `json:"name"`
+	Source          ReachabilityEndpoints `json:"source"`
+	Destination     ReachabilityEndpoints `json:"destination"`
+	Protocol        string                `json:"protocol,omitempty"`
+	DestinationPort int32                 `json:"destinationPort,omitempty"`
+	// Expect is the expected result, ReachabilityAllowed or ReachabilityDenied.
+	Expect string `json:"expect"`
+	// Interval is the time between two runs, in seconds.
+	Interval int64 `json:"interval"`
+	// SLOTarget is the expected success rate, between 0 and 1.
+	SLOTarget float64 `json:"sloTarget"`
+	// LastRun is unset until the probe has run once.
+	LastRun *ProbeRun `json:"lastRun,omitempty"`
+	// Runs and Passed count the runs in the history, and the ones that passed.
+	Runs   int `json:"runs"`
+	Passed int `json:"passed"`
+	// SuccessRate is Passed / Runs, or 0 without runs.
+	SuccessRate float64 `json:"successRate"`
+	// SLOMet is true when SuccessRate is at least SLOTarget. It is false without runs.
+	SLOMet bool `json:"sloMet"`
+	// ConsecutiveFailures is the number of runs that failed since the last one that passed.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+}
+
+// ProbeRun is the result of one run of a probe.
+type ProbeRun struct {
+	// Time is when the run started, in RFC 3339 format.
+	Time string `json:"time"`
+	// Source and Destination are the endpoints of the Traceflow, after Pod selectors are
+	// resolved. They are empty if no Pod could be selected.
+	Source      TraceflowSource      `json:"source"`
+	Destination TraceflowDestination `json:"destination"`
+	ReachabilityCell
+	// Passed is true when the result is the expected one. A run that ends with
+	// ReachabilityError never passes.
+	Passed bool `json:"passed"`
+}
+
+// ProbeDetails is the response of GET /api/v1/probes/:name.
+type ProbeDetails struct {
+	ProbeStatus
+	// History holds the most recent runs, the oldest first.
+	History []ProbeRun `json:"history"`
+}
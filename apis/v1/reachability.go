@@ -0,0 +1,82 @@
+// Copyright 2026 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ReachabilityMatrixRequest is the body of POST /api/v1/traceflow/matrix. The backend runs one
+// regular Traceflow from each source Pod to each destination, with the same packet for all of
+// them, and returns the results as a ReachabilityMatrix.
+type ReachabilityMatrixRequest struct {
+	// Sources must select Pods.
+	Sources      []ReachabilityEndpoints `json:"sources"`
+	Destinations []ReachabilityEndpoints `json:"destinations"`
+	// Protocol, DestinationPort, IPv6 and Timeout have the same meaning as in TraceflowRequest.
+	Protocol        string `json:"protocol,omitempty"`
+	DestinationPort int32  `json:"destinationPort,omitempty"`
+	IPv6            bool   `json:"ipv6,omitempty"`
+	Timeout         int32  `json:"timeout,omitempty"`
+}
+
+// ReachabilityEndpoints selects the endpoints of one side of a reachability matrix. It is one of:
+//   - a Pod (Namespace and Pod);
+//   - the running Pods of Namespace that match PodSelector, a label selector (all of them if it
+//     is empty);
+//   - destinations only: a Service (Namespace and Service) or an IP address.
+type ReachabilityEndpoints struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Pod         string `json:"pod,omitempty"`
+	PodSelector string `json:"podSelector,omitempty"`
+	Service     string `json:"service,omitempty"`
+	IP          string `json:"ip,omitempty"`
+}
+
+// Results of a ReachabilityCell.
+const (
+	ReachabilityAllowed = "Allowed"
+	ReachabilityDenied  = "Denied"
+	ReachabilityError   = "Error"
+)
+
+// ReachabilityMatrix is the response of POST /api/v1/traceflow/matrix.
+type ReachabilityMatrix struct {
+	// Sources and Destinations are the endpoints selected by the request, in order.
+	Sources      []TraceflowSource      `json:"sources"`
+	Destinations []TraceflowDestination `json:"destinations"`
+	// Cells[i][j] is the result of the Traceflow from Sources[i] to Destinations[j].
+	Cells [][]ReachabilityCell `json:"cells"`
+}
+
+type ReachabilityCell struct {
+	// Result is one of ReachabilityAllowed, ReachabilityDenied and ReachabilityError.
+	Result string `json:"result"`
+	// Reason explains an error: the Traceflow could not be run, or it failed.
+	Reason string `json:"reason,omitempty"`
+	// BlockedBy is set for a denied cell.
+	BlockedBy *ReachabilityBlock `json:"blockedBy,omitempty"`
+}
+
+// ReachabilityBlock is the Traceflow observation of a dropped or rejected packet.
+type ReachabilityBlock struct {
+	Node string `json:"node,omitempty"`
+	// Component is the datapath component that dropped the packet, e.g. "NetworkPolicy", and
+	// ComponentInfo gives details, e.g. "IngressRule" or "IngressDefaultRule".
+	Component     string `json:"component,omitempty"`
+	ComponentInfo string `json:"componentInfo,omitempty"`
+	// Action is "Dropped" or "Rejected".
+	Action string `json:"action"`
+	// NetworkPolicy and NetworkPolicyRule identify the policy rule that blocked the packet, when
+	// the component is "NetworkPolicy".
+	NetworkPolicy     string `json:"networkPolicy,omitempty"`
+	NetworkPolicyRule string `json:"networkPolicyRule,omitempty"`
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// SupportBundleRequest is the body of POST /api/v1/supportbundles. The backend validates it and
+// creates an Antrea SupportBundleCollection CR for Nodes, which uploads one bundle per Node to the
+// file server.
+type SupportBundleRequest struct {
+	// Nodes are the names of the Nodes to collect a bundle from. At least one is required.
+	Nodes []string `json:"nodes"`
+	// Controller also collects the bundle of the antrea-controller.
+	Controller bool `json:"controller,omitempty"`
+	// Since limits the logs to the most recent ones, e.g. "2h" or "30m". Empty means all logs.
+	Since string `json:"since,omitempty"`
+	// Title is an optional, free-form description shown in the list of bundles.
+	Title string `json:"title,omitempty"`
+}
+
+// SupportBundleSummary describes one SupportBundleCollection created by antrea-ui, in the list
+// returned by GET /api/v1/supportbundles, and as the result of one request.
+type SupportBundleSummary struct {
+	// ID is the request ID, used in the other /api/v1/supportbundles/:id endpoints.
+	ID        string `json:"id"`
+	Title     string `json:"title,omitempty"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	// CreationTimestamp is in RFC 3339 format.
+	CreationTimestamp string `json:"creationTimestamp"`
+	// Phase is "Running" until the collection is over, then "Succeeded" if at least one
+	// bundle was uploaded, or "Failed". Message explains a failure, including a partial one.
+	Phase          string   `json:"phase"`
+	Message        string   `json:"message,omitempty"`
+	Nodes          []string `json:"nodes"`
+	CollectedNodes int32    `json:"collectedNodes"`
+	DesiredNodes   int32    `json:"desiredNodes"`
+	Controller     bool     `json:"controller,omitempty"`
+	// ControllerStatus is the status of the antrea-controller bundle: "Collecting" or
+	// "Collected", "None" if the antrea-controller no longer has it, or "Superseded" if it was
+	// requested again, for another request, since. It is only set in the result of a request,
+	// not in the list.
+	ControllerStatus string `json:"controllerStatus,omitempty"`
+}
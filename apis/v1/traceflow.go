@@ -0,0 +1,210 @@
+// Copyright 2026 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// TraceflowRequest is the body of POST /api/v1/traceflow. The backend validates it and translates
+// it into the spec of an Antrea Traceflow CR.
+//
+// A regular Traceflow injects a packet from a source Pod, so Source.Pod and a Destination are
+// required. A live-traffic Traceflow captures the first matching packet instead: either endpoint
+// may be omitted, but at least one of them must be a Pod.
+type TraceflowRequest struct {
+	Source      TraceflowSource      `json:"source"`
+	Destination TraceflowDestination `json:"destination"`
+	// Protocol is one of "TCP" (the default), "UDP" or "ICMP".
+	Protocol string `json:"protocol,omitempty"`
+	// SourcePort and DestinationPort only apply to TCP and UDP. Zero leaves them unset.
+	SourcePort      int32 `json:"sourcePort,omitempty"`
+	DestinationPort int32 `json:"destinationPort,omitempty"`
+	// TCPFlags only applies to TCP. It defaults to SYN (2) for a regular Traceflow, and to
+	// matching any flags for a live-traffic one.
+	TCPFlags *int32 `json:"tcpFlags,omitempty"`
+	// IPv6 selects an IPv6 packet when no endpoint is given as an IPv6 address.
+	IPv6 bool `json:"ipv6,omitempty"`
+	// LiveTraffic traces the first real packet matching the request instead of injecting one.
+	LiveTraffic bool `json:"liveTraffic,omitempty"`
+	// DroppedOnly only captures a packet that is dropped. It requires LiveTraffic.
+	DroppedOnly bool `json:"droppedOnly,omitempty"`
+	// Timeout is in seconds, between 1 and 120. Zero means Antrea's default (20s).
+	Timeout int32 `json:"timeout,omitempty"`
+	// Title is an optional, free-form description shown in the Traceflow history.
+	Title string `json:"title,omitempty"`
+}
+
+// TraceflowSource is a Pod (Namespace and Pod) or, for live traffic only, an IP address.
+type TraceflowSource struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// TraceflowDestination is at most one of a Pod, a Service (both with Namespace), an IP address,
+// or a domain name. A domain name is resolved to an IP address by the antrea-ui backend, so
+// in-cluster names resolve as they would from a Pod.
+type TraceflowDestination struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Service   string `json:"service,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	FQDN      string `json:"fqdn,omitempty"`
+}
+
+// TraceflowSummary describes one Traceflow created by antrea-ui, in the list returned by
+// GET /api/v1/traceflow.
+type TraceflowSummary struct {
+	// ID is the request ID, used in the other /api/v1/traceflow/:id endpoints.
+	ID        string `json:"id"`
+	Title     string `json:"title,omitempty"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	// CreationTimestamp and StartTime are in RFC 3339 format. StartTime is empty until Antrea
+	// starts the Traceflow.
+	CreationTimestamp string `json:"creationTimestamp"`
+	StartTime         string `json:"startTime,omitempty"`
+	// Phase is empty until Antrea starts the Traceflow, and then one of "Running", "Succeeded"
+	// or "Failed". Reason explains a failure.
+	Phase       string               `json:"phase,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+	Source      TraceflowSource      `json:"source"`
+	Destination TraceflowDestination `json:"destination"`
+	LiveTraffic bool                 `json:"liveTraffic,omitempty"`
+	// Pinned Traceflows are never garbage-collected.
+	Pinned bool `json:"pinned"`
+	// Delegated Traceflows were created by antrea-ui on behalf of CreatedBy, who may not be
+	// able to create Traceflows.
+	Delegated bool `json:"delegated,omitempty"`
+}
+
+// TraceflowPolicyDetails describes the NetworkPolicy a Traceflow observation refers to. It is
+// added to the observation as "policyDetails" by GET /api/v1/traceflow/:id/result when
+// ?resolvePolicies=true is set.
+type TraceflowPolicyDetails struct {
+	// Kind is the kind of policy, as named by Antrea in the observation, e.g.
+	// "AntreaClusterNetworkPolicy", "AntreaNetworkPolicy" or "K8sNetworkPolicy".
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+	// Tier and Priority are only set for Antrea-native policies.
+	Tier     string   `json:"tier,omitempty"`
+	Priority *float64 `json:"priority,omitempty"`
+	// Rule is the rule named by the observation, if it could be found in the policy.
+	Rule *TraceflowPolicyRule `json:"rule,omitempty"`
+	// ChangedSinceTraceflow is true if the policy was updated or re-created after the
+	// Traceflow started, so that it may no longer behave as observed.
+	ChangedSinceTraceflow bool `json:"changedSinceTraceflow"`
+	// Error explains why the policy could not be resolved, e.g. because it was deleted or the
+	// caller may not get it. Only Kind, Namespace and Name are set then.
+	Error string `json:"error,omitempty"`
+}
+
+// TraceflowPolicyRule is one rule of a NetworkPolicy, as found in its spec.
+type TraceflowPolicyRule struct {
+	Name string `json:"name,omitempty"`
+	// Direction is "Ingress" or "Egress".
+	Direction string `json:"direction"`
+	// Action is only set for Antrea-native policies, e.g. "Allow" or "Drop".
+	Action string `json:"action,omitempty"`
+	// Peers and Ports are copied from the rule: "from" or "to", and "ports".
+	Peers []interface{} `json:"peers,omitempty"`
+	Ports []interface{} `json:"ports,omitempty"`
+}
+
+// TraceflowGCStats describes the garbage collection of the Traceflows created by this antrea-ui
+// install, as returned by GET /api/v1/traceflow/gc.
+type TraceflowGCStats struct {
+	// InstanceID is the value of the ui.antrea.io/instance label of the Traceflows created by
+	// this install. The GC only deletes those, and Traceflows with no such label.
+	InstanceID string `json:"instanceID"`
+	// ExpiryTimeout and Period are in seconds.
+	ExpiryTimeout int64 `json:"expiryTimeout"`
+	Period        int64 `json:"period"`
+	Runs          int64 `json:"runs"`
+	// LastRun is in RFC 3339 format, and empty until the GC first runs.
+	LastRun        string `json:"lastRun,omitempty"`
+	LastRunDeleted int    `json:"lastRunDeleted"`
+	TotalDeleted   int64  `json:"totalDeleted"`
+	// Errors counts the failures to list or delete Traceflows.
+	Errors int64 `json:"errors"`
+}
+
+// TraceflowStatusEvent is the JSON payload of an SSE "status" event of
+// GET /api/v1/traceflow/:id/stream, sent when the Traceflow phase changes.
+type TraceflowStatusEvent struct {
+	// Phase is empty until Antrea starts the Traceflow.
+	Phase  string `json:"phase"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TraceflowStreamErrorEvent is the JSON payload of an SSE "error" event of
+// GET /api/v1/traceflow/:id/stream. It is the last event of the stream.
+type TraceflowStreamErrorEvent struct {
+	Message string `json:"message"`
+}
+
+// TraceflowHuntRequest is the body of POST /api/v1/traceflow/hunt. The embedded TraceflowRequest
+// describes the traffic to watch: LiveTraffic and DroppedOnly are implied, and Timeout (120s by
+// default, at least 60s) applies to each of the hunt's Traceflows.
+type TraceflowHuntRequest struct {
+	TraceflowRequest
+	// Duration is how long the hunt stays armed, in seconds. Zero means the longest duration
+	// allowed by the server.
+	Duration int32 `json:"duration,omitempty"`
+	// MaxCaptures is the number of drops after which the hunt ends. Zero means 1.
+	MaxCaptures int32 `json:"maxCaptures,omitempty"`
+}
+
+// TraceflowHuntArmedEvent is the JSON payload of an SSE "armed" event of
+// POST /api/v1/traceflow/hunt, sent every time a Traceflow is created to wait for a drop.
+type TraceflowHuntArmedEvent struct {
+	// ID is the request ID of the new Traceflow.
+	ID       string `json:"id"`
+	Captures int32  `json:"captures"`
+}
+
+// TraceflowHuntCaptureEvent is the JSON payload of an SSE "capture" event of
+// POST /api/v1/traceflow/hunt, sent when a drop is captured. The Traceflow is kept, and can be
+// read again with its ID.
+type TraceflowHuntCaptureEvent struct {
+	ID string `json:"id"`
+	// Capture counts the drops captured so far, including this one.
+	Capture   int32                  `json:"capture"`
+	Traceflow map[string]interface{} `json:"traceflow"`
+}
+
+// TraceflowHuntEndEvent is the JSON payload of an SSE "end" event of POST /api/v1/traceflow/hunt.
+// It is the last event of a hunt that did not fail.
+type TraceflowHuntEndEvent struct {
+	Captures int32 `json:"captures"`
+	// Reason is "captured" once MaxCaptures drops were captured, and "expired" once Duration
+	// elapsed.
+	Reason string `json:"reason"`
+}
+
+// TraceflowFromFlowRequest is the body of POST /api/v1/traceflow/from-flow. Exactly one of FlowID
+// and Flow must be set. The Traceflow reproduces the flow's connection: its source Pod, its
+// destination Service, Pod or IP address, its protocol and its ports.
+type TraceflowFromFlowRequest struct {
+	// FlowID is the ID of a flow recently received by the caller from GET /api/v1/flows/stream.
+	FlowID string `json:"flowId,omitempty"`
+	// Flow is a flow record, e.g. one the caller saved.
+	Flow *Flow `json:"flow,omitempty"`
+	// LiveTraffic, DroppedOnly, Timeout and Title have the same meaning as in
+	// TraceflowRequest. With LiveTraffic, a flow without a source Pod is traced from its source
+	// IP address.
+	LiveTraffic bool   `json:"liveTraffic,omitempty"`
+	DroppedOnly bool   `json:"droppedOnly,omitempty"`
+	Timeout     int32  `json:"timeout,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
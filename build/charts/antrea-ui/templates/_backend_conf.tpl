@@ -2,6 +2,13 @@
 addr: ":{{ .Values.backend.port }}"
 url: {{ .Values.url | quote }}
 antreaNamespace: {{ .Values.antreaNamespace | quote }}
+antreaAPI:
+  controller:
+    allowedPaths:
+      {{- toYaml .Values.antreaAPI.controller.allowedPaths | nindent 6 }}
+  agent:
+    allowedPaths:
+      {{- toYaml .Values.antreaAPI.agent.allowedPaths | nindent 6 }}
 auth:
   basic:
     enabled: {{ .Values.auth.basic.enable }}
@@ -25,10 +32,53 @@ session:
   maxLifetime: {{ .Values.session.maxLifetime | quote }}
   maxSessions: {{ .Values.session.maxSessions }}
   maxSessionsPerUser: {{ .Values.session.maxSessionsPerUser }}
+instanceID: {{ include "instanceID" . | quote }}
+traceflow:
+  expiryTimeout: {{ .Values.traceflow.expiryTimeout | quote }}
+  gcPeriod: {{ .Values.traceflow.gcPeriod | quote }}
+  delegation:
+    enabled: {{ .Values.traceflow.delegation.enabled }}
+  hunt:
+    maxDuration: {{ .Values.traceflow.hunt.maxDuration | quote }}
+    maxCaptures: {{ .Values.traceflow.hunt.maxCaptures }}
+packetCapture:
+  expiryTimeout: {{ .Values.packetCapture.expiryTimeout | quote }}
+  gcPeriod: {{ .Values.packetCapture.gcPeriod | quote }}
+  fileServer:
+    url: {{ .Values.packetCapture.fileServer.url | quote }}
+    hostPublicKey: {{ .Values.packetCapture.fileServer.hostPublicKey | quote }}
+supportBundle:
+  expiryTimeout: {{ .Values.supportBundle.expiryTimeout | quote }}
+  gcPeriod: {{ .Values.supportBundle.gcPeriod | quote }}
+  fileServer:
+    url: {{ .Values.supportBundle.fileServer.url | quote }}
+    hostPublicKey: {{ .Values.supportBundle.fileServer.hostPublicKey | quote }}
+  authSecret:
+    {{- if .Values.supportBundle.fileServer.url }}
+    name: {{ required "supportBundle.authSecret.name is required when supportBundle.fileServer.url is set" .Values.supportBundle.authSecret.name | quote }}
+    {{- else }}
+    name: {{ .Values.supportBundle.authSecret.name | quote }}
+    {{- end }}
+    namespace: {{ .Values.supportBundle.authSecret.namespace | quote }}
+limits:
+  maxTraceflowsPerHour: {{ .Values.limits.maxTraceflowsPerHour }}
+  maxPacketCapturesPerHour: {{ .Values.limits.maxPacketCapturesPerHour }}
+  maxSupportBundlesPerHour: {{ .Values.limits.maxSupportBundlesPerHour }}
+  traceflowQuota:
+    maxPerHour: {{ .Values.limits.traceflowQuota.maxPerHour }}
+    maxConcurrent: {{ .Values.limits.traceflowQuota.maxConcurrent }}
+    overrides:
+      {{- toYaml .Values.limits.traceflowQuota.overrides | nindent 6 }}
 logVerbosity: {{ .Values.backend.logVerbosity }}
 plugins:
   labelSelector: {{ .Values.plugins.labelSelector | quote }}
   namespace: {{ .Values.plugins.namespace | default .Release.Namespace | quote }}
+probes:
+  interval: {{ .Values.probes.interval | quote }}
+  historySize: {{ .Values.probes.historySize }}
+  sloTarget: {{ .Values.probes.sloTarget }}
+  definitions:
+    {{- toYaml .Values.probes.definitions | nindent 4 }}
 flowAggregator:
   enabled: {{ .Values.flowAggregator.enabled }}
   address: {{ .Values.flowAggregator.address | quote }}
@@ -37,5 +87,15 @@ flowAggregator:
   namespace: {{ .Values.flowAggregator.namespace | default "flow-aggregator" | quote }}
   serverName: {{ .Values.flowAggregator.serverName | quote }}
   insecureSkipVerify: {{ .Values.flowAggregator.insecureSkipVerify }}
+  masking:
+    enabled: {{ .Values.flowAggregator.masking.enabled }}
+    rules:
+      {{- toYaml .Values.flowAggregator.masking.rules | nindent 6 }}
+    default:
+      {{- toYaml .Values.flowAggregator.masking.default | nindent 6 }}
+  streams:
+    maxStreams: {{ .Values.flowAggregator.streams.maxStreams }}
+    maxStreamsPerUser: {{ .Values.flowAggregator.streams.maxStreamsPerUser }}
+    evictOldest: {{ .Values.flowAggregator.streams.evictOldest }}
 {{- end }}
 {{- end }}
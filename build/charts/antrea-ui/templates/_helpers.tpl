@@ -36,6 +36,16 @@
 {{- end }}
 {{- end -}}
 
+{{- define "instanceID" -}}
+{{- if .Values.instanceID }}
+{{- .Values.instanceID -}}
+{{- else if .Values.traceflow.instanceID }}
+{{- .Values.traceflow.instanceID -}}
+{{- else }}
+{{- printf "%s.%s" .Release.Namespace .Release.Name | trunc 63 | regexReplaceAll "[-._]+$" "" -}}
+{{- end }}
+{{- end -}}
+
 {{- define "oidcProviderName" -}}
 {{- .Values.auth.oidc.providerName -}}
 {{- end -}}
@@ -63,6 +63,23 @@ server {
             {{- end }}
         }
 
+        # POST /api/v1/traceflow/matrix only responds once every Traceflow of the matrix is done,
+        # which takes up to 10 minutes (reachabilityMatrixTimeout in the backend), way past nginx's
+        # default proxy_read_timeout (~60s).
+        location = /api/v1/traceflow/matrix {
+            proxy_http_version 1.1;
+            proxy_pass_request_headers on;
+            proxy_hide_header Access-Control-Allow-Origin;
+            proxy_read_timeout 660s;
+            proxy_pass http://127.0.0.1:{{ .Values.backend.port }};
+            {{- $secure := include "cookieSecure" . -}}
+            {{- if eq $secure "true" }}
+            proxy_cookie_flags ~ httponly secure;
+            {{- else }}
+            proxy_cookie_flags ~ httponly;
+            {{- end }}
+        }
+
         location /api {
             proxy_http_version 1.1;
             proxy_pass_request_headers on;
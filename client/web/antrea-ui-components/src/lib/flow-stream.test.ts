@@ -29,6 +29,12 @@ describe('streamFilterKey', () => {
         expect(streamFilterKey(a)).toBe(streamFilterKey(b));
     });
 
+    it('ignores app protocol case and order', () => {
+        const a: FlowStreamFilter = { appProtocols: ['HTTP', 'grpc'], observationDomainIds: [42, 1] };
+        const b: FlowStreamFilter = { appProtocols: ['grpc', 'http'], observationDomainIds: [1, 42] };
+        expect(streamFilterKey(a)).toBe(streamFilterKey(b));
+    });
+
     it('changes when a filter field changes', () => {
         const empty: FlowStreamFilter = {};
         const withNs: FlowStreamFilter = { namespaces: ['default'] };
@@ -190,6 +196,21 @@ describe('FlowStreamClient', () => {
         client.stop();
     });
 
+    // Reconnecting after an eviction would evict the user's newer stream in turn.
+    test('stops for good on an evicted event', async () => {
+        stubFetch(async () => sseResponse([
+            'event: evicted\ndata: {"message":"superseded"}\n\n',
+        ]));
+        const cb = makeCallbacks();
+        const client = new FlowStreamClient({}, cb, 10);
+        client.start();
+        await vi.advanceTimersByTimeAsync(0);
+        await vi.advanceTimersByTimeAsync(60000);
+
+        expect(cb.errors.map(e => e.message)).toEqual(['superseded']);
+        expect(fetchMock).toHaveBeenCalledTimes(1);
+    });
+
     test('batches flows and flushes on the batch interval', async () => {
         stubFetch(async () => sseResponse([
             'event: flow\ndata: {"flows":[{"id":"a"}]}\n\n',
@@ -14,6 +14,7 @@
 
 import { Flow } from './flow-types.js';
 import { getApiBase } from './api.js';
+import { SSEEvent, parseSSEBuffer } from './sse.js';
 
 export type FlowFilterDirection = 'both' | 'from' | 'to';
 export type FlowTypeName = 'intra-node' | 'inter-node' | 'to-external' | 'from-external';
@@ -26,6 +27,9 @@ export interface FlowStreamFilter {
     flowTypes?: FlowTypeName[];
     ips?: string[];
     direction?: FlowFilterDirection;
+    appProtocols?: string[];
+    observationDomainIds?: number[];
+    exporterIps?: string[];
 }
 
 export function streamFilterKey(f: FlowStreamFilter): string {
@@ -35,7 +39,13 @@ export function streamFilterKey(f: FlowStreamFilter): string {
     const flowTypes = [...(f.flowTypes ?? [])].sort();
     const ips = [...(f.ips ?? [])].sort();
     const direction = f.direction && f.direction !== 'both' ? f.direction : 'both';
-    return JSON.stringify({ namespaces, pods, podLabelSelector: f.podLabelSelector ?? '', services, flowTypes, ips, direction });
+    const appProtocols = [...(f.appProtocols ?? [])].map(p => p.toLowerCase()).sort();
+    const observationDomainIds = [...(f.observationDomainIds ?? [])].sort((a, b) => a - b);
+    const exporterIps = [...(f.exporterIps ?? [])].sort();
+    return JSON.stringify({
+        namespaces, pods, podLabelSelector: f.podLabelSelector ?? '', services, flowTypes, ips, direction,
+        appProtocols, observationDomainIds, exporterIps,
+    });
 }
 
 export interface FlowStreamCallbacks {
@@ -52,7 +62,6 @@ export interface FlowStreamCallbacks {
     onDisabled?: () => void;
 }
 
-interface SSEEvent { type: string; data: string; }
 interface SSEFlowEvent { flows: Flow[]; }
 interface SSEDroppedEvent { droppedCount: number; }
 interface SSEErrorEvent { message: string; }
@@ -66,6 +75,9 @@ function buildStreamURL(filter: FlowStreamFilter): string {
     if (filter.flowTypes?.length) params.set('flowTypes', filter.flowTypes.join(','));
     if (filter.ips?.length) params.set('ips', filter.ips.join(','));
     if (filter.direction && filter.direction !== 'both') params.set('direction', filter.direction);
+    if (filter.appProtocols?.length) params.set('appProtocols', filter.appProtocols.join(','));
+    if (filter.observationDomainIds?.length) params.set('observationDomainIds', filter.observationDomainIds.join(','));
+    if (filter.exporterIps?.length) params.set('exporterIps', filter.exporterIps.join(','));
     return `${getApiBase()}/api/v1/flows/stream?${params.toString()}`;
 }
 
@@ -76,7 +88,8 @@ function buildStreamURL(filter: FlowStreamFilter): string {
  * backend keeps the session alive for as long as the stream is attached, and closes the stream if
  * the session ends. On HTTP 401 the session is gone for good: onAuthError() fires and the stream
  * stops for good too. On HTTP 501, Flow Aggregator integration is disabled for this deployment:
- * onDisabled() fires and the stream stops for good, the same way.
+ * onDisabled() fires and the stream stops for good, the same way. An "evicted" event, sent when the
+ * same user opened more streams than the backend allows, also stops the stream for good.
  */
 export class FlowStreamClient {
     private abortController: AbortController | null = null;
@@ -187,7 +200,7 @@ export class FlowStreamClient {
                 const { done, value } = await reader.read();
                 if (done) break;
                 buffer += decoder.decode(value, { stream: true });
-                const { parsed, remaining } = this.parseSSEBuffer(buffer);
+                const { parsed, remaining } = parseSSEBuffer(buffer);
                 buffer = remaining;
                 for (const event of parsed) this.handleSSEEvent(event);
             }
@@ -200,27 +213,6 @@ export class FlowStreamClient {
         if (this.running) this.scheduleReconnect();
     }
 
-    private parseSSEBuffer(buffer: string): { parsed: SSEEvent[]; remaining: string } {
-        const events: SSEEvent[] = [];
-        const normalized = buffer.replace(/\r\n/g, '\n');
-        const blocks = normalized.split('\n\n');
-        const remaining = blocks.pop() ?? '';
-        for (const block of blocks) {
-            if (!block.trim()) continue;
-            let eventType = 'message';
-            let data = '';
-            for (const line of block.split('\n')) {
-                if (line.startsWith('event:')) { eventType = line.slice(6).trim(); }
-                else if (line.startsWith('data:')) {
-                    const value = line.startsWith('data: ') ? line.slice(6) : line.slice(5);
-                    data += (data ? '\n' : '') + value;
-                }
-            }
-            if (data) events.push({ type: eventType, data });
-        }
-        return { parsed: events, remaining };
-    }
-
     private handleSSEEvent(event: SSEEvent): void {
         try {
             if (event.type === 'flow') {
@@ -232,6 +224,14 @@ export class FlowStreamClient {
             } else if (event.type === 'error') {
                 const payload = JSON.parse(event.data) as SSEErrorEvent;
                 this.callbacks.onError(new Error(payload.message));
+            } else if (event.type === 'evicted') {
+                // The same user opened a newer stream past their limit. Reconnecting would
+                // evict that one in turn, so stop for good.
+                const payload = JSON.parse(event.data) as SSEErrorEvent;
+                this.running = false;
+                this.stopBatchTimer();
+                this.flushBatch();
+                this.callbacks.onError(new Error(payload.message));
             }
         } catch (err) { console.error('Failed to parse SSE event', event, err); }
     }
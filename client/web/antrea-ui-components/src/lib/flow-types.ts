@@ -124,6 +124,32 @@ export interface Kubernetes {
     egressUid: string;
 }
 
+export interface App {
+    protocolName: string;
+    // JSON-encoded HTTP transaction data, as exported by the agent.
+    httpVals?: string;
+}
+
+export interface IPFIX {
+    exportTime?: string;
+    sequenceNumber: number;
+    observationDomainId: number;
+    exporterIp: string;
+}
+
+export interface Aggregation {
+    endTsFromSource?: string;
+    endTsFromDestination?: string;
+    statsFromSource: Stats;
+    reverseStatsFromSource: Stats;
+    statsFromDestination: Stats;
+    reverseStatsFromDestination: Stats;
+    throughputFromSource: number;
+    reverseThroughputFromSource: number;
+    throughputFromDestination: number;
+    reverseThroughputFromDestination: number;
+}
+
 export interface Flow {
     id: string;
     startTs: string;
@@ -134,6 +160,9 @@ export interface Flow {
     k8s: Kubernetes;
     stats: Stats;
     reverseStats: Stats;
+    app?: App;
+    ipfix?: IPFIX;
+    aggregation?: Aggregation;
 }
 
 export const flowTypeLabel: Record<FlowType, string> = {
@@ -0,0 +1,41 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+export interface SSEEvent { type: string; data: string; }
+
+/**
+ * Parses the complete Server-Sent Events in buffer, and returns them with the trailing partial
+ * event, which the caller should prepend to the next chunk read from the stream. Comments (used
+ * by the backend as keepalives) and events without data are dropped.
+ */
+export function parseSSEBuffer(buffer: string): { parsed: SSEEvent[]; remaining: string } {
+    const events: SSEEvent[] = [];
+    const normalized = buffer.replace(/\r\n/g, '\n');
+    const blocks = normalized.split('\n\n');
+    const remaining = blocks.pop() ?? '';
+    for (const block of blocks) {
+        if (!block.trim()) continue;
+        let eventType = 'message';
+        let data = '';
+        for (const line of block.split('\n')) {
+            if (line.startsWith('event:')) { eventType = line.slice(6).trim(); }
+            else if (line.startsWith('data:')) {
+                const value = line.startsWith('data: ') ? line.slice(6) : line.slice(5);
+                data += (data ? '\n' : '') + value;
+            }
+        }
+        if (data) events.push({ type: eventType, data });
+    }
+    return { parsed: events, remaining };
+}
@@ -185,14 +185,14 @@ describe('AntreaTraceflowPage — building and submitting the Traceflow request'
 
         const createCall = calls.find(c => c.url === '/api/v1/traceflow');
         expect(createCall).toBeDefined();
-        const sentSpec = JSON.parse(createCall!.init!.body as string).spec;
-        expect(sentSpec).toEqual({
+        const sentRequest = JSON.parse(createCall!.init!.body as string);
+        expect(sentRequest).toEqual({
             source: { namespace: 'namespaceA', pod: 'podA' },
             destination: { namespace: 'namespaceA', service: 'serviceA' },
-            packet: {
-                ipv6Header: { nextHeader: 6 },
-                transportHeader: { tcp: { dstPort: 80, flags: 2 } },
-            },
+            protocol: 'TCP',
+            destinationPort: 80,
+            tcpFlags: 2,
+            ipv6: true,
             timeout: 20,
         });
     });
@@ -218,14 +218,11 @@ describe('AntreaTraceflowPage — building and submitting the Traceflow request'
 
         const createCall = calls.find(c => c.url === '/api/v1/traceflow');
         expect(createCall).toBeDefined();
-        const sentSpec = JSON.parse(createCall!.init!.body as string).spec;
-        expect(sentSpec).toEqual({
+        const sentRequest = JSON.parse(createCall!.init!.body as string);
+        expect(sentRequest).toEqual({
             source: {},
             destination: { namespace: 'namespaceA', pod: 'podA' },
-            packet: {
-                ipHeader: { protocol: 17 },
-                transportHeader: { udp: {} },
-            },
+            protocol: 'UDP',
             liveTraffic: true,
             droppedOnly: true,
             timeout: 120,
@@ -305,3 +302,139 @@ describe('AntreaTraceflowPage — building and submitting the Traceflow request'
         expect(alertText).toContain('timeout must be between');
     });
 });
+
+describe('AntreaTraceflowPage — history', () => {
+    test('lists past Traceflows on demand, sends the title, and pins', async () => {
+        const calls: { url: string; init?: RequestInit }[] = [];
+        let pinned = false;
+        const fn = vi.fn(async (url: string, init?: RequestInit) => {
+            calls.push({ url, init });
+            if (url === '/api/v1/traceflow' && (init?.method ?? 'GET') === 'GET') {
+                const history = [{
+                    id: 'tf-1', title: 'web tier', createdBy: 'alice', creationTimestamp: '2026-10-18T10:00:00Z',
+                    phase: 'Succeeded', source: { namespace: 'ns', pod: 'client' },
+                    destination: { namespace: 'ns', service: 'web' }, pinned,
+                }];
+                return { ok: true, status: 200, statusText: 'OK', url, headers: { get: () => null }, json: async () => history } as unknown as Response;
+            }
+            if (url === '/api/v1/traceflow/tf-1/pin') {
+                pinned = init?.method === 'PUT';
+                return { ok: true, status: 200, statusText: 'OK', url, headers: { get: () => null }, text: async () => '' } as unknown as Response;
+            }
+            throw new Error(`unexpected fetch: ${init?.method ?? 'GET'} ${url}`);
+        });
+        vi.stubGlobal('fetch', fn);
+        const page = await mount();
+        expect(calls).toHaveLength(0);
+
+        const historyButton = Array.from(page.shadowRoot!.querySelectorAll('antrea-button'))
+            .find(b => b.textContent?.trim() === 'History')!;
+        historyButton.click();
+        await new Promise(r => setTimeout(r, 0));
+        await page.updateComplete;
+
+        const rows = page.shadowRoot!.querySelectorAll('#history tbody tr');
+        expect(rows).toHaveLength(1);
+        expect(rows[0].textContent).toContain('web tier');
+        expect(rows[0].textContent).toContain('ns/web (Service)');
+
+        const pinButton = Array.from(rows[0].querySelectorAll('antrea-button')).find(b => b.textContent?.trim() === 'Pin')!;
+        pinButton.click();
+        await new Promise(r => setTimeout(r, 0));
+        await page.updateComplete;
+        expect(calls.some(c => c.url === '/api/v1/traceflow/tf-1/pin' && c.init?.method === 'PUT')).toBe(true);
+        expect(page.shadowRoot!.querySelector('#history tbody tr')!.textContent).toContain('Unpin');
+    });
+
+    test('sends the title with the request', async () => {
+        const calls: { url: string; init?: RequestInit }[] = [];
+        vi.stubGlobal('fetch', vi.fn(async (url: string, init?: RequestInit) => {
+            calls.push({ url, init });
+            throw new Error('stop here');
+        }));
+        const page = await mount();
+        setInput(page, 'title', 'web tier');
+        setInput(page, 'src', 'client');
+        setInput(page, 'dst', 'server');
+        await page.updateComplete;
+
+        page.shadowRoot!.querySelector('form')!.dispatchEvent(new Event('submit', { bubbles: true, cancelable: true }));
+        await new Promise(r => setTimeout(r, 0));
+
+        const createCall = calls.find(c => c.url === '/api/v1/traceflow' && c.init?.method === 'POST');
+        expect(JSON.parse(createCall!.init!.body as string).title).toBe('web tier');
+    });
+});
+
+describe('AntreaTraceflowPage — status stream', () => {
+    function mockStreamFetch(events: string[]) {
+        const calls: { url: string; init?: RequestInit }[] = [];
+        const fn = vi.fn(async (url: string, init?: RequestInit) => {
+            calls.push({ url, init });
+            if (url === '/api/v1/traceflow' && init?.method === 'POST') {
+                return {
+                    ok: true, status: 202, statusText: 'Accepted', url,
+                    headers: { get: (k: string) => (k.toLowerCase() === 'location' ? '/api/v1/traceflow/tf-1' : null) },
+                    text: async () => '', json: async () => ({}),
+                } as unknown as Response;
+            }
+            if (url === '/api/v1/traceflow/tf-1/stream') {
+                // Split each event across two chunks, as the network may.
+                const chunks = events.flatMap(e => [e.slice(0, 5), e.slice(5)]).map(c => new TextEncoder().encode(c));
+                const reader = {
+                    read: async () => (chunks.length > 0 ? { done: false, value: chunks.shift() } : { done: true, value: undefined }),
+                };
+                return {
+                    ok: true, status: 200, statusText: 'OK', url,
+                    headers: { get: () => null }, body: { getReader: () => reader },
+                } as unknown as Response;
+            }
+            throw new Error(`unexpected fetch: ${init?.method ?? 'GET'} ${url}`);
+        });
+        return { fn, calls };
+    }
+
+    async function submit(page: AntreaTraceflowPage) {
+        setInput(page, 'src', 'client');
+        setInput(page, 'dst', 'server');
+        await page.updateComplete;
+        page.shadowRoot!.querySelector('form')!.dispatchEvent(new Event('submit', { bubbles: true, cancelable: true }));
+        await new Promise(r => setTimeout(r, 50));
+        await page.updateComplete;
+    }
+
+    test('gets the result from the stream without polling', async () => {
+        const result = { status: { phase: 'Failed', reason: 'timed out', results: [] } };
+        const { fn, calls } = mockStreamFetch([
+            'event:status\ndata:{"phase":"Running"}\n\n',
+            ': keepalive\n\n',
+            `event:result\ndata:${JSON.stringify(result)}\n\n`,
+        ]);
+        vi.stubGlobal('fetch', fn);
+        const page = await mount();
+        await submit(page);
+
+        expect(calls.some(c => c.url.endsWith('/status'))).toBe(false);
+        expect((page as unknown as { _resultStatus?: { phase: string } })._resultStatus?.phase).toBe('Failed');
+
+        // The displayed result can be exported.
+        const exportButton = Array.from(page.shadowRoot!.querySelectorAll('antrea-button'))
+            .find(b => b.textContent?.trim() === 'Export Bundle')!;
+        exportButton.click();
+        await new Promise(r => setTimeout(r, 0));
+        expect(calls.some(c => c.url === '/api/v1/traceflow/tf-1/export')).toBe(true);
+    });
+
+    test('an error event is reported instead of polling', async () => {
+        const { fn, calls } = mockStreamFetch([
+            'event:error\ndata:{"message":"traceflow was deleted before completing"}\n\n',
+        ]);
+        vi.stubGlobal('fetch', fn);
+        const page = await mount();
+        await submit(page);
+
+        expect(calls.some(c => c.url.endsWith('/status'))).toBe(false);
+        expect(page.shadowRoot!.querySelector('antrea-alert[status="danger"]')?.textContent)
+            .toContain('traceflow was deleted before completing');
+    });
+});
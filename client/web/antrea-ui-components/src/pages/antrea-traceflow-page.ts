@@ -17,7 +17,8 @@ import { state, query } from 'lit/decorators.js';
 import { isIP, ipVersion } from 'is-ip';
 import { graphviz } from 'd3-graphviz';
 import { pageStyles } from '../lib/styles.js';
-import { apiFetch } from '../lib/api.js';
+import { APIError, apiFetch, apiFetchJSON } from '../lib/api.js';
+import { parseSSEBuffer } from '../lib/sse.js';
 import { SessionAwarePage } from '../lib/session-aware-page.js';
 import '../antrea-button';
 import '../antrea-alert';
@@ -44,6 +45,37 @@ interface TraceflowSpec {
     timeout?: number;
 }
 
+// TraceflowRequest is the body of POST /api/v1/traceflow (apisv1.TraceflowRequest), which the
+// backend validates and translates into a Traceflow spec.
+interface TraceflowRequest {
+    source: { namespace?: string; pod?: string; ip?: string };
+    destination: { namespace?: string; pod?: string; service?: string; ip?: string; fqdn?: string };
+    protocol: 'TCP' | 'UDP' | 'ICMP';
+    sourcePort?: number;
+    destinationPort?: number;
+    tcpFlags?: number;
+    ipv6?: boolean;
+    liveTraffic?: boolean;
+    droppedOnly?: boolean;
+    timeout?: number;
+    title?: string;
+}
+
+// TraceflowSummary is one entry of GET /api/v1/traceflow (apisv1.TraceflowSummary).
+interface TraceflowSummary {
+    id: string;
+    title?: string;
+    createdBy?: string;
+    creationTimestamp: string;
+    startTime?: string;
+    phase?: string;
+    reason?: string;
+    source: TraceflowRequest['source'];
+    destination: TraceflowRequest['destination'];
+    liveTraffic?: boolean;
+    pinned: boolean;
+}
+
 interface TraceflowObservation {
     component: string; componentInfo: string; action: string; pod: string;
     dstMAC: string; networkPolicy: string; egress: string; ttl: number;
@@ -55,6 +87,32 @@ interface TraceflowNodeResult { node: string; role: string; timestamp: number; o
 interface TraceflowStatus { phase: string; reason: string; startTime: string; results: TraceflowNodeResult[]; capturedPacket?: TraceflowPacket; }
 interface TraceflowResult { apiVersion?: string; kind?: string; metadata?: { name?: string }; spec?: TraceflowSpec; status?: TraceflowStatus; }
 
+// requestForSpec translates the spec built (and rendered) by the page into the API request that
+// produces it.
+function requestForSpec(spec: TraceflowSpec): TraceflowRequest {
+    const th = spec.packet?.transportHeader ?? {};
+    const ports = th.tcp ?? th.udp;
+    const req: TraceflowRequest = {
+        source: spec.source,
+        destination: spec.destination,
+        protocol: th.icmp ? 'ICMP' : th.udp ? 'UDP' : 'TCP',
+    };
+    if (ports?.srcPort) req.sourcePort = ports.srcPort;
+    if (ports?.dstPort) req.destinationPort = ports.dstPort;
+    if (th.tcp?.flags !== undefined) req.tcpFlags = th.tcp.flags;
+    if (spec.packet?.ipv6Header) req.ipv6 = true;
+    if (spec.liveTraffic) req.liveTraffic = true;
+    if (spec.droppedOnly) req.droppedOnly = true;
+    if (spec.timeout) req.timeout = spec.timeout;
+    return req;
+}
+
+function endpointLabel(e: { namespace?: string; pod?: string; service?: string; ip?: string }): string {
+    if (e.pod) return `${e.namespace}/${e.pod}`;
+    if (e.service) return `${e.namespace}/${e.service} (Service)`;
+    return e.ip ?? '';
+}
+
 // ── DOT graph builder (ported from traceflowresult.tsx) ───────────────────────
 
 const ghostWhite = '"#F8F8FF"';
@@ -199,6 +257,7 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         .tf-layout { display: flex; gap: 1.5rem; flex-wrap: wrap; }
         .tf-form { flex: 0 0 auto; min-width: 340px; }
         .tf-result { flex: 1 1 0; min-width: 0; }
+        .tf-export { display: flex; gap: 0.5rem; margin-top: 1rem; }
     `];
 
     // Form state
@@ -215,15 +274,32 @@ export class AntreaTraceflowPage extends SessionAwarePage {
     @state() private _ipv6 = false;
     @state() private _live = false;
     @state() private _droppedOnly = false;
+    @state() private _title = '';
 
     // Run state
     @state() private _running = false;
     @state() private _resultSpec?: TraceflowSpec;
     @state() private _resultStatus?: TraceflowStatus;
+    // _resultId is the request ID of the displayed result, used to export it.
+    @state() private _resultId?: string;
     @state() private _formError = '';
 
+    // History state. The history is only fetched once the user opens it: listing Traceflows
+    // needs a permission that running one does not.
+    @state() private _historyOpen = false;
+    @state() private _history?: TraceflowSummary[];
+    @state() private _historyError = '';
+
+    // Aborts the status stream of the running Traceflow when the page is removed.
+    private _streamAbort?: AbortController;
+
     @query('#graph-container') private _graphContainer?: HTMLDivElement;
 
+    override disconnectedCallback() {
+        super.disconnectedCallback();
+        this._streamAbort?.abort();
+    }
+
     override updated(changed: Map<string, unknown>) {
         super.updated(changed);
         if ((changed.has('_resultStatus') || changed.has('_resultSpec')) && this._resultStatus?.phase === 'Succeeded') {
@@ -324,17 +400,23 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         return spec;
     }
 
-    private async _run(spec: TraceflowSpec) {
+    private async _run(spec: TraceflowSpec): Promise<{ id: string; status?: TraceflowStatus } | undefined> {
         try {
             const createResp = await apiFetch('traceflow', {
                 method: 'POST',
                 headers: { 'content-type': 'application/json' },
-                body: JSON.stringify({ spec }),
+                body: JSON.stringify({ ...requestForSpec(spec), ...(this._title ? { title: this._title } : {}) }),
             });
             if (createResp.status !== 202) throw new Error('Expected 202 from traceflow create');
             const location = createResp.headers.get('location');
             if (!location) throw new Error('Missing Location header');
-            const statusURL = `${location}/status`;
+            const requestPath = location.replace('/api/v1/', '');
+            const id = location.substring(location.lastIndexOf('/') + 1);
+            // Prefer the pushed status; fall back to polling if the stream is not available
+            // (e.g. an older backend, or a proxy that buffers responses) or breaks off early.
+            const streamed = await this._streamResult(`${requestPath}/stream`);
+            if (streamed) return { id, status: streamed.status };
+            const statusURL = `${requestPath}/status`;
 
             let pollResp = createResp;
             // Bounded by both disconnection (user navigated away — stop polling with what may
@@ -348,15 +430,14 @@ export class AntreaTraceflowPage extends SessionAwarePage {
                 if (isNaN(wait) || wait === 0) wait = 100;
                 await new Promise(r => setTimeout(r, wait));
                 if (!this.isConnected) return undefined;
-                pollResp = await apiFetch(statusURL.replace('/api/v1/', ''));
+                pollResp = await apiFetch(statusURL);
                 const done = pollResp.url.endsWith('/result');
                 if (done) {
                     if (!this.isConnected) return undefined;
-                    // Clean up
-                    apiFetch(location.replace('/api/v1/', ''), { method: 'DELETE' })
-                        .catch(() => { /* best-effort */ });
+                    // The Traceflow is not deleted: it stays in the history until the backend
+                    // garbage-collects it.
                     const tf = await pollResp.json() as TraceflowResult;
-                    return tf.status;
+                    return { id, status: tf.status };
                 }
             }
             throw new Error('Timed out waiting for the Traceflow result');
@@ -369,6 +450,49 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         }
     }
 
+    /**
+     * Waits for the Traceflow result on its SSE stream. Returns undefined if the stream is not
+     * usable, in which case the caller polls instead; a 401 or an "error" event from the backend
+     * (the Traceflow was deleted or never completed) is thrown, since polling would not help.
+     */
+    private async _streamResult(path: string): Promise<TraceflowResult | undefined> {
+        if (!this.isConnected) return undefined;
+        const abort = new AbortController();
+        this._streamAbort = abort;
+        try {
+            let resp: Response;
+            try {
+                resp = await apiFetch(path, { headers: { 'Accept': 'text/event-stream' }, signal: abort.signal });
+            } catch (e) {
+                if (this.isSessionExpiredError(e)) throw e;
+                return undefined;
+            }
+            if (!resp.body) return undefined;
+            const reader = resp.body.getReader();
+            const decoder = new TextDecoder();
+            let buffer = '';
+            for (;;) {
+                let chunk: ReadableStreamReadResult<Uint8Array>;
+                try {
+                    chunk = await reader.read();
+                } catch {
+                    return undefined;
+                }
+                if (chunk.done) return undefined;
+                buffer += decoder.decode(chunk.value, { stream: true });
+                const { parsed, remaining } = parseSSEBuffer(buffer);
+                buffer = remaining;
+                for (const event of parsed) {
+                    if (event.type === 'result') return JSON.parse(event.data) as TraceflowResult;
+                    if (event.type === 'error') throw new Error((JSON.parse(event.data) as { message: string }).message);
+                }
+            }
+        } finally {
+            abort.abort();
+            if (this._streamAbort === abort) this._streamAbort = undefined;
+        }
+    }
+
     private async _submit(e: Event) {
         e.preventDefault();
         this._formError = '';
@@ -382,8 +506,9 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         }
         this._running = true;
         try {
-            const status = await this._run(spec);
-            if (status) { this._resultSpec = spec; this._resultStatus = status; }
+            const result = await this._run(spec);
+            if (result?.status) { this._resultSpec = spec; this._resultStatus = result.status; this._resultId = result.id; }
+            if (result?.status && this._historyOpen) void this._loadHistory();
         } catch (err) {
             this._formError = err instanceof Error ? err.message : String(err);
             this.dispatchEvent(new CustomEvent('antrea-error', { detail: { message: this._formError }, bubbles: true, composed: true }));
@@ -397,8 +522,96 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         this._srcPort = 0; this._dstType = 'Pod'; this._dstNs = 'default';
         this._dst = ''; this._dstPort = 80; this._tcpFlags = 2;
         this._timeout = 20; this._ipv6 = false; this._live = false;
-        this._droppedOnly = false; this._formError = '';
-        this._resultSpec = undefined; this._resultStatus = undefined;
+        this._droppedOnly = false; this._title = ''; this._formError = '';
+        this._resultSpec = undefined; this._resultStatus = undefined; this._resultId = undefined;
+    }
+
+    private async _toggleHistory() {
+        this._historyOpen = !this._historyOpen;
+        if (this._historyOpen) await this._loadHistory();
+    }
+
+    private async _loadHistory() {
+        this._historyError = '';
+        try {
+            this._history = await apiFetchJSON<TraceflowSummary[]>('traceflow');
+        } catch (err) {
+            if (this.isSessionExpiredError(err)) {
+                this.dispatchSessionExpired();
+                return;
+            }
+            this._history = undefined;
+            this._historyError = err instanceof APIError && err.code === 403
+                ? 'Your account is not allowed to list Traceflows.'
+                : `Failed to load the Traceflow history: ${err instanceof Error ? err.message : String(err)}`;
+        }
+    }
+
+    private async _openFromHistory(summary: TraceflowSummary) {
+        this._formError = '';
+        try {
+            const tf = await apiFetchJSON<TraceflowResult>(`traceflow/${summary.id}/result`);
+            this._resultSpec = tf.spec;
+            this._resultStatus = tf.status;
+            this._resultId = summary.id;
+        } catch (err) {
+            if (this.isSessionExpiredError(err)) {
+                this.dispatchSessionExpired();
+                return;
+            }
+            this._formError = err instanceof Error ? err.message : String(err);
+        }
+    }
+
+    private async _setPinned(summary: TraceflowSummary, pinned: boolean) {
+        try {
+            await apiFetch(`traceflow/${summary.id}/pin`, { method: pinned ? 'PUT' : 'DELETE' });
+            await this._loadHistory();
+        } catch (err) {
+            if (this.isSessionExpiredError(err)) {
+                this.dispatchSessionExpired();
+                return;
+            }
+            this._historyError = err instanceof Error ? err.message : String(err);
+        }
+    }
+
+    private _renderHistory() {
+        if (!this._historyOpen) return nothing;
+        const done = (phase?: string) => phase === 'Succeeded' || phase === 'Failed';
+        return html`
+            <div class="page-layout">
+                <p class="page-title">History</p>
+                ${this._historyError ? html`<antrea-alert status="danger">${this._historyError}</antrea-alert>` : nothing}
+                ${this._history?.length === 0 ? html`<p class="text-muted">No Traceflows yet.</p>` : nothing}
+                ${this._history?.length ? html`
+                    <table class="data-table" id="history">
+                        <thead>
+                            <tr><th>Title</th><th>Created By</th><th>Created</th><th>Source</th><th>Destination</th><th>Phase</th><th></th></tr>
+                        </thead>
+                        <tbody>
+                            ${this._history.map(tf => html`
+                                <tr>
+                                    <td>${tf.title ?? ''}${tf.liveTraffic ? html` <span class="text-muted">(live)</span>` : nothing}</td>
+                                    <td>${tf.createdBy ?? ''}</td>
+                                    <td>${new Date(tf.creationTimestamp).toLocaleString()}</td>
+                                    <td>${endpointLabel(tf.source)}</td>
+                                    <td>${endpointLabel(tf.destination)}</td>
+                                    <td>${tf.phase ?? 'Pending'}</td>
+                                    <td>
+                                        <div class="btn-group">
+                                            <antrea-button type="button" action="outline" ?disabled=${!done(tf.phase)}
+                                                @click=${() => this._openFromHistory(tf)}>View</antrea-button>
+                                            <antrea-button type="button" action="outline"
+                                                @click=${() => this._setPinned(tf, !tf.pinned)}>${tf.pinned ? 'Unpin' : 'Pin'}</antrea-button>
+                                        </div>
+                                    </td>
+                                </tr>
+                            `)}
+                        </tbody>
+                    </table>
+                ` : nothing}
+            </div>`;
     }
 
     private _onProtoChange(e: Event) {
@@ -413,6 +626,36 @@ export class AntreaTraceflowPage extends SessionAwarePage {
         this._tcpFlags = this._proto === 'TCP' && !this._live ? 2 : 0;
     }
 
+    // _download saves the response of an API call as a file, since the session cookie cannot be
+    // sent by a plain link to another origin.
+    private async _download(path: string, filename: string) {
+        try {
+            const resp = await apiFetch(path);
+            const url = URL.createObjectURL(await resp.blob());
+            const a = document.createElement('a');
+            a.href = url;
+            a.download = filename;
+            a.click();
+            URL.revokeObjectURL(url);
+        } catch (err) {
+            if (this.isSessionExpiredError(err)) {
+                this.dispatchSessionExpired();
+                return;
+            }
+            this._formError = `Failed to export the Traceflow: ${err instanceof Error ? err.message : String(err)}`;
+        }
+    }
+
+    private _renderExport() {
+        const id = this._resultId;
+        if (!id) return nothing;
+        return html`
+            <div class="tf-export">
+                <antrea-button @click=${() => this._download(`traceflow/${id}/diagram?format=svg`, `traceflow-${id}.svg`)}>Download SVG</antrea-button>
+                <antrea-button @click=${() => this._download(`traceflow/${id}/export`, `traceflow-${id}.zip`)}>Export Bundle</antrea-button>
+            </div>`;
+    }
+
     private _renderResult() {
         if (!this._resultStatus) return nothing;
         const { phase, reason } = this._resultStatus;
@@ -422,6 +665,7 @@ export class AntreaTraceflowPage extends SessionAwarePage {
                     <p class="page-title">Result</p>
                     <antrea-alert status="danger">Traceflow Failed</antrea-alert>
                     <antrea-alert status="danger">${reason}</antrea-alert>
+                    ${this._renderExport()}
                 </div>`;
         }
         if (phase === 'Succeeded') {
@@ -429,6 +673,7 @@ export class AntreaTraceflowPage extends SessionAwarePage {
                 <div class="page-layout">
                     <p class="page-title">Result</p>
                     <div id="graph-container"></div>
+                    ${this._renderExport()}
                 </div>`;
         }
         return html`<p>Unknown phase: ${phase}</p>`;
@@ -452,6 +697,12 @@ export class AntreaTraceflowPage extends SessionAwarePage {
                         ${this._running ? html`<antrea-alert status="loading">Running Traceflow, this may take a few seconds…</antrea-alert>` : nothing}
 
                         <form class="form-stack" @submit=${this._submit}>
+                            <div class="field-group">
+                                <label class="field-label" for="title">Title</label>
+                                <input id="title" class="field-input" maxlength="256" .value=${this._title}
+                                    placeholder="Optional, shown in the history"
+                                    @input=${(e: Event) => { this._title = (e.target as HTMLInputElement).value; }} />
+                            </div>
                             <div class="field-group">
                                 <label class="field-label" for="src-ns">Source Namespace</label>
                                 <input id="src-ns" class="field-input" .value=${this._srcNs} @input=${(e: Event) => { this._srcNs = (e.target as HTMLInputElement).value; }} />
@@ -560,12 +811,16 @@ export class AntreaTraceflowPage extends SessionAwarePage {
                             <div class="btn-group">
                                 <antrea-button type="submit" ?disabled=${this._running}>Run Traceflow</antrea-button>
                                 <antrea-button type="button" action="outline" @click=${this._reset}>Reset</antrea-button>
+                                <antrea-button type="button" action="outline" @click=${this._toggleHistory}>
+                                    ${this._historyOpen ? 'Hide History' : 'History'}
+                                </antrea-button>
                             </div>
                         </form>
                     </div>
 
                     <div class="tf-result">
                         ${this._renderResult()}
+                        ${this._renderHistory()}
                     </div>
                 </div>
             </main>
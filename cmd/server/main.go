@@ -34,13 +34,20 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	apisv1 "antrea.io/antrea-ui/apis/v1"
 	"antrea.io/antrea-ui/pkg/auth/session"
 	serverconfig "antrea.io/antrea-ui/pkg/config/server"
 	"antrea.io/antrea-ui/pkg/env"
+	"antrea.io/antrea-ui/pkg/fileserver"
 	accesshandler "antrea.io/antrea-ui/pkg/handlers/access"
+	antreaagenthandler "antrea.io/antrea-ui/pkg/handlers/antreaagent"
 	antreasvchandler "antrea.io/antrea-ui/pkg/handlers/antreasvc"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
 	"antrea.io/antrea-ui/pkg/handlers/flowstream"
 	"antrea.io/antrea-ui/pkg/handlers/k8sproxy"
+	"antrea.io/antrea-ui/pkg/handlers/packetcapture"
+	"antrea.io/antrea-ui/pkg/handlers/probes"
+	"antrea.io/antrea-ui/pkg/handlers/supportbundle"
 	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
 	"antrea.io/antrea-ui/pkg/k8s"
 	"antrea.io/antrea-ui/pkg/password"
@@ -106,6 +113,9 @@ func ginLogger(logger logr.Logger, level int) gin.HandlerFunc {
 
 func run() error {
 	logger.Info("Starting Antrea UI backend", "version", version.GetFullVersionWithRuntimeInfo())
+	if config.Traceflow.InstanceID != "" {
+		logger.Info("WARNING: traceflow.instanceID is deprecated, use instanceID instead")
+	}
 
 	k8sRESTConfig, k8sHTTPClient, k8sDynamicClient, err := k8s.Client()
 	if err != nil {
@@ -131,7 +141,11 @@ func run() error {
 		return fmt.Errorf("failed to create K8s client factory: %w", err)
 	}
 
-	traceflowHandler := traceflowhandler.NewRequestsHandler(logger, k8sAdminDynamicClient)
+	traceflowHandler := traceflowhandler.NewRequestsHandler(logger, k8sAdminDynamicClient, traceflowhandler.GCConfig{
+		InstanceID:    config.InstanceID,
+		ExpiryTimeout: config.Traceflow.ExpiryTimeout,
+		Period:        config.Traceflow.GCPeriod,
+	})
 	k8sProxyHandler := k8sproxy.NewK8sProxyHandler(logger, k8sServerURL, func(req *http.Request) (http.RoundTripper, error) {
 		return clientFactory.TransportForRequest(req.Context())
 	})
@@ -146,12 +160,79 @@ func run() error {
 	}
 	pluginRegistry := pluginregistry.NewRegistry(logger, k8sClientset, pluginsNamespace, config.Plugins.LabelSelector)
 	accessResolver := accesshandler.NewResolver(logger, k8sClientset)
+	crdResolver := crdversions.NewResolver(logger, k8sClientset.Discovery())
+	traceflowHandler.SetCRDResolver(crdResolver)
+
+	// The scheduler is only created if probes are configured, as resolving their Pod selectors
+	// requires antrea-ui to be allowed to list Pods.
+	var probeScheduler probes.Scheduler
+	var runProbes func(stopCh <-chan struct{})
+	if len(config.Probes.Definitions) > 0 {
+		scheduler := probes.NewScheduler(logger, k8sClientset, buildProbes(config.Probes), config.Probes.HistorySize)
+		probeScheduler, runProbes = scheduler, scheduler.Run
+	}
+
+	// PacketCaptures are only enabled if a file server is configured: Antrea uploads the
+	// captured packets there, and antrea-ui downloads them from it.
+	var packetCaptureHandler packetcapture.RequestsHandler
+	var runPacketCaptureGC func(stopCh <-chan struct{})
+	if config.PacketCapture.FileServer.URL != "" {
+		handler, err := packetcapture.NewRequestsHandler(logger, k8sAdminDynamicClient, packetcapture.GCConfig{
+			InstanceID:    config.InstanceID,
+			ExpiryTimeout: config.PacketCapture.ExpiryTimeout,
+			Period:        config.PacketCapture.GCPeriod,
+		}, fileserver.Config{
+			URL:           config.PacketCapture.FileServer.URL,
+			Username:      config.PacketCapture.FileServer.Username,
+			Password:      config.PacketCapture.FileServer.Password,
+			HostPublicKey: config.PacketCapture.FileServer.HostPublicKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create handler for PacketCapture requests: %w", err)
+		}
+		handler.SetCRDResolver(crdResolver)
+		packetCaptureHandler, runPacketCaptureGC = handler, handler.Run
+	}
+
+	// Support bundles are enabled the same way. Antrea reads the credential of the file server
+	// from its own Secret, which is referenced in every SupportBundleCollection.
+	var supportBundleHandler supportbundle.RequestsHandler
+	var runSupportBundleGC func(stopCh <-chan struct{})
+	if config.SupportBundle.FileServer.URL != "" {
+		authSecretNamespace := config.SupportBundle.AuthSecret.Namespace
+		if authSecretNamespace == "" {
+			authSecretNamespace = config.AntreaNamespace
+		}
+		handler, err := supportbundle.NewRequestsHandler(logger, k8sAdminDynamicClient, supportbundle.GCConfig{
+			InstanceID:    config.InstanceID,
+			ExpiryTimeout: config.SupportBundle.ExpiryTimeout,
+			Period:        config.SupportBundle.GCPeriod,
+		}, fileserver.Config{
+			URL:           config.SupportBundle.FileServer.URL,
+			Username:      config.SupportBundle.FileServer.Username,
+			Password:      config.SupportBundle.FileServer.Password,
+			HostPublicKey: config.SupportBundle.FileServer.HostPublicKey,
+		}, supportbundle.AuthSecret{
+			Name:      config.SupportBundle.AuthSecret.Name,
+			Namespace: authSecretNamespace,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create handler for SupportBundleCollection requests: %w", err)
+		}
+		handler.SetCRDResolver(crdResolver)
+		supportBundleHandler, runSupportBundleGC = handler, handler.Run
+	}
 
 	antreaSvcHandler, err := antreasvchandler.NewRequestsHandler(logger, k8sRESTConfig, config.AntreaNamespace)
 	if err != nil {
 		return fmt.Errorf("failed to create handler for Antrea Service requests: %w", err)
 	}
 
+	antreaAgentHandler, err := antreaagenthandler.NewRequestsHandler(logger, k8sRESTConfig, clientFactory, config.AntreaNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to create handler for antrea-agent requests: %w", err)
+	}
+
 	var passwordStore password.Store
 	if config.Auth.Basic.Enabled {
 		store := password.NewStore(passwordrw.NewK8sSecret(env.GetNamespace(), "antrea-ui-passwd", k8sDynamicClient), passwordhasher.NewArgon2id())
@@ -203,15 +284,17 @@ func run() error {
 	}
 
 	var flowStreamSubscriber flowstream.FlowStreamSubscriber
+	var grpcSubscriber *flowstream.GRPCFlowStreamSubscriber
+	var flowMasker *flowstream.Masker
 	if config.FlowAggregator.Enabled {
 		logger.Info("FlowAggregator integration enabled", "address", config.FlowAggregator.Address)
 
+		ns := config.FlowAggregator.Namespace
+		if ns == "" {
+			ns = "flow-aggregator"
+		}
 		var caData []byte
 		if config.FlowAggregator.CAConfigMap != "" {
-			ns := config.FlowAggregator.Namespace
-			if ns == "" {
-				ns = "flow-aggregator"
-			}
 			fetchCtx, fetchCancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer fetchCancel()
 			logger.Info("Fetching FlowAggregator CA cert", "namespace", ns, "configMap", config.FlowAggregator.CAConfigMap)
@@ -230,31 +313,54 @@ func run() error {
 		if err != nil {
 			return fmt.Errorf("failed to build TLS config for FlowAggregator: %w", err)
 		}
-		grpcSubscriber, err := flowstream.NewGRPCFlowStreamSubscriber(logger, flowstream.GRPCConfig{
+		grpcConfig := flowstream.GRPCConfig{
 			Address:   config.FlowAggregator.Address,
 			TLSConfig: tlsCfg,
-		})
+		}
+		if !env.IsRunningInPod() {
+			logger.Info("Server is not running in Pod, port forwarding is required to access the FlowAggregator")
+			grpcConfig.PortForward = &flowstream.PortForwardConfig{
+				RESTConfig: k8sRESTConfig,
+				Namespace:  ns,
+			}
+		}
+		grpcSubscriber, err = flowstream.NewGRPCFlowStreamSubscriber(logger, grpcConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create gRPC flow stream handler: %w", err)
 		}
 		defer grpcSubscriber.Close()
 		flowStreamSubscriber = grpcSubscriber
+
+		if config.FlowAggregator.Masking.Enabled {
+			flowMasker, err = buildFlowMasker(config.FlowAggregator.Masking)
+			if err != nil {
+				return fmt.Errorf("invalid flow masking configuration: %w", err)
+			}
+			logger.Info("Flow data masking enabled", "rules", len(config.FlowAggregator.Masking.Rules))
+		}
 	}
 
 	s, err := server.NewServer(server.Options{
-		Logger:                   logger,
-		Config:                   config,
-		TraceflowRequestsHandler: traceflowHandler,
-		K8sProxyHandler:          k8sProxyHandler,
-		AntreaSvcRequestsHandler: antreaSvcHandler,
-		FlowStreamSubscriber:     flowStreamSubscriber,
-		PasswordStore:            passwordStore,
-		SessionStore:             sessionStore,
-		ClientFactory:            clientFactory,
-		OIDCProvider:             oidcProvider,
-		PluginRegistry:           pluginRegistry,
-		AdminUserName:            antreaUIAdminUser,
-		AccessResolver:           accessResolver,
+		Logger:                       logger,
+		Config:                       config,
+		TraceflowRequestsHandler:     traceflowHandler,
+		K8sProxyHandler:              k8sProxyHandler,
+		AntreaSvcRequestsHandler:     antreaSvcHandler,
+		AntreaAgentRequestsHandler:   antreaAgentHandler,
+		FlowStreamSubscriber:         flowStreamSubscriber,
+		FlowMasker:                   flowMasker,
+		PasswordStore:                passwordStore,
+		SessionStore:                 sessionStore,
+		ClientFactory:                clientFactory,
+		OIDCProvider:                 oidcProvider,
+		PluginRegistry:               pluginRegistry,
+		AdminUserName:                antreaUIAdminUser,
+		AccessResolver:               accessResolver,
+		AdminDynamicClient:           k8sAdminDynamicClient,
+		ProbeScheduler:               probeScheduler,
+		CRDResolver:                  crdResolver,
+		PacketCaptureRequestsHandler: packetCaptureHandler,
+		SupportBundleRequestsHandler: supportBundleHandler,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
@@ -290,6 +396,19 @@ func run() error {
 	go sessionStore.Run(stopCh)
 	go pluginRegistry.Run(stopCh)
 	go accessResolver.Run(stopCh)
+	go crdResolver.Run(stopCh)
+	if grpcSubscriber != nil {
+		go grpcSubscriber.Run(stopCh)
+	}
+	if runProbes != nil {
+		go runProbes(stopCh)
+	}
+	if runPacketCaptureGC != nil {
+		go runPacketCaptureGC(stopCh)
+	}
+	if runSupportBundleGC != nil {
+		go runSupportBundleGC(stopCh)
+	}
 
 	// Initializing the server in a goroutine so that
 	// it won't block the graceful shutdown handling below
@@ -336,6 +455,63 @@ func buildFlowAggregatorTLSConfig(logger logr.Logger, cfg serverconfig.FlowAggre
 	return tlsCfg, nil
 }
 
+func buildFlowMasker(cfg serverconfig.FlowMaskingConfig) (*flowstream.Masker, error) {
+	toPolicy := func(p serverconfig.FlowMaskingPolicy) flowstream.MaskingPolicy {
+		return flowstream.MaskingPolicy{
+			IPs:              flowstream.IPMaskMode(p.IPs),
+			IPv4PrefixLength: p.IPv4PrefixLength,
+			IPv6PrefixLength: p.IPv6PrefixLength,
+			StripPodLabels:   p.StripPodLabels,
+			HideEgressIPs:    p.HideEgressIPs,
+		}
+	}
+	rules := make([]flowstream.MaskingRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rules = append(rules, flowstream.MaskingRule{
+			Groups: r.Groups,
+			Policy: toPolicy(r.Policy),
+		})
+	}
+	return flowstream.NewMasker(rules, toPolicy(cfg.Default))
+}
+
+// buildProbes applies the defaults of cfg to its probe definitions.
+func buildProbes(cfg serverconfig.ProbesConfig) []probes.Probe {
+	endpoints := func(e serverconfig.ProbeEndpointsConfig) apisv1.ReachabilityEndpoints {
+		return apisv1.ReachabilityEndpoints{
+			Namespace:   e.Namespace,
+			Pod:         e.Pod,
+			PodSelector: e.PodSelector,
+			Service:     e.Service,
+			IP:          e.IP,
+		}
+	}
+	result := make([]probes.Probe, 0, len(cfg.Definitions))
+	for _, d := range cfg.Definitions {
+		probe := probes.Probe{
+			Name:            d.Name,
+			Source:          endpoints(d.Source),
+			Destination:     endpoints(d.Destination),
+			Protocol:        d.Protocol,
+			DestinationPort: d.DestinationPort,
+			Expect:          d.Expect,
+			Interval:        d.Interval,
+			SLOTarget:       d.SLOTarget,
+		}
+		if probe.Expect == "" {
+			probe.Expect = apisv1.ReachabilityAllowed
+		}
+		if probe.Interval == 0 {
+			probe.Interval = cfg.Interval
+		}
+		if probe.SLOTarget == 0 {
+			probe.SLOTarget = cfg.SLOTarget
+		}
+		result = append(result, probe)
+	}
+	return result
+}
+
 func main() {
 	var err error
 	config, err = serverconfig.LoadConfig()
@@ -16,23 +16,81 @@ package server
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
 	DefaultMaxLoginsPerSecond   = 1
 	DefaultMaxTraceflowsPerHour = 100
 
+	DefaultMaxTraceflowsPerUserPerHour    = 30
+	DefaultMaxConcurrentTraceflowsPerUser = 5
+
 	DefaultSessionIdleTimeout = 30 * time.Minute
 	DefaultSessionMaxLifetime = 12 * time.Hour
 	DefaultMaxSessions        = 1000
 	DefaultMaxSessionsPerUser = 10
+
+	DefaultInstanceID = "antrea-ui"
+
+	DefaultTraceflowExpiryTimeout = 60 * time.Minute
+	DefaultTraceflowGCPeriod      = 1 * time.Minute
+
+	DefaultTraceflowHuntMaxDuration = 4 * time.Hour
+	DefaultTraceflowHuntMaxCaptures = 20
+
+	DefaultMaxFlowStreams        = 100
+	DefaultMaxFlowStreamsPerUser = 5
+
+	DefaultMaxPacketCapturesPerHour   = 20
+	DefaultPacketCaptureExpiryTimeout = 24 * time.Hour
+	DefaultPacketCaptureGCPeriod      = 5 * time.Minute
+
+	DefaultMaxSupportBundlesPerHour   = 5
+	DefaultSupportBundleExpiryTimeout = 24 * time.Hour
+	DefaultSupportBundleGCPeriod      = 5 * time.Minute
+
+	DefaultProbeInterval    = 5 * time.Minute
+	DefaultProbeHistorySize = 288
+	DefaultProbeSLOTarget   = 0.99
 )
 
+// DefaultAntreaControllerAllowedPaths are the read-only antrea-controller API paths forwarded by
+// default: endpoint queries, the NetworkPolicies computed by the controller and their groups,
+// NetworkPolicy statistics, feature gates and version information.
+var DefaultAntreaControllerAllowedPaths = []string{
+	"/endpoint",
+	"/featuregates",
+	"/version",
+	"/apis/controlplane.antrea.io/v1beta2/networkpolicies",
+	"/apis/controlplane.antrea.io/v1beta2/networkpolicies/*",
+	"/apis/controlplane.antrea.io/v1beta2/addressgroups",
+	"/apis/controlplane.antrea.io/v1beta2/addressgroups/*",
+	"/apis/controlplane.antrea.io/v1beta2/appliedtogroups",
+	"/apis/controlplane.antrea.io/v1beta2/appliedtogroups/*",
+	"/apis/stats.antrea.io/v1alpha1/*",
+	"/apis/stats.antrea.io/v1alpha1/*/*",
+	"/apis/stats.antrea.io/v1alpha1/namespaces/*/*",
+	"/apis/stats.antrea.io/v1alpha1/namespaces/*/*/*",
+}
+
+// DefaultAntreaAgentAllowedPaths are the read-only antrea-agent API paths forwarded by default:
+// the information about the agent, and the OVS flows and interfaces of its Pods.
+var DefaultAntreaAgentAllowedPaths = []string{
+	"/agentinfo",
+	"/ovsflows",
+	"/podinterface",
+}
+
 type FlowAggregatorConfig struct {
 	Enabled bool
 	Address string
@@ -43,28 +101,243 @@ type FlowAggregatorConfig struct {
 	// Namespace is the Kubernetes namespace where the Flow Aggregator is installed.
 	Namespace string
 	// ServerName overrides the TLS server name used for certificate verification.
-	// Useful when dialing via kubectl port-forward, where the address is loopback
-	// but the server cert is issued for the in-cluster Service DNS name.
-	// If empty, the hostname from Address is used.
+	// If empty, the hostname from Address is used. When the backend runs out-of-cluster,
+	// it reaches the FlowAggregator through a port-forward it sets up itself, and Address
+	// is still used for certificate verification, so it should be the in-cluster Service
+	// DNS name rather than a loopback address.
 	ServerName string
 	// InsecureSkipVerify disables TLS server certificate verification.
 	// This should only be used for development/testing and must never be enabled in production.
 	InsecureSkipVerify bool
+	// Masking redacts flow records for callers who may see traffic patterns but not the raw
+	// data.
+	Masking FlowMaskingConfig
+	// Streams bounds how many flow streams are open at once.
+	Streams FlowStreamsConfig
+}
+
+// FlowStreamsConfig bounds the number of concurrent /api/v1/flows/stream requests. Each one holds
+// a gRPC stream to the Flow Aggregator for as long as the browser tab stays open.
+type FlowStreamsConfig struct {
+	// MaxStreams bounds the number of streams the backend serves at once.
+	MaxStreams int
+	// MaxStreamsPerUser bounds how many of those one identity may hold, so that a single user
+	// cannot take every slot.
+	MaxStreamsPerUser int
+	// EvictOldest closes the user's oldest stream when they open one past MaxStreamsPerUser,
+	// instead of refusing the new one.
+	EvictOldest bool
+}
+
+// FlowMaskingConfig selects, from the caller's Kubernetes groups, how flow records are redacted
+// before they are streamed to that caller.
+type FlowMaskingConfig struct {
+	Enabled bool
+	// Rules are evaluated in order: the first one naming any of the caller's groups applies.
+	Rules []FlowMaskingRule
+	// Default applies to callers that no rule matches.
+	Default FlowMaskingPolicy
+}
+
+type FlowMaskingRule struct {
+	Groups []string
+	Policy FlowMaskingPolicy
+}
+
+type FlowMaskingPolicy struct {
+	// IPs is one of "none" (the default), "hash" or "truncate".
+	IPs string
+	// IPv4PrefixLength and IPv6PrefixLength are the prefix lengths kept when IPs is
+	// "truncate". Zero means /24 and /64 respectively.
+	IPv4PrefixLength int
+	IPv6PrefixLength int
+	// StripPodLabels drops the source and destination Pod labels.
+	StripPodLabels bool
+	// HideEgressIPs drops the Egress IP.
+	HideEgressIPs bool
+}
+
+// TraceflowConfig controls the Traceflow CRs created by antrea-ui, which are deleted once they
+// expire unless a user pinned them.
+type TraceflowConfig struct {
+	// InstanceID is the former name of Config.InstanceID, which it defaults to.
+	//
+	// Deprecated: set Config.InstanceID instead.
+	InstanceID string
+	// ExpiryTimeout is the age past which a Traceflow is deleted.
+	ExpiryTimeout time.Duration
+	// GCPeriod is how often expired Traceflows are looked for.
+	GCPeriod time.Duration
+	// Delegation lets a user who cannot create Traceflows trace between two Pods they can get:
+	// the Traceflow is then created as antrea-ui-admin on their behalf.
+	Delegation struct {
+		Enabled bool
+	}
+	Hunt TraceflowHuntConfig
+}
+
+// PacketCaptureConfig configures the PacketCaptures of /api/v1/packetcapture. They are disabled
+// unless FileServer.URL is set, as Antrea uploads the captured packets there, and antrea-ui
+// downloads them from there. They are garbage-collected like Traceflows, and belong to the install
+// identified by instanceID.
+type PacketCaptureConfig struct {
+	// ExpiryTimeout is the age past which a PacketCapture is deleted. Its file is left on the
+	// file server.
+	ExpiryTimeout time.Duration
+	// GCPeriod is how often expired PacketCaptures are looked for.
+	GCPeriod time.Duration
+	// FileServer is where Antrea uploads pcap files. Antrea reads its credential from the
+	// antrea-packetcapture-fileserver-auth Secret; antrea-ui needs the same one to download the
+	// files.
+	FileServer FileServerConfig
+}
+
+// SupportBundleConfig configures the SupportBundleCollections of /api/v1/supportbundles. Like
+// PacketCaptures, they are disabled unless FileServer.URL is set, and are garbage-collected like
+// Traceflows.
+type SupportBundleConfig struct {
+	// ExpiryTimeout is the age past which a SupportBundleCollection is deleted. Its bundles are
+	// left on the file server.
+	ExpiryTimeout time.Duration
+	// GCPeriod is how often expired SupportBundleCollections are looked for.
+	GCPeriod time.Duration
+	// FileServer is where Antrea uploads bundles. antrea-ui needs the same credential as Antrea
+	// to download them.
+	FileServer FileServerConfig
+	// AuthSecret is the Secret from which Antrea reads the credential of the file server, set in
+	// the spec of every SupportBundleCollection.
+	AuthSecret struct {
+		Name string
+		// Namespace defaults to AntreaNamespace.
+		Namespace string
+	}
+}
+
+// FileServerConfig is an SFTP server that Antrea uploads files to.
+type FileServerConfig struct {
+	// URL is set in the spec of every object that uploads files, e.g.
+	// "sftp://10.0.0.1:22/upload". antrea-ui only downloads files under it.
+	URL      string
+	Username string
+	Password string
+	// HostPublicKey is the public key of the server, in the authorized_keys format. When it is
+	// empty, the key of the server is not checked.
+	HostPublicKey string
+}
+
+// AntreaAPIConfig lists the read-only Antrea API paths that users can reach through antrea-ui, as
+// themselves.
+type AntreaAPIConfig struct {
+	// Controller is the allowlist of GET /api/v1/antrea/controller/*path.
+	Controller AntreaAPIAllowlistConfig
+	// Agent is the allowlist of GET /api/v1/nodes/:node/agent/*path.
+	Agent AntreaAPIAllowlistConfig
+}
+
+type AntreaAPIAllowlistConfig struct {
+	// AllowedPaths are path.Match patterns, e.g. "/apis/controlplane.antrea.io/v1beta2/*". A
+	// request is forwarded if its path matches one of them.
+	AllowedPaths []string
+}
+
+// TraceflowHuntConfig bounds the drop hunts of POST /api/v1/traceflow/hunt, which keep re-arming a
+// dropped-only live-traffic Traceflow.
+type TraceflowHuntConfig struct {
+	// MaxDuration is the longest a hunt may stay armed.
+	MaxDuration time.Duration
+	// MaxCaptures is the largest number of drops a hunt may capture.
+	MaxCaptures int
+}
+
+// TraceflowQuotaConfig bounds the Traceflows one identity may create, so that no user can use up
+// limits.maxTraceflowsPerHour, which is shared by everyone.
+type TraceflowQuotaConfig struct {
+	// MaxPerHour is the number of Traceflows a user may create per hour. A negative value
+	// disables the per-user rate limit.
+	MaxPerHour int
+	// MaxConcurrent is the number of the user's Traceflows that may be running at once. 0 means
+	// no limit.
+	MaxConcurrent int
+	// Overrides are evaluated in order: the first one naming any of the caller's groups applies
+	// instead of MaxPerHour and MaxConcurrent.
+	Overrides []TraceflowQuotaOverride
+}
+
+type TraceflowQuotaOverride struct {
+	Groups        []string
+	MaxPerHour    int
+	MaxConcurrent int
 }
 
 type Config struct {
-	Addr           string
-	URL            string
+	Addr string
+	URL  string
+	// InstanceID is recorded on the Traceflows, PacketCaptures and SupportBundleCollections
+	// created by this install, as the value of the ui.antrea.io/instance label. Every install
+	// sharing a cluster needs its own, so that each one only deletes its own objects.
+	InstanceID     string
 	Auth           AuthConfig
 	Session        SessionConfig
 	FlowAggregator FlowAggregatorConfig
+	Traceflow      TraceflowConfig
+	PacketCapture  PacketCaptureConfig
+	SupportBundle  SupportBundleConfig
+	AntreaAPI      AntreaAPIConfig
 	Limits         struct {
 		MaxLoginsPerSecond   int
 		MaxTraceflowsPerHour int
+		// TraceflowQuota applies per user, on top of MaxTraceflowsPerHour.
+		TraceflowQuota TraceflowQuotaConfig
+		// MaxPacketCapturesPerHour applies to all users. A negative value disables the
+		// rate limit.
+		MaxPacketCapturesPerHour int
+		// MaxSupportBundlesPerHour applies to all users. A negative value disables the
+		// rate limit.
+		MaxSupportBundlesPerHour int
 	}
 	LogVerbosity    int
 	AntreaNamespace string
 	Plugins         PluginsConfig
+	Probes          ProbesConfig
+}
+
+// ProbesConfig configures the connectivity probes: Traceflows that the backend runs periodically
+// as antrea-ui-admin, to check that critical paths keep behaving as expected.
+type ProbesConfig struct {
+	// Interval is the time between two runs of a probe that does not set its own.
+	Interval time.Duration
+	// HistorySize is the number of runs kept for each probe. The success rate is computed over
+	// them.
+	HistorySize int
+	// SLOTarget is the success rate expected of a probe that does not set its own, between 0
+	// and 1.
+	SLOTarget float64
+	// Definitions are the probes to run.
+	Definitions []ProbeConfig
+}
+
+type ProbeConfig struct {
+	// Name identifies the probe in the API and in metrics.
+	Name string
+	// Source must select a Pod. When it is a Pod selector, the first running Pod it matches
+	// (by name) is used.
+	Source ProbeEndpointsConfig
+	// Destination is a Pod, a Pod selector, a Service or an IP address.
+	Destination     ProbeEndpointsConfig
+	Protocol        string
+	DestinationPort int32
+	// Expect is the expected result: "Allowed" (the default) or "Denied".
+	Expect    string
+	Interval  time.Duration
+	SLOTarget float64
+}
+
+type ProbeEndpointsConfig struct {
+	Namespace   string
+	Pod         string
+	PodSelector string
+	Service     string
+	IP          string
 }
 
 type PluginsConfig struct {
@@ -180,10 +453,188 @@ func validateConfig(config *Config) error {
 	if config.Session.MaxSessionsPerUser > config.Session.MaxSessions {
 		return fmt.Errorf("session.maxSessionsPerUser must be <= session.maxSessions")
 	}
+	if config.FlowAggregator.Streams.MaxStreams <= 0 {
+		return fmt.Errorf("flowAggregator.streams.maxStreams must be positive")
+	}
+	if config.FlowAggregator.Streams.MaxStreamsPerUser <= 0 {
+		return fmt.Errorf("flowAggregator.streams.maxStreamsPerUser must be positive")
+	}
+	if config.FlowAggregator.Streams.MaxStreamsPerUser > config.FlowAggregator.Streams.MaxStreams {
+		return fmt.Errorf("flowAggregator.streams.maxStreamsPerUser must be <= flowAggregator.streams.maxStreams")
+	}
+	if config.InstanceID == "" {
+		return fmt.Errorf("instanceID must not be empty")
+	}
+	if errs := validation.IsValidLabelValue(config.InstanceID); len(errs) > 0 {
+		return fmt.Errorf("instanceID must be a valid label value: %s", strings.Join(errs, "; "))
+	}
+	if config.Traceflow.InstanceID != "" && config.Traceflow.InstanceID != config.InstanceID {
+		return fmt.Errorf("traceflow.instanceID, deprecated, must be the same as instanceID when both are set")
+	}
+	if config.Traceflow.ExpiryTimeout <= 0 {
+		return fmt.Errorf("traceflow.expiryTimeout must be positive")
+	}
+	if config.Traceflow.GCPeriod <= 0 {
+		return fmt.Errorf("traceflow.gcPeriod must be positive")
+	}
+	if config.Traceflow.Hunt.MaxDuration <= 0 {
+		return fmt.Errorf("traceflow.hunt.maxDuration must be positive")
+	}
+	if config.Traceflow.Hunt.MaxCaptures <= 0 {
+		return fmt.Errorf("traceflow.hunt.maxCaptures must be positive")
+	}
+	if config.Limits.TraceflowQuota.MaxConcurrent < 0 {
+		return fmt.Errorf("limits.traceflowQuota.maxConcurrent must be >= 0")
+	}
+	for idx, o := range config.Limits.TraceflowQuota.Overrides {
+		if len(o.Groups) == 0 {
+			return fmt.Errorf("limits.traceflowQuota.overrides[%d].groups must not be empty", idx)
+		}
+		if o.MaxConcurrent < 0 {
+			return fmt.Errorf("limits.traceflowQuota.overrides[%d].maxConcurrent must be >= 0", idx)
+		}
+	}
+	if err := validatePacketCaptureConfig(&config.PacketCapture); err != nil {
+		return err
+	}
+	if err := validateSupportBundleConfig(&config.SupportBundle); err != nil {
+		return err
+	}
+	if err := validateAllowedPaths("antreaAPI.controller.allowedPaths", config.AntreaAPI.Controller.AllowedPaths); err != nil {
+		return err
+	}
+	if err := validateAllowedPaths("antreaAPI.agent.allowedPaths", config.AntreaAPI.Agent.AllowedPaths); err != nil {
+		return err
+	}
+	if err := validateProbesConfig(&config.Probes); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+func validatePacketCaptureConfig(config *PacketCaptureConfig) error {
+	if config.ExpiryTimeout <= 0 {
+		return fmt.Errorf("packetCapture.expiryTimeout must be positive")
+	}
+	if config.GCPeriod <= 0 {
+		return fmt.Errorf("packetCapture.gcPeriod must be positive")
+	}
+	return validateFileServerConfig("packetCapture.fileServer", &config.FileServer)
+}
+
+func validateSupportBundleConfig(config *SupportBundleConfig) error {
+	if config.ExpiryTimeout <= 0 {
+		return fmt.Errorf("supportBundle.expiryTimeout must be positive")
+	}
+	if config.GCPeriod <= 0 {
+		return fmt.Errorf("supportBundle.gcPeriod must be positive")
+	}
+	if err := validateFileServerConfig("supportBundle.fileServer", &config.FileServer); err != nil {
+		return err
+	}
+	if config.FileServer.URL != "" && config.AuthSecret.Name == "" {
+		return fmt.Errorf("supportBundle.authSecret.name is required when supportBundle.fileServer.url is set")
+	}
+	return nil
+}
+
+// validateFileServerConfig validates the file server at key, which is disabled when its URL is
+// empty.
+func validateFileServerConfig(key string, config *FileServerConfig) error {
+	if config.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("%s.url is invalid: %w", key, err)
+	}
+	if u.Scheme != "sftp" || u.Host == "" {
+		return fmt.Errorf("%s.url must be an sftp:// URL", key)
+	}
+	if config.Username == "" {
+		return fmt.Errorf("%s.username is required when %s.url is set", key, key)
+	}
+	if config.HostPublicKey != "" {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostPublicKey)); err != nil {
+			return fmt.Errorf("%s.hostPublicKey is invalid: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func validateAllowedPaths(key string, allowedPaths []string) error {
+	for idx, pattern := range allowedPaths {
+		if !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("%s[%d] must be an absolute path", key, idx)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s[%d] is not a valid pattern: %w", key, idx, err)
+		}
+	}
+	return nil
+}
+
+func validateProbesConfig(config *ProbesConfig) error {
+	if config.Interval <= 0 {
+		return fmt.Errorf("probes.interval must be positive")
+	}
+	if config.HistorySize <= 0 {
+		return fmt.Errorf("probes.historySize must be positive")
+	}
+	if config.SLOTarget <= 0 || config.SLOTarget > 1 {
+		return fmt.Errorf("probes.sloTarget must be in (0, 1]")
+	}
+	names := make(map[string]bool)
+	for idx, p := range config.Definitions {
+		field := func(name string) string {
+			return fmt.Sprintf("probes.definitions[%d].%s", idx, name)
+		}
+		// The name is a metric label value and a URL path segment.
+		if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+			return fmt.Errorf("%s must be a valid DNS label: %s", field("name"), strings.Join(errs, "; "))
+		}
+		if names[p.Name] {
+			return fmt.Errorf("%s must be unique", field("name"))
+		}
+		names[p.Name] = true
+		src, dst := &p.Source, &p.Destination
+		if src.Namespace == "" || (src.Pod == "") == (src.PodSelector == "") || src.Service != "" || src.IP != "" {
+			return fmt.Errorf("%s must set namespace and exactly one of pod and podSelector", field("source"))
+		}
+		set := 0
+		for _, v := range []string{dst.Pod, dst.PodSelector, dst.Service, dst.IP} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("%s must set exactly one of pod, podSelector, service and ip", field("destination"))
+		}
+		if dst.IP == "" && dst.Namespace == "" {
+			return fmt.Errorf("%s must set namespace", field("destination"))
+		}
+		switch strings.ToUpper(p.Protocol) {
+		case "", "TCP", "UDP", "ICMP":
+		default:
+			return fmt.Errorf("%s must be one of TCP, UDP and ICMP", field("protocol"))
+		}
+		if p.DestinationPort < 0 || p.DestinationPort > 65535 {
+			return fmt.Errorf("%s must be in [0, 65535]", field("destinationPort"))
+		}
+		if p.Expect != "" && p.Expect != "Allowed" && p.Expect != "Denied" {
+			return fmt.Errorf("%s must be Allowed or Denied", field("expect"))
+		}
+		if p.Interval < 0 {
+			return fmt.Errorf("%s must be >= 0", field("interval"))
+		}
+		if p.SLOTarget < 0 || p.SLOTarget > 1 {
+			return fmt.Errorf("%s must be in [0, 1]", field("sloTarget"))
+		}
+	}
+	return nil
+}
+
 func LoadConfig() (*Config, error) {
 	v := viper.New()
 
@@ -213,10 +664,29 @@ func LoadConfig() (*Config, error) {
 	// Configuration variables that can be set through environment
 	v.MustBindEnv("auth.oidc.clientId", "ANTREA_UI_AUTH_OIDC_CLIENT_ID")
 	v.MustBindEnv("auth.oidc.clientSecret", "ANTREA_UI_AUTH_OIDC_CLIENT_SECRET")
+	v.MustBindEnv("packetCapture.fileServer.username", "ANTREA_UI_PACKETCAPTURE_FILESERVER_USERNAME")
+	v.MustBindEnv("packetCapture.fileServer.password", "ANTREA_UI_PACKETCAPTURE_FILESERVER_PASSWORD")
+	v.MustBindEnv("supportBundle.fileServer.username", "ANTREA_UI_SUPPORTBUNDLE_FILESERVER_USERNAME")
+	v.MustBindEnv("supportBundle.fileServer.password", "ANTREA_UI_SUPPORTBUNDLE_FILESERVER_PASSWORD")
 
 	// You can set defaults for configuration parameters here
+	v.SetDefault("traceflow.expiryTimeout", DefaultTraceflowExpiryTimeout)
+	v.SetDefault("traceflow.gcPeriod", DefaultTraceflowGCPeriod)
+	v.SetDefault("traceflow.delegation.enabled", false)
+	v.SetDefault("traceflow.hunt.maxDuration", DefaultTraceflowHuntMaxDuration)
+	v.SetDefault("traceflow.hunt.maxCaptures", DefaultTraceflowHuntMaxCaptures)
 	v.SetDefault("limits.maxLoginsPerSecond", DefaultMaxLoginsPerSecond)
 	v.SetDefault("limits.maxTraceflowsPerHour", DefaultMaxTraceflowsPerHour)
+	v.SetDefault("limits.traceflowQuota.maxPerHour", DefaultMaxTraceflowsPerUserPerHour)
+	v.SetDefault("limits.traceflowQuota.maxConcurrent", DefaultMaxConcurrentTraceflowsPerUser)
+	v.SetDefault("limits.maxPacketCapturesPerHour", DefaultMaxPacketCapturesPerHour)
+	v.SetDefault("packetCapture.expiryTimeout", DefaultPacketCaptureExpiryTimeout)
+	v.SetDefault("packetCapture.gcPeriod", DefaultPacketCaptureGCPeriod)
+	v.SetDefault("packetCapture.fileServer.url", "")
+	v.SetDefault("limits.maxSupportBundlesPerHour", DefaultMaxSupportBundlesPerHour)
+	v.SetDefault("supportBundle.expiryTimeout", DefaultSupportBundleExpiryTimeout)
+	v.SetDefault("supportBundle.gcPeriod", DefaultSupportBundleGCPeriod)
+	v.SetDefault("supportBundle.fileServer.url", "")
 	v.SetDefault("auth.cookieSecure", true)
 	v.SetDefault("auth.basic.enabled", true)
 	v.SetDefault("auth.oidc.enabled", false)
@@ -232,6 +702,8 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("session.maxSessions", DefaultMaxSessions)
 	v.SetDefault("session.maxSessionsPerUser", DefaultMaxSessionsPerUser)
 	v.SetDefault("antreaNamespace", "kube-system")
+	v.SetDefault("antreaAPI.controller.allowedPaths", DefaultAntreaControllerAllowedPaths)
+	v.SetDefault("antreaAPI.agent.allowedPaths", DefaultAntreaAgentAllowedPaths)
 	v.SetDefault("plugins.labelSelector", "ui.antrea.io/plugin=true")
 	v.SetDefault("flowAggregator.enabled", false)
 	v.SetDefault("flowAggregator.address", "flow-aggregator.flow-aggregator.svc:14740")
@@ -239,6 +711,13 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("flowAggregator.namespace", "flow-aggregator")
 	v.SetDefault("flowAggregator.serverName", "")
 	v.SetDefault("flowAggregator.insecureSkipVerify", false)
+	v.SetDefault("flowAggregator.masking.enabled", false)
+	v.SetDefault("flowAggregator.streams.maxStreams", DefaultMaxFlowStreams)
+	v.SetDefault("flowAggregator.streams.maxStreamsPerUser", DefaultMaxFlowStreamsPerUser)
+	v.SetDefault("flowAggregator.streams.evictOldest", false)
+	v.SetDefault("probes.interval", DefaultProbeInterval)
+	v.SetDefault("probes.historySize", DefaultProbeHistorySize)
+	v.SetDefault("probes.sloTarget", DefaultProbeSLOTarget)
 
 	// By default, look for a file named config (any supported extension) in the working directory.
 	v.AddConfigPath(".")
@@ -259,6 +738,13 @@ func LoadConfig() (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error when unmarshalling config: %w", err)
 	}
+	// traceflow.instanceID is the deprecated name of instanceID.
+	if config.InstanceID == "" {
+		config.InstanceID = config.Traceflow.InstanceID
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = DefaultInstanceID
+	}
 
 	if err := validateConfig(&config); err != nil {
 		return nil, err
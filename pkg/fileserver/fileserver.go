@@ -0,0 +1,136 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fileserver downloads the files that Antrea uploads to an SFTP server, such as the pcapng
+// files of PacketCaptures and the bundles of SupportBundleCollections.
+package fileserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ssh"
+)
+
+// timeout bounds connecting to the file server and opening or removing a file. Reading a file is
+// only bounded by the caller's context.
+const timeout = 30 * time.Second
+
+// Config is an SFTP server that Antrea uploads files to.
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	// HostPublicKey is in the authorized_keys format. When it is empty, the key of the server
+	// is not checked.
+	HostPublicKey string
+}
+
+// Server opens the files under the URL of a file server, with antrea-ui's own credential.
+type Server struct {
+	url       *url.URL
+	sshConfig *ssh.ClientConfig
+}
+
+func New(logger logr.Logger, config Config) (*Server, error) {
+	serverURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file server URL: %w", err)
+	}
+	if serverURL.Scheme != "sftp" || serverURL.Host == "" {
+		return nil, fmt.Errorf("file server URL must be an sftp:// URL")
+	}
+	// #nosec G106: the host key is optional, as it is for Antrea.
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if config.HostPublicKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostPublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid file server host public key: %w", err)
+		}
+		hostKeyCallback = ssh.FixedHostKey(key)
+	} else {
+		logger.Info("No host public key for the file server, its identity will not be checked", "url", config.URL)
+	}
+	return &Server{
+		url: serverURL,
+		sshConfig: &ssh.ClientConfig{
+			User:            config.Username,
+			Auth:            []ssh.AuthMethod{ssh.Password(config.Password)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         timeout,
+		},
+	}, nil
+}
+
+// URL returns the URL of the file server, as Antrea should be given it.
+func (s *Server) URL() string {
+	return s.url.String()
+}
+
+// Resolve resolves filePath, a URL or a bare path as reported by Antrea, against the file server
+// URL. The file must be on the file server, under its path: an object whose status points
+// elsewhere would otherwise get antrea-ui to send its credential to another server.
+func (s *Server) Resolve(filePath string) (*url.URL, error) {
+	ref, err := url.Parse(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file path %q: %w", filePath, err)
+	}
+	fileURL := s.url.ResolveReference(ref)
+	if fileURL.Scheme != s.url.Scheme || fileURL.Host != s.url.Host {
+		return nil, fmt.Errorf("file %q is not on the file server", filePath)
+	}
+	fileURL.Path = path.Clean(fileURL.Path)
+	dir := strings.TrimSuffix(s.url.Path, "/") + "/"
+	if !strings.HasPrefix(fileURL.Path, dir) {
+		return nil, fmt.Errorf("file %q is not under the file server path", filePath)
+	}
+	return fileURL, nil
+}
+
+// File returns the URL of the file called name, directly under the file server path, for files
+// whose name Antrea does not report but which follows a convention.
+func (s *Server) File(name string) (*url.URL, error) {
+	if name == "" || strings.Contains(name, "/") || name == ".." {
+		return nil, fmt.Errorf("invalid file name %q", name)
+	}
+	return s.Resolve(s.url.JoinPath(name).String())
+}
+
+// Open opens the file at fileURL, as returned by Resolve, for reading. A missing file is reported
+// as fs.ErrNotExist.
+func (s *Server) Open(ctx context.Context, fileURL *url.URL) (io.ReadCloser, error) {
+	f, err := sftpOpen(ctx, fileURL, s.sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error when downloading %s from file server: %w", fileURL.Path, err)
+	}
+	return f, nil
+}
+
+// Remove removes the file at fileURL, as returned by Resolve. A missing file is not an error, so
+// that removing a file is idempotent.
+func (s *Server) Remove(ctx context.Context, fileURL *url.URL) error {
+	err := sftpRemove(ctx, fileURL, s.sshConfig)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error when removing %s from file server: %w", fileURL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileserver
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	fileservertesting "antrea.io/antrea-ui/pkg/fileserver/testing"
+)
+
+func TestResolve(t *testing.T) {
+	s, err := New(testr.New(t), Config{URL: "sftp://10.0.0.1:22/upload"})
+	require.NoError(t, err)
+	for filePath, expected := range map[string]string{
+		"sftp://10.0.0.1:22/upload/pc.pcapng":    "sftp://10.0.0.1:22/upload/pc.pcapng",
+		"/upload/pc.pcapng":                      "sftp://10.0.0.1:22/upload/pc.pcapng",
+		"sftp://10.0.0.2:22/upload/pc.pcapng":    "",
+		"sftp://10.0.0.1:22/uploads/pc.pcapng":   "",
+		"sftp://10.0.0.1:22/upload/../pc.pcapng": "",
+		"/etc/passwd":                            "",
+	} {
+		fileURL, err := s.Resolve(filePath)
+		if expected == "" {
+			assert.Error(t, err, filePath)
+			continue
+		}
+		require.NoError(t, err, filePath)
+		assert.Equal(t, expected, fileURL.String())
+	}
+}
+
+func TestFile(t *testing.T) {
+	s, err := New(testr.New(t), Config{URL: "sftp://10.0.0.1:22/upload"})
+	require.NoError(t, err)
+	fileURL, err := s.File("node-1_sbc.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "sftp://10.0.0.1:22/upload/node-1_sbc.tar.gz", fileURL.String())
+	for _, name := range []string{"", "..", "../node-1_sbc.tar.gz"} {
+		_, err := s.File(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, config := range []Config{
+		{URL: "https://10.0.0.1/upload"},
+		{URL: "sftp:///upload"},
+		{URL: "sftp://10.0.0.1/upload", HostPublicKey: "not a key"},
+	} {
+		_, err := New(testr.New(t), config)
+		assert.Error(t, err, config.URL)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	// Larger than a read, so that the file takes several requests.
+	content := bytes.Repeat([]byte("pcapng"), 20000)
+	server := fileservertesting.StartSFTPServer(t, map[string][]byte{
+		"/upload/pc.pcapng":  content,
+		"/upload/old.pcapng": content,
+	})
+	config := Config{
+		URL:           server.URL,
+		Username:      fileservertesting.SFTPUser,
+		Password:      fileservertesting.SFTPPassword,
+		HostPublicKey: string(ssh.MarshalAuthorizedKey(server.HostKey)),
+	}
+	s, err := New(testr.New(t), config)
+	require.NoError(t, err)
+
+	t.Run("read", func(t *testing.T) {
+		fileURL, err := s.Resolve("/upload/pc.pcapng")
+		require.NoError(t, err)
+		f, err := s.Open(t.Context(), fileURL)
+		require.NoError(t, err)
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+		assert.NoError(t, f.Close())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fileURL, err := s.Resolve("/upload/other.pcapng")
+		require.NoError(t, err)
+		_, err = s.Open(t.Context(), fileURL)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		wrongConfig := config
+		wrongConfig.Password = "wrong"
+		s, err := New(testr.New(t), wrongConfig)
+		require.NoError(t, err)
+		fileURL, err := s.Resolve("/upload/pc.pcapng")
+		require.NoError(t, err)
+		_, err = s.Open(t.Context(), fileURL)
+		assert.ErrorContains(t, err, "unable to authenticate")
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		fileURL, err := s.Resolve("/upload/old.pcapng")
+		require.NoError(t, err)
+		require.NoError(t, s.Remove(t.Context(), fileURL))
+		assert.False(t, server.HasFile("/upload/old.pcapng"))
+		assert.True(t, server.HasFile("/upload/pc.pcapng"))
+		// Removing a missing file succeeds.
+		assert.NoError(t, s.Remove(t.Context(), fileURL))
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpClient is an SFTP session over its own SSH connection.
+type sftpClient struct {
+	*sftp.Client
+	sshClient *ssh.Client
+	stop      func() bool
+}
+
+// sftpDial connects to the server of fileURL with config. Connecting, and the requests that follow,
+// must take less than config.Timeout, until the deadline of the returned connection is cleared.
+// The connection is closed with the client, or when ctx is done.
+func sftpDial(ctx context.Context, fileURL *url.URL, config *ssh.ClientConfig) (*sftpClient, net.Conn, error) {
+	addr := fileURL.Host
+	if fileURL.Port() == "" {
+		addr = net.JoinHostPort(fileURL.Hostname(), "22")
+	}
+	dialer := net.Dialer{Timeout: config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(config.Timeout))
+	}
+	// SSH and SFTP requests are not cancellable, so they are bounded by ctx through the
+	// connection.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		stop()
+		conn.Close()
+		return nil, nil, err
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		stop()
+		sshClient.Close()
+		return nil, nil, fmt.Errorf("error when starting sftp subsystem: %w", err)
+	}
+	return &sftpClient{Client: client, sshClient: sshClient, stop: stop}, conn, nil
+}
+
+func (c *sftpClient) Close() error {
+	err := c.Client.Close()
+	c.stop()
+	if closeErr := c.sshClient.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// sftpFile is a file opened for reading, which owns its SFTP session.
+type sftpFile struct {
+	*sftp.File
+	client *sftpClient
+}
+
+func (f *sftpFile) Close() error {
+	err := f.File.Close()
+	if closeErr := f.client.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// sftpOpen opens the file at fileURL for reading, over a new SSH connection made with config.
+// Opening the file must take less than config.Timeout. The connection is closed with the file, or
+// when ctx is done.
+func sftpOpen(ctx context.Context, fileURL *url.URL, config *ssh.ClientConfig) (*sftpFile, error) {
+	client, conn, err := sftpDial(ctx, fileURL, config)
+	if err != nil {
+		return nil, err
+	}
+	f, err := client.Open(fileURL.Path)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return &sftpFile{File: f, client: client}, nil
+}
+
+// sftpRemove removes the file at fileURL, over a new SSH connection made with config. It must
+// take less than config.Timeout.
+func sftpRemove(ctx context.Context, fileURL *url.URL, config *ssh.ClientConfig) error {
+	client, _, err := sftpDial(ctx, fileURL, config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Remove(fileURL.Path)
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testing provides a local stand-in for the SFTP server that Antrea uploads files to.
+package testing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	SFTPUser     = "antrea"
+	SFTPPassword = "secret" // #nosec G101: not a real credential
+)
+
+// SFTPServer serves files from memory over SSH, to SFTPUser with SFTPPassword. Files can be read
+// and removed.
+type SFTPServer struct {
+	// URL is the sftp:// URL of the "/upload" directory of the server.
+	URL     string
+	HostKey ssh.PublicKey
+
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+// StartSFTPServer starts an SFTPServer serving files, keyed by their absolute path, e.g.
+// "/upload/pc.pcapng". It is stopped when the test ends.
+func StartSFTPServer(t testing.TB, files map[string][]byte) *SFTPServer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == SFTPUser && string(password) == SFTPPassword {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credential")
+		},
+	}
+	config.AddHostKey(signer)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	s := &SFTPServer{
+		URL:     "sftp://" + listener.Addr().String() + "/upload",
+		HostKey: signer.PublicKey(),
+		files:   make(map[string][]byte),
+	}
+	for path, content := range files {
+		s.files[path] = content
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveSSHConn(conn, config)
+		}
+	}()
+	return s
+}
+
+// HasFile returns true if the file at path, e.g. "/upload/pc.pcapng", was not removed.
+func (s *SFTPServer) HasFile(path string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.files[path]
+	return ok
+}
+
+func (s *SFTPServer) serveSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	handlers := sftp.Handlers{FileGet: s, FilePut: s, FileCmd: s, FileList: s}
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					go func() {
+						server := sftp.NewRequestServer(channel, handlers)
+						defer server.Close()
+						server.Serve()
+					}()
+				}
+			}
+		}()
+	}
+}
+
+func (s *SFTPServer) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	content, ok := s.files[r.Filepath]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return bytes.NewReader(content), nil
+}
+
+func (s *SFTPServer) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+func (s *SFTPServer) Filecmd(r *sftp.Request) error {
+	if r.Method != "Remove" && r.Method != "Rmdir" {
+		return sftp.ErrSSHFxOpUnsupported
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.files[r.Filepath]; !ok {
+		return os.ErrNotExist
+	}
+	if r.Method == "Rmdir" {
+		return sftp.ErrSSHFxFailure
+	}
+	delete(s.files, r.Filepath)
+	return nil
+}
+
+func (s *SFTPServer) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package antreaagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"antrea.io/antrea-ui/pkg/auth/session"
+	"antrea.io/antrea-ui/pkg/k8s"
+)
+
+const (
+	// antreaAgentLabelSelector selects the antrea-agent Pods of the Antrea DaemonSet.
+	antreaAgentLabelSelector  = "app=antrea,component=antrea-agent"
+	antreaAgentContainerName  = "antrea-agent"
+	antreaAgentAPIPortName    = "api"
+	defaultAntreaAgentAPIPort = 10350
+)
+
+type requestsHandler struct {
+	logger          logr.Logger
+	antreaNamespace string
+	kubeClient      kubernetes.Interface
+	// restClient builds the URLs of the Pod proxy subresource of the antrea-agent Pods.
+	restClient rest.Interface
+	// clientFactory is the factory for the K8s API of the end user.
+	clientFactory *k8s.ClientFactory
+}
+
+// NewRequestsHandler creates a handler for forwarding requests to the antrea-agent API of a Node.
+//
+// Requests go through the Pod proxy of the Kubernetes API server (pods/proxy), with the credential
+// of the end user who triggered them, so that they are authorized by the API server against that
+// user's RBAC. The certificate of the antrea-agent API is self-signed by every antrea-agent at
+// startup and cannot be verified: going through the API server means that the user's credential
+// is only ever sent to the API server, over a verified connection. The antrea-agent Pods
+// themselves are located with antrea-ui's own identity.
+func NewRequestsHandler(logger logr.Logger, config *rest.Config, clientFactory *k8s.ClientFactory, antreaNamespace string) (*requestsHandler, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &requestsHandler{
+		logger:          logger,
+		antreaNamespace: antreaNamespace,
+		kubeClient:      kubeClient,
+		restClient:      kubeClient.CoreV1().RESTClient(),
+		clientFactory:   clientFactory,
+	}, nil
+}
+
+// locateAgent returns the running antrea-agent Pod on node, and the port of its API.
+func (h *requestsHandler) locateAgent(ctx context.Context, node string) (*corev1.Pod, int, error) {
+	pods, err := h.kubeClient.CoreV1().Pods(h.antreaNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: antreaAgentLabelSelector,
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error when listing antrea-agent Pods: %w", err)
+	}
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		return pod, agentAPIPort(pod), nil
+	}
+	return nil, 0, ErrAgentNotFound
+}
+
+// agentAPIPort returns the port of the antrea-agent API, which is set by the apiPort option of
+// the agent, and declared by its container.
+func agentAPIPort(pod *corev1.Pod) int {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != antreaAgentContainerName {
+			continue
+		}
+		for _, port := range container.Ports {
+			if port.Name == antreaAgentAPIPortName {
+				return int(port.ContainerPort)
+			}
+		}
+	}
+	return defaultAntreaAgentAPIPort
+}
+
+// Request forwards a GET request to the antrea-agent API on node, as the end user behind ctx. ctx
+// must carry the identity resolved by the authentication middleware.
+func (h *requestsHandler) Request(ctx context.Context, node string, path string, query url.Values) ([]byte, int, error) {
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok {
+		return nil, 0, fmt.Errorf("request is not authenticated")
+	}
+	pod, port, err := h.locateAgent(ctx, node)
+	if err != nil {
+		return nil, 0, err
+	}
+	rt, err := h.clientFactory.TransportForRequest(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	proxyRequest := h.restClient.Get().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("https:%s:%d", pod.Name, port)).
+		SubResource("proxy").
+		Suffix(path)
+	for key, values := range query {
+		for _, value := range values {
+			proxyRequest.Param(key, value)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyRequest.URL().String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := h.clientFactory.HTTPClient(rt).Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The credential itself was rejected, so no later request with this session can
+		// succeed either.
+		ra.Invalidate()
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	return respBody, resp.StatusCode, err
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package antreaagent
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"antrea.io/antrea-ui/pkg/auth/session"
+	"antrea.io/antrea-ui/pkg/k8s"
+)
+
+const antreaNamespace = "kube-system"
+
+func agentPod(name, node, ip string, port int, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: antreaNamespace,
+			Name:      name,
+			Labels:    map[string]string{"app": "antrea", "component": "antrea-agent"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name:  antreaAgentContainerName,
+				Ports: []corev1.ContainerPort{{Name: antreaAgentAPIPortName, ContainerPort: int32(port)}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase, PodIP: ip},
+	}
+}
+
+func TestRequestsHandler(t *testing.T) {
+	logger := testr.New(t)
+
+	var gotRequest *http.Request
+	// The Kubernetes API server, which proxies the request to the antrea-agent Pod.
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+		w.Write([]byte(`[{"flow":"table=0"}]`))
+	}))
+	defer ts.Close()
+
+	// The fake clientset does not support field selectors, so only one Node has antrea-agent
+	// Pods.
+	fakeClient := fake.NewSimpleClientset(
+		agentPod("antrea-agent-old", "node-1", "10.0.0.1", 10350, corev1.PodFailed),
+		agentPod("antrea-agent-new", "node-1", "10.0.0.2", 10351, corev1.PodRunning),
+	)
+	config := &rest.Config{
+		Host:            ts.URL,
+		TLSClientConfig: rest.TLSClientConfig{CAData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})},
+	}
+	clientFactory, err := k8s.NewClientFactory(config, http.DefaultTransport, session.TransportKeyK8s)
+	require.NoError(t, err)
+	kubeClient, err := kubernetes.NewForConfig(config)
+	require.NoError(t, err)
+	handler := &requestsHandler{
+		logger:          logger,
+		antreaNamespace: antreaNamespace,
+		kubeClient:      fakeClient,
+		restClient:      kubeClient.CoreV1().RESTClient(),
+		clientFactory:   clientFactory,
+	}
+
+	store := session.NewStore(logger, session.Options{})
+	sess, err := store.Create(&session.Spec{
+		Mode:       session.ModeSAToken,
+		Credential: session.Credential{Kind: session.KindBearer, Token: []byte("user-token")},
+	})
+	require.NoError(t, err)
+	ctx := session.WithRequestAuth(t.Context(), session.NewSessionAuth(store, sess))
+
+	b, statusCode, err := handler.Request(ctx, "node-1", "/ovsflows", url.Values{"pod": {"pod-x"}, "namespace": {"default"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, `[{"flow":"table=0"}]`, string(b))
+	assert.Equal(t, "/api/v1/namespaces/kube-system/pods/https:antrea-agent-new:10351/proxy/ovsflows?namespace=default&pod=pod-x", gotRequest.URL.RequestURI())
+	assert.Equal(t, "Bearer user-token", gotRequest.Header.Get("Authorization"))
+
+	t.Run("unauthenticated context", func(t *testing.T) {
+		_, _, err := handler.Request(t.Context(), "node-1", "/ovsflows", nil)
+		assert.ErrorContains(t, err, "not authenticated")
+	})
+
+	t.Run("no agent", func(t *testing.T) {
+		handler := *handler
+		handler.kubeClient = fake.NewSimpleClientset(agentPod("antrea-agent-old", "node-1", "10.0.0.1", 10350, corev1.PodFailed))
+		_, _, err := handler.Request(ctx, "node-1", "/ovsflows", nil)
+		assert.ErrorIs(t, err, ErrAgentNotFound)
+	})
+}
+
+func TestAgentAPIPort(t *testing.T) {
+	assert.Equal(t, 10351, agentAPIPort(agentPod("antrea-agent", "node-1", "10.0.0.1", 10351, corev1.PodRunning)))
+	pod := agentPod("antrea-agent", "node-1", "10.0.0.1", 10351, corev1.PodRunning)
+	pod.Spec.Containers[0].Ports = nil
+	assert.Equal(t, defaultAntreaAgentAPIPort, agentAPIPort(pod))
+}
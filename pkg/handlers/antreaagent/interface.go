@@ -0,0 +1,34 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package antreaagent
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+//go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
+
+// ErrAgentNotFound is returned by Request when no running antrea-agent Pod is found on the Node.
+var ErrAgentNotFound = errors.New("no running antrea-agent Pod found on Node")
+
+type RequestsHandler interface {
+	// Request forwards a GET request to the antrea-agent API on node, as the end user behind
+	// ctx, and returns the response body along with the upstream status code. Like for
+	// antreasvc.RequestsHandler, callers must keep an upstream 401 distinct from a 403. query
+	// may be nil.
+	Request(ctx context.Context, node string, path string, query url.Values) ([]byte, int, error)
+}
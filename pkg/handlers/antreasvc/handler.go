@@ -150,7 +150,19 @@ func (h *requestsHandler) getHost() (string, error) {
 // It returns the upstream status code alongside the body so that callers can keep 401 (the
 // credential was rejected: the session is dead) distinct from 403 (an ordinary authorization
 // failure, which must not log the user out).
-func (h *requestsHandler) Request(ctx context.Context, method string, path string, body io.Reader) ([]byte, int, error) {
+func (h *requestsHandler) Request(ctx context.Context, method string, path string, query url.Values, body io.Reader) ([]byte, int, error) {
+	respBody, statusCode, err := h.Stream(ctx, method, path, query, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer respBody.Close()
+	b, err := io.ReadAll(respBody)
+	return b, statusCode, err
+}
+
+// Stream forwards a request to the Antrea Service like Request, without reading the response
+// body.
+func (h *requestsHandler) Stream(ctx context.Context, method string, path string, query url.Values, body io.Reader) (io.ReadCloser, int, error) {
 	host, err := h.getHost()
 	if err != nil {
 		return nil, 0, err
@@ -168,9 +180,10 @@ func (h *requestsHandler) Request(ctx context.Context, method string, path strin
 		return nil, 0, err
 	}
 	url := url.URL{
-		Scheme: "https",
-		Host:   host,
-		Path:   path,
+		Scheme:   "https",
+		Host:     host,
+		Path:     path,
+		RawQuery: query.Encode(),
 	}
 	req, err := http.NewRequestWithContext(ctx, method, url.String(), body)
 	if err != nil {
@@ -180,12 +193,10 @@ func (h *requestsHandler) Request(ctx context.Context, method string, path strin
 	if err != nil {
 		return nil, 0, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusUnauthorized {
 		// The credential itself was rejected, so no later request with this session can
 		// succeed either.
 		ra.Invalidate()
 	}
-	respBody, err := io.ReadAll(resp.Body)
-	return respBody, resp.StatusCode, err
+	return resp.Body, resp.StatusCode, nil
 }
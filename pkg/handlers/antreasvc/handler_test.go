@@ -50,8 +50,10 @@ func TestRequestsHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	var gotHeader http.Header
+	var gotURI string
 	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		gotHeader = r.Header.Clone()
+		gotURI = r.URL.RequestURI()
 		b, _ := io.ReadAll(r.Body)
 		w.Write(b)
 	}))
@@ -73,13 +75,13 @@ func TestRequestsHandler(t *testing.T) {
 	}
 	fakeClient := fake.NewSimpleClientset(cm)
 
-	url, err := url.Parse(ts.URL)
+	serverURL, err := url.Parse(ts.URL)
 	require.NoError(t, err)
 
 	handler := &requestsHandler{
 		logger:          logger,
 		antreaNamespace: antreaNamespace,
-		host:            url.Host,
+		host:            serverURL.Host,
 		kubeClient:      fakeClient,
 		clientProvider:  newAntreaClientProvider(logger, restConfig, fakeClient, antreaNamespace, antreaSvcAddr),
 		// the port forwarding case cannot be validated in the context of a unit test
@@ -108,7 +110,7 @@ func TestRequestsHandler(t *testing.T) {
 		ctx := session.WithRequestAuth(t.Context(), session.NewSessionAuth(store, sess))
 
 		body := "bar"
-		b, statusCode, err := handler.Request(ctx, "GET", "/foo", bytes.NewBufferString(body))
+		b, statusCode, err := handler.Request(ctx, "GET", "/foo", nil, bytes.NewBufferString(body))
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusOK, statusCode)
 		assert.Equal(t, body, string(b))
@@ -127,14 +129,28 @@ func TestRequestsHandler(t *testing.T) {
 		require.NoError(t, err)
 		ctx := session.WithRequestAuth(t.Context(), session.NewSessionAuth(store, sess))
 
-		_, statusCode, err := handler.Request(ctx, "GET", "/foo", bytes.NewBufferString("bar"))
+		_, statusCode, err := handler.Request(ctx, "GET", "/foo", nil, bytes.NewBufferString("bar"))
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusOK, statusCode)
 		assert.Equal(t, impersonatedUser, gotHeader.Get(transport.ImpersonateUserHeader))
 	})
 
+	t.Run("query", func(t *testing.T) {
+		sess, err := store.Create(&session.Spec{
+			Mode:       session.ModeSAToken,
+			Credential: session.Credential{Kind: session.KindBearer, Token: []byte("user-token")},
+		})
+		require.NoError(t, err)
+		ctx := session.WithRequestAuth(t.Context(), session.NewSessionAuth(store, sess))
+
+		_, statusCode, err := handler.Request(ctx, "GET", "/endpoint", url.Values{"namespace": {"default"}, "pod": {"pod-x"}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "/endpoint?namespace=default&pod=pod-x", gotURI)
+	})
+
 	t.Run("unauthenticated context", func(t *testing.T) {
-		_, _, err := handler.Request(t.Context(), "GET", "/foo", nil)
+		_, _, err := handler.Request(t.Context(), "GET", "/foo", nil, nil)
 		assert.ErrorContains(t, err, "not authenticated")
 	})
 }
@@ -17,6 +17,7 @@ package antreasvc
 import (
 	"context"
 	"io"
+	"net/url"
 )
 
 //go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
@@ -25,6 +26,9 @@ type RequestsHandler interface {
 	// Request forwards a request to the Antrea Service as the end user behind ctx, and returns
 	// the response body along with the upstream status code. Callers must keep an upstream 401
 	// (rejected credential, session is dead) distinct from a 403 (authorization failure, which
-	// must not end the session).
-	Request(ctx context.Context, method string, path string, body io.Reader) ([]byte, int, error)
+	// must not end the session). query may be nil.
+	Request(ctx context.Context, method string, path string, query url.Values, body io.Reader) ([]byte, int, error)
+	// Stream is like Request, but returns the response body as is, e.g. for a large download.
+	// The caller must close it.
+	Stream(ctx context.Context, method string, path string, query url.Values, body io.Reader) (io.ReadCloser, int, error)
 }
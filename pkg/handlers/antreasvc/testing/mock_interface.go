@@ -22,6 +22,7 @@ package testing
 import (
 	context "context"
 	io "io"
+	url "net/url"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -51,9 +52,9 @@ func (m *MockRequestsHandler) EXPECT() *MockRequestsHandlerMockRecorder {
 }
 
 // Request mocks base method.
-func (m *MockRequestsHandler) Request(ctx context.Context, method, path string, body io.Reader) ([]byte, int, error) {
+func (m *MockRequestsHandler) Request(ctx context.Context, method, path string, query url.Values, body io.Reader) ([]byte, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Request", ctx, method, path, body)
+	ret := m.ctrl.Call(m, "Request", ctx, method, path, query, body)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -61,7 +62,23 @@ func (m *MockRequestsHandler) Request(ctx context.Context, method, path string,
 }
 
 // Request indicates an expected call of Request.
-func (mr *MockRequestsHandlerMockRecorder) Request(ctx, method, path, body interface{}) *gomock.Call {
+func (mr *MockRequestsHandlerMockRecorder) Request(ctx, method, path, query, body interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockRequestsHandler)(nil).Request), ctx, method, path, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockRequestsHandler)(nil).Request), ctx, method, path, query, body)
+}
+
+// Stream mocks base method.
+func (m *MockRequestsHandler) Stream(ctx context.Context, method, path string, query url.Values, body io.Reader) (io.ReadCloser, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, method, path, query, body)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockRequestsHandlerMockRecorder) Stream(ctx, method, path, query, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockRequestsHandler)(nil).Stream), ctx, method, path, query, body)
 }
@@ -0,0 +1,34 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdversions
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+//go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
+
+// Resolver picks the version of each Antrea CRD that antrea-ui uses, among the ones served by the
+// cluster, so that a single antrea-ui build works with several Antrea releases.
+type Resolver interface {
+	// GVR returns the GroupVersionResource to use for resource, one of the Resource*
+	// constants. Until the served versions are known, or if none of them is supported, it
+	// returns the latest version supported by antrea-ui.
+	GVR(resource string) schema.GroupVersionResource
+	// Status reports the resolved versions, for GET /api/v1/settings.
+	Status() apisv1.AntreaAPISettings
+}
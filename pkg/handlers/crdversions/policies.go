@@ -0,0 +1,50 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdversions
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	PolicyTypeAntreaClusterNetworkPolicy = "AntreaClusterNetworkPolicy"
+	PolicyTypeAntreaNetworkPolicy        = "AntreaNetworkPolicy"
+	PolicyTypeK8sNetworkPolicy           = "K8sNetworkPolicy"
+)
+
+// policyResources maps the types Antrea reports for a policy, e.g. in Traceflow observations or
+// NetworkPolicyEvaluation results, to the resource of the policy.
+var policyResources = map[string]schema.GroupResource{
+	PolicyTypeAntreaClusterNetworkPolicy: {Group: Group, Resource: ResourceClusterNetworkPolicies},
+	PolicyTypeAntreaNetworkPolicy:        {Group: Group, Resource: ResourceNetworkPolicies},
+	PolicyTypeK8sNetworkPolicy:           {Group: "networking.k8s.io", Resource: "networkpolicies"},
+}
+
+// PolicyGVR returns the GroupVersionResource of a policy of type policyType. The version of
+// Antrea-native policies is picked by resolver, which may be nil. It returns false if the type is
+// not known.
+func PolicyGVR(resolver Resolver, policyType string) (schema.GroupVersionResource, bool) {
+	gr, ok := policyResources[policyType]
+	switch {
+	case !ok:
+		return schema.GroupVersionResource{}, false
+	case gr.Group != Group:
+		return gr.WithVersion("v1"), true
+	case resolver != nil:
+		return resolver.GVR(gr.Resource), true
+	default:
+		return DefaultGVR(gr.Resource), true
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crdversions discovers the versions of the Antrea CRDs served by the cluster, and picks
+// the one antrea-ui uses for each of them.
+//
+// Only the version changes: the fields antrea-ui reads and writes have the same shape in all the
+// versions it supports, except for Traceflows, which the traceflow handler converts between
+// v1alpha1 and v1beta1.
+package crdversions
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+const (
+	Group = "crd.antrea.io"
+
+	ResourceTraceflows               = "traceflows"
+	ResourceClusterNetworkPolicies   = "clusternetworkpolicies"
+	ResourceNetworkPolicies          = "networkpolicies"
+	ResourcePacketCaptures           = "packetcaptures"
+	ResourceSupportBundleCollections = "supportbundlecollections"
+	ResourceAntreaControllerInfos    = "antreacontrollerinfos"
+	ResourceAntreaAgentInfos         = "antreaagentinfos"
+
+	// refreshPeriod is how often the served versions are discovered again, to notice an Antrea
+	// upgrade or downgrade without restarting antrea-ui.
+	refreshPeriod = 10 * time.Minute
+)
+
+// supportedVersions are the versions of each resource antrea-ui supports, the latest first. v1beta1
+// was introduced by Antrea v1.13, and v1alpha1 removed by Antrea v2.0.
+var supportedVersions = map[string][]string{
+	// The spec and status of v1alpha1 Traceflows are converted by the traceflow handler.
+	ResourceTraceflows:             {"v1beta1", "v1alpha1"},
+	ResourceClusterNetworkPolicies: {"v1beta1", "v1alpha1"},
+	ResourceNetworkPolicies:        {"v1beta1", "v1alpha1"},
+	// PacketCaptures were introduced by Antrea v2.2.
+	ResourcePacketCaptures: {"v1alpha1"},
+	// SupportBundleCollections were introduced by Antrea v1.10.
+	ResourceSupportBundleCollections: {"v1alpha1"},
+	// AntreaControllerInfos and AntreaAgentInfos only moved to this group in v1beta1.
+	ResourceAntreaControllerInfos: {"v1beta1"},
+	ResourceAntreaAgentInfos:      {"v1beta1"},
+}
+
+// resources are reported in this order. Traceflows are required: without them, antrea-ui does
+// not support the cluster's Antrea version.
+var resources = []string{
+	ResourceTraceflows,
+	ResourceClusterNetworkPolicies,
+	ResourceNetworkPolicies,
+	ResourcePacketCaptures,
+	ResourceSupportBundleCollections,
+	ResourceAntreaControllerInfos,
+	ResourceAntreaAgentInfos,
+}
+
+type resolver struct {
+	logger    logr.Logger
+	discovery discovery.DiscoveryInterface
+
+	mutex    sync.RWMutex
+	versions map[string]string
+	status   apisv1.AntreaAPISettings
+}
+
+// NewResolver builds a Resolver using the discovery endpoints of the API server. Call Run in a
+// goroutine to discover the served versions.
+func NewResolver(logger logr.Logger, discovery discovery.DiscoveryInterface) *resolver {
+	r := &resolver{
+		logger:    logger,
+		discovery: discovery,
+		versions:  make(map[string]string),
+	}
+	r.status = apisv1.AntreaAPISettings{
+		Message:   "The served versions of the Antrea CRDs have not been discovered yet",
+		Resources: buildResourceVersions(nil),
+	}
+	return r
+}
+
+// Run discovers the served versions periodically until stopCh is closed. It blocks and should be
+// called from a goroutine.
+func (r *resolver) Run(stopCh <-chan struct{}) {
+	wait.Until(r.refresh, refreshPeriod, stopCh)
+}
+
+func (r *resolver) GVR(resource string) schema.GroupVersionResource {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	version, ok := r.versions[resource]
+	if !ok {
+		return DefaultGVR(resource)
+	}
+	return schema.GroupVersionResource{Group: Group, Version: version, Resource: resource}
+}
+
+// DefaultGVR returns the GVR of resource in the latest version antrea-ui supports, which is what
+// Resolver.GVR returns until the served versions are discovered. It is meant for callers without
+// a Resolver.
+func DefaultGVR(resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: Group, Version: supportedVersions[resource][0], Resource: resource}
+}
+
+func (r *resolver) Status() apisv1.AntreaAPISettings {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	status := r.status
+	status.Resources = slices.Clone(status.Resources)
+	return status
+}
+
+func (r *resolver) refresh() {
+	served, err := r.discoverServedVersions()
+	if err != nil {
+		r.logger.Error(err, "Error when discovering Antrea CRD versions")
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		// Keep using the versions resolved last time, if any.
+		if !r.status.Discovered {
+			r.status.Message = fmt.Sprintf("Error when discovering Antrea CRD versions: %v", err)
+		}
+		return
+	}
+	status := buildStatus(served)
+	versions := make(map[string]string)
+	for _, resource := range status.Resources {
+		if resource.Supported {
+			versions[resource.Resource] = resource.Version
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.status.Discovered || !maps.Equal(r.versions, versions) {
+		r.logger.Info("Resolved Antrea CRD versions", "versions", versions, "supported", status.Supported)
+	}
+	r.versions = versions
+	r.status = status
+}
+
+// discoverServedVersions returns the versions in which each resource of the Antrea group is
+// served, the preferred version of the group first. It returns nil if the group is not served.
+func (r *resolver) discoverServedVersions() (map[string][]string, error) {
+	groups, err := r.discovery.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(groups.Groups, func(g metav1.APIGroup) bool { return g.Name == Group })
+	if idx < 0 {
+		return nil, nil
+	}
+	group := &groups.Groups[idx]
+	var versions []string
+	for _, v := range group.Versions {
+		if v.Version == group.PreferredVersion.Version {
+			versions = append([]string{v.Version}, versions...)
+		} else {
+			versions = append(versions, v.Version)
+		}
+	}
+	served := make(map[string][]string)
+	for _, version := range versions {
+		list, err := r.discovery.ServerResourcesForGroupVersion(Group + "/" + version)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range list.APIResources {
+			// Skip subresources, e.g. "traceflows/status".
+			if !strings.Contains(resource.Name, "/") {
+				served[resource.Name] = append(served[resource.Name], version)
+			}
+		}
+	}
+	return served, nil
+}
+
+// buildStatus reports the version of every resource resolved from served, the versions in which
+// each resource is served (nil if the group is not served at all).
+func buildStatus(served map[string][]string) apisv1.AntreaAPISettings {
+	status := apisv1.AntreaAPISettings{
+		Discovered: true,
+		Supported:  true,
+		Resources:  buildResourceVersions(served),
+	}
+	traceflows := status.Resources[0]
+	switch {
+	case served == nil:
+		status.Supported = false
+		status.Message = fmt.Sprintf("API group %s is not served: Antrea is not installed", Group)
+	case traceflows.Supported:
+	case len(traceflows.ServedVersions) == 0:
+		status.Supported = false
+		status.Message = fmt.Sprintf("Antrea does not serve %s: this Antrea version is not supported", ResourceTraceflows)
+	default:
+		status.Supported = false
+		status.Message = fmt.Sprintf("Antrea serves %s in versions %s, none of which is supported (supported: %s)",
+			ResourceTraceflows, strings.Join(traceflows.ServedVersions, ", "), strings.Join(supportedVersions[ResourceTraceflows], ", "))
+	}
+	return status
+}
+
+// buildResourceVersions resolves the version of every resource: the first served version which is
+// supported, i.e. the preferred version of the group if possible, otherwise the latest supported
+// version.
+func buildResourceVersions(served map[string][]string) []apisv1.AntreaResourceVersion {
+	var result []apisv1.AntreaResourceVersion
+	for _, resource := range resources {
+		supported := supportedVersions[resource]
+		resourceVersion := apisv1.AntreaResourceVersion{
+			Resource:       resource,
+			Version:        supported[0],
+			ServedVersions: served[resource],
+		}
+		for _, version := range served[resource] {
+			if slices.Contains(supported, version) {
+				resourceVersion.Version = version
+				resourceVersion.Supported = true
+				break
+			}
+		}
+		result = append(result, resourceVersion)
+	}
+	return result
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdversions
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// resourceList returns the discovery document of groupVersion, serving resources and their
+// status subresource.
+func resourceList(groupVersion string, resources ...string) *metav1.APIResourceList {
+	list := &metav1.APIResourceList{GroupVersion: groupVersion}
+	for _, resource := range resources {
+		list.APIResources = append(list.APIResources,
+			metav1.APIResource{Name: resource},
+			metav1.APIResource{Name: resource + "/status"},
+		)
+	}
+	return list
+}
+
+func newTestResolver(t *testing.T, resources ...*metav1.APIResourceList) (*resolver, *discoveryfake.FakeDiscovery) {
+	discovery := &discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{Resources: resources}}
+	return NewResolver(testr.New(t), discovery), discovery
+}
+
+func TestResolver(t *testing.T) {
+	allResources := []string{ResourceTraceflows, ResourceClusterNetworkPolicies, ResourceNetworkPolicies}
+	testCases := []struct {
+		name              string
+		resources         []*metav1.APIResourceList
+		expectedSupported bool
+		expectedMessage   string
+		expectedVersions  map[string]string
+	}{
+		{
+			name: "v1beta1",
+			resources: []*metav1.APIResourceList{
+				resourceList("crd.antrea.io/v1beta1", allResources...),
+			},
+			expectedSupported: true,
+			expectedVersions: map[string]string{
+				ResourceTraceflows:             "v1beta1",
+				ResourceClusterNetworkPolicies: "v1beta1",
+				ResourceNetworkPolicies:        "v1beta1",
+			},
+		},
+		{
+			name: "preferred version",
+			resources: []*metav1.APIResourceList{
+				// The fake server prefers the first version it serves.
+				resourceList("crd.antrea.io/v1alpha1", allResources...),
+				resourceList("crd.antrea.io/v1beta1", ResourceTraceflows),
+			},
+			expectedSupported: true,
+			expectedVersions: map[string]string{
+				ResourceTraceflows:             "v1alpha1",
+				ResourceClusterNetworkPolicies: "v1alpha1",
+				ResourceNetworkPolicies:        "v1alpha1",
+			},
+		},
+		{
+			name: "preferred version not supported",
+			resources: []*metav1.APIResourceList{
+				resourceList("crd.antrea.io/v1", allResources...),
+				resourceList("crd.antrea.io/v1beta1", ResourceClusterNetworkPolicies),
+			},
+			expectedSupported: false,
+			expectedMessage:   "Antrea serves traceflows in versions v1, none of which is supported (supported: v1beta1, v1alpha1)",
+			expectedVersions: map[string]string{
+				ResourceTraceflows:             "v1beta1",
+				ResourceClusterNetworkPolicies: "v1beta1",
+				ResourceNetworkPolicies:        "v1beta1",
+			},
+		},
+		{
+			name: "no Traceflows",
+			resources: []*metav1.APIResourceList{
+				resourceList("crd.antrea.io/v1beta1", ResourceClusterNetworkPolicies),
+			},
+			expectedSupported: false,
+			expectedMessage:   "Antrea does not serve traceflows: this Antrea version is not supported",
+			expectedVersions: map[string]string{
+				ResourceTraceflows:             "v1beta1",
+				ResourceClusterNetworkPolicies: "v1beta1",
+				ResourceNetworkPolicies:        "v1beta1",
+			},
+		},
+		{
+			name: "Antrea not installed",
+			resources: []*metav1.APIResourceList{
+				resourceList("apps/v1", "deployments"),
+			},
+			expectedSupported: false,
+			expectedMessage:   "API group crd.antrea.io is not served: Antrea is not installed",
+			expectedVersions: map[string]string{
+				ResourceTraceflows:             "v1beta1",
+				ResourceClusterNetworkPolicies: "v1beta1",
+				ResourceNetworkPolicies:        "v1beta1",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := newTestResolver(t, tc.resources...)
+			r.refresh()
+			status := r.Status()
+			assert.True(t, status.Discovered)
+			assert.Equal(t, tc.expectedSupported, status.Supported)
+			assert.Equal(t, tc.expectedMessage, status.Message)
+			for resource, version := range tc.expectedVersions {
+				gvr := r.GVR(resource)
+				assert.Equal(t, Group, gvr.Group)
+				assert.Equal(t, resource, gvr.Resource)
+				assert.Equal(t, version, gvr.Version, "version of %s", resource)
+			}
+		})
+	}
+}
+
+func TestResolverStatus(t *testing.T) {
+	r, _ := newTestResolver(t,
+		resourceList("crd.antrea.io/v1beta1", ResourceTraceflows, ResourceNetworkPolicies),
+		resourceList("crd.antrea.io/v1alpha1", ResourceTraceflows),
+	)
+	status := r.Status()
+	assert.False(t, status.Discovered)
+	assert.False(t, status.Supported)
+
+	r.refresh()
+	status = r.Status()
+	assert.True(t, status.Discovered)
+	assert.True(t, status.Supported)
+	assert.Empty(t, status.Message)
+	assert.Len(t, status.Resources, 7)
+	assert.Equal(t, ResourceTraceflows, status.Resources[0].Resource)
+	assert.Equal(t, []string{"v1beta1", "v1alpha1"}, status.Resources[0].ServedVersions)
+	assert.True(t, status.Resources[0].Supported)
+	assert.Equal(t, ResourceClusterNetworkPolicies, status.Resources[1].Resource)
+	assert.Empty(t, status.Resources[1].ServedVersions)
+	assert.False(t, status.Resources[1].Supported)
+}
+
+func TestResolverDiscoveryError(t *testing.T) {
+	r, discovery := newTestResolver(t, resourceList("crd.antrea.io/v1alpha1", ResourceTraceflows))
+	fail := false
+	discovery.PrependReactor("get", "group", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if fail {
+			return true, nil, assert.AnError
+		}
+		return false, nil, nil
+	})
+
+	fail = true
+	r.refresh()
+	status := r.Status()
+	assert.False(t, status.Discovered)
+	assert.Contains(t, status.Message, "Error when discovering Antrea CRD versions")
+	assert.Equal(t, "v1beta1", r.GVR(ResourceTraceflows).Version)
+
+	fail = false
+	r.refresh()
+	assert.Equal(t, "v1alpha1", r.GVR(ResourceTraceflows).Version)
+
+	// The versions resolved last time are kept.
+	fail = true
+	r.refresh()
+	assert.True(t, r.Status().Discovered)
+	assert.Equal(t, "v1alpha1", r.GVR(ResourceTraceflows).Version)
+}
+
+func TestPolicyGVR(t *testing.T) {
+	r, _ := newTestResolver(t, resourceList("crd.antrea.io/v1alpha1", ResourceTraceflows, ResourceClusterNetworkPolicies, ResourceNetworkPolicies))
+	r.refresh()
+	testCases := []struct {
+		name        string
+		resolver    Resolver
+		policyType  string
+		expectedGVR schema.GroupVersionResource
+		expectedOK  bool
+	}{
+		{
+			name:        "Antrea ClusterNetworkPolicy",
+			resolver:    r,
+			policyType:  PolicyTypeAntreaClusterNetworkPolicy,
+			expectedGVR: schema.GroupVersionResource{Group: Group, Version: "v1alpha1", Resource: ResourceClusterNetworkPolicies},
+			expectedOK:  true,
+		},
+		{
+			name:        "Antrea NetworkPolicy without resolver",
+			policyType:  PolicyTypeAntreaNetworkPolicy,
+			expectedGVR: schema.GroupVersionResource{Group: Group, Version: "v1beta1", Resource: ResourceNetworkPolicies},
+			expectedOK:  true,
+		},
+		{
+			name:        "K8s NetworkPolicy",
+			resolver:    r,
+			policyType:  PolicyTypeK8sNetworkPolicy,
+			expectedGVR: schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"},
+			expectedOK:  true,
+		},
+		{
+			name:       "unknown type",
+			resolver:   r,
+			policyType: "AdminNetworkPolicy",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gvr, ok := PolicyGVR(tc.resolver, tc.policyType)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedGVR, gvr)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+
+// Package testing is a generated GoMock package.
+package testing
+
+import (
+	reflect "reflect"
+
+	v1 "antrea.io/antrea-ui/apis/v1"
+	gomock "github.com/golang/mock/gomock"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MockResolver is a mock of Resolver interface.
+type MockResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverMockRecorder
+}
+
+// MockResolverMockRecorder is the mock recorder for MockResolver.
+type MockResolverMockRecorder struct {
+	mock *MockResolver
+}
+
+// NewMockResolver creates a new mock instance.
+func NewMockResolver(ctrl *gomock.Controller) *MockResolver {
+	mock := &MockResolver{ctrl: ctrl}
+	mock.recorder = &MockResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
+	return m.recorder
+}
+
+// GVR mocks base method.
+func (m *MockResolver) GVR(resource string) schema.GroupVersionResource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GVR", resource)
+	ret0, _ := ret[0].(schema.GroupVersionResource)
+	return ret0
+}
+
+// GVR indicates an expected call of GVR.
+func (mr *MockResolverMockRecorder) GVR(resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GVR", reflect.TypeOf((*MockResolver)(nil).GVR), resource)
+}
+
+// Status mocks base method.
+func (m *MockResolver) Status() v1.AntreaAPISettings {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(v1.AntreaAPISettings)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockResolverMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockResolver)(nil).Status))
+}
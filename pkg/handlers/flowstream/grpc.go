@@ -26,6 +26,9 @@ import (
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/client-go/kubernetes"
 
 	apisv1 "antrea.io/antrea-ui/apis/v1"
 	flowpb "antrea.io/antrea-ui/pkg/flowpb"
@@ -37,6 +40,8 @@ type GRPCFlowStreamSubscriber struct {
 	logger logr.Logger
 	client flowpb.FlowStreamServiceClient
 	conn   *grpc.ClientConn
+	// forwarder is set when the FlowAggregator is reached through a port-forward.
+	forwarder *flowAggregatorForwarder
 }
 
 // GRPCConfig holds the connection parameters for the FlowAggregator gRPC server.
@@ -45,13 +50,32 @@ type GRPCConfig struct {
 	Address string
 	// TLSConfig is the TLS configuration used for the gRPC connection.
 	TLSConfig *tls.Config
+	// PortForward, when set, makes the client reach the FlowAggregator through a port-forward to
+	// its Pod, for a backend running out-of-cluster. Address is still used for its port, which
+	// selects the Service port, and for its host, which the server certificate is verified
+	// against unless TLSConfig sets ServerName.
+	PortForward *PortForwardConfig
 }
 
 func NewGRPCFlowStreamSubscriber(logger logr.Logger, cfg GRPCConfig) (*GRPCFlowStreamSubscriber, error) {
-	conn, err := grpc.NewClient(
-		cfg.Address,
-		grpc.WithTransportCredentials(credentials.NewTLS(cfg.TLSConfig)),
-	)
+	target := cfg.Address
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(cfg.TLSConfig))}
+	var forwarder *flowAggregatorForwarder
+	if cfg.PortForward != nil {
+		kubeClient, err := kubernetes.NewForConfig(cfg.PortForward.RESTConfig)
+		if err != nil {
+			return nil, err
+		}
+		forwarder, err = newFlowAggregatorForwarder(logger, cfg.PortForward, kubeClient, cfg.Address)
+		if err != nil {
+			return nil, err
+		}
+		// The Service address does not resolve out-of-cluster: skip name resolution and let
+		// the dialer pick the local end of the port-forward.
+		target = "passthrough:///" + cfg.Address
+		opts = append(opts, grpc.WithContextDialer(forwarder.dial))
+	}
+	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC connection to %s: %w", cfg.Address, err)
 	}
@@ -63,12 +87,21 @@ func NewGRPCFlowStreamSubscriber(logger logr.Logger, cfg GRPCConfig) (*GRPCFlowS
 	logger.Info("FlowAggregator gRPC client created", "address", cfg.Address)
 
 	return &GRPCFlowStreamSubscriber{
-		logger: logger,
-		client: client,
-		conn:   conn,
+		logger:    logger,
+		client:    client,
+		conn:      conn,
+		forwarder: forwarder,
 	}, nil
 }
 
+// Run maintains the port-forward to the FlowAggregator, if one is needed, until stopCh is closed.
+func (h *GRPCFlowStreamSubscriber) Run(stopCh <-chan struct{}) {
+	if h.forwarder != nil {
+		go h.forwarder.Run(stopCh)
+	}
+	<-stopCh
+}
+
 func (h *GRPCFlowStreamSubscriber) Close() error {
 	if h.conn != nil {
 		return h.conn.Close()
@@ -117,9 +150,14 @@ func (h *GRPCFlowStreamSubscriber) Subscribe(ctx context.Context, filter *FlowSt
 			if len(resp.Flows) > 0 {
 				converted := make([]apisv1.Flow, 0, len(resp.Flows))
 				for _, pbFlow := range resp.Flows {
-					converted = append(converted, protoFlowToAPI(pbFlow))
+					f := protoFlowToAPI(pbFlow)
+					if filter.MatchesLocalFilters(&f) {
+						converted = append(converted, f)
+					}
+				}
+				if len(converted) > 0 {
+					evt.Flows = converted
 				}
-				evt.Flows = converted
 			}
 			if evt.DroppedCount > 0 || len(evt.Flows) > 0 {
 				select {
@@ -271,5 +309,204 @@ func protoFlowToAPI(pb *flowpb.Flow) apisv1.Flow {
 		}
 	}
 
+	if app := pb.GetApp(); app != nil {
+		f.App = &apisv1.FlowApp{
+			ProtocolName: app.GetProtocolName(),
+			HTTPVals:     string(app.GetHttpVals()),
+		}
+	}
+
+	if ipfix := pb.GetIpfix(); ipfix != nil {
+		f.IPFIX = &apisv1.FlowIPFIX{
+			ExportTime:          timestampToString(ipfix.GetExportTime()),
+			SequenceNumber:      ipfix.GetSequenceNumber(),
+			ObservationDomainId: ipfix.GetObservationDomainId(),
+			ExporterIp:          ipfix.GetExporterIp(),
+		}
+	}
+
+	if agg := pb.GetAggregation(); agg != nil {
+		aggregation := apisv1.FlowAggregation{
+			EndTsFromSource:                  timestampToString(agg.GetEndTsFromSource()),
+			EndTsFromDestination:             timestampToString(agg.GetEndTsFromDestination()),
+			StatsFromSource:                  protoStatsToAPI(agg.GetStatsFromSource()),
+			ReverseStatsFromSource:           protoStatsToAPI(agg.GetReverseStatsFromSource()),
+			StatsFromDestination:             protoStatsToAPI(agg.GetStatsFromDestination()),
+			ReverseStatsFromDestination:      protoStatsToAPI(agg.GetReverseStatsFromDestination()),
+			ThroughputFromSource:             agg.GetThroughputFromSource(),
+			ReverseThroughputFromSource:      agg.GetReverseThroughputFromSource(),
+			ThroughputFromDestination:        agg.GetThroughputFromDestination(),
+			ReverseThroughputFromDestination: agg.GetReverseThroughputFromDestination(),
+		}
+		// Throughput and ReverseThroughput are already surfaced in Stats and ReverseStats;
+		// only report Aggregation when it carries more than that.
+		if aggregation != (apisv1.FlowAggregation{}) {
+			f.Aggregation = &aggregation
+		}
+	}
+
 	return f
 }
+
+func timestampToString(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Format(time.RFC3339Nano)
+}
+
+func protoStatsToAPI(s *flowpb.Stats) apisv1.FlowStats {
+	return apisv1.FlowStats{
+		PacketTotalCount: s.GetPacketTotalCount(),
+		PacketDeltaCount: s.GetPacketDeltaCount(),
+		OctetTotalCount:  s.GetOctetTotalCount(),
+		OctetDeltaCount:  s.GetOctetDeltaCount(),
+	}
+}
+
+// ipStringToBytes is the inverse of ipBytesToString. Anything that is not a plain IP address
+// (including a masked one) converts to nil.
+func ipStringToBytes(s string) []byte {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil
+	}
+	return addr.AsSlice()
+}
+
+func timestampFromString(s string) *timestamppb.Timestamp {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func apiStatsToProto(s apisv1.FlowStats) *flowpb.Stats {
+	if s == (apisv1.FlowStats{}) {
+		return nil
+	}
+	return &flowpb.Stats{
+		PacketTotalCount: s.PacketTotalCount,
+		PacketDeltaCount: s.PacketDeltaCount,
+		OctetTotalCount:  s.OctetTotalCount,
+		OctetDeltaCount:  s.OctetDeltaCount,
+	}
+}
+
+func labelsToProto(labels map[string]string) *flowpb.Labels {
+	if labels == nil {
+		return nil
+	}
+	return &flowpb.Labels{Labels: labels}
+}
+
+// apiFlowToProto converts an API flow back to the protobuf Flow message, for the compact stream
+// encoding. It is the inverse of protoFlowToAPI for every field the API exposes; zero-valued
+// sub-messages are left unset, so a projected flow (see FieldProjection.Clear) encodes only its
+// selected fields.
+func apiFlowToProto(f *apisv1.Flow) *flowpb.Flow {
+	pb := &flowpb.Flow{
+		Id:        f.ID,
+		StartTs:   timestampFromString(f.StartTs),
+		EndTs:     timestampFromString(f.EndTs),
+		EndReason: flowpb.FlowEndReason(f.EndReason),
+	}
+	if f.IP != (apisv1.FlowIP{}) {
+		pb.Ip = &flowpb.IP{
+			Version:     flowpb.IPVersion(f.IP.Version),
+			Source:      ipStringToBytes(f.IP.Source),
+			Destination: ipStringToBytes(f.IP.Destination),
+		}
+	}
+	if f.Transport != (apisv1.FlowTransport{}) {
+		pb.Transport = &flowpb.Transport{
+			ProtocolNumber:  f.Transport.ProtocolNumber,
+			SourcePort:      f.Transport.SourcePort,
+			DestinationPort: f.Transport.DestinationPort,
+		}
+		if f.Transport.TCP != nil {
+			pb.Transport.Protocol = &flowpb.Transport_TCP{
+				TCP: &flowpb.TCP{StateName: f.Transport.TCP.StateName},
+			}
+		}
+	}
+	k := &f.K8s
+	pbK8s := &flowpb.Kubernetes{
+		FlowType:                       flowpb.FlowType(k.FlowType),
+		SourcePodNamespace:             k.SourcePodNamespace,
+		SourcePodName:                  k.SourcePodName,
+		SourcePodUid:                   k.SourcePodUid,
+		SourcePodLabels:                labelsToProto(k.SourcePodLabels),
+		SourceNodeName:                 k.SourceNodeName,
+		SourceNodeUid:                  k.SourceNodeUid,
+		DestinationPodNamespace:        k.DestinationPodNamespace,
+		DestinationPodName:             k.DestinationPodName,
+		DestinationPodUid:              k.DestinationPodUid,
+		DestinationPodLabels:           labelsToProto(k.DestinationPodLabels),
+		DestinationNodeName:            k.DestinationNodeName,
+		DestinationNodeUid:             k.DestinationNodeUid,
+		DestinationClusterIp:           ipStringToBytes(k.DestinationClusterIp),
+		DestinationServicePort:         k.DestinationServicePort,
+		DestinationServicePortName:     k.DestinationServicePortName,
+		DestinationServiceUid:          k.DestinationServiceUid,
+		IngressNetworkPolicyType:       flowpb.NetworkPolicyType(k.IngressNetworkPolicyType),
+		IngressNetworkPolicyNamespace:  k.IngressNetworkPolicyNamespace,
+		IngressNetworkPolicyName:       k.IngressNetworkPolicyName,
+		IngressNetworkPolicyUid:        k.IngressNetworkPolicyUid,
+		IngressNetworkPolicyRuleName:   k.IngressNetworkPolicyRuleName,
+		IngressNetworkPolicyRuleAction: flowpb.NetworkPolicyRuleAction(k.IngressNetworkPolicyRuleAction),
+		EgressNetworkPolicyType:        flowpb.NetworkPolicyType(k.EgressNetworkPolicyType),
+		EgressNetworkPolicyNamespace:   k.EgressNetworkPolicyNamespace,
+		EgressNetworkPolicyName:        k.EgressNetworkPolicyName,
+		EgressNetworkPolicyUid:         k.EgressNetworkPolicyUid,
+		EgressNetworkPolicyRuleName:    k.EgressNetworkPolicyRuleName,
+		EgressNetworkPolicyRuleAction:  flowpb.NetworkPolicyRuleAction(k.EgressNetworkPolicyRuleAction),
+		EgressName:                     k.EgressName,
+		EgressIp:                       ipStringToBytes(k.EgressIp),
+		EgressNodeName:                 k.EgressNodeName,
+		EgressNodeUid:                  k.EgressNodeUid,
+		EgressUid:                      k.EgressUid,
+	}
+	if !proto.Equal(pbK8s, &flowpb.Kubernetes{}) {
+		pb.K8S = pbK8s
+	}
+	pb.Stats = apiStatsToProto(f.Stats)
+	pb.ReverseStats = apiStatsToProto(f.ReverseStats)
+	if f.Stats.Throughput != 0 || f.ReverseStats.Throughput != 0 || f.Aggregation != nil {
+		pb.Aggregation = &flowpb.Aggregation{
+			Throughput:        f.Stats.Throughput,
+			ReverseThroughput: f.ReverseStats.Throughput,
+		}
+	}
+	if agg := f.Aggregation; agg != nil {
+		pb.Aggregation.EndTsFromSource = timestampFromString(agg.EndTsFromSource)
+		pb.Aggregation.EndTsFromDestination = timestampFromString(agg.EndTsFromDestination)
+		pb.Aggregation.StatsFromSource = apiStatsToProto(agg.StatsFromSource)
+		pb.Aggregation.ReverseStatsFromSource = apiStatsToProto(agg.ReverseStatsFromSource)
+		pb.Aggregation.StatsFromDestination = apiStatsToProto(agg.StatsFromDestination)
+		pb.Aggregation.ReverseStatsFromDestination = apiStatsToProto(agg.ReverseStatsFromDestination)
+		pb.Aggregation.ThroughputFromSource = agg.ThroughputFromSource
+		pb.Aggregation.ReverseThroughputFromSource = agg.ReverseThroughputFromSource
+		pb.Aggregation.ThroughputFromDestination = agg.ThroughputFromDestination
+		pb.Aggregation.ReverseThroughputFromDestination = agg.ReverseThroughputFromDestination
+	}
+	if f.App != nil {
+		pb.App = &flowpb.App{
+			ProtocolName: f.App.ProtocolName,
+			HttpVals:     []byte(f.App.HTTPVals),
+		}
+	}
+	if f.IPFIX != nil {
+		pb.Ipfix = &flowpb.IPFIX{
+			ExportTime:          timestampFromString(f.IPFIX.ExportTime),
+			SequenceNumber:      f.IPFIX.SequenceNumber,
+			ObservationDomainId: f.IPFIX.ObservationDomainId,
+			ExporterIp:          f.IPFIX.ExporterIp,
+		}
+	}
+	return pb
+}
@@ -446,4 +446,98 @@ func TestProtoFlowToAPI_FullFlow(t *testing.T) {
 	assert.Equal(t, "10.96.1.1", got.K8s.DestinationClusterIp)
 	assert.Equal(t, uint64(200), got.Stats.PacketTotalCount)
 	assert.Equal(t, uint64(150), got.ReverseStats.PacketTotalCount)
+	assert.Nil(t, got.App)
+	assert.Nil(t, got.IPFIX)
+	assert.Nil(t, got.Aggregation)
+}
+
+func TestProtoFlowToAPI_AppIPFIXAggregation(t *testing.T) {
+	pb := &flowpb.Flow{
+		Stats: &flowpb.Stats{PacketTotalCount: 19},
+		App: &flowpb.App{
+			ProtocolName: "http",
+			HttpVals:     []byte(`{"0":{"hostname":"example.com"}}`),
+		},
+		Ipfix: &flowpb.IPFIX{
+			ExportTime:          timestamppb.New(mustParseTime("2026-03-25T10:01:00Z")),
+			SequenceNumber:      7,
+			ObservationDomainId: 42,
+			ExporterIp:          "10.0.0.1",
+		},
+		Aggregation: &flowpb.Aggregation{
+			EndTsFromSource:      timestamppb.New(mustParseTime("2026-03-25T10:00:59Z")),
+			StatsFromSource:      &flowpb.Stats{PacketTotalCount: 10},
+			StatsFromDestination: &flowpb.Stats{PacketTotalCount: 9},
+			Throughput:           100,
+			ThroughputFromSource: 60,
+		},
+	}
+
+	got := protoFlowToAPI(pb)
+
+	assert.Equal(t, &apisv1.FlowApp{ProtocolName: "http", HTTPVals: `{"0":{"hostname":"example.com"}}`}, got.App)
+	assert.Equal(t, &apisv1.FlowIPFIX{
+		ExportTime:          "2026-03-25T10:01:00Z",
+		SequenceNumber:      7,
+		ObservationDomainId: 42,
+		ExporterIp:          "10.0.0.1",
+	}, got.IPFIX)
+	assert.Equal(t, &apisv1.FlowAggregation{
+		EndTsFromSource:      "2026-03-25T10:00:59Z",
+		StatsFromSource:      apisv1.FlowStats{PacketTotalCount: 10},
+		StatsFromDestination: apisv1.FlowStats{PacketTotalCount: 9},
+		ThroughputFromSource: 60,
+	}, got.Aggregation)
+	assert.Equal(t, uint64(100), got.Stats.Throughput)
+
+	// an Aggregation message that only carries the throughputs is reported through Stats
+	got = protoFlowToAPI(&flowpb.Flow{Stats: &flowpb.Stats{}, Aggregation: &flowpb.Aggregation{Throughput: 100}})
+	assert.Nil(t, got.Aggregation)
+}
+
+func TestAPIFlowToProtoRoundTrip(t *testing.T) {
+	f := apisv1.Flow{
+		ID:        "flow-round-trip",
+		StartTs:   "2026-03-25T10:00:00Z",
+		EndTs:     "2026-03-25T10:01:00.5Z",
+		EndReason: apisv1.FlowEndReasonEndOfFlow,
+		IP: apisv1.FlowIP{
+			Version:     apisv1.IPVersionIPv6,
+			Source:      "fd00::1",
+			Destination: "fd00::2",
+		},
+		Transport: apisv1.FlowTransport{
+			ProtocolNumber:  6,
+			SourcePort:      12345,
+			DestinationPort: 443,
+			TCP:             &apisv1.FlowTCP{StateName: "ESTABLISHED"},
+		},
+		K8s: apisv1.FlowKubernetes{
+			FlowType:                      apisv1.FlowTypeInterNode,
+			SourcePodNamespace:            "default",
+			SourcePodName:                 "web",
+			SourcePodLabels:               map[string]string{"app": "web"},
+			DestinationClusterIp:          "10.96.0.10",
+			EgressNetworkPolicyType:       apisv1.NetworkPolicyTypeACNP,
+			EgressNetworkPolicyName:       "deny-all",
+			EgressNetworkPolicyRuleAction: apisv1.NetworkPolicyRuleActionDrop,
+			EgressIp:                      "172.16.0.1",
+		},
+		Stats:        apisv1.FlowStats{PacketTotalCount: 10, OctetTotalCount: 1000, Throughput: 80},
+		ReverseStats: apisv1.FlowStats{PacketTotalCount: 5, Throughput: 40},
+	}
+	assert.Equal(t, f, protoFlowToAPI(apiFlowToProto(&f)))
+
+	f.App = &apisv1.FlowApp{ProtocolName: "http", HTTPVals: "{}"}
+	f.IPFIX = &apisv1.FlowIPFIX{ExportTime: "2026-03-25T10:01:01Z", SequenceNumber: 3, ObservationDomainId: 1, ExporterIp: "fd00::10"}
+	f.Aggregation = &apisv1.FlowAggregation{
+		EndTsFromDestination:        "2026-03-25T10:01:00Z",
+		ReverseStatsFromDestination: apisv1.FlowStats{OctetDeltaCount: 12},
+		ReverseThroughputFromSource: 20,
+	}
+	assert.Equal(t, f, protoFlowToAPI(apiFlowToProto(&f)))
+
+	// masked addresses cannot be represented as bytes
+	masked := apisv1.Flow{IP: apisv1.FlowIP{Source: "10.0.1.0/24"}}
+	assert.Nil(t, apiFlowToProto(&masked).GetIp().GetSource())
 }
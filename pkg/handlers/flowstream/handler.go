@@ -15,19 +15,25 @@
 package flowstream
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
 
 	apisv1 "antrea.io/antrea-ui/apis/v1"
 	"antrea.io/antrea-ui/pkg/auth/session"
+	flowpb "antrea.io/antrea-ui/pkg/flowpb"
 )
 
 // FlowFilterDirection controls which endpoint of a flow the directional filters are matched against.
@@ -49,6 +55,131 @@ type FlowStreamFilter struct {
 	FlowTypes        []apisv1.FlowType
 	IPs              []string
 	Direction        FlowFilterDirection
+
+	// The fields below have no equivalent in the Flow Aggregator's FlowFilter, so antrea-ui
+	// applies them itself to the records it receives (see MatchesLocalFilters). Records they
+	// exclude still count against the stream's ring buffer upstream.
+
+	// AppProtocols matches the L7 protocol name (App.ProtocolName), case-insensitively.
+	AppProtocols []string
+	// ObservationDomainIDs matches the IPFIX observation domain of the exporter.
+	ObservationDomainIDs []uint32
+	// ExporterIPs matches the IPFIX exporter address.
+	ExporterIPs []string
+}
+
+// MatchesLocalFilters reports whether f passes the filters that antrea-ui applies itself rather
+// than forwarding them to the Flow Aggregator.
+func (filter *FlowStreamFilter) MatchesLocalFilters(f *apisv1.Flow) bool {
+	if len(filter.AppProtocols) > 0 {
+		if f.App == nil || !slices.ContainsFunc(filter.AppProtocols, func(p string) bool {
+			return strings.EqualFold(p, f.App.ProtocolName)
+		}) {
+			return false
+		}
+	}
+	if len(filter.ObservationDomainIDs) > 0 {
+		if f.IPFIX == nil || !slices.Contains(filter.ObservationDomainIDs, f.IPFIX.ObservationDomainId) {
+			return false
+		}
+	}
+	if len(filter.ExporterIPs) > 0 {
+		if f.IPFIX == nil || !slices.Contains(filter.ExporterIPs, f.IPFIX.ExporterIp) {
+			return false
+		}
+	}
+	return true
+}
+
+// FlowEncoding is the wire format of the flow records in SSE "flow" events.
+type FlowEncoding string
+
+const (
+	// FlowEncodingJSON sends an apisv1.FlowStreamEvent as JSON. It is the default.
+	FlowEncodingJSON FlowEncoding = "json"
+	// FlowEncodingProtobuf sends a base64-encoded flowpb.GetFlowsResponse, the Flow
+	// Aggregator's own message, with only its Flows field set. It is several times smaller than
+	// JSON, which carries every field name in every record.
+	FlowEncodingProtobuf FlowEncoding = "protobuf"
+)
+
+// FlowEncodingHeader tells the client which encoding the stream actually uses. It can differ from
+// the one requested: see negotiateEncoding.
+const FlowEncodingHeader = "X-Flow-Encoding"
+
+// flowStreamOutput is how the caller wants flow records shaped, as opposed to FlowStreamFilter,
+// which selects which records they get.
+type flowStreamOutput struct {
+	projection *FieldProjection
+	encoding   FlowEncoding
+}
+
+func parseFlowStreamOutput(c *gin.Context) (*flowStreamOutput, error) {
+	out := &flowStreamOutput{encoding: FlowEncodingJSON}
+	if fields := c.Query("fields"); fields != "" {
+		projection, err := ParseFieldProjection(fields)
+		if err != nil {
+			return nil, err
+		}
+		out.projection = projection
+	}
+	if enc := c.Query("encoding"); enc != "" {
+		switch FlowEncoding(strings.ToLower(enc)) {
+		case FlowEncodingJSON:
+			out.encoding = FlowEncodingJSON
+		case FlowEncodingProtobuf:
+			out.encoding = FlowEncodingProtobuf
+		default:
+			return nil, fmt.Errorf("invalid encoding value %q: expected one of json, protobuf", enc)
+		}
+	}
+	return out, nil
+}
+
+// negotiateEncoding returns the encoding the stream will use. The protobuf message carries IP
+// addresses as bytes, so it has no way to represent a hashed or truncated one: a caller whose
+// masking policy rewrites IPs gets JSON whatever they asked for, and learns that from the
+// FlowEncodingHeader response header.
+func negotiateEncoding(requested FlowEncoding, policy MaskingPolicy) FlowEncoding {
+	if requested == FlowEncodingProtobuf && policy.masksIPs() {
+		return FlowEncodingJSON
+	}
+	return requested
+}
+
+// encodeFlows renders flows as the data of one SSE "flow" event.
+func encodeFlows(flows []apisv1.Flow, output *flowStreamOutput) (string, error) {
+	if output.encoding == FlowEncodingProtobuf {
+		resp := &flowpb.GetFlowsResponse{Flows: make([]*flowpb.Flow, 0, len(flows))}
+		for idx := range flows {
+			if output.projection != nil {
+				output.projection.Clear(&flows[idx])
+			}
+			resp.Flows = append(resp.Flows, apiFlowToProto(&flows[idx]))
+		}
+		data, err := proto.Marshal(resp)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	var data []byte
+	var err error
+	if output.projection != nil {
+		projected := make([]map[string]interface{}, 0, len(flows))
+		for idx := range flows {
+			projected = append(projected, output.projection.Project(&flows[idx]))
+		}
+		data, err = json.Marshal(struct {
+			Flows []map[string]interface{} `json:"flows"`
+		}{Flows: projected})
+	} else {
+		data, err = json.Marshal(apisv1.FlowStreamEvent{Flows: flows})
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 // defaultKeepAliveInterval is how often the stream emits an SSE comment and re-checks its session.
@@ -70,6 +201,14 @@ type SSEHandler struct {
 	handler FlowStreamSubscriber
 	// keepAliveInterval is a field so tests do not have to wait seconds for a tick.
 	keepAliveInterval time.Duration
+	// masker, when set, redacts flow records according to the caller's groups, which are
+	// looked up once per stream with groupsFor. See EnableMasking.
+	masker    *Masker
+	groupsFor GroupsResolver
+	// limiter bounds how many streams are open at once. See SetStreamLimits.
+	limiter *streamLimiter
+	// recentFlows holds the flows recently sent to each user. See RecentFlow.
+	recentFlows *recentFlows
 }
 
 func NewSSEHandler(logger logr.Logger, handler FlowStreamSubscriber) *SSEHandler {
@@ -77,9 +216,54 @@ func NewSSEHandler(logger logr.Logger, handler FlowStreamSubscriber) *SSEHandler
 		logger:            logger,
 		handler:           handler,
 		keepAliveInterval: defaultKeepAliveInterval,
+		limiter:           newStreamLimiter(StreamLimits{}),
+		recentFlows:       newRecentFlows(recentFlowsCacheSize),
 	}
 }
 
+// SetStreamLimits replaces the default concurrency caps (see DefaultMaxStreams and
+// DefaultMaxStreamsPerUser). It must be called before the handler serves any request.
+func (h *SSEHandler) SetStreamLimits(limits StreamLimits) {
+	h.limiter = newStreamLimiter(limits)
+}
+
+// streamCapKey is the identity the per-user stream cap groups the caller's streams under. An empty
+// key means the stream is only subject to the global cap.
+//
+// ModeAdmin is exempt for the same reason it is exempt from the per-user session cap (see
+// session.perUserCapKey): everyone using the static admin password is the same literal "admin", so
+// a per-user budget would be shared by all of them, and with EvictOldest one admin would close
+// another's stream.
+func streamCapKey(ctx context.Context) string {
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok || ra.Mode == session.ModeAdmin {
+		return ""
+	}
+	return ra.Username
+}
+
+// EnableMasking makes the handler redact every flow record it sends, according to the policy
+// masker selects for the caller's groups. Groups are resolved once, when the stream opens: a group
+// membership change takes effect on the next stream, like any other RBAC change does for a
+// long-running watch.
+func (h *SSEHandler) EnableMasking(masker *Masker, groupsFor GroupsResolver) {
+	h.masker = masker
+	h.groupsFor = groupsFor
+}
+
+// maskingPolicyFor resolves the policy for the caller behind ctx. It fails closed: a caller whose
+// groups cannot be determined gets no stream at all, rather than the unmasked one.
+func (h *SSEHandler) maskingPolicyFor(ctx context.Context) (MaskingPolicy, error) {
+	if h.masker == nil {
+		return MaskingPolicy{}, nil
+	}
+	groups, err := h.groupsFor(ctx)
+	if err != nil {
+		return MaskingPolicy{}, err
+	}
+	return h.masker.PolicyFor(groups), nil
+}
+
 // splitTrimmed splits s by comma and trims whitespace from each element,
 // omitting any elements that are empty after trimming.
 func splitTrimmed(s string) []string {
@@ -134,6 +318,21 @@ func parseFlowStreamFilter(c *gin.Context) (*FlowStreamFilter, error) {
 	if ips := c.Query("ips"); ips != "" {
 		filter.IPs = splitTrimmed(ips)
 	}
+	if protocols := c.Query("appProtocols"); protocols != "" {
+		filter.AppProtocols = splitTrimmed(protocols)
+	}
+	if ids := c.Query("observationDomainIds"); ids != "" {
+		for _, p := range splitTrimmed(ids) {
+			id, err := strconv.ParseUint(p, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid observationDomainIds value %q: expected an unsigned 32-bit integer", p)
+			}
+			filter.ObservationDomainIDs = append(filter.ObservationDomainIDs, uint32(id))
+		}
+	}
+	if ips := c.Query("exporterIps"); ips != "" {
+		filter.ExporterIPs = splitTrimmed(ips)
+	}
 	if dir := c.Query("direction"); dir != "" {
 		switch strings.ToLower(dir) {
 		case "from":
@@ -148,14 +347,50 @@ func parseFlowStreamFilter(c *gin.Context) (*FlowStreamFilter, error) {
 }
 
 // StreamFlows handles GET /api/v1/flows/stream as an SSE endpoint.
+//
+// Besides the filters (see parseFlowStreamFilter), the caller can shape the records it receives:
+// fields= restricts each record to a list of field paths (see FieldProjection), and
+// encoding=protobuf asks for the compact encoding (see FlowEncodingProtobuf). Both matter on large
+// clusters, where a browser behind a VPN would otherwise receive every field of every record as
+// JSON.
+//
+// A stream past the concurrency caps (see StreamLimits) is refused with 429. A stream evicted to
+// make room for a newer one of the same user ends with an "evicted" event.
 func (h *SSEHandler) StreamFlows(c *gin.Context) {
 	filter, err := parseFlowStreamFilter(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	output, err := parseFlowStreamOutput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The stream's context is cancelled with errStreamEvicted if the limiter closes it to make
+	// room for a newer stream of the same user.
+	ctx, cancel := context.WithCancelCause(c.Request.Context())
+	defer cancel(nil)
+	maskingPolicy, err := h.maskingPolicyFor(ctx)
+	if err != nil {
+		h.logger.Error(err, "Failed to resolve flow masking policy for caller")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to determine which flow data the caller may see"})
+		return
+	}
+	if err := maskingPolicy.checkFilter(filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	output.encoding = negotiateEncoding(output.encoding, maskingPolicy)
+
+	release, err := h.limiter.acquire(streamCapKey(ctx), cancel)
+	if err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	defer release()
 
-	ctx := c.Request.Context()
 	flowsCh, errCh := h.handler.Subscribe(ctx, filter)
 
 	// Set headers required for Server-Sent Events (SSE).
@@ -168,6 +403,8 @@ func (h *SSEHandler) StreamFlows(c *gin.Context) {
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Accel-Buffering", "no")
+	c.Header("Access-Control-Expose-Headers", FlowEncodingHeader)
+	c.Header(FlowEncodingHeader, string(output.encoding))
 
 	// Emit one SSE comment and flush before blocking on the first gRPC read. Otherwise, when the
 	// Flow Aggregator ring buffer is empty, the select below blocks indefinitely with no bytes
@@ -220,6 +457,14 @@ func (h *SSEHandler) StreamFlows(c *gin.Context) {
 		writePreamble(w)
 		select {
 		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), errStreamEvicted) {
+				// Tell the client, so that it does not reconnect and evict the newer
+				// stream in turn.
+				data, err := json.Marshal(apisv1.FlowStreamErrorEvent{Message: errStreamEvicted.Error()})
+				if err == nil {
+					c.SSEvent("evicted", string(data))
+				}
+			}
 			return false
 		case <-keepAlive.C:
 			if !sessionAlive() {
@@ -247,13 +492,18 @@ func (h *SSEHandler) StreamFlows(c *gin.Context) {
 				c.SSEvent("dropped", string(data))
 			}
 			if len(event.Flows) > 0 {
-				flowEvt := apisv1.FlowStreamEvent{Flows: event.Flows}
-				data, err := json.Marshal(flowEvt)
+				if h.masker != nil {
+					h.masker.MaskFlows(maskingPolicy, event.Flows)
+				}
+				if ra, ok := session.RequestAuthFrom(ctx); ok {
+					h.recentFlows.add(ra.Username, event.Flows, output.projection)
+				}
+				data, err := encodeFlows(event.Flows, output)
 				if err != nil {
 					h.logger.Error(err, "Failed to marshal flow event")
 					return true
 				}
-				c.SSEvent("flow", string(data))
+				c.SSEvent("flow", data)
 			}
 			return true
 		case streamErr, ok := <-errCh:
@@ -77,6 +77,20 @@ func TestParseFlowStreamFilter(t *testing.T) {
 				FlowTypes: []apisv1.FlowType{apisv1.FlowTypeToExternal, apisv1.FlowTypeFromExternal},
 			},
 		},
+		{
+			name:  "app and IPFIX filters",
+			query: "appProtocols=http,grpc&observationDomainIds=1,42&exporterIps=10.0.0.1",
+			expected: &FlowStreamFilter{
+				AppProtocols:         []string{"http", "grpc"},
+				ObservationDomainIDs: []uint32{1, 42},
+				ExporterIPs:          []string{"10.0.0.1"},
+			},
+		},
+		{
+			name:        "invalid observationDomainIds returns error",
+			query:       "observationDomainIds=-1",
+			expectError: true,
+		},
 		{
 			name:        "invalid flowType returns error",
 			query:       "flowTypes=unknown-type",
@@ -225,3 +239,36 @@ func TestStreamFlowsBadFilter(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
+
+func TestFlowStreamFilterMatchesLocalFilters(t *testing.T) {
+	httpFlow := apisv1.Flow{
+		App:   &apisv1.FlowApp{ProtocolName: "http"},
+		IPFIX: &apisv1.FlowIPFIX{ObservationDomainId: 42, ExporterIp: "10.0.0.1"},
+	}
+	plainFlow := apisv1.Flow{}
+
+	tests := []struct {
+		name         string
+		filter       FlowStreamFilter
+		matchesHTTP  bool
+		matchesPlain bool
+	}{
+		{name: "no filter", filter: FlowStreamFilter{}, matchesHTTP: true, matchesPlain: true},
+		{name: "app protocol case-insensitive", filter: FlowStreamFilter{AppProtocols: []string{"HTTP"}}, matchesHTTP: true},
+		{name: "other app protocol", filter: FlowStreamFilter{AppProtocols: []string{"grpc"}}},
+		{name: "observation domain", filter: FlowStreamFilter{ObservationDomainIDs: []uint32{1, 42}}, matchesHTTP: true},
+		{name: "exporter IP", filter: FlowStreamFilter{ExporterIPs: []string{"10.0.0.2"}}},
+		{
+			name:        "all filters",
+			filter:      FlowStreamFilter{AppProtocols: []string{"http"}, ObservationDomainIDs: []uint32{42}, ExporterIPs: []string{"10.0.0.1"}},
+			matchesHTTP: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matchesHTTP, tt.filter.MatchesLocalFilters(&httpFlow))
+			assert.Equal(t, tt.matchesPlain, tt.filter.MatchesLocalFilters(&plainFlow))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	// DefaultMaxStreams bounds how many flow streams the backend serves at once. Each one pins
+	// a goroutine, a gRPC stream to the Flow Aggregator and a keepalive ticker for up to 24
+	// hours.
+	DefaultMaxStreams = 100
+	// DefaultMaxStreamsPerUser bounds how many of those one identity may hold. A person needs
+	// one per flow-visibility tab.
+	DefaultMaxStreamsPerUser = 5
+)
+
+var (
+	// errTooManyStreams means the backend is serving as many streams as it allows.
+	errTooManyStreams = errors.New("too many active flow streams, try again later")
+	// errStreamEvicted is the cancellation cause of a stream closed to make room for a newer
+	// stream of the same user.
+	errStreamEvicted = errors.New("flow stream closed because the same user opened a newer one")
+)
+
+// StreamLimits bounds how many flow streams are open at once. Zero values fall back to the
+// Default* constants.
+type StreamLimits struct {
+	MaxStreams        int
+	MaxStreamsPerUser int
+	// EvictOldest makes a user who is at MaxStreamsPerUser lose their oldest stream, instead
+	// of being refused the new one. The global cap is never enforced by eviction: that would
+	// let one user close another's stream.
+	EvictOldest bool
+}
+
+func (l *StreamLimits) setDefaults() {
+	if l.MaxStreams <= 0 {
+		l.MaxStreams = DefaultMaxStreams
+	}
+	if l.MaxStreamsPerUser <= 0 {
+		l.MaxStreamsPerUser = DefaultMaxStreamsPerUser
+	}
+	if l.MaxStreamsPerUser > l.MaxStreams {
+		l.MaxStreamsPerUser = l.MaxStreams
+	}
+}
+
+type openStream struct {
+	capKey string
+	cancel context.CancelCauseFunc
+}
+
+// streamLimiter keeps track of the open flow streams.
+type streamLimiter struct {
+	limits StreamLimits
+	mutex  sync.Mutex
+	// streams is keyed by a sequence number, so that a lower key is an older stream.
+	streams map[uint64]*openStream
+	nextID  uint64
+}
+
+func newStreamLimiter(limits StreamLimits) *streamLimiter {
+	limits.setDefaults()
+	return &streamLimiter{
+		limits:  limits,
+		streams: make(map[uint64]*openStream),
+	}
+}
+
+// acquire registers a new stream grouped under capKey, whose context is cancelled with
+// errStreamEvicted if a newer stream evicts it. An empty capKey means the stream is only subject to
+// the global cap (see streamCapKey). The returned release function must be called when the stream
+// ends.
+func (l *streamLimiter) acquire(capKey string, cancel context.CancelCauseFunc) (func(), error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if capKey != "" {
+		if err := l.enforcePerUserLimitLocked(capKey); err != nil {
+			return nil, err
+		}
+	}
+	if len(l.streams) >= l.limits.MaxStreams {
+		return nil, errTooManyStreams
+	}
+	id := l.nextID
+	l.nextID++
+	l.streams[id] = &openStream{capKey: capKey, cancel: cancel}
+	return func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		delete(l.streams, id)
+	}, nil
+}
+
+// enforcePerUserLimitLocked makes room for one more stream grouped under capKey, by evicting that
+// user's oldest streams if EvictOldest is set, and fails otherwise. The caller must hold l.mutex.
+// The map holds at most MaxStreams entries, so a scan is cheap enough.
+func (l *streamLimiter) enforcePerUserLimitLocked(capKey string) error {
+	var owned []uint64
+	for id, s := range l.streams {
+		if s.capKey == capKey {
+			owned = append(owned, id)
+		}
+	}
+	if len(owned) < l.limits.MaxStreamsPerUser {
+		return nil
+	}
+	if !l.limits.EvictOldest {
+		return fmt.Errorf("too many active flow streams for this user (limit is %d), close one before opening another", l.limits.MaxStreamsPerUser)
+	}
+	slices.Sort(owned)
+	for _, id := range owned[:len(owned)-l.limits.MaxStreamsPerUser+1] {
+		// The evicted stream's own release is then a no-op.
+		l.streams[id].cancel(errStreamEvicted)
+		delete(l.streams, id)
+	}
+	return nil
+}
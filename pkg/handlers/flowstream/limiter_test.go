@@ -0,0 +1,157 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+)
+
+func TestStreamLimitsDefaults(t *testing.T) {
+	l := StreamLimits{}
+	l.setDefaults()
+	assert.Equal(t, StreamLimits{MaxStreams: DefaultMaxStreams, MaxStreamsPerUser: DefaultMaxStreamsPerUser}, l)
+
+	l = StreamLimits{MaxStreams: 2, MaxStreamsPerUser: 10}
+	l.setDefaults()
+	assert.Equal(t, 2, l.MaxStreamsPerUser, "the per-user cap cannot exceed the global one")
+}
+
+func TestStreamLimiterPerUser(t *testing.T) {
+	l := newStreamLimiter(StreamLimits{MaxStreams: 10, MaxStreamsPerUser: 2})
+	release1, err := l.acquire("alice", func(error) {})
+	require.NoError(t, err)
+	_, err = l.acquire("alice", func(error) {})
+	require.NoError(t, err)
+
+	_, err = l.acquire("alice", func(error) {})
+	assert.EqualError(t, err, "too many active flow streams for this user (limit is 2), close one before opening another")
+	_, err = l.acquire("bob", func(error) {})
+	assert.NoError(t, err, "the cap is per user")
+
+	release1()
+	_, err = l.acquire("alice", func(error) {})
+	assert.NoError(t, err, "releasing a stream frees its slot")
+}
+
+func TestStreamLimiterEvictOldest(t *testing.T) {
+	l := newStreamLimiter(StreamLimits{MaxStreams: 10, MaxStreamsPerUser: 2, EvictOldest: true})
+	causes := make([]error, 3)
+	var releases []func()
+	for idx := range causes {
+		release, err := l.acquire("alice", func(cause error) { causes[idx] = cause })
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+	assert.ErrorIs(t, causes[0], errStreamEvicted, "the oldest stream should have been evicted")
+	assert.NoError(t, causes[1])
+	assert.NoError(t, causes[2])
+
+	// The evicted stream's release must not free someone else's slot.
+	releases[0]()
+	_, err := l.acquire("bob", func(error) {})
+	require.NoError(t, err)
+	assert.Len(t, l.streams, 3)
+}
+
+func TestStreamLimiterGlobal(t *testing.T) {
+	l := newStreamLimiter(StreamLimits{MaxStreams: 2, MaxStreamsPerUser: 2, EvictOldest: true})
+	_, err := l.acquire("alice", func(error) {})
+	require.NoError(t, err)
+	_, err = l.acquire("", func(error) {})
+	require.NoError(t, err)
+
+	evicted := false
+	_, err = l.acquire("bob", func(error) { evicted = true })
+	assert.ErrorIs(t, err, errTooManyStreams)
+	assert.False(t, evicted, "the global cap is never enforced by evicting another user's stream")
+}
+
+// blockingSubscriber never sends anything, so that streams stay open until their context ends.
+type blockingSubscriber struct{}
+
+func (blockingSubscriber) Subscribe(ctx context.Context, _ *FlowStreamFilter) (<-chan apisv1.FlowStreamEvent, <-chan error) {
+	flowsCh := make(chan apisv1.FlowStreamEvent)
+	go func() {
+		<-ctx.Done()
+		close(flowsCh)
+	}()
+	return flowsCh, make(chan error)
+}
+
+func TestStreamFlowsLimits(t *testing.T) {
+	newServer := func(t *testing.T, limits StreamLimits, ra *session.RequestAuth) *httptest.Server {
+		sseHandler := NewSSEHandler(testr.New(t), blockingSubscriber{})
+		sseHandler.SetStreamLimits(limits)
+		router := gin.New()
+		router.GET("/api/v1/flows/stream", func(c *gin.Context) {
+			c.Request = c.Request.WithContext(session.WithRequestAuth(c.Request.Context(), ra))
+		}, sseHandler.StreamFlows)
+		ts := httptest.NewServer(router)
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	open := func(t *testing.T, ts *httptest.Server) *http.Response {
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream")
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	alice := &session.RequestAuth{Mode: session.ModeOIDC, Username: "alice"}
+
+	t.Run("refused", func(t *testing.T) {
+		ts := newServer(t, StreamLimits{MaxStreamsPerUser: 1}, alice)
+		assert.Equal(t, http.StatusOK, open(t, ts).StatusCode)
+		resp := open(t, ts)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("oldest evicted", func(t *testing.T) {
+		ts := newServer(t, StreamLimits{MaxStreamsPerUser: 1, EvictOldest: true}, alice)
+		first := open(t, ts)
+		require.Equal(t, http.StatusOK, first.StatusCode)
+		scanner := bufio.NewScanner(first.Body)
+		// wait for the preamble, so that the first stream is registered
+		require.True(t, scanner.Scan())
+
+		assert.Equal(t, http.StatusOK, open(t, ts).StatusCode)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		assert.Contains(t, lines, "event:evicted")
+		assert.True(t, strings.Contains(strings.Join(lines, "\n"), errStreamEvicted.Error()))
+	})
+
+	t.Run("admin-password callers only share the global cap", func(t *testing.T) {
+		admin := &session.RequestAuth{Mode: session.ModeAdmin, Username: "admin"}
+		ts := newServer(t, StreamLimits{MaxStreams: 2, MaxStreamsPerUser: 1}, admin)
+		assert.Equal(t, http.StatusOK, open(t, ts).StatusCode)
+		assert.Equal(t, http.StatusOK, open(t, ts).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, open(t, ts).StatusCode)
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+// IPMaskMode selects how IP addresses in a flow record are masked.
+type IPMaskMode string
+
+const (
+	IPMaskModeNone     IPMaskMode = "none"
+	IPMaskModeHash     IPMaskMode = "hash"
+	IPMaskModeTruncate IPMaskMode = "truncate"
+)
+
+const (
+	defaultIPv4MaskPrefixLength = 24
+	defaultIPv6MaskPrefixLength = 64
+)
+
+// MaskingPolicy describes how flow records are redacted before they are sent to a caller. The zero
+// value masks nothing.
+type MaskingPolicy struct {
+	IPs IPMaskMode
+	// IPv4PrefixLength and IPv6PrefixLength are the prefix lengths kept by IPMaskModeTruncate.
+	// Zero means the default (/24 and /64).
+	IPv4PrefixLength int
+	IPv6PrefixLength int
+	// StripPodLabels drops SourcePodLabels and DestinationPodLabels.
+	StripPodLabels bool
+	// HideEgressIPs drops the Egress IP, whatever IPs is set to.
+	HideEgressIPs bool
+}
+
+// IsZero reports whether p leaves flow records untouched.
+func (p MaskingPolicy) IsZero() bool {
+	return (p.IPs == "" || p.IPs == IPMaskModeNone) && !p.StripPodLabels && !p.HideEgressIPs
+}
+
+// masksIPs reports whether p rewrites IP addresses.
+func (p MaskingPolicy) masksIPs() bool {
+	return p.IPs != "" && p.IPs != IPMaskModeNone
+}
+
+// checkFilter rejects the filters which match a field that p masks. They are evaluated on the raw
+// records, before masking, so a caller could otherwise try candidate addresses or labels and learn
+// which ones return flows, i.e. reverse the masked values.
+func (p MaskingPolicy) checkFilter(filter *FlowStreamFilter) error {
+	if p.masksIPs() && len(filter.IPs) > 0 {
+		return fmt.Errorf("the ips filter is not available, as IP addresses are masked for the caller")
+	}
+	if p.masksIPs() && len(filter.ExporterIPs) > 0 {
+		return fmt.Errorf("the exporterIps filter is not available, as IP addresses are masked for the caller")
+	}
+	if p.StripPodLabels && filter.PodLabelSelector != "" {
+		return fmt.Errorf("the podLabelSelector filter is not available, as Pod labels are masked for the caller")
+	}
+	return nil
+}
+
+func (p MaskingPolicy) validate() error {
+	switch p.IPs {
+	case "", IPMaskModeNone, IPMaskModeHash, IPMaskModeTruncate:
+	default:
+		return fmt.Errorf("invalid IP masking mode %q: expected one of none, hash, truncate", p.IPs)
+	}
+	if p.IPv4PrefixLength < 0 || p.IPv4PrefixLength > 32 {
+		return fmt.Errorf("invalid IPv4 prefix length %d: it should be >= 0 and <= 32", p.IPv4PrefixLength)
+	}
+	if p.IPv6PrefixLength < 0 || p.IPv6PrefixLength > 128 {
+		return fmt.Errorf("invalid IPv6 prefix length %d: it should be >= 0 and <= 128", p.IPv6PrefixLength)
+	}
+	return nil
+}
+
+// MaskingRule applies Policy to any caller that belongs to at least one of Groups.
+type MaskingRule struct {
+	Groups []string
+	Policy MaskingPolicy
+}
+
+// GroupsResolver returns the Kubernetes groups of the caller behind ctx. ctx carries the identity
+// resolved by the authentication middleware.
+type GroupsResolver func(ctx context.Context) ([]string, error)
+
+// Masker picks a MaskingPolicy for a caller and applies it to flow records.
+//
+// Masking happens here, in the backend, rather than in the frontend: a field the browser never
+// receives is the only one a viewer is guaranteed not to see.
+type Masker struct {
+	rules         []MaskingRule
+	defaultPolicy MaskingPolicy
+	// hashKey keys the HMAC used by IPMaskModeHash. A plain hash of an IPv4 address can be
+	// reversed by enumerating the address space; a keyed one cannot, while still mapping the same
+	// address to the same token, so traffic patterns stay visible. The key is per-process, so
+	// tokens are only stable until the backend restarts.
+	hashKey []byte
+}
+
+// NewMasker builds a Masker. Rules are evaluated in order and the first one naming any of the
+// caller's groups wins; defaultPolicy applies to callers that no rule matches.
+func NewMasker(rules []MaskingRule, defaultPolicy MaskingPolicy) (*Masker, error) {
+	for idx := range rules {
+		if len(rules[idx].Groups) == 0 {
+			return nil, fmt.Errorf("masking rule %d has no groups", idx)
+		}
+		if err := rules[idx].Policy.validate(); err != nil {
+			return nil, fmt.Errorf("masking rule %d: %w", idx, err)
+		}
+	}
+	if err := defaultPolicy.validate(); err != nil {
+		return nil, fmt.Errorf("default masking policy: %w", err)
+	}
+	hashKey := make([]byte, 32)
+	if _, err := rand.Read(hashKey); err != nil {
+		return nil, fmt.Errorf("failed to generate masking hash key: %w", err)
+	}
+	return &Masker{
+		rules:         rules,
+		defaultPolicy: defaultPolicy,
+		hashKey:       hashKey,
+	}, nil
+}
+
+// PolicyFor returns the policy that applies to a caller belonging to groups.
+func (m *Masker) PolicyFor(groups []string) MaskingPolicy {
+	groupSet := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		groupSet[g] = true
+	}
+	for _, rule := range m.rules {
+		for _, g := range rule.Groups {
+			if groupSet[g] {
+				return rule.Policy
+			}
+		}
+	}
+	return m.defaultPolicy
+}
+
+// MaskFlows applies policy to flows in place.
+func (m *Masker) MaskFlows(policy MaskingPolicy, flows []apisv1.Flow) {
+	if policy.IsZero() {
+		return
+	}
+	for idx := range flows {
+		m.maskFlow(policy, &flows[idx])
+	}
+}
+
+func (m *Masker) maskFlow(policy MaskingPolicy, f *apisv1.Flow) {
+	f.IP.Source = m.maskIP(policy, f.IP.Source)
+	f.IP.Destination = m.maskIP(policy, f.IP.Destination)
+	f.K8s.DestinationClusterIp = m.maskIP(policy, f.K8s.DestinationClusterIp)
+	if policy.HideEgressIPs {
+		f.K8s.EgressIp = ""
+	} else {
+		f.K8s.EgressIp = m.maskIP(policy, f.K8s.EgressIp)
+	}
+	if f.IPFIX != nil {
+		f.IPFIX.ExporterIp = m.maskIP(policy, f.IPFIX.ExporterIp)
+	}
+	// The HTTP transaction data is free-form, and may name hosts and addresses (e.g. in the Host
+	// header or the URL), so it cannot be masked field by field.
+	if f.App != nil && policy.masksIPs() {
+		f.App.HTTPVals = ""
+	}
+	if policy.StripPodLabels {
+		f.K8s.SourcePodLabels = nil
+		f.K8s.DestinationPodLabels = nil
+	}
+}
+
+func (m *Masker) maskIP(policy MaskingPolicy, ip string) string {
+	if ip == "" {
+		return ""
+	}
+	switch policy.IPs {
+	case IPMaskModeHash:
+		mac := hmac.New(sha256.New, m.hashKey)
+		mac.Write([]byte(ip))
+		return "ip-" + hex.EncodeToString(mac.Sum(nil)[:8])
+	case IPMaskModeTruncate:
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			// Not an address we can truncate (e.g. the <invalid-ip:...> placeholder):
+			// drop it rather than pass it through unmasked.
+			return ""
+		}
+		addr = addr.Unmap()
+		bits := policy.IPv4PrefixLength
+		if bits == 0 {
+			bits = defaultIPv4MaskPrefixLength
+		}
+		if addr.Is6() {
+			bits = policy.IPv6PrefixLength
+			if bits == 0 {
+				bits = defaultIPv6MaskPrefixLength
+			}
+		}
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			return ""
+		}
+		return prefix.String()
+	default:
+		return ip
+	}
+}
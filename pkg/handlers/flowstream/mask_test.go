@@ -0,0 +1,258 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+func testFlow() apisv1.Flow {
+	return apisv1.Flow{
+		ID: "flow-1",
+		IP: apisv1.FlowIP{
+			Version:     apisv1.IPVersionIPv4,
+			Source:      "10.0.1.17",
+			Destination: "fd00::1234:5678",
+		},
+		K8s: apisv1.FlowKubernetes{
+			SourcePodName:        "pod-a",
+			SourcePodLabels:      map[string]string{"app": "a"},
+			DestinationPodLabels: map[string]string{"app": "b"},
+			DestinationClusterIp: "10.96.0.10",
+			EgressIp:             "172.16.0.1",
+		},
+		IPFIX: &apisv1.FlowIPFIX{ObservationDomainId: 1, ExporterIp: "192.168.77.3"},
+		App:   &apisv1.FlowApp{ProtocolName: "http", HTTPVals: `{"0":{"hostname":"10.0.2.9"}}`},
+	}
+}
+
+func TestNewMaskerValidation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         []MaskingRule
+		defaultPolicy MaskingPolicy
+		expectedErr   string
+	}{
+		{
+			name:          "valid",
+			rules:         []MaskingRule{{Groups: []string{"viewers"}, Policy: MaskingPolicy{IPs: IPMaskModeHash}}},
+			defaultPolicy: MaskingPolicy{IPs: IPMaskModeTruncate, IPv4PrefixLength: 16},
+		},
+		{
+			name:        "rule without groups",
+			rules:       []MaskingRule{{Policy: MaskingPolicy{IPs: IPMaskModeHash}}},
+			expectedErr: "masking rule 0 has no groups",
+		},
+		{
+			name:          "invalid mode",
+			defaultPolicy: MaskingPolicy{IPs: "scramble"},
+			expectedErr:   `default masking policy: invalid IP masking mode "scramble": expected one of none, hash, truncate`,
+		},
+		{
+			name:        "invalid prefix length",
+			rules:       []MaskingRule{{Groups: []string{"viewers"}, Policy: MaskingPolicy{IPs: IPMaskModeTruncate, IPv6PrefixLength: 129}}},
+			expectedErr: "masking rule 0: invalid IPv6 prefix length 129: it should be >= 0 and <= 128",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewMasker(tc.rules, tc.defaultPolicy)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaskerPolicyFor(t *testing.T) {
+	restricted := MaskingPolicy{IPs: IPMaskModeHash, StripPodLabels: true}
+	partial := MaskingPolicy{HideEgressIPs: true}
+	m, err := NewMasker([]MaskingRule{
+		{Groups: []string{"flow-admins"}, Policy: MaskingPolicy{}},
+		{Groups: []string{"contractors", "auditors"}, Policy: partial},
+	}, restricted)
+	require.NoError(t, err)
+
+	assert.Equal(t, MaskingPolicy{}, m.PolicyFor([]string{"system:authenticated", "flow-admins"}))
+	assert.Equal(t, partial, m.PolicyFor([]string{"auditors"}))
+	// the first matching rule wins
+	assert.Equal(t, MaskingPolicy{}, m.PolicyFor([]string{"auditors", "flow-admins"}))
+	assert.Equal(t, restricted, m.PolicyFor([]string{"system:authenticated"}))
+	assert.Equal(t, restricted, m.PolicyFor(nil))
+}
+
+func TestMaskFlows(t *testing.T) {
+	m, err := NewMasker(nil, MaskingPolicy{})
+	require.NoError(t, err)
+
+	t.Run("zero policy", func(t *testing.T) {
+		flows := []apisv1.Flow{testFlow()}
+		m.MaskFlows(MaskingPolicy{}, flows)
+		assert.Equal(t, testFlow(), flows[0])
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		flows := []apisv1.Flow{testFlow()}
+		m.MaskFlows(MaskingPolicy{IPs: IPMaskModeTruncate}, flows)
+		assert.Equal(t, "10.0.1.0/24", flows[0].IP.Source)
+		assert.Equal(t, "fd00::/64", flows[0].IP.Destination)
+		assert.Equal(t, "10.96.0.0/24", flows[0].K8s.DestinationClusterIp)
+		assert.Equal(t, "172.16.0.0/24", flows[0].K8s.EgressIp)
+		assert.Equal(t, "192.168.77.0/24", flows[0].IPFIX.ExporterIp)
+		assert.Empty(t, flows[0].App.HTTPVals)
+		assert.Equal(t, "http", flows[0].App.ProtocolName)
+		assert.Equal(t, map[string]string{"app": "a"}, flows[0].K8s.SourcePodLabels)
+	})
+
+	t.Run("truncate with custom prefix lengths", func(t *testing.T) {
+		flows := []apisv1.Flow{testFlow()}
+		m.MaskFlows(MaskingPolicy{IPs: IPMaskModeTruncate, IPv4PrefixLength: 8, IPv6PrefixLength: 16}, flows)
+		assert.Equal(t, "10.0.0.0/8", flows[0].IP.Source)
+		assert.Equal(t, "fd00::/16", flows[0].IP.Destination)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		flows := []apisv1.Flow{testFlow(), testFlow()}
+		flows[1].IP.Source, flows[1].IP.Destination = flows[1].IP.Destination, flows[1].IP.Source
+		m.MaskFlows(MaskingPolicy{IPs: IPMaskModeHash}, flows)
+		assert.True(t, strings.HasPrefix(flows[0].IP.Source, "ip-"))
+		assert.NotContains(t, flows[0].IP.Source, "10.0.1.17")
+		assert.NotEqual(t, flows[0].IP.Source, flows[0].IP.Destination)
+		// the same address maps to the same token, so traffic patterns are preserved
+		assert.Equal(t, flows[0].IP.Source, flows[1].IP.Destination)
+		assert.Equal(t, flows[0].IP.Destination, flows[1].IP.Source)
+	})
+
+	t.Run("strip labels and hide egress IP", func(t *testing.T) {
+		flows := []apisv1.Flow{testFlow()}
+		m.MaskFlows(MaskingPolicy{StripPodLabels: true, HideEgressIPs: true}, flows)
+		assert.Nil(t, flows[0].K8s.SourcePodLabels)
+		assert.Nil(t, flows[0].K8s.DestinationPodLabels)
+		assert.Empty(t, flows[0].K8s.EgressIp)
+		assert.Equal(t, "10.0.1.17", flows[0].IP.Source)
+		assert.Equal(t, "pod-a", flows[0].K8s.SourcePodName)
+		assert.Equal(t, testFlow().App, flows[0].App)
+	})
+
+	t.Run("empty addresses stay empty", func(t *testing.T) {
+		flows := []apisv1.Flow{{ID: "no-ip"}}
+		m.MaskFlows(MaskingPolicy{IPs: IPMaskModeHash}, flows)
+		assert.Empty(t, flows[0].IP.Source)
+		assert.Empty(t, flows[0].K8s.EgressIp)
+	})
+}
+
+func TestStreamFlowsMasking(t *testing.T) {
+	newServer := func(t *testing.T, groupsFor GroupsResolver) *httptest.Server {
+		stub := &stubFlowStreamSubscriber{
+			events: []apisv1.FlowStreamEvent{{Flows: []apisv1.Flow{testFlow()}}},
+		}
+		m, err := NewMasker([]MaskingRule{
+			{Groups: []string{"flow-admins"}, Policy: MaskingPolicy{}},
+		}, MaskingPolicy{IPs: IPMaskModeTruncate, StripPodLabels: true})
+		require.NoError(t, err)
+		sseHandler := NewSSEHandler(testr.New(t), stub)
+		sseHandler.EnableMasking(m, groupsFor)
+		ts := httptest.NewServer(newTestRouter(sseHandler))
+		t.Cleanup(ts.Close)
+		return ts
+	}
+
+	readFlow := func(t *testing.T, resp *http.Response) apisv1.Flow {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if data, ok := strings.CutPrefix(line, "data:"); ok {
+				var event apisv1.FlowStreamEvent
+				require.NoError(t, json.Unmarshal([]byte(data), &event))
+				require.Len(t, event.Flows, 1)
+				return event.Flows[0]
+			}
+		}
+		require.NoError(t, scanner.Err())
+		t.Fatal("no flow event in SSE stream")
+		return apisv1.Flow{}
+	}
+
+	t.Run("unprivileged caller", func(t *testing.T) {
+		ts := newServer(t, func(context.Context) ([]string, error) {
+			return []string{"system:authenticated"}, nil
+		})
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		flow := readFlow(t, resp)
+		assert.Equal(t, "10.0.1.0/24", flow.IP.Source)
+		assert.Nil(t, flow.K8s.SourcePodLabels)
+	})
+
+	t.Run("privileged caller", func(t *testing.T) {
+		ts := newServer(t, func(context.Context) ([]string, error) {
+			return []string{"system:authenticated", "flow-admins"}, nil
+		})
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, testFlow(), readFlow(t, resp))
+	})
+
+	t.Run("filters on masked fields", func(t *testing.T) {
+		ts := newServer(t, func(context.Context) ([]string, error) {
+			return []string{"system:authenticated"}, nil
+		})
+		for _, query := range []string{"ips=10.0.1.17", "podLabelSelector=app%3Da", "exporterIps=192.168.77.3"} {
+			resp, err := http.Get(ts.URL + "/api/v1/flows/stream?" + query)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+
+	t.Run("filters for privileged caller", func(t *testing.T) {
+		ts := newServer(t, func(context.Context) ([]string, error) {
+			return []string{"flow-admins"}, nil
+		})
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream?ips=10.0.1.17&podLabelSelector=app%3Da")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, testFlow(), readFlow(t, resp))
+	})
+
+	t.Run("groups cannot be resolved", func(t *testing.T) {
+		ts := newServer(t, func(context.Context) ([]string, error) {
+			return nil, fmt.Errorf("API server unavailable")
+		})
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
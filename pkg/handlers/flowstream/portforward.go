@@ -0,0 +1,225 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"antrea.io/antrea-ui/pkg/utils/portforwarder"
+)
+
+const (
+	flowAggregatorSvcName = "flow-aggregator"
+	// portForwardPeriod is how often a broken port-forward is re-established, and how often the
+	// Pod behind a running one is checked.
+	portForwardPeriod = 5 * time.Second
+)
+
+// PortForwardConfig configures how the Flow Aggregator is reached when the backend runs
+// out-of-cluster, where the Service address does not resolve.
+type PortForwardConfig struct {
+	RESTConfig *rest.Config
+	// Namespace is where the flow-aggregator Service and its Pod are.
+	Namespace string
+}
+
+// flowAggregatorForwarder maintains a port-forward to the Pod behind the flow-aggregator Service,
+// and dials the gRPC connection through it.
+type flowAggregatorForwarder struct {
+	logger     logr.Logger
+	config     *rest.Config
+	kubeClient kubernetes.Interface
+	namespace  string
+	// svcPort is the Service port the gRPC server is exposed on, taken from the configured
+	// address.
+	svcPort   int32
+	hostMutex sync.RWMutex
+	host      string
+}
+
+func newFlowAggregatorForwarder(logger logr.Logger, cfg *PortForwardConfig, kubeClient kubernetes.Interface, address string) (*flowAggregatorForwarder, error) {
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FlowAggregator address %q: %w", address, err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FlowAggregator address %q: %w", address, err)
+	}
+	return &flowAggregatorForwarder{
+		logger:     logger,
+		config:     cfg.RESTConfig,
+		kubeClient: kubeClient,
+		namespace:  cfg.Namespace,
+		svcPort:    int32(port),
+	}, nil
+}
+
+// Run keeps a port-forward up until stopCh is closed, re-establishing it when it breaks or when
+// the Pod it points to is replaced.
+func (f *flowAggregatorForwarder) Run(stopCh <-chan struct{}) {
+	ctx := wait.ContextForChannel(stopCh)
+	wait.UntilWithContext(ctx, f.forwardOnce, portForwardPeriod)
+}
+
+func (f *flowAggregatorForwarder) forwardOnce(ctx context.Context) {
+	pod, targetPort, err := f.discover(ctx)
+	if err != nil {
+		f.logger.Error(err, "Failed to discover FlowAggregator Pod")
+		return
+	}
+	// use a random local port for listening
+	pf, err := portforwarder.NewPortForwarder(f.config, pod.Namespace, pod.Name, targetPort, "localhost", 0)
+	if err != nil {
+		f.logger.Error(err, "Failed to create port forwarder")
+		return
+	}
+	fwdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	portCh := make(chan int)
+	go func() {
+		if err := pf.Run(fwdCtx.Done(), portCh); err != nil {
+			f.logger.Error(err, "Port forwarding error")
+		}
+		close(portCh)
+	}()
+	// A port-forward notices when its connection to the kubelet is lost, but a Pod can be
+	// replaced (e.g. by a rollout) without that happening promptly, so check on it too.
+	go wait.UntilWithContext(fwdCtx, func(ctx context.Context) {
+		if !f.podIsCurrent(ctx, pod) {
+			f.logger.Info("FlowAggregator Pod was replaced, re-establishing port forwarding", "pod", podRef(pod))
+			cancel()
+		}
+	}, portForwardPeriod)
+	for port := range portCh {
+		host := fmt.Sprintf("localhost:%d", port)
+		f.setHost(host)
+		f.logger.Info("Port forwarding is running for FlowAggregator", "pod", podRef(pod), "listenAddr", host)
+	}
+	f.setHost("")
+}
+
+// discover returns a running Pod selected by the flow-aggregator Service, and the container port
+// its gRPC server listens on.
+func (f *flowAggregatorForwarder) discover(ctx context.Context) (*corev1.Pod, int, error) {
+	svc, err := f.kubeClient.CoreV1().Services(f.namespace).Get(ctx, flowAggregatorSvcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get FlowAggregator Service: %w", err)
+	}
+	var svcPort *corev1.ServicePort
+	for idx := range svc.Spec.Ports {
+		if svc.Spec.Ports[idx].Port == f.svcPort {
+			svcPort = &svc.Spec.Ports[idx]
+			break
+		}
+	}
+	if svcPort == nil {
+		return nil, 0, fmt.Errorf("no port %d in Service %s/%s", f.svcPort, f.namespace, flowAggregatorSvcName)
+	}
+	pods, err := f.kubeClient.CoreV1().Pods(f.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list FlowAggregator Service Pods: %w", err)
+	}
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		targetPort, err := resolveTargetPort(pod, svcPort)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pod, targetPort, nil
+	}
+	return nil, 0, fmt.Errorf("no running Pod found for Service %s/%s", f.namespace, flowAggregatorSvcName)
+}
+
+// resolveTargetPort returns the container port that svcPort forwards to on pod.
+func resolveTargetPort(pod *corev1.Pod, svcPort *corev1.ServicePort) (int, error) {
+	switch {
+	case svcPort.TargetPort.Type == intstr.String:
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == svcPort.TargetPort.StrVal {
+					return int(p.ContainerPort), nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("no port named %q in Pod %s", svcPort.TargetPort.StrVal, podRef(pod))
+	case svcPort.TargetPort.IntVal != 0:
+		return int(svcPort.TargetPort.IntVal), nil
+	default:
+		// targetPort defaults to the Service port
+		return int(svcPort.Port), nil
+	}
+}
+
+// podIsCurrent reports whether pod is still the one to forward to. Errors other than NotFound
+// count as current: a transient API server error should not tear down a working port-forward.
+func (f *flowAggregatorForwarder) podIsCurrent(ctx context.Context, pod *corev1.Pod) bool {
+	current, err := f.kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return current.UID == pod.UID && current.DeletionTimestamp == nil
+}
+
+func (f *flowAggregatorForwarder) setHost(host string) {
+	f.hostMutex.Lock()
+	defer f.hostMutex.Unlock()
+	f.host = host
+}
+
+func (f *flowAggregatorForwarder) getHost() string {
+	f.hostMutex.RLock()
+	defer f.hostMutex.RUnlock()
+	return f.host
+}
+
+// dial is the gRPC dialer: it ignores the target address and connects to the local end of the
+// port-forward instead. gRPC retries a failed dial with backoff, so a port-forward that is not up
+// yet (or is being re-established) only delays the connection.
+func (f *flowAggregatorForwarder) dial(ctx context.Context, _ string) (net.Conn, error) {
+	host := f.getHost()
+	if host == "" {
+		return nil, fmt.Errorf("port forwarding to the FlowAggregator is not ready")
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", host)
+}
+
+func podRef(pod *corev1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testFANamespace = "flow-aggregator"
+
+func testFAService(targetPort intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testFANamespace, Name: flowAggregatorSvcName},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "flow-aggregator"},
+			Ports: []corev1.ServicePort{
+				{Name: "ipfix-tcp", Port: 4739},
+				{Name: "grpc", Port: 14740, TargetPort: targetPort},
+			},
+		},
+	}
+}
+
+func testFAPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testFANamespace,
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+			Labels:    map[string]string{"app": "flow-aggregator"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "flow-aggregator",
+				Ports: []corev1.ContainerPort{{Name: "flow-grpc", ContainerPort: 14741}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newTestForwarder(t *testing.T, address string, objects ...runtime.Object) *flowAggregatorForwarder {
+	f, err := newFlowAggregatorForwarder(testr.New(t), &PortForwardConfig{Namespace: testFANamespace}, fake.NewSimpleClientset(objects...), address)
+	require.NoError(t, err)
+	return f
+}
+
+func TestNewFlowAggregatorForwarderInvalidAddress(t *testing.T) {
+	_, err := newFlowAggregatorForwarder(testr.New(t), &PortForwardConfig{}, fake.NewSimpleClientset(), "flow-aggregator.flow-aggregator.svc")
+	assert.ErrorContains(t, err, "invalid FlowAggregator address")
+}
+
+func TestFlowAggregatorForwarderDiscover(t *testing.T) {
+	const address = "flow-aggregator.flow-aggregator.svc:14740"
+	testCases := []struct {
+		name         string
+		objects      []runtime.Object
+		address      string
+		expectedPod  string
+		expectedPort int
+		expectedErr  string
+	}{
+		{
+			name:         "named target port",
+			objects:      []runtime.Object{testFAService(intstr.FromString("flow-grpc")), testFAPod("fa-1", corev1.PodRunning)},
+			expectedPod:  "fa-1",
+			expectedPort: 14741,
+		},
+		{
+			name:         "numeric target port",
+			objects:      []runtime.Object{testFAService(intstr.FromInt32(9000)), testFAPod("fa-1", corev1.PodRunning)},
+			expectedPod:  "fa-1",
+			expectedPort: 9000,
+		},
+		{
+			name:         "target port defaults to the Service port",
+			objects:      []runtime.Object{testFAService(intstr.IntOrString{}), testFAPod("fa-1", corev1.PodRunning)},
+			expectedPod:  "fa-1",
+			expectedPort: 14740,
+		},
+		{
+			name: "Pods that are not running are skipped",
+			objects: []runtime.Object{
+				testFAService(intstr.FromString("flow-grpc")),
+				testFAPod("fa-0", corev1.PodPending),
+				testFAPod("fa-1", corev1.PodRunning),
+			},
+			expectedPod:  "fa-1",
+			expectedPort: 14741,
+		},
+		{
+			name:        "no running Pod",
+			objects:     []runtime.Object{testFAService(intstr.FromString("flow-grpc")), testFAPod("fa-0", corev1.PodPending)},
+			expectedErr: "no running Pod found for Service flow-aggregator/flow-aggregator",
+		},
+		{
+			name:        "no matching Service port",
+			objects:     []runtime.Object{testFAService(intstr.FromString("flow-grpc")), testFAPod("fa-1", corev1.PodRunning)},
+			address:     "flow-aggregator.flow-aggregator.svc:1234",
+			expectedErr: "no port 1234 in Service flow-aggregator/flow-aggregator",
+		},
+		{
+			name:        "unknown named port",
+			objects:     []runtime.Object{testFAService(intstr.FromString("grpc")), testFAPod("fa-1", corev1.PodRunning)},
+			expectedErr: `no port named "grpc" in Pod flow-aggregator/fa-1`,
+		},
+		{
+			name:        "no Service",
+			expectedErr: "failed to get FlowAggregator Service",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := tc.address
+			if addr == "" {
+				addr = address
+			}
+			f := newTestForwarder(t, addr, tc.objects...)
+			pod, port, err := f.discover(context.Background())
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPod, pod.Name)
+			assert.Equal(t, tc.expectedPort, port)
+		})
+	}
+}
+
+func TestFlowAggregatorForwarderPodIsCurrent(t *testing.T) {
+	ctx := context.Background()
+	pod := testFAPod("fa-1", corev1.PodRunning)
+	f := newTestForwarder(t, "flow-aggregator:14740", pod)
+	assert.True(t, f.podIsCurrent(ctx, pod))
+
+	replaced := testFAPod("fa-1", corev1.PodRunning)
+	replaced.UID = "uid-new"
+	assert.False(t, f.podIsCurrent(ctx, replaced), "a Pod recreated with the same name is a different Pod")
+
+	terminating := pod.DeepCopy()
+	now := metav1.Now()
+	terminating.DeletionTimestamp = &now
+	f = newTestForwarder(t, "flow-aggregator:14740", terminating)
+	assert.False(t, f.podIsCurrent(ctx, pod))
+
+	f = newTestForwarder(t, "flow-aggregator:14740")
+	assert.False(t, f.podIsCurrent(ctx, pod), "a deleted Pod is not current")
+}
+
+func TestFlowAggregatorForwarderDialNotReady(t *testing.T) {
+	f := newTestForwarder(t, "flow-aggregator:14740")
+	_, err := f.dial(context.Background(), "flow-aggregator:14740")
+	assert.EqualError(t, err, "port forwarding to the FlowAggregator is not ready")
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+// FieldProjection is the subset of apisv1.Flow fields a caller asked for with the fields= query
+// parameter. Paths use the JSON field names, with "." separating nested fields, e.g.
+// "ip.source" or "k8s.sourcePodName". Selecting a struct field selects all of its sub-fields.
+//
+// A nil *FieldProjection selects everything.
+type FieldProjection struct {
+	// children maps a JSON field name to the projection of that field. A nil child selects the
+	// whole field.
+	children map[string]*FieldProjection
+}
+
+var flowType = reflect.TypeOf(apisv1.Flow{})
+
+// jsonFieldName returns the JSON name of f, or "" if f is not serialized.
+func jsonFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// structType dereferences pointers, and returns nil if t is not a struct.
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.IsExported() && jsonFieldName(f) == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// ParseFieldProjection parses a comma-separated list of field paths. Unknown fields are an error,
+// so that a typo does not silently produce empty records.
+func ParseFieldProjection(s string) (*FieldProjection, error) {
+	paths := splitTrimmed(s)
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	root := &FieldProjection{children: map[string]*FieldProjection{}}
+	for _, path := range paths {
+		node := root
+		t := flowType
+		segments := strings.Split(path, ".")
+		for idx, segment := range segments {
+			f, ok := fieldByJSONName(t, segment)
+			if !ok {
+				return nil, fmt.Errorf("invalid fields value %q: unknown field %q", path, segment)
+			}
+			last := idx == len(segments)-1
+			child, seen := node.children[segment]
+			if seen && child == nil {
+				// the whole field is already selected
+				break
+			}
+			if last {
+				node.children[segment] = nil
+				break
+			}
+			t = structType(f.Type)
+			if t == nil {
+				return nil, fmt.Errorf("invalid fields value %q: field %q has no sub-fields", path, segment)
+			}
+			if child == nil {
+				child = &FieldProjection{children: map[string]*FieldProjection{}}
+				node.children[segment] = child
+			}
+			node = child
+		}
+	}
+	return root, nil
+}
+
+// Project returns the selected fields of f, keyed by their JSON names, ready to be marshalled.
+func (p *FieldProjection) Project(f *apisv1.Flow) map[string]interface{} {
+	return p.project(reflect.ValueOf(f).Elem())
+}
+
+func (p *FieldProjection) project(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, len(p.children))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonFieldName(sf)
+		child, ok := p.children[name]
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if child == nil {
+			out[name] = fv.Interface()
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		out[name] = child.project(fv)
+	}
+	return out
+}
+
+// Clear zeroes the fields of f that are not selected. Encodings that omit zero values, like
+// protobuf, then carry only the selected fields.
+func (p *FieldProjection) Clear(f *apisv1.Flow) {
+	p.clear(reflect.ValueOf(f).Elem())
+}
+
+func (p *FieldProjection) clear(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		child, ok := p.children[jsonFieldName(sf)]
+		switch {
+		case !ok:
+			fv.Set(reflect.Zero(sf.Type))
+		case child == nil:
+			// selected as a whole
+		case fv.Kind() == reflect.Pointer:
+			if !fv.IsNil() {
+				child.clear(fv.Elem())
+			}
+		default:
+			child.clear(fv)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	flowpb "antrea.io/antrea-ui/pkg/flowpb"
+)
+
+func TestParseFieldProjection(t *testing.T) {
+	testCases := []struct {
+		name        string
+		fields      string
+		expectedErr string
+	}{
+		{name: "top-level fields", fields: "id,startTs,stats"},
+		{name: "nested fields", fields: "ip.source, k8s.sourcePodName,transport.tcp.stateName"},
+		{name: "whole field and sub-field", fields: "ip,ip.source"},
+		{name: "unknown field", fields: "id,sourceIP", expectedErr: `invalid fields value "sourceIP": unknown field "sourceIP"`},
+		{name: "unknown sub-field", fields: "k8s.podName", expectedErr: `invalid fields value "k8s.podName": unknown field "podName"`},
+		{name: "no sub-fields", fields: "id.value", expectedErr: `invalid fields value "id.value": field "id" has no sub-fields`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParseFieldProjection(tc.fields)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, p)
+			}
+		})
+	}
+
+	p, err := ParseFieldProjection(" , ")
+	require.NoError(t, err)
+	assert.Nil(t, p, "an empty list selects everything")
+}
+
+func TestFieldProjectionProject(t *testing.T) {
+	f := testFlow()
+	f.Transport.TCP = &apisv1.FlowTCP{StateName: "ESTABLISHED"}
+
+	p, err := ParseFieldProjection("id,ip.source,k8s.sourcePodName,transport.tcp.stateName,ip.version")
+	require.NoError(t, err)
+	data, err := json.Marshal(p.Project(&f))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "flow-1",
+		"ip": {"source": "10.0.1.17", "version": 4},
+		"k8s": {"sourcePodName": "pod-a"},
+		"transport": {"tcp": {"stateName": "ESTABLISHED"}}
+	}`, string(data))
+
+	p, err = ParseFieldProjection("ip.source,ip")
+	require.NoError(t, err)
+	data, err = json.Marshal(p.Project(&f))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ip": {"version": 4, "source": "10.0.1.17", "destination": "fd00::1234:5678"}}`, string(data))
+}
+
+func TestFieldProjectionClear(t *testing.T) {
+	f := testFlow()
+	p, err := ParseFieldProjection("id,k8s.sourcePodLabels")
+	require.NoError(t, err)
+	p.Clear(&f)
+	assert.Equal(t, apisv1.Flow{
+		ID: "flow-1",
+		K8s: apisv1.FlowKubernetes{
+			SourcePodLabels: map[string]string{"app": "a"},
+		},
+	}, f)
+}
+
+func TestStreamFlowsEncoding(t *testing.T) {
+	flow := testFlow()
+	flow.StartTs = "2026-03-25T10:00:00Z"
+	flow.Stats = apisv1.FlowStats{PacketTotalCount: 10, Throughput: 100}
+
+	openStream := func(t *testing.T, masker *Masker, query string) (*http.Response, string) {
+		stub := &stubFlowStreamSubscriber{
+			events: []apisv1.FlowStreamEvent{{Flows: []apisv1.Flow{flow}}},
+		}
+		sseHandler := NewSSEHandler(testr.New(t), stub)
+		if masker != nil {
+			sseHandler.EnableMasking(masker, func(_ context.Context) ([]string, error) { return nil, nil })
+		}
+		ts := httptest.NewServer(newTestRouter(sseHandler))
+		t.Cleanup(ts.Close)
+		resp, err := http.Get(ts.URL + "/api/v1/flows/stream?" + query)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				return resp, data
+			}
+		}
+		return resp, ""
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		resp, data := openStream(t, nil, "encoding=protobuf&fields=id,ip.source,stats")
+		assert.Equal(t, "protobuf", resp.Header.Get(FlowEncodingHeader))
+		b, err := base64.StdEncoding.DecodeString(data)
+		require.NoError(t, err)
+		var msg flowpb.GetFlowsResponse
+		require.NoError(t, proto.Unmarshal(b, &msg))
+		require.Len(t, msg.Flows, 1)
+		got := protoFlowToAPI(msg.Flows[0])
+		assert.Equal(t, apisv1.Flow{
+			ID: "flow-1",
+			IP: apisv1.FlowIP{Source: "10.0.1.17"},
+			Stats: apisv1.FlowStats{
+				PacketTotalCount: 10,
+				Throughput:       100,
+			},
+		}, got)
+	})
+
+	t.Run("protobuf falls back to JSON when IPs are masked", func(t *testing.T) {
+		m, err := NewMasker(nil, MaskingPolicy{IPs: IPMaskModeHash})
+		require.NoError(t, err)
+		resp, data := openStream(t, m, "encoding=protobuf")
+		assert.Equal(t, "json", resp.Header.Get(FlowEncodingHeader))
+		var event apisv1.FlowStreamEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &event))
+		require.Len(t, event.Flows, 1)
+		assert.True(t, strings.HasPrefix(event.Flows[0].IP.Source, "ip-"))
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		resp, _ := openStream(t, nil, "encoding=msgpack")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		resp, _ := openStream(t, nil, "fields=bogus")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
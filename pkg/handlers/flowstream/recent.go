@@ -0,0 +1,92 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+)
+
+// recentFlowsCacheSize bounds the number of flows remembered across all users, see RecentFlow.
+const recentFlowsCacheSize = 10000
+
+type recentFlowKey struct {
+	user string
+	id   string
+}
+
+// recentFlows remembers the flows recently sent to each user, as they were sent (i.e. masked and
+// projected), so that a user can refer to one of them by ID. Only the fields that identify the
+// connection are kept.
+type recentFlows struct {
+	cache *lru.Cache[recentFlowKey, apisv1.Flow]
+}
+
+func newRecentFlows(size int) *recentFlows {
+	cache, err := lru.New[recentFlowKey, apisv1.Flow](size)
+	if err != nil {
+		panic(err)
+	}
+	return &recentFlows{cache: cache}
+}
+
+// add remembers flows, sent to user with projection, which may be nil.
+func (r *recentFlows) add(user string, flows []apisv1.Flow, projection *FieldProjection) {
+	for idx := range flows {
+		f := &flows[idx]
+		if f.ID == "" {
+			continue
+		}
+		flow := apisv1.Flow{
+			ID:        f.ID,
+			IP:        f.IP,
+			Transport: f.Transport,
+			K8s:       f.K8s,
+		}
+		flow.Transport.TCP = nil
+		flow.K8s.SourcePodLabels = nil
+		flow.K8s.DestinationPodLabels = nil
+		if projection != nil {
+			projection.Clear(&flow)
+			// The caller refers to the flow by its ID, even if it was not selected.
+			flow.ID = f.ID
+		}
+		r.cache.Add(recentFlowKey{user: user, id: f.ID}, flow)
+	}
+}
+
+func (r *recentFlows) get(user string, id string) (*apisv1.Flow, bool) {
+	flow, ok := r.cache.Get(recentFlowKey{user: user, id: id})
+	if !ok {
+		return nil, false
+	}
+	return &flow, true
+}
+
+// RecentFlow returns flow id, if it was recently sent to the caller behind ctx by one of their
+// streams. Flows are only remembered for the user they were sent to, and as they were sent, masked
+// and restricted to the fields= of the stream, so that this never reveals more than the stream
+// did. Only the IP, transport and Kubernetes fields of the flow are set, without Pod labels.
+func (h *SSEHandler) RecentFlow(ctx context.Context, id string) (*apisv1.Flow, bool) {
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok {
+		return nil, false
+	}
+	return h.recentFlows.get(ra.Username, id)
+}
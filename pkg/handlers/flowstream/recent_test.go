@@ -0,0 +1,88 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+)
+
+func TestRecentFlow(t *testing.T) {
+	h := NewSSEHandler(testr.New(t), blockingSubscriber{})
+	h.recentFlows = newRecentFlows(2)
+	h.recentFlows.add("alice", []apisv1.Flow{
+		{
+			ID:        "flow-1",
+			IP:        apisv1.FlowIP{Source: "10.0.0.1", Destination: "10.0.0.2"},
+			Transport: apisv1.FlowTransport{ProtocolNumber: 6, DestinationPort: 80, TCP: &apisv1.FlowTCP{StateName: "ESTABLISHED"}},
+			K8s:       apisv1.FlowKubernetes{SourcePodName: "client", SourcePodLabels: map[string]string{"app": "client"}},
+			Stats:     apisv1.FlowStats{PacketTotalCount: 10},
+		},
+		// Flows without an ID cannot be referred to.
+		{IP: apisv1.FlowIP{Source: "10.0.0.3"}},
+	}, nil)
+	ctxFor := func(username string) context.Context {
+		return session.WithRequestAuth(t.Context(), &session.RequestAuth{Mode: session.ModeOIDC, Username: username})
+	}
+
+	flow, ok := h.RecentFlow(ctxFor("alice"), "flow-1")
+	require.True(t, ok)
+	assert.Equal(t, apisv1.Flow{
+		ID:        "flow-1",
+		IP:        apisv1.FlowIP{Source: "10.0.0.1", Destination: "10.0.0.2"},
+		Transport: apisv1.FlowTransport{ProtocolNumber: 6, DestinationPort: 80},
+		K8s:       apisv1.FlowKubernetes{SourcePodName: "client"},
+	}, *flow)
+	// Flows are only remembered for the user they were sent to.
+	_, ok = h.RecentFlow(ctxFor("bob"), "flow-1")
+	assert.False(t, ok)
+	_, ok = h.RecentFlow(t.Context(), "flow-1")
+	assert.False(t, ok)
+
+	h.recentFlows.add("bob", []apisv1.Flow{{ID: "flow-2"}, {ID: "flow-3"}}, nil)
+	_, ok = h.RecentFlow(ctxFor("alice"), "flow-1")
+	assert.False(t, ok, "oldest flow should have been evicted")
+}
+
+func TestRecentFlowProjection(t *testing.T) {
+	h := NewSSEHandler(testr.New(t), blockingSubscriber{})
+	projection, err := ParseFieldProjection("ip.destination,transport")
+	require.NoError(t, err)
+	h.recentFlows.add("alice", []apisv1.Flow{
+		{
+			ID:        "flow-1",
+			IP:        apisv1.FlowIP{Source: "10.0.0.1", Destination: "10.0.0.2"},
+			Transport: apisv1.FlowTransport{ProtocolNumber: 6, DestinationPort: 80},
+			K8s:       apisv1.FlowKubernetes{SourcePodName: "client"},
+		},
+	}, projection)
+
+	ctx := session.WithRequestAuth(t.Context(), &session.RequestAuth{Mode: session.ModeOIDC, Username: "alice"})
+	flow, ok := h.RecentFlow(ctx, "flow-1")
+	require.True(t, ok)
+	// Only the fields the stream sent are remembered.
+	assert.Equal(t, apisv1.Flow{
+		ID:        "flow-1",
+		IP:        apisv1.FlowIP{Destination: "10.0.0.2"},
+		Transport: apisv1.FlowTransport{ProtocolNumber: 6, DestinationPort: 80},
+	}, *flow)
+}
@@ -0,0 +1,334 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packetcapture
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/clock"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/fileserver"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+)
+
+var (
+	// packetCaptureGVR is used when no CRD resolver is set.
+	packetCaptureGVR = schema.GroupVersionResource{
+		Group:    "crd.antrea.io",
+		Version:  "v1alpha1",
+		Resource: "packetcaptures",
+	}
+
+	packetCaptureLabels = map[string]string{
+		"ui.antrea.io": "",
+	}
+)
+
+const (
+	// The labels and annotations are the same as for Traceflows.
+	instanceLabel       = "ui.antrea.io/instance"
+	createdByAnnotation = "ui.antrea.io/created-by"
+	titleAnnotation     = "ui.antrea.io/title"
+
+	// The conditions Antrea sets on a PacketCapture, in this order.
+	conditionStarted      = "PacketCaptureStarted"
+	conditionComplete     = "PacketCaptureComplete"
+	conditionFileUploaded = "PacketCaptureFileUploaded"
+)
+
+// GCConfig controls the garbage collection of the PacketCaptures created by the handler.
+type GCConfig struct {
+	// InstanceID identifies the antrea-ui install, see traceflow.GCConfig.
+	InstanceID string
+	// ExpiryTimeout is the age past which a PacketCapture is deleted.
+	ExpiryTimeout time.Duration
+	// Period is how often the handler looks for expired PacketCaptures.
+	Period time.Duration
+}
+
+type requestsHandler struct {
+	logger logr.Logger
+	// gcClient is only used by the background GC loop.
+	gcClient dynamic.Interface
+	gcConfig GCConfig
+	clock    clock.Clock
+	// crdResolver picks the version of the PacketCapture CRD. When it is nil, v1alpha1 is
+	// used.
+	crdResolver crdversions.Resolver
+
+	// fileServer is the SFTP server that Antrea uploads pcap files to.
+	fileServer *fileserver.Server
+}
+
+func newRequestsHandlerWithClock(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig, fileServerConfig fileserver.Config, clock clock.Clock) (*requestsHandler, error) {
+	fileServer, err := fileserver.New(logger, fileServerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &requestsHandler{
+		logger:     logger,
+		gcClient:   gcClient,
+		gcConfig:   gcConfig,
+		clock:      clock,
+		fileServer: fileServer,
+	}, nil
+}
+
+func NewRequestsHandler(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig, fileServer fileserver.Config) (*requestsHandler, error) {
+	return newRequestsHandlerWithClock(logger, gcClient, gcConfig, fileServer, &clock.RealClock{})
+}
+
+// SetCRDResolver makes the handler use the version of the PacketCapture CRD picked by resolver. It
+// must be called before the handler is used.
+func (h *requestsHandler) SetCRDResolver(resolver crdversions.Resolver) {
+	h.crdResolver = resolver
+}
+
+func (h *requestsHandler) packetCaptureResource() schema.GroupVersionResource {
+	if h.crdResolver == nil {
+		return packetCaptureGVR
+	}
+	return h.crdResolver.GVR(crdversions.ResourcePacketCaptures)
+}
+
+func (h *requestsHandler) Run(stopCh <-chan struct{}) {
+	ctx := wait.ContextForChannel(stopCh)
+	//lint:ignore SA1019 apimachinery doesn't provide a correct alternative yet
+	go wait.BackoffUntil(func() { h.deleteExpiredPacketCaptures(ctx) }, wait.NewJitteredBackoffManager(h.gcConfig.Period, 0.0, h.clock), true, stopCh)
+	<-stopCh
+}
+
+func (h *requestsHandler) CreateRequest(ctx context.Context, client dynamic.Interface, request *Request) (string, error) {
+	requestID := uuid.NewString()
+	gvr := h.packetCaptureResource()
+	spec := maps.Clone(request.Object["spec"].(map[string]interface{}))
+	spec["fileServer"] = map[string]interface{}{
+		"url": h.fileServer.URL(),
+	}
+	pc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": gvr.GroupVersion().String(),
+			"kind":       "PacketCapture",
+			"metadata": map[string]interface{}{
+				"name": requestID,
+			},
+			"spec": spec,
+		},
+	}
+	pcLabels := maps.Clone(packetCaptureLabels)
+	pcLabels[instanceLabel] = h.gcConfig.InstanceID
+	pc.SetLabels(pcLabels)
+	annotations := map[string]string{}
+	if request.Username != "" {
+		annotations[createdByAnnotation] = request.Username
+	}
+	if request.Title != "" {
+		annotations[titleAnnotation] = request.Title
+	}
+	if len(annotations) > 0 {
+		pc.SetAnnotations(annotations)
+	}
+	if _, err := client.Resource(gvr).Create(ctx, pc, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	return requestID, nil
+}
+
+func (h *requestsHandler) GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error) {
+	pc, err := client.Resource(h.packetCaptureResource()).Get(ctx, requestID, metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	phase, _ := Phase(pc.Object)
+	return pc.Object, phase != "Running", nil
+}
+
+func (h *requestsHandler) DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error) {
+	err := client.Resource(h.packetCaptureResource()).Delete(ctx, requestID, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *requestsHandler) ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.PacketCaptureSummary, error) {
+	pcs, err := h.listPacketCaptures(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]apisv1.PacketCaptureSummary, 0, len(pcs))
+	for idx := range pcs {
+		summaries = append(summaries, packetCaptureSummary(&pcs[idx]))
+	}
+	// RFC 3339 timestamps in UTC sort chronologically as strings.
+	slices.SortStableFunc(summaries, func(a, b apisv1.PacketCaptureSummary) int {
+		return cmp.Compare(b.CreationTimestamp, a.CreationTimestamp)
+	})
+	return summaries, nil
+}
+
+func (h *requestsHandler) OpenFile(ctx context.Context, packetCapture map[string]interface{}) (io.ReadCloser, string, error) {
+	filePath, _, _ := unstructured.NestedString(packetCapture, "status", "filePath")
+	if phase, _ := Phase(packetCapture); phase != "Succeeded" || filePath == "" {
+		return nil, "", ErrNoFile
+	}
+	// Antrea reports a URL, but a bare path is accepted too.
+	fileURL, err := h.fileServer.Resolve(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := h.fileServer.Open(ctx, fileURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return f, path.Base(fileURL.Path), nil
+}
+
+// removeFile removes the file uploaded for packetCapture from the file server, if any.
+func (h *requestsHandler) removeFile(ctx context.Context, packetCapture map[string]interface{}) error {
+	filePath, _, _ := unstructured.NestedString(packetCapture, "status", "filePath")
+	if filePath == "" {
+		return nil
+	}
+	fileURL, err := h.fileServer.Resolve(filePath)
+	if err != nil {
+		// The file is not on the file server, antrea-ui has nothing to remove.
+		return nil
+	}
+	return h.fileServer.Remove(ctx, fileURL)
+}
+
+// listPacketCaptures lists the PacketCaptures created by this install.
+func (h *requestsHandler) listPacketCaptures(ctx context.Context, client dynamic.Interface) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(h.packetCaptureResource()).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(packetCaptureLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(list.Items, func(pc unstructured.Unstructured) bool {
+		return pc.GetLabels()[instanceLabel] != h.gcConfig.InstanceID
+	}), nil
+}
+
+// Phase derives the phase of a PacketCapture from its conditions: "Running", "Succeeded" once its
+// file is uploaded, or "Failed", with a message explaining why.
+func Phase(packetCapture map[string]interface{}) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(packetCapture, "status", "conditions")
+	byType := make(map[string]map[string]interface{})
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok {
+			conditionType, _ := condition["type"].(string)
+			byType[conditionType] = condition
+		}
+	}
+	failed := func(condition map[string]interface{}) (string, string) {
+		message, _ := condition["message"].(string)
+		if message == "" {
+			message, _ = condition["reason"].(string)
+		}
+		return "Failed", message
+	}
+	if condition, ok := byType[conditionFileUploaded]; ok {
+		if condition["status"] == string(metav1.ConditionTrue) {
+			return "Succeeded", ""
+		}
+		return failed(condition)
+	}
+	for _, conditionType := range []string{conditionStarted, conditionComplete} {
+		if condition, ok := byType[conditionType]; ok && condition["status"] == string(metav1.ConditionFalse) {
+			return failed(condition)
+		}
+	}
+	if condition, ok := byType[conditionComplete]; ok && condition["status"] == string(metav1.ConditionTrue) {
+		// Antrea uploads nothing if it captured no packet before the timeout.
+		if numberCaptured, _, _ := unstructured.NestedInt64(packetCapture, "status", "numberCaptured"); numberCaptured == 0 {
+			return "Failed", "No packet was captured"
+		}
+	}
+	return "Running", ""
+}
+
+func packetCaptureSummary(pc *unstructured.Unstructured) apisv1.PacketCaptureSummary {
+	annotations := pc.GetAnnotations()
+	summary := apisv1.PacketCaptureSummary{
+		ID:                pc.GetName(),
+		Title:             annotations[titleAnnotation],
+		CreatedBy:         annotations[createdByAnnotation],
+		CreationTimestamp: pc.GetCreationTimestamp().UTC().Format(time.RFC3339),
+	}
+	summary.Phase, summary.Message = Phase(pc.Object)
+	numberCaptured, _, _ := unstructured.NestedInt64(pc.Object, "status", "numberCaptured")
+	summary.NumberCaptured = int32(numberCaptured)
+	// The spec was written by antrea-ui, so a field of an unexpected type is just left empty.
+	for _, endpoint := range []struct {
+		field    string
+		endpoint *apisv1.PacketCaptureEndpoint
+	}{{"source", &summary.Source}, {"destination", &summary.Destination}} {
+		endpoint.endpoint.Namespace, _, _ = unstructured.NestedString(pc.Object, "spec", endpoint.field, "pod", "namespace")
+		endpoint.endpoint.Pod, _, _ = unstructured.NestedString(pc.Object, "spec", endpoint.field, "pod", "name")
+		endpoint.endpoint.IP, _, _ = unstructured.NestedString(pc.Object, "spec", endpoint.field, "ip")
+	}
+	return summary
+}
+
+func (h *requestsHandler) deleteExpiredPacketCaptures(ctx context.Context) {
+	pcs, err := h.listPacketCaptures(ctx, h.gcClient)
+	if err != nil {
+		h.logger.Error(err, "Error when listing PacketCaptures")
+		return
+	}
+	now := h.clock.Now()
+	deleted := 0
+	for idx := range pcs {
+		pc := &pcs[idx]
+		if now.Sub(pc.GetCreationTimestamp().Time) <= h.gcConfig.ExpiryTimeout {
+			continue
+		}
+		if err := h.removeFile(ctx, pc.Object); err != nil {
+			// The PacketCapture is kept, so that removing its file is retried next time.
+			h.logger.Error(err, "Error when removing file of expired PacketCapture", "name", pc.GetName())
+			continue
+		}
+		err := h.gcClient.Resource(h.packetCaptureResource()).Delete(ctx, pc.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			h.logger.Error(err, "Error when deleting expired PacketCapture", "name", pc.GetName())
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		h.logger.V(2).Info("Deleted expired PacketCaptures", "count", deleted)
+	}
+}
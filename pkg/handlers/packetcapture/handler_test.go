@@ -0,0 +1,276 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packetcapture
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"antrea.io/antrea-ui/pkg/fileserver"
+	fileservertesting "antrea.io/antrea-ui/pkg/fileserver/testing"
+)
+
+var testGCConfig = GCConfig{
+	InstanceID:    "test",
+	ExpiryTimeout: 60 * time.Minute,
+	Period:        1 * time.Minute,
+}
+
+func setup(t *testing.T, clock clock.Clock, fileServer fileserver.Config) (*requestsHandler, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(packetCaptureGVR.GroupVersion().WithKind("PacketCaptureList"), &unstructured.UnstructuredList{})
+	k8sClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	if fileServer.URL == "" {
+		fileServer.URL = "sftp://10.0.0.1:22/upload"
+	}
+	handler, err := newRequestsHandlerWithClock(testr.New(t), k8sClient, testGCConfig, fileServer, clock)
+	require.NoError(t, err)
+	return handler, k8sClient
+}
+
+func testRequest() *Request {
+	return &Request{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"source": map[string]interface{}{
+					"pod": map[string]interface{}{"namespace": "default", "name": "pod-x"},
+				},
+				"destination": map[string]interface{}{
+					"ip": "10.0.0.10",
+				},
+				"captureConfig": map[string]interface{}{
+					"firstN": map[string]interface{}{"number": int64(10)},
+				},
+			},
+		},
+		Username: "alice",
+		Title:    "drops to 10.0.0.10",
+	}
+}
+
+func condition(conditionType string, status metav1.ConditionStatus, reason string) interface{} {
+	return map[string]interface{}{"type": conditionType, "status": string(status), "reason": reason}
+}
+
+// setStatus sets the status of PacketCapture requestID as Antrea would.
+func setStatus(t *testing.T, k8sClient *dynamicfake.FakeDynamicClient, requestID string, numberCaptured int64, filePath string, conditions ...interface{}) {
+	ctx := t.Context()
+	pc, err := k8sClient.Resource(packetCaptureGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(pc.Object, map[string]interface{}{
+		"numberCaptured": numberCaptured,
+		"filePath":       filePath,
+		"conditions":     conditions,
+	}, "status"))
+	_, err = k8sClient.Resource(packetCaptureGVR).Update(ctx, pc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestRequestsHandler(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{}, fileserver.Config{})
+
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	pc, err := k8sClient.Resource(packetCaptureGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	url, _, _ := unstructured.NestedString(pc.Object, "spec", "fileServer", "url")
+	assert.Equal(t, "sftp://10.0.0.1:22/upload", url)
+	assert.Equal(t, "test", pc.GetLabels()[instanceLabel])
+	assert.Equal(t, "alice", pc.GetAnnotations()[createdByAnnotation])
+
+	_, done, err := h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	setStatus(t, k8sClient, requestID, 10, "sftp://10.0.0.1:22/upload/"+requestID+".pcapng",
+		condition(conditionStarted, metav1.ConditionTrue, "Started"),
+		condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+		condition(conditionFileUploaded, metav1.ConditionTrue, "Succeed"),
+	)
+	_, done, err = h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	summaries, err := h.ListRequests(ctx, k8sClient)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, requestID, summaries[0].ID)
+	assert.Equal(t, "Succeeded", summaries[0].Phase)
+	assert.Equal(t, int32(10), summaries[0].NumberCaptured)
+	assert.Equal(t, "pod-x", summaries[0].Source.Pod)
+	assert.Equal(t, "10.0.0.10", summaries[0].Destination.IP)
+
+	ok, err := h.DeleteRequest(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = h.DeleteRequest(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPhase(t *testing.T) {
+	testCases := []struct {
+		name            string
+		numberCaptured  int64
+		conditions      []interface{}
+		expectedPhase   string
+		expectedMessage string
+	}{
+		{
+			name:          "not started",
+			expectedPhase: "Running",
+		},
+		{
+			name:           "capturing",
+			numberCaptured: 3,
+			conditions:     []interface{}{condition(conditionStarted, metav1.ConditionTrue, "Started")},
+			expectedPhase:  "Running",
+		},
+		{
+			name:           "uploading",
+			numberCaptured: 10,
+			conditions: []interface{}{
+				condition(conditionStarted, metav1.ConditionTrue, "Started"),
+				condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+			},
+			expectedPhase: "Running",
+		},
+		{
+			name:           "uploaded",
+			numberCaptured: 10,
+			conditions: []interface{}{
+				condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+				condition(conditionFileUploaded, metav1.ConditionTrue, "Succeed"),
+			},
+			expectedPhase: "Succeeded",
+		},
+		{
+			name:           "upload failed",
+			numberCaptured: 10,
+			conditions: []interface{}{
+				condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+				condition(conditionFileUploaded, metav1.ConditionFalse, "Failed"),
+			},
+			expectedPhase:   "Failed",
+			expectedMessage: "Failed",
+		},
+		{
+			name:            "timeout without packets",
+			conditions:      []interface{}{condition(conditionComplete, metav1.ConditionTrue, "Timeout")},
+			expectedPhase:   "Failed",
+			expectedMessage: "No packet was captured",
+		},
+		{
+			name:            "start failed",
+			conditions:      []interface{}{condition(conditionStarted, metav1.ConditionFalse, "PodNotFound")},
+			expectedPhase:   "Failed",
+			expectedMessage: "PodNotFound",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pc := map[string]interface{}{
+				"status": map[string]interface{}{
+					"numberCaptured": tc.numberCaptured,
+					"conditions":     tc.conditions,
+				},
+			}
+			phase, message := Phase(pc)
+			assert.Equal(t, tc.expectedPhase, phase)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	ctx := t.Context()
+	content := []byte("pcapng data")
+	server := fileservertesting.StartSFTPServer(t, map[string][]byte{"/upload/node-1_pc.pcapng": content})
+	h, k8sClient := setup(t, &clock.RealClock{}, fileserver.Config{
+		URL:           server.URL,
+		Username:      fileservertesting.SFTPUser,
+		Password:      fileservertesting.SFTPPassword,
+		HostPublicKey: string(ssh.MarshalAuthorizedKey(server.HostKey)),
+	})
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+
+	pc, _, err := h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	_, _, err = h.OpenFile(ctx, pc)
+	assert.ErrorIs(t, err, ErrNoFile)
+
+	setStatus(t, k8sClient, requestID, 1, server.URL+"/node-1_pc.pcapng",
+		condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+		condition(conditionFileUploaded, metav1.ConditionTrue, "Succeed"),
+	)
+	pc, _, err = h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	f, name, err := h.OpenFile(ctx, pc)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, "node-1_pc.pcapng", name)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
+
+func TestGC(t *testing.T) {
+	ctx := t.Context()
+	clock := clocktesting.NewFakeClock(time.Now())
+	server := fileservertesting.StartSFTPServer(t, map[string][]byte{"/upload/node-1_pc.pcapng": []byte("pcapng data")})
+	h, k8sClient := setup(t, clock, fileserver.Config{
+		URL:           server.URL,
+		Username:      fileservertesting.SFTPUser,
+		Password:      fileservertesting.SFTPPassword,
+		HostPublicKey: string(ssh.MarshalAuthorizedKey(server.HostKey)),
+	})
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	setStatus(t, k8sClient, requestID, 1, server.URL+"/node-1_pc.pcapng",
+		condition(conditionComplete, metav1.ConditionTrue, "Succeed"),
+		condition(conditionFileUploaded, metav1.ConditionTrue, "Succeed"),
+	)
+	// The fake client does not set the creation timestamp.
+	pc, err := k8sClient.Resource(packetCaptureGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	pc.SetCreationTimestamp(metav1.NewTime(clock.Now()))
+	_, err = k8sClient.Resource(packetCaptureGVR).Update(ctx, pc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	h.deleteExpiredPacketCaptures(ctx)
+	_, err = k8sClient.Resource(packetCaptureGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, server.HasFile("/upload/node-1_pc.pcapng"))
+
+	clock.Step(testGCConfig.ExpiryTimeout + time.Minute)
+	h.deleteExpiredPacketCaptures(ctx)
+	_, err = k8sClient.Resource(packetCaptureGVR).Get(ctx, requestID, metav1.GetOptions{})
+	assert.Error(t, err)
+	assert.False(t, server.HasFile("/upload/node-1_pc.pcapng"))
+}
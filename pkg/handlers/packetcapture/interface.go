@@ -0,0 +1,51 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packetcapture
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+//go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
+
+// ErrNoFile is returned by OpenFile for a PacketCapture whose file was not uploaded.
+var ErrNoFile = errors.New("no file was uploaded for this PacketCapture")
+
+// RequestsHandler manages the PacketCapture CRs created through antrea-ui, and downloads their
+// pcap files from the file server.
+//
+// Like for Traceflows, every method that acts on a PacketCapture takes the dynamic client of the
+// end user, and the handler's own client is reserved for the background GC loop.
+type RequestsHandler interface {
+	CreateRequest(ctx context.Context, client dynamic.Interface, request *Request) (string, error)
+	// GetRequestResult returns the PacketCapture object, and a boolean to indicate whether the
+	// PacketCapture is completed: its file was uploaded, or it failed.
+	GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error)
+	DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error)
+	// ListRequests returns the PacketCaptures created by antrea-ui that client can list, most
+	// recent first.
+	ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.PacketCaptureSummary, error)
+	// OpenFile opens the pcapng file uploaded for packetCapture, an object returned by
+	// GetRequestResult, and returns its name. The file is downloaded from the file server with
+	// antrea-ui's own credential, so the caller must have been allowed to get packetCapture
+	// first. Only files under the configured file server URL are opened.
+	OpenFile(ctx context.Context, packetCapture map[string]interface{}) (io.ReadCloser, string, error)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+
+// Package testing is a generated GoMock package.
+package testing
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	v1 "antrea.io/antrea-ui/apis/v1"
+	packetcapture "antrea.io/antrea-ui/pkg/handlers/packetcapture"
+	gomock "github.com/golang/mock/gomock"
+	dynamic "k8s.io/client-go/dynamic"
+)
+
+// MockRequestsHandler is a mock of RequestsHandler interface.
+type MockRequestsHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequestsHandlerMockRecorder
+}
+
+// MockRequestsHandlerMockRecorder is the mock recorder for MockRequestsHandler.
+type MockRequestsHandlerMockRecorder struct {
+	mock *MockRequestsHandler
+}
+
+// NewMockRequestsHandler creates a new mock instance.
+func NewMockRequestsHandler(ctrl *gomock.Controller) *MockRequestsHandler {
+	mock := &MockRequestsHandler{ctrl: ctrl}
+	mock.recorder = &MockRequestsHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRequestsHandler) EXPECT() *MockRequestsHandlerMockRecorder {
+	return m.recorder
+}
+
+// CreateRequest mocks base method.
+func (m *MockRequestsHandler) CreateRequest(ctx context.Context, client dynamic.Interface, request *packetcapture.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRequest", ctx, client, request)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRequest indicates an expected call of CreateRequest.
+func (mr *MockRequestsHandlerMockRecorder) CreateRequest(ctx, client, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRequest", reflect.TypeOf((*MockRequestsHandler)(nil).CreateRequest), ctx, client, request)
+}
+
+// DeleteRequest mocks base method.
+func (m *MockRequestsHandler) DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRequest", ctx, client, requestID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRequest indicates an expected call of DeleteRequest.
+func (mr *MockRequestsHandlerMockRecorder) DeleteRequest(ctx, client, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRequest", reflect.TypeOf((*MockRequestsHandler)(nil).DeleteRequest), ctx, client, requestID)
+}
+
+// GetRequestResult mocks base method.
+func (m *MockRequestsHandler) GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequestResult", ctx, client, requestID)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRequestResult indicates an expected call of GetRequestResult.
+func (mr *MockRequestsHandlerMockRecorder) GetRequestResult(ctx, client, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestResult", reflect.TypeOf((*MockRequestsHandler)(nil).GetRequestResult), ctx, client, requestID)
+}
+
+// ListRequests mocks base method.
+func (m *MockRequestsHandler) ListRequests(ctx context.Context, client dynamic.Interface) ([]v1.PacketCaptureSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequests", ctx, client)
+	ret0, _ := ret[0].([]v1.PacketCaptureSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequests indicates an expected call of ListRequests.
+func (mr *MockRequestsHandlerMockRecorder) ListRequests(ctx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequests", reflect.TypeOf((*MockRequestsHandler)(nil).ListRequests), ctx, client)
+}
+
+// OpenFile mocks base method.
+func (m *MockRequestsHandler) OpenFile(ctx context.Context, packetCapture map[string]interface{}) (io.ReadCloser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", ctx, packetCapture)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockRequestsHandlerMockRecorder) OpenFile(ctx, packetCapture interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockRequestsHandler)(nil).OpenFile), ctx, packetCapture)
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probes
+
+import (
+	"context"
+	"io"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+//go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
+
+// Runner runs the Traceflow of a probe, once its Pod selectors have been resolved, and returns the
+// result.
+type Runner func(ctx context.Context, tfRequest *apisv1.TraceflowRequest) apisv1.ReachabilityCell
+
+// Scheduler runs the configured connectivity probes periodically and keeps their recent history.
+type Scheduler interface {
+	// SetRunner sets the function that runs the probes' Traceflows. It must be called before
+	// Run.
+	SetRunner(runner Runner)
+	// Statuses returns the status of every probe, in configuration order.
+	Statuses() []apisv1.ProbeStatus
+	// Details returns the status and history of probe name, or false if there is no such probe.
+	Details(name string) (*apisv1.ProbeDetails, bool)
+	// WriteMetrics writes the probe metrics in the Prometheus text exposition format.
+	WriteMetrics(w io.Writer) error
+}
@@ -0,0 +1,349 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probes runs connectivity probes: Traceflows configured by the operator, which the
+// backend runs periodically to check that critical paths keep being allowed (or denied) as
+// policies change. It keeps a bounded history of the runs of each probe, and derives a success
+// rate from it, to be compared with the probe's SLO target.
+package probes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+const (
+	// probeRunTimeout bounds one run of a probe: the Pod selection and the whole Traceflow,
+	// whose timeout is at most 2 minutes.
+	probeRunTimeout = 3 * time.Minute
+	// probeConcurrency bounds the number of probes running at once, as Antrea only supports a
+	// few concurrent Traceflows.
+	probeConcurrency = 4
+	// probeJitterFactor spreads the runs of probes which share the same interval.
+	probeJitterFactor = 0.1
+)
+
+// Probe is a connectivity probe, with its defaults applied.
+type Probe struct {
+	Name string
+	// Source is a Pod or a Pod selector.
+	Source apisv1.ReachabilityEndpoints
+	// Destination is a Pod, a Pod selector, a Service or an IP address.
+	Destination     apisv1.ReachabilityEndpoints
+	Protocol        string
+	DestinationPort int32
+	// Expect is apisv1.ReachabilityAllowed or apisv1.ReachabilityDenied.
+	Expect    string
+	Interval  time.Duration
+	SLOTarget float64
+}
+
+type probeState struct {
+	probe Probe
+	// history holds the most recent runs, the oldest first.
+	history []apisv1.ProbeRun
+	// runs and failures count all the runs since startup, for the metrics.
+	runs, failures      uint64
+	consecutiveFailures int
+	lastRunTime         time.Time
+}
+
+type scheduler struct {
+	logger      logr.Logger
+	clientset   kubernetes.Interface
+	clock       clock.Clock
+	historySize int
+	runner      Runner
+
+	mutex  sync.Mutex
+	probes []*probeState
+}
+
+// NewScheduler builds a Scheduler for probes, keeping historySize runs of each. clientset, which
+// needs to list Pods, is used to resolve Pod selectors. Call SetRunner, then Run in a goroutine.
+func NewScheduler(logger logr.Logger, clientset kubernetes.Interface, probes []Probe, historySize int) *scheduler {
+	return newSchedulerWithClock(logger, clientset, probes, historySize, clock.RealClock{})
+}
+
+func newSchedulerWithClock(logger logr.Logger, clientset kubernetes.Interface, probes []Probe, historySize int, clock clock.Clock) *scheduler {
+	s := &scheduler{
+		logger:      logger,
+		clientset:   clientset,
+		clock:       clock,
+		historySize: historySize,
+	}
+	for _, probe := range probes {
+		s.probes = append(s.probes, &probeState{probe: probe})
+	}
+	return s
+}
+
+func (s *scheduler) SetRunner(runner Runner) {
+	s.runner = runner
+}
+
+// Run runs every probe at its interval until stopCh is closed. It blocks and should be called from
+// a goroutine.
+func (s *scheduler) Run(stopCh <-chan struct{}) {
+	if s.runner == nil {
+		s.logger.Error(nil, "No runner for probes, they will not run")
+		return
+	}
+	s.logger.Info("Starting connectivity probes", "count", len(s.probes))
+	ctx := wait.ContextForChannel(stopCh)
+	sem := make(chan struct{}, probeConcurrency)
+	var wg sync.WaitGroup
+	for _, state := range s.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+				s.runProbe(ctx, state)
+			}, state.probe.Interval, probeJitterFactor, true)
+		}()
+	}
+	wg.Wait()
+}
+
+// runProbe runs a probe once and records the result. Nothing is recorded if ctx is cancelled, as
+// the backend is stopping.
+func (s *scheduler) runProbe(ctx context.Context, state *probeState) {
+	start := s.clock.Now()
+	runCtx, cancel := context.WithTimeout(ctx, probeRunTimeout)
+	defer cancel()
+	run := apisv1.ProbeRun{
+		Time: start.UTC().Format(time.RFC3339),
+	}
+	tfRequest, err := s.traceflowRequest(runCtx, &state.probe)
+	if err != nil {
+		run.ReachabilityCell = apisv1.ReachabilityCell{
+			Result: apisv1.ReachabilityError,
+			Reason: err.Error(),
+		}
+	} else {
+		run.Source = tfRequest.Source
+		run.Destination = tfRequest.Destination
+		run.ReachabilityCell = s.runner(runCtx, tfRequest)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	run.Passed = run.Result == state.probe.Expect
+	s.record(state, start, run)
+}
+
+func (s *scheduler) record(state *probeState, start time.Time, run apisv1.ProbeRun) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	state.history = append(state.history, run)
+	if len(state.history) > s.historySize {
+		state.history = state.history[len(state.history)-s.historySize:]
+	}
+	state.runs++
+	state.lastRunTime = start
+	if run.Passed {
+		if state.consecutiveFailures > 0 {
+			s.logger.Info("Probe passed again", "probe", state.probe.Name, "failures", state.consecutiveFailures)
+		}
+		state.consecutiveFailures = 0
+		return
+	}
+	state.failures++
+	state.consecutiveFailures++
+	if state.consecutiveFailures == 1 {
+		s.logger.Info("Probe failed", "probe", state.probe.Name, "expected", state.probe.Expect, "result", run.Result, "reason", run.Reason, "blockedBy", run.BlockedBy)
+	}
+}
+
+// traceflowRequest builds the Traceflow request of probe, resolving its Pod selectors.
+func (s *scheduler) traceflowRequest(ctx context.Context, probe *Probe) (*apisv1.TraceflowRequest, error) {
+	srcPod, err := s.selectPod(ctx, &probe.Source)
+	if err != nil {
+		return nil, err
+	}
+	tfRequest := &apisv1.TraceflowRequest{
+		Source: apisv1.TraceflowSource{
+			Namespace: probe.Source.Namespace,
+			Pod:       srcPod,
+		},
+		Protocol:        probe.Protocol,
+		DestinationPort: probe.DestinationPort,
+		Title:           "Probe " + probe.Name,
+	}
+	dst := &probe.Destination
+	switch {
+	case dst.Pod != "" || dst.PodSelector != "":
+		dstPod, err := s.selectPod(ctx, dst)
+		if err != nil {
+			return nil, err
+		}
+		tfRequest.Destination = apisv1.TraceflowDestination{Namespace: dst.Namespace, Pod: dstPod}
+	case dst.Service != "":
+		tfRequest.Destination = apisv1.TraceflowDestination{Namespace: dst.Namespace, Service: dst.Service}
+	default:
+		tfRequest.Destination = apisv1.TraceflowDestination{IP: dst.IP}
+	}
+	return tfRequest, nil
+}
+
+// selectPod returns the Pod of endpoints, or the first running Pod (by name) matched by its
+// selector, so that a probe keeps tracing from the same Pod while it exists. Pods on the host
+// network are skipped, since Antrea cannot trace them.
+func (s *scheduler) selectPod(ctx context.Context, endpoints *apisv1.ReachabilityEndpoints) (string, error) {
+	if endpoints.Pod != "" {
+		return endpoints.Pod, nil
+	}
+	list, err := s.clientset.CoreV1().Pods(endpoints.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: endpoints.PodSelector,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error when listing Pods: %w", err)
+	}
+	var names []string
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && !pod.Spec.HostNetwork {
+			names = append(names, pod.Name)
+		}
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no running Pod matches selector %q in Namespace %s", endpoints.PodSelector, endpoints.Namespace)
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
+
+// status must be called with the mutex held.
+func (s *scheduler) status(state *probeState) apisv1.ProbeStatus {
+	probe := &state.probe
+	status := apisv1.ProbeStatus{
+		Name:                probe.Name,
+		Source:              probe.Source,
+		Destination:         probe.Destination,
+		Protocol:            probe.Protocol,
+		DestinationPort:     probe.DestinationPort,
+		Expect:              probe.Expect,
+		Interval:            int64(probe.Interval / time.Second),
+		SLOTarget:           probe.SLOTarget,
+		Runs:                len(state.history),
+		ConsecutiveFailures: state.consecutiveFailures,
+	}
+	if len(state.history) == 0 {
+		return status
+	}
+	lastRun := state.history[len(state.history)-1]
+	status.LastRun = &lastRun
+	for i := range state.history {
+		if state.history[i].Passed {
+			status.Passed++
+		}
+	}
+	status.SuccessRate = float64(status.Passed) / float64(status.Runs)
+	status.SLOMet = status.SuccessRate >= probe.SLOTarget
+	return status
+}
+
+func (s *scheduler) Statuses() []apisv1.ProbeStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	statuses := make([]apisv1.ProbeStatus, 0, len(s.probes))
+	for _, state := range s.probes {
+		statuses = append(statuses, s.status(state))
+	}
+	return statuses
+}
+
+func (s *scheduler) Details(name string) (*apisv1.ProbeDetails, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, state := range s.probes {
+		if state.probe.Name == name {
+			return &apisv1.ProbeDetails{
+				ProbeStatus: s.status(state),
+				History:     append([]apisv1.ProbeRun{}, state.history...),
+			}, true
+		}
+	}
+	return nil, false
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (s *scheduler) WriteMetrics(w io.Writer) error {
+	s.mutex.Lock()
+	statuses := make([]apisv1.ProbeStatus, len(s.probes))
+	for i, state := range s.probes {
+		statuses[i] = s.status(state)
+	}
+	var buf bytes.Buffer
+	// writeMetric writes one metric family. value returns false for probes that have no
+	// sample, e.g. because they have not run yet.
+	writeMetric := func(name, metricType, help string, value func(state *probeState, status *apisv1.ProbeStatus) (float64, bool)) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+		for i, state := range s.probes {
+			if v, ok := value(state, &statuses[i]); ok {
+				fmt.Fprintf(&buf, "%s{probe=\"%s\"} %s\n", name, labelValueEscaper.Replace(state.probe.Name), strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+	}
+	writeMetric("antrea_ui_probe_runs_total", "counter", "Number of runs of the probe.", func(state *probeState, _ *apisv1.ProbeStatus) (float64, bool) {
+		return float64(state.runs), true
+	})
+	writeMetric("antrea_ui_probe_failures_total", "counter", "Number of runs of the probe that did not get the expected result.", func(state *probeState, _ *apisv1.ProbeStatus) (float64, bool) {
+		return float64(state.failures), true
+	})
+	writeMetric("antrea_ui_probe_passed", "gauge", "Whether the last run of the probe got the expected result.", func(_ *probeState, status *apisv1.ProbeStatus) (float64, bool) {
+		if status.LastRun == nil {
+			return 0, false
+		}
+		if status.LastRun.Passed {
+			return 1, true
+		}
+		return 0, true
+	})
+	writeMetric("antrea_ui_probe_success_ratio", "gauge", "Ratio of the runs in the probe history that got the expected result.", func(_ *probeState, status *apisv1.ProbeStatus) (float64, bool) {
+		return status.SuccessRate, status.Runs > 0
+	})
+	writeMetric("antrea_ui_probe_slo_target", "gauge", "Expected success ratio of the probe.", func(state *probeState, _ *apisv1.ProbeStatus) (float64, bool) {
+		return state.probe.SLOTarget, true
+	})
+	writeMetric("antrea_ui_probe_last_run_timestamp_seconds", "gauge", "Time of the last run of the probe, in seconds since the epoch.", func(state *probeState, _ *apisv1.ProbeStatus) (float64, bool) {
+		return float64(state.lastRunTime.Unix()), !state.lastRunTime.IsZero()
+	})
+	s.mutex.Unlock()
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+func pod(name string, phase corev1.PodPhase, hostNetwork bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{HostNetwork: hostNetwork},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+var (
+	webProbe = Probe{
+		Name:            "web-to-db",
+		Source:          apisv1.ReachabilityEndpoints{Namespace: "default", PodSelector: "app=web"},
+		Destination:     apisv1.ReachabilityEndpoints{Namespace: "default", Service: "db"},
+		Protocol:        "TCP",
+		DestinationPort: 5432,
+		Expect:          apisv1.ReachabilityAllowed,
+		Interval:        time.Minute,
+		SLOTarget:       0.5,
+	}
+	deniedProbe = Probe{
+		Name:        "web-to-internet",
+		Source:      apisv1.ReachabilityEndpoints{Namespace: "default", Pod: "web-0"},
+		Destination: apisv1.ReachabilityEndpoints{IP: "8.8.8.8"},
+		Expect:      apisv1.ReachabilityDenied,
+		Interval:    time.Minute,
+		SLOTarget:   1,
+	}
+)
+
+func newTestScheduler(t *testing.T, historySize int, results ...apisv1.ReachabilityCell) (*scheduler, *[]*apisv1.TraceflowRequest) {
+	clientset := k8sfake.NewSimpleClientset(
+		pod("web-2", corev1.PodRunning, false),
+		pod("web-1", corev1.PodRunning, false),
+		pod("web-0", corev1.PodPending, false),
+		pod("web-host", corev1.PodRunning, true),
+	)
+	s := newSchedulerWithClock(testr.New(t), clientset, []Probe{webProbe, deniedProbe}, historySize, clocktesting.NewFakeClock(time.Unix(1700000000, 0)))
+	var tfRequests []*apisv1.TraceflowRequest
+	s.SetRunner(func(_ context.Context, tfRequest *apisv1.TraceflowRequest) apisv1.ReachabilityCell {
+		tfRequests = append(tfRequests, tfRequest)
+		result := results[0]
+		results = results[1:]
+		return result
+	})
+	return s, &tfRequests
+}
+
+func TestRunProbe(t *testing.T) {
+	blocked := apisv1.ReachabilityCell{
+		Result:    apisv1.ReachabilityDenied,
+		BlockedBy: &apisv1.ReachabilityBlock{Action: "Dropped", NetworkPolicy: "K8sNetworkPolicy:default/deny-db"},
+	}
+	s, tfRequests := newTestScheduler(t, 2,
+		apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed},
+		blocked,
+		blocked,
+		apisv1.ReachabilityCell{Result: apisv1.ReachabilityDenied},
+	)
+	web := s.probes[0]
+	for range 3 {
+		s.runProbe(t.Context(), web)
+	}
+	s.runProbe(t.Context(), s.probes[1])
+
+	require.Len(t, *tfRequests, 4)
+	assert.Equal(t, apisv1.TraceflowRequest{
+		Source:          apisv1.TraceflowSource{Namespace: "default", Pod: "web-1"},
+		Destination:     apisv1.TraceflowDestination{Namespace: "default", Service: "db"},
+		Protocol:        "TCP",
+		DestinationPort: 5432,
+		Title:           "Probe web-to-db",
+	}, *(*tfRequests)[0])
+	assert.Equal(t, apisv1.TraceflowDestination{IP: "8.8.8.8"}, (*tfRequests)[3].Destination)
+
+	details, ok := s.Details("web-to-db")
+	require.True(t, ok)
+	// The history only keeps the last 2 runs.
+	require.Len(t, details.History, 2)
+	assert.False(t, details.History[1].Passed)
+	assert.Equal(t, "K8sNetworkPolicy:default/deny-db", details.History[1].BlockedBy.NetworkPolicy)
+	assert.Equal(t, "2023-11-14T22:13:20Z", details.History[1].Time)
+	assert.Equal(t, 2, details.Runs)
+	assert.Equal(t, 0, details.Passed)
+	assert.Equal(t, 2, details.ConsecutiveFailures)
+	assert.False(t, details.SLOMet)
+
+	statuses := s.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "web-to-internet", statuses[1].Name)
+	assert.True(t, statuses[1].LastRun.Passed)
+	assert.Equal(t, 1.0, statuses[1].SuccessRate)
+	assert.True(t, statuses[1].SLOMet)
+
+	_, ok = s.Details("unknown")
+	assert.False(t, ok)
+}
+
+func TestRunProbeNoPod(t *testing.T) {
+	s, tfRequests := newTestScheduler(t, 10)
+	state := s.probes[0]
+	state.probe.Source.PodSelector = "app=unknown"
+	s.runProbe(t.Context(), state)
+	assert.Empty(t, *tfRequests)
+	details, _ := s.Details("web-to-db")
+	require.Len(t, details.History, 1)
+	assert.Equal(t, apisv1.ReachabilityError, details.History[0].Result)
+	assert.Contains(t, details.History[0].Reason, "no running Pod matches selector")
+	assert.False(t, details.History[0].Passed)
+}
+
+func TestWriteMetrics(t *testing.T) {
+	s, _ := newTestScheduler(t, 10,
+		apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed},
+		apisv1.ReachabilityCell{Result: apisv1.ReachabilityError, Reason: "Traceflow failed"},
+	)
+	s.runProbe(t.Context(), s.probes[0])
+	s.runProbe(t.Context(), s.probes[0])
+	var b strings.Builder
+	require.NoError(t, s.WriteMetrics(&b))
+	metrics := b.String()
+	for _, line := range []string{
+		"# TYPE antrea_ui_probe_runs_total counter",
+		`antrea_ui_probe_runs_total{probe="web-to-db"} 2`,
+		`antrea_ui_probe_runs_total{probe="web-to-internet"} 0`,
+		`antrea_ui_probe_failures_total{probe="web-to-db"} 1`,
+		`antrea_ui_probe_passed{probe="web-to-db"} 0`,
+		`antrea_ui_probe_success_ratio{probe="web-to-db"} 0.5`,
+		`antrea_ui_probe_slo_target{probe="web-to-internet"} 1`,
+		`antrea_ui_probe_last_run_timestamp_seconds{probe="web-to-db"} 1700000000`,
+	} {
+		assert.Contains(t, metrics, line+"\n")
+	}
+	// Probes that have not run have no last result.
+	assert.NotContains(t, metrics, `antrea_ui_probe_passed{probe="web-to-internet"}`)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+
+// Package testing is a generated GoMock package.
+package testing
+
+import (
+	io "io"
+	reflect "reflect"
+
+	v1 "antrea.io/antrea-ui/apis/v1"
+	probes "antrea.io/antrea-ui/pkg/handlers/probes"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockScheduler is a mock of Scheduler interface.
+type MockScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerMockRecorder
+}
+
+// MockSchedulerMockRecorder is the mock recorder for MockScheduler.
+type MockSchedulerMockRecorder struct {
+	mock *MockScheduler
+}
+
+// NewMockScheduler creates a new mock instance.
+func NewMockScheduler(ctrl *gomock.Controller) *MockScheduler {
+	mock := &MockScheduler{ctrl: ctrl}
+	mock.recorder = &MockSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduler) EXPECT() *MockSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Details mocks base method.
+func (m *MockScheduler) Details(name string) (*v1.ProbeDetails, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Details", name)
+	ret0, _ := ret[0].(*v1.ProbeDetails)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Details indicates an expected call of Details.
+func (mr *MockSchedulerMockRecorder) Details(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Details", reflect.TypeOf((*MockScheduler)(nil).Details), name)
+}
+
+// SetRunner mocks base method.
+func (m *MockScheduler) SetRunner(runner probes.Runner) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRunner", runner)
+}
+
+// SetRunner indicates an expected call of SetRunner.
+func (mr *MockSchedulerMockRecorder) SetRunner(runner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRunner", reflect.TypeOf((*MockScheduler)(nil).SetRunner), runner)
+}
+
+// Statuses mocks base method.
+func (m *MockScheduler) Statuses() []v1.ProbeStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statuses")
+	ret0, _ := ret[0].([]v1.ProbeStatus)
+	return ret0
+}
+
+// Statuses indicates an expected call of Statuses.
+func (mr *MockSchedulerMockRecorder) Statuses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statuses", reflect.TypeOf((*MockScheduler)(nil).Statuses))
+}
+
+// WriteMetrics mocks base method.
+func (m *MockScheduler) WriteMetrics(w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteMetrics", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteMetrics indicates an expected call of WriteMetrics.
+func (mr *MockSchedulerMockRecorder) WriteMetrics(w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMetrics", reflect.TypeOf((*MockScheduler)(nil).WriteMetrics), w)
+}
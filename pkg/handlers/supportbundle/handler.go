@@ -0,0 +1,402 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/clock"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/fileserver"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+)
+
+var (
+	// supportBundleCollectionGVR is used when no CRD resolver is set.
+	supportBundleCollectionGVR = schema.GroupVersionResource{
+		Group:    "crd.antrea.io",
+		Version:  "v1alpha1",
+		Resource: "supportbundlecollections",
+	}
+
+	supportBundleCollectionLabels = map[string]string{
+		"ui.antrea.io": "",
+	}
+)
+
+const (
+	// The labels and annotations are the same as for Traceflows.
+	instanceLabel       = "ui.antrea.io/instance"
+	createdByAnnotation = "ui.antrea.io/created-by"
+	titleAnnotation     = "ui.antrea.io/title"
+	// controllerAnnotation records that the antrea-controller bundle was collected too.
+	controllerAnnotation = "ui.antrea.io/controller-bundle"
+	// controllerRequestedAtAnnotation is when the antrea-controller bundle was requested, in the
+	// controllerRequestedAtLayout format. The antrea-controller only keeps one bundle: the latest
+	// request owns it.
+	controllerRequestedAtAnnotation = "ui.antrea.io/controller-bundle-requested-at"
+	// controllerRequestedAtLayout is RFC 3339 with nanoseconds, which unlike time.RFC3339Nano
+	// are never trimmed, so that the timestamps in UTC sort chronologically as strings.
+	controllerRequestedAtLayout = "2006-01-02T15:04:05.000000000Z07:00"
+	// controllerSumAnnotation is the checksum of the antrea-controller bundle collected for the
+	// request, recorded once it is collected.
+	controllerSumAnnotation = "ui.antrea.io/controller-bundle-sum"
+
+	// The conditions Antrea sets on a SupportBundleCollection which antrea-ui looks at.
+	conditionCompleted         = "Completed"
+	conditionCollectionFailure = "CollectionFailure"
+)
+
+// GCConfig controls the garbage collection of the SupportBundleCollections created by the handler.
+type GCConfig struct {
+	// InstanceID identifies the antrea-ui install, see traceflow.GCConfig.
+	InstanceID string
+	// ExpiryTimeout is the age past which a SupportBundleCollection is deleted.
+	ExpiryTimeout time.Duration
+	// Period is how often the handler looks for expired SupportBundleCollections.
+	Period time.Duration
+}
+
+// AuthSecret is the Secret from which Antrea reads the credential of the file server.
+type AuthSecret struct {
+	Name      string
+	Namespace string
+}
+
+type requestsHandler struct {
+	logger logr.Logger
+	// gcClient is only used by the background GC loop.
+	gcClient dynamic.Interface
+	gcConfig GCConfig
+	clock    clock.Clock
+	// crdResolver picks the version of the SupportBundleCollection CRD. When it is nil,
+	// v1alpha1 is used.
+	crdResolver crdversions.Resolver
+
+	// fileServer is the SFTP server that Antrea uploads bundles to.
+	fileServer *fileserver.Server
+	authSecret AuthSecret
+}
+
+func newRequestsHandlerWithClock(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig, fileServerConfig fileserver.Config, authSecret AuthSecret, clock clock.Clock) (*requestsHandler, error) {
+	fileServer, err := fileserver.New(logger, fileServerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &requestsHandler{
+		logger:     logger,
+		gcClient:   gcClient,
+		gcConfig:   gcConfig,
+		clock:      clock,
+		fileServer: fileServer,
+		authSecret: authSecret,
+	}, nil
+}
+
+func NewRequestsHandler(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig, fileServer fileserver.Config, authSecret AuthSecret) (*requestsHandler, error) {
+	return newRequestsHandlerWithClock(logger, gcClient, gcConfig, fileServer, authSecret, &clock.RealClock{})
+}
+
+// SetCRDResolver makes the handler use the version of the SupportBundleCollection CRD picked by
+// resolver. It must be called before the handler is used.
+func (h *requestsHandler) SetCRDResolver(resolver crdversions.Resolver) {
+	h.crdResolver = resolver
+}
+
+func (h *requestsHandler) supportBundleCollectionResource() schema.GroupVersionResource {
+	if h.crdResolver == nil {
+		return supportBundleCollectionGVR
+	}
+	return h.crdResolver.GVR(crdversions.ResourceSupportBundleCollections)
+}
+
+func (h *requestsHandler) Run(stopCh <-chan struct{}) {
+	ctx := wait.ContextForChannel(stopCh)
+	//lint:ignore SA1019 apimachinery doesn't provide a correct alternative yet
+	go wait.BackoffUntil(func() { h.deleteExpiredSupportBundleCollections(ctx) }, wait.NewJitteredBackoffManager(h.gcConfig.Period, 0.0, h.clock), true, stopCh)
+	<-stopCh
+}
+
+func (h *requestsHandler) CreateRequest(ctx context.Context, client dynamic.Interface, request *Request) (string, error) {
+	requestID := uuid.NewString()
+	gvr := h.supportBundleCollectionResource()
+	spec := maps.Clone(request.Object["spec"].(map[string]interface{}))
+	spec["fileServer"] = map[string]interface{}{
+		"url": h.fileServer.URL(),
+	}
+	spec["authentication"] = map[string]interface{}{
+		"authType": "BasicAuthentication",
+		"authSecret": map[string]interface{}{
+			"name":      h.authSecret.Name,
+			"namespace": h.authSecret.Namespace,
+		},
+	}
+	sbc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": gvr.GroupVersion().String(),
+			"kind":       "SupportBundleCollection",
+			"metadata": map[string]interface{}{
+				"name": requestID,
+			},
+			"spec": spec,
+		},
+	}
+	sbcLabels := maps.Clone(supportBundleCollectionLabels)
+	sbcLabels[instanceLabel] = h.gcConfig.InstanceID
+	sbc.SetLabels(sbcLabels)
+	annotations := map[string]string{}
+	if request.Username != "" {
+		annotations[createdByAnnotation] = request.Username
+	}
+	if request.Title != "" {
+		annotations[titleAnnotation] = request.Title
+	}
+	if request.Controller {
+		annotations[controllerAnnotation] = "true"
+		annotations[controllerRequestedAtAnnotation] = h.clock.Now().UTC().Format(controllerRequestedAtLayout)
+	}
+	if len(annotations) > 0 {
+		sbc.SetAnnotations(annotations)
+	}
+	if _, err := client.Resource(gvr).Create(ctx, sbc, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	return requestID, nil
+}
+
+func (h *requestsHandler) GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error) {
+	sbc, err := client.Resource(h.supportBundleCollectionResource()).Get(ctx, requestID, metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	phase, _ := Phase(sbc.Object)
+	return sbc.Object, phase != "Running", nil
+}
+
+func (h *requestsHandler) DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error) {
+	err := client.Resource(h.supportBundleCollectionResource()).Delete(ctx, requestID, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *requestsHandler) ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.SupportBundleSummary, error) {
+	sbcs, err := h.listSupportBundleCollections(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]apisv1.SupportBundleSummary, 0, len(sbcs))
+	for idx := range sbcs {
+		summaries = append(summaries, Summary(sbcs[idx].Object))
+	}
+	// RFC 3339 timestamps in UTC sort chronologically as strings.
+	slices.SortStableFunc(summaries, func(a, b apisv1.SupportBundleSummary) int {
+		return cmp.Compare(b.CreationTimestamp, a.CreationTimestamp)
+	})
+	return summaries, nil
+}
+
+func (h *requestsHandler) LatestControllerRequest(ctx context.Context, client dynamic.Interface) (string, error) {
+	sbcs, err := h.listSupportBundleCollections(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	var latest, latestRequestedAt string
+	for idx := range sbcs {
+		requestedAt := sbcs[idx].GetAnnotations()[controllerRequestedAtAnnotation]
+		if requestedAt != "" && requestedAt > latestRequestedAt {
+			latest, latestRequestedAt = sbcs[idx].GetName(), requestedAt
+		}
+	}
+	return latest, nil
+}
+
+func (h *requestsHandler) SetControllerBundleSum(ctx context.Context, requestID string, sum string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				controllerSumAnnotation: sum,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = h.gcClient.Resource(h.supportBundleCollectionResource()).Patch(ctx, requestID, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (h *requestsHandler) OpenFile(ctx context.Context, supportBundleCollection map[string]interface{}, node string) (io.ReadCloser, string, error) {
+	nodes, _, _ := unstructured.NestedStringSlice(supportBundleCollection, "spec", "nodes", "nodeNames")
+	if phase, _ := Phase(supportBundleCollection); phase != "Succeeded" || !slices.Contains(nodes, node) {
+		return nil, "", ErrNoFile
+	}
+	// Antrea does not report where it uploads bundles, which is "<node>_<name>.tar.gz" under the
+	// file server path. A Node whose bundle failed has no file.
+	name, _, _ := unstructured.NestedString(supportBundleCollection, "metadata", "name")
+	fileURL, err := h.fileServer.File(node + "_" + name + ".tar.gz")
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := h.fileServer.Open(ctx, fileURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return f, node + "_" + name + ".tar.gz", nil
+}
+
+// removeFiles removes the bundles uploaded for supportBundleCollection from the file server. Nodes
+// whose bundle failed have no file, which is not an error.
+func (h *requestsHandler) removeFiles(ctx context.Context, supportBundleCollection map[string]interface{}) error {
+	nodes, _, _ := unstructured.NestedStringSlice(supportBundleCollection, "spec", "nodes", "nodeNames")
+	name, _, _ := unstructured.NestedString(supportBundleCollection, "metadata", "name")
+	for _, node := range nodes {
+		fileURL, err := h.fileServer.File(node + "_" + name + ".tar.gz")
+		if err != nil {
+			return err
+		}
+		if err := h.fileServer.Remove(ctx, fileURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSupportBundleCollections lists the SupportBundleCollections created by this install.
+func (h *requestsHandler) listSupportBundleCollections(ctx context.Context, client dynamic.Interface) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(h.supportBundleCollectionResource()).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(supportBundleCollectionLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(list.Items, func(sbc unstructured.Unstructured) bool {
+		return sbc.GetLabels()[instanceLabel] != h.gcConfig.InstanceID
+	}), nil
+}
+
+// Phase derives the phase of a SupportBundleCollection from its conditions: "Running",
+// "Succeeded" once it is completed with at least one bundle, or "Failed". The message explains a
+// failure, which may be partial.
+func Phase(supportBundleCollection map[string]interface{}) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(supportBundleCollection, "status", "conditions")
+	byType := make(map[string]map[string]interface{})
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok {
+			conditionType, _ := condition["type"].(string)
+			byType[conditionType] = condition
+		}
+	}
+	if condition, ok := byType[conditionCompleted]; !ok || condition["status"] != string(metav1.ConditionTrue) {
+		return "Running", ""
+	}
+	var message string
+	if condition, ok := byType[conditionCollectionFailure]; ok && condition["status"] == string(metav1.ConditionTrue) {
+		message, _ = condition["message"].(string)
+		if message == "" {
+			message, _ = condition["reason"].(string)
+		}
+	}
+	if collectedNodes, _, _ := unstructured.NestedInt64(supportBundleCollection, "status", "collectedNodes"); collectedNodes == 0 {
+		if message == "" {
+			message = "No bundle was collected"
+		}
+		return "Failed", message
+	}
+	return "Succeeded", message
+}
+
+// ControllerBundleSum returns the checksum of the antrea-controller bundle recorded for
+// supportBundleCollection with SetControllerBundleSum, or an empty string.
+func ControllerBundleSum(supportBundleCollection map[string]interface{}) string {
+	sbc := &unstructured.Unstructured{Object: supportBundleCollection}
+	return sbc.GetAnnotations()[controllerSumAnnotation]
+}
+
+// Summary describes a SupportBundleCollection created by antrea-ui. Its ControllerStatus is not
+// set, as the antrea-controller bundle is not tracked by the SupportBundleCollection.
+func Summary(supportBundleCollection map[string]interface{}) apisv1.SupportBundleSummary {
+	sbc := &unstructured.Unstructured{Object: supportBundleCollection}
+	annotations := sbc.GetAnnotations()
+	summary := apisv1.SupportBundleSummary{
+		ID:                sbc.GetName(),
+		Title:             annotations[titleAnnotation],
+		CreatedBy:         annotations[createdByAnnotation],
+		CreationTimestamp: sbc.GetCreationTimestamp().UTC().Format(time.RFC3339),
+		Controller:        annotations[controllerAnnotation] == "true",
+	}
+	summary.Phase, summary.Message = Phase(supportBundleCollection)
+	// The spec was written by antrea-ui, so a field of an unexpected type is just left empty.
+	summary.Nodes, _, _ = unstructured.NestedStringSlice(supportBundleCollection, "spec", "nodes", "nodeNames")
+	if summary.Nodes == nil {
+		summary.Nodes = []string{}
+	}
+	collectedNodes, _, _ := unstructured.NestedInt64(supportBundleCollection, "status", "collectedNodes")
+	desiredNodes, _, _ := unstructured.NestedInt64(supportBundleCollection, "status", "desiredNodes")
+	summary.CollectedNodes, summary.DesiredNodes = int32(collectedNodes), int32(desiredNodes)
+	return summary
+}
+
+func (h *requestsHandler) deleteExpiredSupportBundleCollections(ctx context.Context) {
+	sbcs, err := h.listSupportBundleCollections(ctx, h.gcClient)
+	if err != nil {
+		h.logger.Error(err, "Error when listing SupportBundleCollections")
+		return
+	}
+	now := h.clock.Now()
+	deleted := 0
+	for idx := range sbcs {
+		sbc := &sbcs[idx]
+		if now.Sub(sbc.GetCreationTimestamp().Time) <= h.gcConfig.ExpiryTimeout {
+			continue
+		}
+		if err := h.removeFiles(ctx, sbc.Object); err != nil {
+			// The SupportBundleCollection is kept, so that removing its files is retried next
+			// time.
+			h.logger.Error(err, "Error when removing files of expired SupportBundleCollection", "name", sbc.GetName())
+			continue
+		}
+		err := h.gcClient.Resource(h.supportBundleCollectionResource()).Delete(ctx, sbc.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			h.logger.Error(err, "Error when deleting expired SupportBundleCollection", "name", sbc.GetName())
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		h.logger.V(2).Info("Deleted expired SupportBundleCollections", "count", deleted)
+	}
+}
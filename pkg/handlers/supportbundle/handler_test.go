@@ -0,0 +1,295 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"antrea.io/antrea-ui/pkg/fileserver"
+	fileservertesting "antrea.io/antrea-ui/pkg/fileserver/testing"
+)
+
+var (
+	testGCConfig = GCConfig{
+		InstanceID:    "test",
+		ExpiryTimeout: 60 * time.Minute,
+		Period:        1 * time.Minute,
+	}
+	testAuthSecret = AuthSecret{
+		Name:      "antrea-supportbundle-fileserver-auth",
+		Namespace: "kube-system",
+	}
+)
+
+func setup(t *testing.T, clock clock.Clock, fileServer fileserver.Config) (*requestsHandler, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(supportBundleCollectionGVR.GroupVersion().WithKind("SupportBundleCollectionList"), &unstructured.UnstructuredList{})
+	k8sClient := dynamicfake.NewSimpleDynamicClient(scheme)
+	if fileServer.URL == "" {
+		fileServer.URL = "sftp://10.0.0.1:22/upload"
+	}
+	handler, err := newRequestsHandlerWithClock(testr.New(t), k8sClient, testGCConfig, fileServer, testAuthSecret, clock)
+	require.NoError(t, err)
+	return handler, k8sClient
+}
+
+func testRequest() *Request {
+	return &Request{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"nodes": map[string]interface{}{
+					"nodeNames": []interface{}{"node-1", "node-2"},
+				},
+				"sinceTime": "2h",
+			},
+		},
+		Controller: true,
+		Username:   "alice",
+		Title:      "ticket 1234",
+	}
+}
+
+func condition(conditionType string, status metav1.ConditionStatus, message string) interface{} {
+	return map[string]interface{}{"type": conditionType, "status": string(status), "message": message}
+}
+
+// setStatus sets the status of SupportBundleCollection requestID as Antrea would.
+func setStatus(t *testing.T, k8sClient *dynamicfake.FakeDynamicClient, requestID string, collectedNodes int64, conditions ...interface{}) {
+	ctx := t.Context()
+	sbc, err := k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(sbc.Object, map[string]interface{}{
+		"collectedNodes": collectedNodes,
+		"desiredNodes":   int64(2),
+		"conditions":     conditions,
+	}, "status"))
+	_, err = k8sClient.Resource(supportBundleCollectionGVR).Update(ctx, sbc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func TestRequestsHandler(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{}, fileserver.Config{})
+
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	sbc, err := k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	url, _, _ := unstructured.NestedString(sbc.Object, "spec", "fileServer", "url")
+	assert.Equal(t, "sftp://10.0.0.1:22/upload", url)
+	secretName, _, _ := unstructured.NestedString(sbc.Object, "spec", "authentication", "authSecret", "name")
+	assert.Equal(t, testAuthSecret.Name, secretName)
+	assert.Equal(t, "test", sbc.GetLabels()[instanceLabel])
+	assert.Equal(t, "alice", sbc.GetAnnotations()[createdByAnnotation])
+
+	_, done, err := h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	setStatus(t, k8sClient, requestID, 2, condition(conditionCompleted, metav1.ConditionTrue, ""))
+	_, done, err = h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	summaries, err := h.ListRequests(ctx, k8sClient)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, requestID, summaries[0].ID)
+	assert.Equal(t, "Succeeded", summaries[0].Phase)
+	assert.Equal(t, []string{"node-1", "node-2"}, summaries[0].Nodes)
+	assert.Equal(t, int32(2), summaries[0].CollectedNodes)
+	assert.True(t, summaries[0].Controller)
+	assert.Equal(t, "ticket 1234", summaries[0].Title)
+
+	ok, err := h.DeleteRequest(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = h.DeleteRequest(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestControllerBundleOwnership(t *testing.T) {
+	ctx := t.Context()
+	fakeClock := clocktesting.NewFakeClock(time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC))
+	h, k8sClient := setup(t, fakeClock, fileserver.Config{})
+
+	latest, err := h.LatestControllerRequest(ctx, k8sClient)
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	first, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	// Timestamps with fewer significant digits must still sort after the previous ones.
+	fakeClock.Step(100 * time.Millisecond)
+	second, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	fakeClock.Step(time.Millisecond)
+	request := testRequest()
+	request.Controller = false
+	_, err = h.CreateRequest(ctx, k8sClient, request)
+	require.NoError(t, err)
+
+	latest, err = h.LatestControllerRequest(ctx, k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, second, latest)
+
+	require.NoError(t, h.SetControllerBundleSum(ctx, second, "abc"))
+	sbc, err := k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, second, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", ControllerBundleSum(sbc.Object))
+	sbc, err = k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, first, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, ControllerBundleSum(sbc.Object))
+}
+
+func TestPhase(t *testing.T) {
+	testCases := []struct {
+		name            string
+		collectedNodes  int64
+		conditions      []interface{}
+		expectedPhase   string
+		expectedMessage string
+	}{
+		{
+			name:          "not started",
+			expectedPhase: "Running",
+		},
+		{
+			name:           "collecting",
+			collectedNodes: 1,
+			conditions:     []interface{}{condition("Started", metav1.ConditionTrue, "")},
+			expectedPhase:  "Running",
+		},
+		{
+			name:           "completed",
+			collectedNodes: 2,
+			conditions:     []interface{}{condition(conditionCompleted, metav1.ConditionTrue, "")},
+			expectedPhase:  "Succeeded",
+		},
+		{
+			name:           "partially failed",
+			collectedNodes: 1,
+			conditions: []interface{}{
+				condition(conditionCollectionFailure, metav1.ConditionTrue, "Failed Agent count: 1, \"unreachable\": [node-2]"),
+				condition(conditionCompleted, metav1.ConditionTrue, ""),
+			},
+			expectedPhase:   "Succeeded",
+			expectedMessage: "Failed Agent count: 1, \"unreachable\": [node-2]",
+		},
+		{
+			name:            "nothing collected",
+			conditions:      []interface{}{condition(conditionCompleted, metav1.ConditionTrue, "")},
+			expectedPhase:   "Failed",
+			expectedMessage: "No bundle was collected",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sbc := map[string]interface{}{
+				"status": map[string]interface{}{
+					"collectedNodes": tc.collectedNodes,
+					"conditions":     tc.conditions,
+				},
+			}
+			phase, message := Phase(sbc)
+			assert.Equal(t, tc.expectedPhase, phase)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	ctx := t.Context()
+	content := []byte("tar.gz data")
+	h, k8sClient := setup(t, &clock.RealClock{}, fileserver.Config{})
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	// The name of the bundle depends on the request ID, so the file server is started after.
+	server := fileservertesting.StartSFTPServer(t, map[string][]byte{"/upload/node-1_" + requestID + ".tar.gz": content})
+	h.fileServer, err = fileserver.New(testr.New(t), fileserver.Config{
+		URL:           server.URL,
+		Username:      fileservertesting.SFTPUser,
+		Password:      fileservertesting.SFTPPassword,
+		HostPublicKey: string(ssh.MarshalAuthorizedKey(server.HostKey)),
+	})
+	require.NoError(t, err)
+
+	sbc, _, err := h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	_, _, err = h.OpenFile(ctx, sbc, "node-1")
+	assert.ErrorIs(t, err, ErrNoFile)
+
+	setStatus(t, k8sClient, requestID, 1, condition(conditionCompleted, metav1.ConditionTrue, ""))
+	sbc, _, err = h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	_, _, err = h.OpenFile(ctx, sbc, "node-3")
+	assert.ErrorIs(t, err, ErrNoFile)
+	f, name, err := h.OpenFile(ctx, sbc, "node-1")
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, "node-1_"+requestID+".tar.gz", name)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
+
+func TestGC(t *testing.T) {
+	ctx := t.Context()
+	clock := clocktesting.NewFakeClock(time.Now())
+	h, k8sClient := setup(t, clock, fileserver.Config{})
+	requestID, err := h.CreateRequest(ctx, k8sClient, testRequest())
+	require.NoError(t, err)
+	// The fake client does not set the creation timestamp.
+	sbc, err := k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	sbc.SetCreationTimestamp(metav1.NewTime(clock.Now()))
+	_, err = k8sClient.Resource(supportBundleCollectionGVR).Update(ctx, sbc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	// Only node-1 uploaded a bundle.
+	bundlePath := "/upload/node-1_" + requestID + ".tar.gz"
+	server := fileservertesting.StartSFTPServer(t, map[string][]byte{bundlePath: []byte("tar.gz data")})
+	h.fileServer, err = fileserver.New(testr.New(t), fileserver.Config{
+		URL:           server.URL,
+		Username:      fileservertesting.SFTPUser,
+		Password:      fileservertesting.SFTPPassword,
+		HostPublicKey: string(ssh.MarshalAuthorizedKey(server.HostKey)),
+	})
+	require.NoError(t, err)
+
+	h.deleteExpiredSupportBundleCollections(ctx)
+	_, err = k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, server.HasFile(bundlePath))
+
+	clock.Step(testGCConfig.ExpiryTimeout + time.Minute)
+	h.deleteExpiredSupportBundleCollections(ctx)
+	_, err = k8sClient.Resource(supportBundleCollectionGVR).Get(ctx, requestID, metav1.GetOptions{})
+	assert.Error(t, err)
+	assert.False(t, server.HasFile(bundlePath))
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+//go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
+
+// ErrNoFile is returned by OpenFile for a SupportBundleCollection which uploaded no bundle.
+var ErrNoFile = errors.New("no bundle was uploaded for this SupportBundleCollection")
+
+// RequestsHandler manages the SupportBundleCollection CRs created through antrea-ui, and
+// downloads their bundles from the file server.
+//
+// Like for PacketCaptures, every method that acts on a SupportBundleCollection takes the dynamic
+// client of the end user, and the handler's own client is reserved for the background GC loop.
+type RequestsHandler interface {
+	CreateRequest(ctx context.Context, client dynamic.Interface, request *Request) (string, error)
+	// GetRequestResult returns the SupportBundleCollection object, and a boolean to indicate
+	// whether the collection is completed.
+	GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error)
+	DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error)
+	// ListRequests returns the SupportBundleCollections created by antrea-ui that client can
+	// list, most recent first.
+	ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.SupportBundleSummary, error)
+	// LatestControllerRequest returns the ID of the SupportBundleCollection created by antrea-ui
+	// that most recently requested the antrea-controller bundle, among the ones client can list,
+	// or an empty string if there is none.
+	LatestControllerRequest(ctx context.Context, client dynamic.Interface) (string, error)
+	// SetControllerBundleSum records on the SupportBundleCollection the checksum of the
+	// antrea-controller bundle collected for it, see ControllerBundleSum. The
+	// SupportBundleCollection is patched with antrea-ui's own client, as the bookkeeping of
+	// antrea-ui, so the caller must have been allowed to get it first.
+	SetControllerBundleSum(ctx context.Context, requestID string, sum string) error
+	// OpenFile opens the bundle of node uploaded for supportBundleCollection, an object returned
+	// by GetRequestResult, and returns its name. The bundle is downloaded from the file server
+	// with antrea-ui's own credential, so the caller must have been allowed to get
+	// supportBundleCollection first.
+	OpenFile(ctx context.Context, supportBundleCollection map[string]interface{}, node string) (io.ReadCloser, string, error)
+}
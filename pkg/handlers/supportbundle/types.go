@@ -0,0 +1,28 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+type Request struct {
+	// Object holds the spec of the SupportBundleCollection. The handler sets its file server and
+	// authentication.
+	Object map[string]interface{}
+	// Controller records that the antrea-controller bundle was collected along with the Nodes
+	// ones. The handler does not collect it.
+	Controller bool
+	// Username and Title are recorded on the SupportBundleCollection, so that it can be found
+	// again in the list of bundles. Both are informational only.
+	Username string
+	Title    string
+}
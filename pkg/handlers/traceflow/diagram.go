@@ -0,0 +1,413 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Diagram is the hop-by-hop path of a Traceflow: the source endpoint, the observations made by
+// each Node the packet went through, and the destination endpoint. It renders the same graph as
+// the Traceflow page of the UI, as Graphviz DOT, Mermaid or SVG.
+type Diagram struct {
+	title string
+	// items is the path, in order. Consecutive items are connected.
+	items []diagramItem
+	// nodes are the names of the K8s Nodes the items are grouped by.
+	nodes []string
+}
+
+type diagramItem struct {
+	id    string
+	lines []string
+	// node is an index in Diagram.nodes, or -1 for an item outside of any Node.
+	node     int
+	endpoint bool
+	// blocked is true for an observation of a dropped or rejected packet.
+	blocked bool
+}
+
+type traceflowObject struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Source struct {
+			Namespace string `json:"namespace"`
+			Pod       string `json:"pod"`
+			IP        string `json:"ip"`
+		} `json:"source"`
+		Destination struct {
+			Namespace string `json:"namespace"`
+			Pod       string `json:"pod"`
+			Service   string `json:"service"`
+			IP        string `json:"ip"`
+		} `json:"destination"`
+	} `json:"spec"`
+	Status struct {
+		Phase          string                `json:"phase"`
+		Reason         string                `json:"reason"`
+		Results        []traceflowNodeResult `json:"results"`
+		CapturedPacket *struct {
+			SrcIP string `json:"srcIP"`
+			DstIP string `json:"dstIP"`
+		} `json:"capturedPacket"`
+	} `json:"status"`
+}
+
+type traceflowNodeResult struct {
+	Node         string                 `json:"node"`
+	Observations []traceflowObservation `json:"observations"`
+}
+
+type traceflowObservation struct {
+	Component         string `json:"component"`
+	ComponentInfo     string `json:"componentInfo"`
+	Action            string `json:"action"`
+	Pod               string `json:"pod"`
+	NetworkPolicy     string `json:"networkPolicy"`
+	NetworkPolicyRule string `json:"networkPolicyRule"`
+	TranslatedSrcIP   string `json:"translatedSrcIP"`
+	TranslatedDstIP   string `json:"translatedDstIP"`
+	TunnelDstIP       string `json:"tunnelDstIP"`
+	EgressIP          string `json:"egressIP"`
+	Egress            string `json:"egress"`
+	EgressNode        string `json:"egressNode"`
+}
+
+func (r *traceflowNodeResult) isSender() bool {
+	return len(r.Observations) > 0 && r.Observations[0].Component == "SpoofGuard" && r.Observations[0].Action == "Forwarded"
+}
+
+func (r *traceflowNodeResult) isReceiver() bool {
+	return len(r.Observations) > 0 && r.Observations[0].Component == "Forwarding" && r.Observations[0].Action == "Received"
+}
+
+func (o *traceflowObservation) lines() []string {
+	lines := []string{o.Component}
+	if o.ComponentInfo != "" {
+		lines = append(lines, o.ComponentInfo)
+	}
+	lines = append(lines, o.Action)
+	if o.Component == "NetworkPolicy" && o.NetworkPolicy != "" {
+		lines = append(lines, "Netpol: "+o.NetworkPolicy)
+		if o.NetworkPolicyRule != "" {
+			lines = append(lines, "Rule: "+o.NetworkPolicyRule)
+		}
+	}
+	if o.Pod != "" {
+		lines = append(lines, "To: "+o.Pod)
+	}
+	if o.Action != "Dropped" {
+		for _, field := range []struct{ name, value string }{
+			{"Translated Source IP", o.TranslatedSrcIP},
+			{"Translated Destination IP", o.TranslatedDstIP},
+			{"Tunnel Destination IP", o.TunnelDstIP},
+			{"Egress IP", o.EgressIP},
+			{"Egress", o.Egress},
+			{"Egress Node", o.EgressNode},
+		} {
+			if field.value != "" {
+				lines = append(lines, field.name+": "+field.value)
+			}
+		}
+	}
+	return lines
+}
+
+// NewDiagram builds the Diagram of a Traceflow object, as returned by GetRequestResult. A
+// Traceflow without results, e.g. because it failed, has a Diagram with only a title.
+func NewDiagram(traceflow map[string]interface{}) (*Diagram, error) {
+	data, err := json.Marshal(traceflow)
+	if err != nil {
+		return nil, fmt.Errorf("error when marshalling Traceflow: %w", err)
+	}
+	var tf traceflowObject
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling Traceflow: %w", err)
+	}
+	spec, status := &tf.Spec, &tf.Status
+
+	srcLabel := spec.Source.IP
+	if srcLabel == "" && spec.Source.Pod != "" {
+		srcLabel = spec.Source.Namespace + "/" + spec.Source.Pod
+	}
+	if srcLabel == "" && status.CapturedPacket != nil {
+		srcLabel = status.CapturedPacket.SrcIP
+	}
+	dstLabel := spec.Destination.IP
+	if dstLabel == "" && spec.Destination.Service != "" {
+		dstLabel = spec.Destination.Namespace + "/" + spec.Destination.Service
+	}
+	if dstLabel == "" && spec.Destination.Pod != "" {
+		dstLabel = spec.Destination.Namespace + "/" + spec.Destination.Pod
+	}
+	if dstLabel == "" {
+		// Live traffic to an unspecified destination: use the Pod the packet was delivered
+		// to, if any.
+		for _, r := range status.Results {
+			for _, o := range r.Observations {
+				if o.Pod != "" {
+					dstLabel = o.Pod
+				}
+			}
+		}
+	}
+	if dstLabel == "" && status.CapturedPacket != nil {
+		dstLabel = status.CapturedPacket.DstIP
+	}
+
+	d := &Diagram{
+		title: fmt.Sprintf("Traceflow %s: %s to %s", tf.Metadata.Name, srcLabel, dstLabel),
+	}
+	if status.Phase != "" {
+		d.title += fmt.Sprintf(" (%s)", status.Phase)
+	}
+	if status.Reason != "" {
+		d.title += ": " + status.Reason
+	}
+	var sender, receiver *traceflowNodeResult
+	for i := range status.Results {
+		r := &status.Results[i]
+		if sender == nil && r.isSender() {
+			sender = r
+		} else if receiver == nil && r.isReceiver() {
+			receiver = r
+		}
+	}
+	if sender == nil && receiver == nil {
+		return d, nil
+	}
+	src := diagramItem{id: "source", lines: []string{srcLabel}, node: -1, endpoint: true}
+	dst := diagramItem{id: "dest", lines: []string{dstLabel}, node: -1, endpoint: true}
+	addNode := func(r *traceflowNodeResult, name string) int {
+		node := len(d.nodes)
+		d.nodes = append(d.nodes, r.Node)
+		for i := range r.Observations {
+			o := &r.Observations[i]
+			d.items = append(d.items, diagramItem{
+				id:      fmt.Sprintf("%s_%d", name, i),
+				lines:   o.lines(),
+				node:    node,
+				blocked: o.Action == "Dropped" || o.Action == "Rejected",
+			})
+		}
+		return node
+	}
+	if sender != nil {
+		src.node = len(d.nodes)
+		d.items = append(d.items, src)
+		dst.node = addNode(sender, "cluster_source")
+	} else {
+		d.items = append(d.items, src)
+	}
+	if receiver != nil {
+		dst.node = addNode(receiver, "cluster_destination")
+	}
+	d.items = append(d.items, dst)
+	return d, nil
+}
+
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// DOT returns the Diagram in the Graphviz DOT language.
+func (d *Diagram) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph tf {\n")
+	fmt.Fprintf(&b, "\tlabel=\"%s\"\n\tlabelloc=t\n", escapeDOT(d.title))
+	writeItem := func(indent string, item *diagramItem) {
+		lines := make([]string, len(item.lines))
+		for i, line := range item.lines {
+			lines[i] = escapeDOT(line)
+		}
+		label := strings.Join(lines, `\n`)
+		switch {
+		case item.endpoint:
+			fmt.Fprintf(&b, "%s%s [style=\"filled,bold\",label=\"%s\",color=\"#808080\",fillcolor=\"#C8C8C8\"]\n", indent, item.id, label)
+		case item.blocked:
+			fmt.Fprintf(&b, "%s%s [shape=\"box\",style=\"rounded,filled,solid\",label=\"%s\",color=\"#B22222\",fillcolor=\"#F4C7C3\"]\n", indent, item.id, label)
+		default:
+			fmt.Fprintf(&b, "%s%s [shape=\"box\",style=\"rounded,filled,solid\",label=\"%s\",color=\"#808080\",fillcolor=\"#C8C8C8\"]\n", indent, item.id, label)
+		}
+	}
+	for node, name := range d.nodes {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", node)
+		fmt.Fprintf(&b, "\t\tstyle=\"filled,bold\"\n\t\tbgcolor=\"#F8F8FF\"\n\t\tlabel=\"%s\"\n", escapeDOT(name))
+		for i := range d.items {
+			if d.items[i].node == node {
+				writeItem("\t\t", &d.items[i])
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	for i := range d.items {
+		if d.items[i].node == -1 {
+			writeItem("\t", &d.items[i])
+		}
+	}
+	for i := 1; i < len(d.items); i++ {
+		fmt.Fprintf(&b, "\t%s -> %s\n", d.items[i-1].id, d.items[i].id)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// escapeMermaid escapes s for a double-quoted Mermaid label, in which HTML entities are
+// supported but quotes are not.
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// Mermaid returns the Diagram as a Mermaid flowchart.
+func (d *Diagram) Mermaid() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: \"%s\"\n---\n", escapeMermaid(d.title))
+	b.WriteString("flowchart TD\n")
+	writeItem := func(indent string, item *diagramItem) {
+		label := escapeMermaid(strings.Join(item.lines, "\n"))
+		label = strings.ReplaceAll(label, "\n", "<br/>")
+		if item.endpoint {
+			fmt.Fprintf(&b, "%s%s([\"%s\"])\n", indent, item.id, label)
+		} else {
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, item.id, label)
+		}
+	}
+	for node, name := range d.nodes {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d[\"%s\"]\n", node, escapeMermaid(name))
+		for i := range d.items {
+			if d.items[i].node == node {
+				writeItem("\t\t", &d.items[i])
+			}
+		}
+		b.WriteString("\tend\n")
+	}
+	for i := range d.items {
+		if d.items[i].node == -1 {
+			writeItem("\t", &d.items[i])
+		}
+	}
+	for i := 1; i < len(d.items); i++ {
+		fmt.Fprintf(&b, "\t%s --> %s\n", d.items[i-1].id, d.items[i].id)
+	}
+	var blocked []string
+	for i := range d.items {
+		if d.items[i].blocked {
+			blocked = append(blocked, d.items[i].id)
+		}
+	}
+	if len(blocked) > 0 {
+		b.WriteString("\tclassDef blocked fill:#F4C7C3,stroke:#B22222\n")
+		fmt.Fprintf(&b, "\tclass %s blocked\n", strings.Join(blocked, ","))
+	}
+	return b.String()
+}
+
+// Layout of the SVG rendering, in pixels. The path is drawn top to bottom, with one box per
+// item, inside one box per K8s Node.
+const (
+	svgMargin        = 20
+	svgFontSize      = 12
+	svgLineHeight    = 16
+	svgCharWidth     = 7
+	svgBoxPadding    = 8
+	svgMinBoxWidth   = 240
+	svgGap           = 28
+	svgNodePadding   = 12
+	svgNodeLabelSize = 24
+	svgTitleHeight   = 32
+)
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// SVG returns the Diagram as a standalone SVG document. The layout is computed here, so that
+// the backend does not need Graphviz.
+func (d *Diagram) SVG() string {
+	boxWidth := svgMinBoxWidth
+	for _, item := range d.items {
+		for _, line := range item.lines {
+			boxWidth = max(boxWidth, len(line)*svgCharWidth+2*svgBoxPadding)
+		}
+	}
+	width := max(2*svgMargin+boxWidth+2*svgNodePadding, len(d.title)*svgCharWidth+2*svgMargin)
+	boxX := svgMargin + svgNodePadding
+
+	type rect struct{ y, height int }
+	boxes := make([]rect, len(d.items))
+	nodeRects := make([]rect, len(d.nodes))
+	y := svgMargin + svgTitleHeight
+	for i, item := range d.items {
+		startsNode := item.node >= 0 && (i == 0 || d.items[i-1].node != item.node)
+		endsNode := item.node >= 0 && (i == len(d.items)-1 || d.items[i+1].node != item.node)
+		if startsNode {
+			nodeRects[item.node].y = y
+			y += svgNodeLabelSize
+		}
+		height := len(item.lines)*svgLineHeight + 2*svgBoxPadding
+		boxes[i] = rect{y, height}
+		y += height
+		if endsNode {
+			y += svgNodePadding
+			nodeRects[item.node].height = y - nodeRects[item.node].y
+		}
+		y += svgGap
+	}
+	height := y - svgGap + svgMargin
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`+"\n", width, height, width, height, svgFontSize)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#808080"/></marker></defs>` + "\n")
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="bold">%s</text>`+"\n", svgMargin, svgMargin+svgFontSize, escapeXML(d.title))
+	for node, name := range d.nodes {
+		r := nodeRects[node]
+		fmt.Fprintf(&b, `<g class="node"><rect x="%d" y="%d" width="%d" height="%d" fill="#F8F8FF" stroke="#808080" stroke-width="2"/>`, svgMargin, r.y, boxWidth+2*svgNodePadding, r.height)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="bold">%s</text></g>`+"\n", boxX, r.y+svgNodeLabelSize-8, escapeXML(name))
+	}
+	centerX := boxX + boxWidth/2
+	for i := 1; i < len(d.items); i++ {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#808080" stroke-width="1.5" marker-end="url(#arrow)"/>`+"\n",
+			centerX, boxes[i-1].y+boxes[i-1].height, centerX, boxes[i].y)
+	}
+	for i, item := range d.items {
+		r := boxes[i]
+		stroke, fill, rx := "#808080", "#C8C8C8", 8
+		if item.endpoint {
+			rx = r.height / 2
+		}
+		if item.blocked {
+			stroke, fill = "#B22222", "#F4C7C3"
+		}
+		class := "observation"
+		if item.endpoint {
+			class = "endpoint"
+		}
+		fmt.Fprintf(&b, `<g class="%s"><rect x="%d" y="%d" width="%d" height="%d" rx="%d" fill="%s" stroke="%s"/>`, class, boxX, r.y, boxWidth, r.height, rx, fill, stroke)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">`, centerX, r.y+svgBoxPadding)
+		for _, line := range item.lines {
+			fmt.Fprintf(&b, `<tspan x="%d" dy="%d">%s</tspan>`, centerX, svgLineHeight, escapeXML(line))
+		}
+		b.WriteString("</text></g>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
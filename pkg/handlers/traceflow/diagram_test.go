@@ -0,0 +1,153 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diagramTraceflow(receiverObservations ...interface{}) map[string]interface{} {
+	results := []interface{}{
+		map[string]interface{}{
+			"node": "node-a",
+			"observations": []interface{}{
+				map[string]interface{}{"component": "SpoofGuard", "action": "Forwarded"},
+				map[string]interface{}{"component": "Forwarding", "componentInfo": "Output", "action": "Forwarded", "tunnelDstIP": "10.0.0.2"},
+			},
+		},
+	}
+	if len(receiverObservations) > 0 {
+		results = append(results, map[string]interface{}{
+			"node":         "node-b",
+			"observations": receiverObservations,
+		})
+	}
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "tf-1"},
+		"spec": map[string]interface{}{
+			"source":      map[string]interface{}{"namespace": "default", "pod": "client"},
+			"destination": map[string]interface{}{"namespace": "default", "pod": "server"},
+		},
+		"status": map[string]interface{}{
+			"phase":   "Succeeded",
+			"results": results,
+		},
+	}
+}
+
+var droppedByPolicy = []interface{}{
+	map[string]interface{}{"component": "Forwarding", "action": "Received"},
+	map[string]interface{}{
+		"component":         "NetworkPolicy",
+		"componentInfo":     "IngressRule",
+		"action":            "Dropped",
+		"networkPolicy":     "AntreaNetworkPolicy:default/deny",
+		"networkPolicyRule": "deny-client",
+	},
+}
+
+func TestDiagramDOT(t *testing.T) {
+	d, err := NewDiagram(diagramTraceflow(droppedByPolicy...))
+	require.NoError(t, err)
+	assert.Equal(t, `digraph tf {
+	label="Traceflow tf-1: default/client to default/server (Succeeded)"
+	labelloc=t
+	subgraph cluster_0 {
+		style="filled,bold"
+		bgcolor="#F8F8FF"
+		label="node-a"
+		source [style="filled,bold",label="default/client",color="#808080",fillcolor="#C8C8C8"]
+		cluster_source_0 [shape="box",style="rounded,filled,solid",label="SpoofGuard\nForwarded",color="#808080",fillcolor="#C8C8C8"]
+		cluster_source_1 [shape="box",style="rounded,filled,solid",label="Forwarding\nOutput\nForwarded\nTunnel Destination IP: 10.0.0.2",color="#808080",fillcolor="#C8C8C8"]
+	}
+	subgraph cluster_1 {
+		style="filled,bold"
+		bgcolor="#F8F8FF"
+		label="node-b"
+		cluster_destination_0 [shape="box",style="rounded,filled,solid",label="Forwarding\nReceived",color="#808080",fillcolor="#C8C8C8"]
+		cluster_destination_1 [shape="box",style="rounded,filled,solid",label="NetworkPolicy\nIngressRule\nDropped\nNetpol: AntreaNetworkPolicy:default/deny\nRule: deny-client",color="#B22222",fillcolor="#F4C7C3"]
+		dest [style="filled,bold",label="default/server",color="#808080",fillcolor="#C8C8C8"]
+	}
+	source -> cluster_source_0
+	cluster_source_0 -> cluster_source_1
+	cluster_source_1 -> cluster_destination_0
+	cluster_destination_0 -> cluster_destination_1
+	cluster_destination_1 -> dest
+}
+`, d.DOT())
+}
+
+func TestDiagramMermaid(t *testing.T) {
+	d, err := NewDiagram(diagramTraceflow(droppedByPolicy...))
+	require.NoError(t, err)
+	mermaid := d.Mermaid()
+	assert.True(t, strings.HasPrefix(mermaid, "---\ntitle: \"Traceflow tf-1: default/client to default/server (Succeeded)\"\n---\nflowchart TD\n"))
+	for _, line := range []string{
+		"\tsubgraph cluster_1[\"node-b\"]\n",
+		"\t\tsource([\"default/client\"])\n",
+		"\t\tcluster_destination_1[\"NetworkPolicy<br/>IngressRule<br/>Dropped<br/>Netpol: AntreaNetworkPolicy:default/deny<br/>Rule: deny-client\"]\n",
+		"\tcluster_source_1 --> cluster_destination_0\n",
+		"\tclass cluster_destination_1 blocked\n",
+	} {
+		assert.Contains(t, mermaid, line)
+	}
+}
+
+func TestDiagramSVG(t *testing.T) {
+	for name, tf := range map[string]map[string]interface{}{
+		"sender and receiver": diagramTraceflow(droppedByPolicy...),
+		"sender only":         diagramTraceflow(),
+		"no results": {
+			"metadata": map[string]interface{}{"name": "tf-1"},
+			"status":   map[string]interface{}{"phase": "Failed", "reason": "<timeout>"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDiagram(tf)
+			require.NoError(t, err)
+			svg := d.SVG()
+			// The SVG must be well-formed XML.
+			decoder := xml.NewDecoder(strings.NewReader(svg))
+			var texts []string
+			for {
+				token, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				if data, ok := token.(xml.CharData); ok && strings.TrimSpace(string(data)) != "" {
+					texts = append(texts, string(data))
+				}
+			}
+			switch name {
+			case "sender and receiver":
+				assert.Contains(t, texts, "node-b")
+				assert.Contains(t, texts, "Rule: deny-client")
+				assert.Contains(t, svg, `fill="#F4C7C3"`)
+			case "sender only":
+				assert.Contains(t, texts, "node-a")
+				assert.Contains(t, texts, "default/server")
+			case "no results":
+				assert.Equal(t, []string{"Traceflow tf-1:  to  (Failed): <timeout>"}, texts)
+			}
+		})
+	}
+}
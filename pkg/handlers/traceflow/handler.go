@@ -15,7 +15,12 @@
 package traceflow
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -25,17 +30,18 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/utils/clock"
-)
 
-const (
-	traceflowExpiryTimeout = 60 * time.Minute
-	gcPeriod               = 1 * time.Minute
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
 )
 
 var (
+	// traceflowGVR is used when no CRD resolver is set.
 	traceflowGVR = schema.GroupVersionResource{
 		Group:    "crd.antrea.io",
 		Version:  "v1beta1",
@@ -47,25 +53,79 @@ var (
 	}
 )
 
+const (
+	// pinnedLabel marks a Traceflow the GC must keep. It is a label rather than an annotation
+	// so that the GC can filter pinned Traceflows out of its List.
+	pinnedLabel = "ui.antrea.io/pinned"
+	// instanceLabel records the antrea-ui install that created a Traceflow, so that installs
+	// sharing a cluster leave each other's Traceflows alone. Traceflows created before it was
+	// introduced have none, and belong to every install.
+	instanceLabel = "ui.antrea.io/instance"
+	// delegatedLabel marks a Traceflow created by antrea-ui-admin on behalf of the user named
+	// by createdByAnnotation.
+	delegatedLabel = "ui.antrea.io/delegated"
+
+	createdByAnnotation = "ui.antrea.io/created-by"
+	titleAnnotation     = "ui.antrea.io/title"
+)
+
+// GCConfig controls the garbage collection of the Traceflows created by the handler.
+type GCConfig struct {
+	// InstanceID identifies the antrea-ui install. It is recorded on every Traceflow the
+	// handler creates, and the handler only lists and deletes the Traceflows of its own
+	// install, or of none.
+	InstanceID string
+	// ExpiryTimeout is the age past which a Traceflow is deleted, unless it is pinned.
+	ExpiryTimeout time.Duration
+	// Period is how often the handler looks for expired Traceflows.
+	Period time.Duration
+}
+
 type requestsHandler struct {
 	logger logr.Logger
 	// gcClient is only used by the background GC loop, which runs with no user request in
 	// flight and so has to act as antrea-ui-admin. User-initiated operations take their client
 	// as an argument instead.
 	gcClient dynamic.Interface
+	gcConfig GCConfig
 	clock    clock.Clock
+	// crdResolver picks the versions of the Antrea CRDs. When it is nil, the latest versions
+	// are used.
+	crdResolver crdversions.Resolver
+
+	statsMutex sync.Mutex
+	stats      apisv1.TraceflowGCStats
 }
 
-func newRequestsHandlerWithClock(logger logr.Logger, gcClient dynamic.Interface, clock clock.Clock) *requestsHandler {
+func newRequestsHandlerWithClock(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig, clock clock.Clock) *requestsHandler {
 	return &requestsHandler{
 		logger:   logger,
 		gcClient: gcClient,
+		gcConfig: gcConfig,
 		clock:    clock,
+		stats: apisv1.TraceflowGCStats{
+			InstanceID:    gcConfig.InstanceID,
+			ExpiryTimeout: int64(gcConfig.ExpiryTimeout.Seconds()),
+			Period:        int64(gcConfig.Period.Seconds()),
+		},
 	}
 }
 
-func NewRequestsHandler(logger logr.Logger, gcClient dynamic.Interface) *requestsHandler {
-	return newRequestsHandlerWithClock(logger, gcClient, &clock.RealClock{})
+func NewRequestsHandler(logger logr.Logger, gcClient dynamic.Interface, gcConfig GCConfig) *requestsHandler {
+	return newRequestsHandlerWithClock(logger, gcClient, gcConfig, &clock.RealClock{})
+}
+
+// SetCRDResolver makes the handler use the versions of the Antrea CRDs picked by resolver. It must
+// be called before the handler is used.
+func (h *requestsHandler) SetCRDResolver(resolver crdversions.Resolver) {
+	h.crdResolver = resolver
+}
+
+func (h *requestsHandler) traceflowResource() schema.GroupVersionResource {
+	if h.crdResolver == nil {
+		return traceflowGVR
+	}
+	return h.crdResolver.GVR(crdversions.ResourceTraceflows)
 }
 
 func (h *requestsHandler) Run(stopCh <-chan struct{}) {
@@ -75,7 +135,7 @@ func (h *requestsHandler) Run(stopCh <-chan struct{}) {
 
 func (h *requestsHandler) CreateRequest(ctx context.Context, client dynamic.Interface, request *Request) (string, error) {
 	requestID := uuid.NewString()
-	if err := h.createTraceflow(ctx, client, requestID, request.Object); err != nil {
+	if err := h.createTraceflow(ctx, client, requestID, request); err != nil {
 		return "", err
 	}
 	return requestID, nil
@@ -87,7 +147,83 @@ func (h *requestsHandler) GetRequestResult(ctx context.Context, client dynamic.I
 
 func (h *requestsHandler) DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error) {
 	tfName := requestID
-	err := client.Resource(traceflowGVR).Delete(ctx, tfName, metav1.DeleteOptions{})
+	err := client.Resource(h.traceflowResource()).Delete(ctx, tfName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *requestsHandler) ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.TraceflowSummary, error) {
+	traceflows, err := h.listTraceflows(ctx, client, labels.SelectorFromSet(traceflowLabels))
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]apisv1.TraceflowSummary, 0, len(traceflows))
+	for idx := range traceflows {
+		summaries = append(summaries, traceflowSummary(&traceflows[idx]))
+	}
+	SortSummaries(summaries)
+	return summaries, nil
+}
+
+func (h *requestsHandler) ListDelegatedRequests(ctx context.Context, username string) ([]apisv1.TraceflowSummary, error) {
+	tfLabels := maps.Clone(traceflowLabels)
+	tfLabels[delegatedLabel] = "true"
+	traceflows, err := h.listTraceflows(ctx, h.gcClient, labels.SelectorFromSet(tfLabels))
+	if err != nil {
+		return nil, err
+	}
+	summaries := []apisv1.TraceflowSummary{}
+	for idx := range traceflows {
+		if traceflows[idx].GetAnnotations()[createdByAnnotation] == username {
+			summaries = append(summaries, traceflowSummary(&traceflows[idx]))
+		}
+	}
+	SortSummaries(summaries)
+	return summaries, nil
+}
+
+func (h *requestsHandler) IsDelegatedTo(ctx context.Context, requestID string, username string) (bool, error) {
+	tf, err := h.gcClient.Resource(h.traceflowResource()).Get(ctx, requestID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.GetLabels()[delegatedLabel] == "true" && tf.GetAnnotations()[createdByAnnotation] == username, nil
+}
+
+// SortSummaries sorts Traceflow summaries, most recent first.
+func SortSummaries(summaries []apisv1.TraceflowSummary) {
+	// RFC 3339 timestamps in UTC sort chronologically as strings.
+	slices.SortStableFunc(summaries, func(a, b apisv1.TraceflowSummary) int {
+		return cmp.Compare(b.CreationTimestamp, a.CreationTimestamp)
+	})
+}
+
+func (h *requestsHandler) SetRequestPinned(ctx context.Context, client dynamic.Interface, requestID string, pinned bool) (bool, error) {
+	tfName := requestID
+	// A JSON merge patch deletes a key set to null.
+	var value interface{}
+	if pinned {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				pinnedLabel: value,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Resource(h.traceflowResource()).Patch(ctx, tfName, types.MergePatchType, patch, metav1.PatchOptions{})
 	if apierrors.IsNotFound(err) {
 		return false, nil
 	}
@@ -96,11 +232,75 @@ func (h *requestsHandler) DeleteRequest(ctx context.Context, client dynamic.Inte
 	}
 	return true, nil
 }
+
+func (h *requestsHandler) CountRunningRequests(ctx context.Context, username string) (int, error) {
+	traceflows, err := h.listTraceflows(ctx, h.gcClient, labels.SelectorFromSet(traceflowLabels))
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for idx := range traceflows {
+		tf := &traceflows[idx]
+		if tf.GetAnnotations()[createdByAnnotation] == username && !isCompleted(tf.Object) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (h *requestsHandler) GCStats() apisv1.TraceflowGCStats {
+	h.statsMutex.Lock()
+	defer h.statsMutex.Unlock()
+	return h.stats
+}
+
+// listTraceflows lists the Traceflows matching selector that belong to this install. A selector
+// cannot match both a label value and a missing label, so the filtering happens here.
+func (h *requestsHandler) listTraceflows(ctx context.Context, client dynamic.Interface, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(h.traceflowResource()).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(list.Items, func(tf unstructured.Unstructured) bool {
+		instanceID, ok := tf.GetLabels()[instanceLabel]
+		return ok && instanceID != h.gcConfig.InstanceID
+	}), nil
+}
+
+func traceflowSummary(tf *unstructured.Unstructured) apisv1.TraceflowSummary {
+	annotations := tf.GetAnnotations()
+	summary := apisv1.TraceflowSummary{
+		ID:                tf.GetName(),
+		Title:             annotations[titleAnnotation],
+		CreatedBy:         annotations[createdByAnnotation],
+		CreationTimestamp: tf.GetCreationTimestamp().UTC().Format(time.RFC3339),
+		Pinned:            tf.GetLabels()[pinnedLabel] == "true",
+		Delegated:         tf.GetLabels()[delegatedLabel] == "true",
+	}
+	// The spec and status were written by antrea-ui and Antrea, so a field of an unexpected
+	// type is just left empty.
+	summary.StartTime, _, _ = unstructured.NestedString(tf.Object, "status", "startTime")
+	summary.Phase, _, _ = unstructured.NestedString(tf.Object, "status", "phase")
+	summary.Reason, _, _ = unstructured.NestedString(tf.Object, "status", "reason")
+	summary.LiveTraffic, _, _ = unstructured.NestedBool(tf.Object, "spec", "liveTraffic")
+	summary.Source.Namespace, _, _ = unstructured.NestedString(tf.Object, "spec", "source", "namespace")
+	summary.Source.Pod, _, _ = unstructured.NestedString(tf.Object, "spec", "source", "pod")
+	summary.Source.IP, _, _ = unstructured.NestedString(tf.Object, "spec", "source", "ip")
+	summary.Destination.Namespace, _, _ = unstructured.NestedString(tf.Object, "spec", "destination", "namespace")
+	summary.Destination.Pod, _, _ = unstructured.NestedString(tf.Object, "spec", "destination", "pod")
+	summary.Destination.Service, _, _ = unstructured.NestedString(tf.Object, "spec", "destination", "service")
+	summary.Destination.IP, _, _ = unstructured.NestedString(tf.Object, "spec", "destination", "ip")
+	return summary
+}
+
 func (h *requestsHandler) getTraceflow(ctx context.Context, client dynamic.Interface, tfName string) (map[string]interface{}, bool, error) {
-	traceflow, err := client.Resource(traceflowGVR).Get(ctx, tfName, metav1.GetOptions{})
+	traceflow, err := client.Resource(h.traceflowResource()).Get(ctx, tfName, metav1.GetOptions{})
 	if err != nil {
 		return nil, false, err
 	}
+	toV1beta1(traceflow)
 	phase, ok, err := unstructured.NestedString(traceflow.Object, "status", "phase")
 	if err != nil {
 		return nil, false, err
@@ -111,51 +311,95 @@ func (h *requestsHandler) getTraceflow(ctx context.Context, client dynamic.Inter
 	return traceflow.Object, (phase == "Succeeded" || phase == "Failed"), nil
 }
 
-func (h *requestsHandler) createTraceflow(ctx context.Context, client dynamic.Interface, tfName string, object map[string]interface{}) error {
+func (h *requestsHandler) createTraceflow(ctx context.Context, client dynamic.Interface, tfName string, request *Request) error {
+	gvr := h.traceflowResource()
 	traceflow := &unstructured.Unstructured{
 		Object: map[string]interface{}{
-			"apiVersion": traceflowGVR.Group + "/" + traceflowGVR.Version,
+			"apiVersion": gvr.GroupVersion().String(),
 			"kind":       "Traceflow",
 			"metadata": map[string]interface{}{
 				"name": tfName,
 			},
-			"spec": object["spec"],
+			"spec": h.specToResolvedVersion(request.Object["spec"]),
 		},
 	}
-	traceflow.SetLabels(traceflowLabels)
-	if _, err := client.Resource(traceflowGVR).Create(ctx, traceflow, metav1.CreateOptions{}); err != nil {
+	tfLabels := maps.Clone(traceflowLabels)
+	tfLabels[instanceLabel] = h.gcConfig.InstanceID
+	if request.Delegated {
+		tfLabels[delegatedLabel] = "true"
+	}
+	traceflow.SetLabels(tfLabels)
+	annotations := map[string]string{}
+	if request.Username != "" {
+		annotations[createdByAnnotation] = request.Username
+	}
+	if request.Title != "" {
+		annotations[titleAnnotation] = request.Title
+	}
+	if len(annotations) > 0 {
+		traceflow.SetAnnotations(annotations)
+	}
+	if _, err := client.Resource(gvr).Create(ctx, traceflow, metav1.CreateOptions{}); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (h *requestsHandler) doGC(ctx context.Context) {
-	list, err := h.gcClient.Resource(traceflowGVR).List(ctx, metav1.ListOptions{
-		LabelSelector: labels.Set(traceflowLabels).String(),
-	})
+	deleted, errs := h.deleteExpiredTraceflows(ctx)
+	h.statsMutex.Lock()
+	defer h.statsMutex.Unlock()
+	h.stats.Runs++
+	h.stats.LastRun = h.clock.Now().UTC().Format(time.RFC3339)
+	h.stats.LastRunDeleted = deleted
+	h.stats.TotalDeleted += int64(deleted)
+	h.stats.Errors += int64(errs)
+}
+
+// deleteExpiredTraceflows returns the number of Traceflows deleted, and of errors.
+func (h *requestsHandler) deleteExpiredTraceflows(ctx context.Context) (int, int) {
+	selector := labels.SelectorFromSet(traceflowLabels)
+	notPinned, err := labels.NewRequirement(pinnedLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		h.logger.Error(err, "Error when building label selector")
+		return 0, 1
+	}
+	traceflows, err := h.listTraceflows(ctx, h.gcClient, selector.Add(*notPinned))
 	if err != nil {
 		h.logger.Error(err, "Error when listing traceflows")
-		return
+		return 0, 1
 	}
 	expiredTraceflows := []string{}
 	now := h.clock.Now()
-	for idx := range list.Items {
-		tf := &list.Items[idx]
+	for idx := range traceflows {
+		tf := &traceflows[idx]
 		creationTimestamp := tf.GetCreationTimestamp()
-		if now.Sub(creationTimestamp.Time) > traceflowExpiryTimeout {
+		if now.Sub(creationTimestamp.Time) > h.gcConfig.ExpiryTimeout {
 			expiredTraceflows = append(expiredTraceflows, tf.GetName())
 		}
 	}
+	deleted, errs := 0, 0
 	for _, tfName := range expiredTraceflows {
-		if err := h.gcClient.Resource(traceflowGVR).Delete(ctx, tfName, metav1.DeleteOptions{}); err != nil {
+		err := h.gcClient.Resource(h.traceflowResource()).Delete(ctx, tfName, metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
 			h.logger.Error(err, "Error when deleting expired traceflow", "name", tfName)
+			errs++
+			continue
 		}
+		deleted++
+	}
+	if deleted > 0 {
+		h.logger.V(2).Info("Deleted expired traceflows", "count", deleted)
 	}
+	return deleted, errs
 }
 
 func (h *requestsHandler) runGC(stopCh <-chan struct{}) {
 	ctx := wait.ContextForChannel(stopCh)
 	//lint:ignore SA1019 apimachinery doesn't provide a correct alternative yet
-	go wait.BackoffUntil(func() { h.doGC(ctx) }, wait.NewJitteredBackoffManager(gcPeriod, 0.0, h.clock), true, stopCh)
+	go wait.BackoffUntil(func() { h.doGC(ctx) }, wait.NewJitteredBackoffManager(h.gcConfig.Period, 0.0, h.clock), true, stopCh)
 	<-stopCh
 }
@@ -19,23 +19,36 @@ import (
 	"time"
 
 	"github.com/go-logr/logr/testr"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/utils/clock"
 	clocktesting "k8s.io/utils/clock/testing"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+	crdversionstesting "antrea.io/antrea-ui/pkg/handlers/crdversions/testing"
 )
 
+var testGCConfig = GCConfig{
+	InstanceID:    "test",
+	ExpiryTimeout: 60 * time.Minute,
+	Period:        1 * time.Minute,
+}
+
 func setup(t *testing.T, clock clock.Clock) (*requestsHandler, *dynamicfake.FakeDynamicClient) {
 	logger := testr.New(t)
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypeWithName(traceflowGVR.GroupVersion().WithKind("TraceflowList"), &unstructured.UnstructuredList{})
 	k8sClient := dynamicfake.NewSimpleDynamicClient(scheme)
-	handler := newRequestsHandlerWithClock(logger, k8sClient, clock)
+	handler := newRequestsHandlerWithClock(logger, k8sClient, testGCConfig, clock)
 	return handler, k8sClient
 }
 
@@ -137,15 +150,343 @@ func TestRequestsHandlerGC(t *testing.T) {
 	_, err = k8sClient.Resource(traceflowGVR).Get(ctx, tfName, metav1.GetOptions{})
 	require.NoError(t, err)
 
-	clock.SetTime(now.Add(traceflowExpiryTimeout - 1*time.Minute))
+	clock.SetTime(now.Add(testGCConfig.ExpiryTimeout - 1*time.Minute))
 	assert.Never(t, func() bool {
 		_, err := k8sClient.Resource(traceflowGVR).Get(ctx, tfName, metav1.GetOptions{})
 		return err != nil
 	}, 1*time.Second, 100*time.Millisecond)
 
-	clock.SetTime(now.Add(traceflowExpiryTimeout + 1*time.Minute))
+	clock.SetTime(now.Add(testGCConfig.ExpiryTimeout + 1*time.Minute))
 	assert.Eventually(t, func() bool {
 		_, err := k8sClient.Resource(traceflowGVR).Get(ctx, tfName, metav1.GetOptions{})
 		return err != nil
 	}, 1*time.Second, 100*time.Millisecond, "Traceflow should be deleted by GC")
 }
+
+func TestRequestsHandlerHistory(t *testing.T) {
+	ctx := t.Context()
+	now := time.Now()
+	clock := clocktesting.NewFakeClock(now)
+	h, k8sClient := setup(t, clock)
+	k8sClient.PrependReactor("create", "traceflows", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tf := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		tf.SetCreationTimestamp(metav1.NewTime(clock.Now()))
+		return false, tf, nil
+	})
+
+	firstID, err := h.CreateRequest(ctx, k8sClient, &Request{
+		Object:   getTraceflow(),
+		Username: "alice",
+		Title:    "checking the web tier",
+	})
+	require.NoError(t, err)
+	clock.Step(time.Minute)
+	secondID, err := h.CreateRequest(ctx, k8sClient, &Request{
+		Object: getTraceflow(),
+	})
+	require.NoError(t, err)
+
+	tf, err := k8sClient.Resource(traceflowGVR).Get(ctx, firstID, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		createdByAnnotation: "alice",
+		titleAnnotation:     "checking the web tier",
+	}, tf.GetAnnotations())
+	tf.Object["status"] = map[string]interface{}{
+		"phase":     "Succeeded",
+		"startTime": now.UTC().Format(time.RFC3339),
+	}
+	_, err = k8sClient.Resource(traceflowGVR).Update(ctx, tf, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	found, err := h.SetRequestPinned(ctx, k8sClient, firstID, true)
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = h.SetRequestPinned(ctx, k8sClient, "missing", true)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	summaries, err := h.ListRequests(ctx, k8sClient)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, secondID, summaries[0].ID, "most recent Traceflow should be first")
+	assert.False(t, summaries[0].Pinned)
+	assert.Equal(t, apisv1.TraceflowSummary{
+		ID:                firstID,
+		Title:             "checking the web tier",
+		CreatedBy:         "alice",
+		CreationTimestamp: now.UTC().Format(time.RFC3339),
+		StartTime:         now.UTC().Format(time.RFC3339),
+		Phase:             "Succeeded",
+		Source:            apisv1.TraceflowSource{Namespace: "default", Pod: "podX"},
+		Destination:       apisv1.TraceflowDestination{Namespace: "default", Pod: "podY"},
+		Pinned:            true,
+	}, summaries[1])
+
+	// Both are expired, but the pinned one must be kept.
+	clock.SetTime(now.Add(testGCConfig.ExpiryTimeout + 2*time.Minute))
+	h.doGC(ctx)
+	_, err = k8sClient.Resource(traceflowGVR).Get(ctx, firstID, metav1.GetOptions{})
+	assert.NoError(t, err, "pinned Traceflow should not be deleted by GC")
+	_, err = k8sClient.Resource(traceflowGVR).Get(ctx, secondID, metav1.GetOptions{})
+	assert.Error(t, err, "expired Traceflow should be deleted by GC")
+
+	found, err = h.SetRequestPinned(ctx, k8sClient, firstID, false)
+	require.NoError(t, err)
+	assert.True(t, found)
+	h.doGC(ctx)
+	_, err = k8sClient.Resource(traceflowGVR).Get(ctx, firstID, metav1.GetOptions{})
+	assert.Error(t, err, "unpinned Traceflow should be deleted by GC")
+}
+
+func TestRequestsHandlerInstances(t *testing.T) {
+	ctx := t.Context()
+	now := time.Now()
+	clock := clocktesting.NewFakeClock(now)
+	h, k8sClient := setup(t, clock)
+	k8sClient.PrependReactor("create", "traceflows", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tf := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		tf.SetCreationTimestamp(metav1.NewTime(clock.Now()))
+		return false, tf, nil
+	})
+
+	ownID, err := h.CreateRequest(ctx, k8sClient, &Request{Object: getTraceflow()})
+	require.NoError(t, err)
+	tf, err := k8sClient.Resource(traceflowGVR).Get(ctx, ownID, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ui.antrea.io": "", instanceLabel: "test"}, tf.GetLabels())
+	for name, tfLabels := range map[string]map[string]string{
+		// Created by another install sharing the cluster.
+		"other": {"ui.antrea.io": "", instanceLabel: "staging"},
+		// Created before instance labels were introduced.
+		"legacy": {"ui.antrea.io": ""},
+	} {
+		tf := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": traceflowGVR.Group + "/" + traceflowGVR.Version,
+			"kind":       "Traceflow",
+			"metadata":   map[string]interface{}{"name": name},
+		}}
+		tf.SetLabels(tfLabels)
+		_, err := k8sClient.Resource(traceflowGVR).Create(ctx, tf, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	summaries, err := h.ListRequests(ctx, k8sClient)
+	require.NoError(t, err)
+	var ids []string
+	for _, summary := range summaries {
+		ids = append(ids, summary.ID)
+	}
+	assert.ElementsMatch(t, []string{ownID, "legacy"}, ids)
+
+	assert.Equal(t, apisv1.TraceflowGCStats{InstanceID: "test", ExpiryTimeout: 3600, Period: 60}, h.GCStats())
+	clock.SetTime(now.Add(testGCConfig.ExpiryTimeout + time.Minute))
+	h.doGC(ctx)
+	list, err := k8sClient.Resource(traceflowGVR).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "other", list.Items[0].GetName(), "another install's Traceflow should not be deleted by GC")
+	h.doGC(ctx)
+	assert.Equal(t, apisv1.TraceflowGCStats{
+		InstanceID:     "test",
+		ExpiryTimeout:  3600,
+		Period:         60,
+		Runs:           2,
+		LastRun:        clock.Now().UTC().Format(time.RFC3339),
+		LastRunDeleted: 0,
+		TotalDeleted:   2,
+	}, h.GCStats())
+}
+
+func TestRequestsHandlerCountRunningRequests(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{})
+	var aliceIDs []string
+	for _, username := range []string{"alice", "alice", "alice", "bob", ""} {
+		requestID, err := h.CreateRequest(ctx, k8sClient, &Request{
+			Object:   getTraceflow(),
+			Username: username,
+		})
+		require.NoError(t, err)
+		if username == "alice" {
+			aliceIDs = append(aliceIDs, requestID)
+		}
+	}
+	for idx, phase := range []string{"Succeeded", "Running"} {
+		tf, err := k8sClient.Resource(traceflowGVR).Get(ctx, aliceIDs[idx], metav1.GetOptions{})
+		require.NoError(t, err)
+		tf.Object["status"] = map[string]interface{}{"phase": phase}
+		_, err = k8sClient.Resource(traceflowGVR).Update(ctx, tf, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	count, err := h.CountRunningRequests(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = h.CountRunningRequests(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = h.CountRunningRequests(ctx, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestRequestsHandlerDelegated(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{})
+	create := func(username string, delegated bool) string {
+		requestID, err := h.CreateRequest(ctx, k8sClient, &Request{
+			Object:    getTraceflow(),
+			Username:  username,
+			Delegated: delegated,
+		})
+		require.NoError(t, err)
+		return requestID
+	}
+	aliceDelegatedID := create("alice", true)
+	aliceID := create("alice", false)
+	bobDelegatedID := create("bob", true)
+
+	summaries, err := h.ListDelegatedRequests(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, aliceDelegatedID, summaries[0].ID)
+	assert.True(t, summaries[0].Delegated)
+	summaries, err = h.ListDelegatedRequests(ctx, "carol")
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+
+	for _, tc := range []struct {
+		requestID string
+		username  string
+		expected  bool
+	}{
+		{aliceDelegatedID, "alice", true},
+		{aliceID, "alice", false},
+		{bobDelegatedID, "alice", false},
+		{bobDelegatedID, "bob", true},
+		{"missing", "alice", false},
+	} {
+		delegated, err := h.IsDelegatedTo(ctx, tc.requestID, tc.username)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, delegated, "request %s, user %s", tc.requestID, tc.username)
+	}
+}
+
+func TestRequestsHandlerWatch(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{})
+	watchers := make(chan *watch.FakeWatcher, 2)
+	k8sClient.PrependWatchReactor("traceflows", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		watchers <- w
+		return true, w, nil
+	})
+
+	_, err := h.WatchRequest(ctx, k8sClient, "missing")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	requestID, err := h.CreateRequest(ctx, k8sClient, &Request{Object: getTraceflow()})
+	require.NoError(t, err)
+	updates, err := h.WatchRequest(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+
+	withPhase := func(phase string) *unstructured.Unstructured {
+		tf, err := k8sClient.Resource(traceflowGVR).Get(ctx, requestID, metav1.GetOptions{})
+		require.NoError(t, err)
+		tf.Object["status"] = map[string]interface{}{"phase": phase}
+		_, err = k8sClient.Resource(traceflowGVR).Update(ctx, tf, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		return tf
+	}
+	nextUpdate := func() RequestUpdate {
+		select {
+		case u, ok := <-updates:
+			require.True(t, ok, "updates channel should not be closed yet")
+			return u
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for update")
+			return RequestUpdate{}
+		}
+	}
+	assertPhase := func(u RequestUpdate, phase string) {
+		require.NoError(t, u.Err)
+		actual, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+		assert.Equal(t, phase, actual)
+	}
+
+	u := nextUpdate()
+	assertPhase(u, "")
+	assert.False(t, u.Done)
+
+	w := <-watchers
+	w.Modify(withPhase("Running"))
+	u = nextUpdate()
+	assertPhase(u, "Running")
+	assert.False(t, u.Done)
+
+	// An expired resourceVersion makes the handler get the current state and watch again.
+	running := withPhase("Running")
+	w.Error(&apierrors.NewResourceExpired("too old").ErrStatus)
+	assertPhase(nextUpdate(), "Running")
+	w = <-watchers
+
+	w.Modify(running)
+	assertPhase(nextUpdate(), "Running")
+	w.Modify(withPhase("Succeeded"))
+	u = nextUpdate()
+	assertPhase(u, "Succeeded")
+	assert.True(t, u.Done)
+	_, ok := <-updates
+	assert.False(t, ok, "updates channel should be closed after the result")
+}
+
+func TestCRDResolver(t *testing.T) {
+	ctx := t.Context()
+	h, k8sClient := setup(t, &clock.RealClock{})
+	v1alpha1GVR := traceflowGVR.GroupResource().WithVersion("v1alpha1")
+	resolver := crdversionstesting.NewMockResolver(gomock.NewController(t))
+	resolver.EXPECT().GVR(crdversions.ResourceTraceflows).Return(v1alpha1GVR).AnyTimes()
+	h.SetCRDResolver(resolver)
+
+	request := &Request{Object: getTraceflow()}
+	packet := map[string]interface{}{
+		"ipv6Header": map[string]interface{}{"nextHeader": int64(6)},
+		"transportHeader": map[string]interface{}{
+			"tcp": map[string]interface{}{"dstPort": int64(80), "flags": int64(0)},
+		},
+	}
+	request.Object["spec"].(map[string]interface{})["packet"] = packet
+	requestID, err := h.CreateRequest(ctx, k8sClient, request)
+	require.NoError(t, err)
+	tf, err := k8sClient.Resource(v1alpha1GVR).Get(ctx, requestID, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "crd.antrea.io/v1alpha1", tf.GetAPIVersion())
+	_, err = k8sClient.Resource(traceflowGVR).Get(ctx, requestID, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "Traceflow should only exist in the resolved version")
+	// v1alpha1 cannot express TCP flags of 0.
+	_, found, _ := unstructured.NestedFieldNoCopy(tf.Object, "spec", "packet", "transportHeader", "tcp", "flags")
+	assert.False(t, found)
+	assert.Contains(t, packet["transportHeader"].(map[string]interface{})["tcp"], "flags", "The request should not be modified")
+
+	// v1alpha1 always sets the IPv4 header of packets.
+	require.NoError(t, unstructured.SetNestedField(tf.Object, map[string]interface{}{}, "spec", "packet", "ipHeader"))
+	require.NoError(t, unstructured.SetNestedMap(tf.Object, map[string]interface{}{
+		"ipHeader":   map[string]interface{}{},
+		"ipv6Header": map[string]interface{}{"nextHeader": int64(6)},
+	}, "status", "capturedPacket"))
+	require.NoError(t, unstructured.SetNestedField(tf.Object, "Succeeded", "status", "phase"))
+	_, err = k8sClient.Resource(v1alpha1GVR).Update(ctx, tf, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	result, done, err := h.GetRequestResult(ctx, k8sClient, requestID)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "crd.antrea.io/v1beta1", result["apiVersion"])
+	_, found, _ = unstructured.NestedFieldNoCopy(result, "spec", "packet", "ipHeader")
+	assert.False(t, found)
+	_, found, _ = unstructured.NestedFieldNoCopy(result, "status", "capturedPacket", "ipHeader")
+	assert.False(t, found)
+	_, found, _ = unstructured.NestedFieldNoCopy(result, "status", "capturedPacket", "ipv6Header")
+	assert.True(t, found)
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 
 	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
 )
 
 //go:generate mockgen -source=interface.go -package=testing -destination=testing/mock_interface.go -copyright_file=$MOCKGEN_COPYRIGHT_FILE
@@ -34,4 +36,34 @@ type RequestsHandler interface {
 	// updated to "Succeeded" or "Failed".
 	GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error)
 	DeleteRequest(ctx context.Context, client dynamic.Interface, requestID string) (bool, error)
+	// WatchRequest returns a channel that receives the Traceflow object now and every time it
+	// changes, until the Traceflow request is completed (the last update is then the result),
+	// an error occurs, or ctx is cancelled. The channel is closed after the last update. An
+	// error getting the Traceflow in the first place, e.g. because it does not exist, is
+	// returned directly.
+	WatchRequest(ctx context.Context, client dynamic.Interface, requestID string) (<-chan RequestUpdate, error)
+	// ListRequests returns the Traceflows created by antrea-ui that client can list, most
+	// recent first.
+	ListRequests(ctx context.Context, client dynamic.Interface) ([]apisv1.TraceflowSummary, error)
+	// SetRequestPinned pins or unpins a Traceflow. Pinned Traceflows are never
+	// garbage-collected. It returns false if the Traceflow does not exist.
+	SetRequestPinned(ctx context.Context, client dynamic.Interface, requestID string, pinned bool) (bool, error)
+	// CountRunningRequests returns the number of Traceflows created by username that are not
+	// completed yet. It uses the handler's own client, as the user may not be able to list
+	// every Traceflow they created.
+	CountRunningRequests(ctx context.Context, username string) (int, error)
+	// ResolvePolicies adds, to every observation of traceflow that names a NetworkPolicy, the
+	// details of that policy as "policyDetails" (see apisv1.TraceflowPolicyDetails). The
+	// policies are fetched with client. A policy that cannot be fetched is reported in its
+	// details, and only a rejected credential is returned as an error.
+	ResolvePolicies(ctx context.Context, client dynamic.Interface, traceflow map[string]interface{}) error
+	// ListDelegatedRequests returns the delegated Traceflows created by username, most recent
+	// first. It uses the handler's own client, as the user may not be able to list Traceflows
+	// at all.
+	ListDelegatedRequests(ctx context.Context, username string) ([]apisv1.TraceflowSummary, error)
+	// IsDelegatedTo reports whether requestID is a delegated Traceflow created by username, using
+	// the handler's own client. A Traceflow that does not exist is not delegated.
+	IsDelegatedTo(ctx context.Context, requestID string, username string) (bool, error)
+	// GCStats describes the garbage collection of expired Traceflows.
+	GCStats() apisv1.TraceflowGCStats
 }
@@ -0,0 +1,198 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+)
+
+// policyRef is a policy named by an observation.
+type policyRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func parsePolicyRef(ref string) policyRef {
+	kind, namespacedName, _ := strings.Cut(ref, ":")
+	namespace, name, ok := strings.Cut(namespacedName, "/")
+	if !ok {
+		return policyRef{kind: kind, name: namespacedName}
+	}
+	return policyRef{kind: kind, namespace: namespace, name: name}
+}
+
+// policyResult is a policy as fetched from the API server, or the error getting it.
+type policyResult struct {
+	policy *unstructured.Unstructured
+	err    error
+}
+
+func (h *requestsHandler) ResolvePolicies(ctx context.Context, client dynamic.Interface, traceflow map[string]interface{}) error {
+	var startTime time.Time
+	if s, ok, _ := unstructured.NestedString(traceflow, "status", "startTime"); ok {
+		// A Traceflow with no (valid) start time is never reported as changed.
+		startTime, _ = time.Parse(time.RFC3339, s)
+	}
+	status, _ := traceflow["status"].(map[string]interface{})
+	results, _ := status["results"].([]interface{})
+	// An observation is reported by every Node the packet went through, so the same policy
+	// may be named several times.
+	policies := map[policyRef]*policyResult{}
+	for _, result := range results {
+		result, _ := result.(map[string]interface{})
+		observations, _ := result["observations"].([]interface{})
+		for _, observation := range observations {
+			observation, ok := observation.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, _ := observation["networkPolicy"].(string)
+			if ref == "" {
+				continue
+			}
+			policyRef := parsePolicyRef(ref)
+			res, ok := policies[policyRef]
+			if !ok {
+				res = h.getPolicy(ctx, client, policyRef)
+				if apierrors.IsUnauthorized(res.err) {
+					return res.err
+				}
+				policies[policyRef] = res
+			}
+			componentInfo, _ := observation["componentInfo"].(string)
+			ruleName, _ := observation["networkPolicyRule"].(string)
+			details := policyDetails(policyRef, res, componentInfo, ruleName, startTime)
+			detailsObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(details)
+			if err != nil {
+				return fmt.Errorf("error when converting policy details: %w", err)
+			}
+			observation["policyDetails"] = detailsObj
+		}
+	}
+	return nil
+}
+
+func (h *requestsHandler) getPolicy(ctx context.Context, client dynamic.Interface, ref policyRef) *policyResult {
+	gvr, ok := crdversions.PolicyGVR(h.crdResolver, ref.kind)
+	if !ok {
+		return &policyResult{err: fmt.Errorf("unsupported policy kind %q", ref.kind)}
+	}
+	var policy *unstructured.Unstructured
+	var err error
+	if ref.namespace == "" {
+		policy, err = client.Resource(gvr).Get(ctx, ref.name, metav1.GetOptions{})
+	} else {
+		policy, err = client.Resource(gvr).Namespace(ref.namespace).Get(ctx, ref.name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return &policyResult{err: fmt.Errorf("policy not found, it may have been deleted since the Traceflow ran")}
+	}
+	return &policyResult{policy: policy, err: err}
+}
+
+func policyDetails(ref policyRef, res *policyResult, componentInfo string, ruleName string, startTime time.Time) *apisv1.TraceflowPolicyDetails {
+	details := &apisv1.TraceflowPolicyDetails{
+		Kind:      ref.kind,
+		Namespace: ref.namespace,
+		Name:      ref.name,
+	}
+	if res.err != nil {
+		details.Error = res.err.Error()
+		return details
+	}
+	policy := res.policy
+	details.UID = string(policy.GetUID())
+	details.Tier, _, _ = unstructured.NestedString(policy.Object, "spec", "tier")
+	// JSON numbers are decoded as int64 when they have no fractional part.
+	priority, _, _ := unstructured.NestedFieldNoCopy(policy.Object, "spec", "priority")
+	switch priority := priority.(type) {
+	case float64:
+		details.Priority = &priority
+	case int64:
+		p := float64(priority)
+		details.Priority = &p
+	}
+	details.Rule = findPolicyRule(policy, componentInfo, ruleName)
+	details.ChangedSinceTraceflow = policyChangedSince(policy, startTime)
+	return details
+}
+
+// findPolicyRule returns the rule named ruleName in policy. componentInfo, e.g. "IngressRule",
+// tells the direction of the rule. Only Antrea-native policies have named rules: a
+// K8s NetworkPolicy drops traffic through its default isolation, not a rule.
+func findPolicyRule(policy *unstructured.Unstructured, componentInfo string, ruleName string) *apisv1.TraceflowPolicyRule {
+	if ruleName == "" {
+		return nil
+	}
+	directions := []string{"Ingress", "Egress"}
+	for _, direction := range directions {
+		if strings.HasPrefix(componentInfo, direction) {
+			directions = []string{direction}
+			break
+		}
+	}
+	for _, direction := range directions {
+		rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", strings.ToLower(direction))
+		for _, rule := range rules {
+			rule, ok := rule.(map[string]interface{})
+			if !ok || rule["name"] != ruleName {
+				continue
+			}
+			peersField := "from"
+			if direction == "Egress" {
+				peersField = "to"
+			}
+			r := &apisv1.TraceflowPolicyRule{
+				Name:      ruleName,
+				Direction: direction,
+			}
+			r.Action, _ = rule["action"].(string)
+			r.Peers, _ = rule[peersField].([]interface{})
+			r.Ports, _ = rule["ports"].([]interface{})
+			return r
+		}
+	}
+	return nil
+}
+
+// policyChangedSince returns true if policy was created, or its spec updated, after t. Antrea's
+// own status updates are not changes to the policy.
+func policyChangedSince(policy *unstructured.Unstructured, t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	if policy.GetCreationTimestamp().After(t) {
+		return true
+	}
+	for _, entry := range policy.GetManagedFields() {
+		if entry.Subresource == "" && entry.Time != nil && entry.Time.After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+)
+
+func TestResolvePolicies(t *testing.T) {
+	startTime := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	acnp := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "crd.antrea.io/v1beta1",
+		"kind":       "ClusterNetworkPolicy",
+		"metadata":   map[string]interface{}{"name": "deny-web", "uid": "acnp-uid"},
+		"spec": map[string]interface{}{
+			"tier":     "securityops",
+			"priority": int64(5),
+			"ingress": []interface{}{
+				map[string]interface{}{
+					"name":   "deny-client",
+					"action": "Drop",
+					"from":   []interface{}{map[string]interface{}{"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "client"}}}},
+					"ports":  []interface{}{map[string]interface{}{"protocol": "TCP", "port": int64(80)}},
+				},
+			},
+		},
+	}}
+	acnp.SetCreationTimestamp(metav1.NewTime(startTime.Add(-time.Hour)))
+	acnp.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Time: &metav1.Time{Time: startTime.Add(-time.Hour)}},
+		// Antrea updating the status is not a change to the policy.
+		{Manager: "antrea-controller", Subresource: "status", Time: &metav1.Time{Time: startTime.Add(time.Minute)}},
+	})
+	k8sNP := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "NetworkPolicy",
+		"metadata":   map[string]interface{}{"namespace": "web", "name": "default-deny", "uid": "np-uid"},
+		"spec":       map[string]interface{}{"podSelector": map[string]interface{}{}},
+	}}
+	k8sNP.SetCreationTimestamp(metav1.NewTime(startTime.Add(-time.Hour)))
+	k8sNP.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Time: &metav1.Time{Time: startTime.Add(time.Minute)}},
+	})
+	scheme := runtime.NewScheme()
+	client := dynamicfake.NewSimpleDynamicClient(scheme, acnp, k8sNP)
+	client.PrependReactor("get", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "secret" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "crd.antrea.io", Resource: "networkpolicies"}, "hidden", nil)
+		}
+		return false, nil, nil
+	})
+	h := newRequestsHandlerWithClock(testr.New(t), client, testGCConfig, &clock.RealClock{})
+
+	observation := func(fields ...string) map[string]interface{} {
+		o := map[string]interface{}{"component": "NetworkPolicy", "action": "Dropped"}
+		for i := 0; i < len(fields); i += 2 {
+			o[fields[i]] = fields[i+1]
+		}
+		return o
+	}
+	traceflow := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":     "Succeeded",
+			"startTime": startTime.Format(time.RFC3339),
+			"results": []interface{}{
+				map[string]interface{}{
+					"node": "node-a",
+					"observations": []interface{}{
+						map[string]interface{}{"component": "Forwarding", "action": "Forwarded"},
+						observation("componentInfo", "IngressRule", "networkPolicy", "AntreaClusterNetworkPolicy:deny-web", "networkPolicyRule", "deny-client"),
+						observation("componentInfo", "IngressDefaultRule", "networkPolicy", "K8sNetworkPolicy:web/default-deny"),
+					},
+				},
+				map[string]interface{}{
+					"node": "node-b",
+					"observations": []interface{}{
+						observation("componentInfo", "EgressRule", "networkPolicy", "AntreaNetworkPolicy:web/gone", "networkPolicyRule", "r1"),
+						observation("componentInfo", "EgressRule", "networkPolicy", "AntreaNetworkPolicy:secret/hidden", "networkPolicyRule", "r1"),
+						observation("networkPolicy", "AdminNetworkPolicy:anp"),
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, h.ResolvePolicies(t.Context(), client, traceflow))
+
+	results := traceflow["status"].(map[string]interface{})["results"].([]interface{})
+	details := func(result, observation int) interface{} {
+		return results[result].(map[string]interface{})["observations"].([]interface{})[observation].(map[string]interface{})["policyDetails"]
+	}
+	assert.Nil(t, details(0, 0))
+	assert.Equal(t, map[string]interface{}{
+		"kind":     "AntreaClusterNetworkPolicy",
+		"name":     "deny-web",
+		"uid":      "acnp-uid",
+		"tier":     "securityops",
+		"priority": float64(5),
+		"rule": map[string]interface{}{
+			"name":      "deny-client",
+			"direction": "Ingress",
+			"action":    "Drop",
+			"peers":     []interface{}{map[string]interface{}{"podSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "client"}}}},
+			"ports":     []interface{}{map[string]interface{}{"protocol": "TCP", "port": int64(80)}},
+		},
+		"changedSinceTraceflow": false,
+	}, details(0, 1))
+	assert.Equal(t, map[string]interface{}{
+		"kind":                  "K8sNetworkPolicy",
+		"namespace":             "web",
+		"name":                  "default-deny",
+		"uid":                   "np-uid",
+		"changedSinceTraceflow": true,
+	}, details(0, 2))
+	assert.Equal(t, map[string]interface{}{
+		"kind":                  "AntreaNetworkPolicy",
+		"namespace":             "web",
+		"name":                  "gone",
+		"changedSinceTraceflow": false,
+		"error":                 "policy not found, it may have been deleted since the Traceflow ran",
+	}, details(1, 0))
+	assert.Contains(t, details(1, 1).(map[string]interface{})["error"], "forbidden")
+	assert.Equal(t, `unsupported policy kind "AdminNetworkPolicy"`, details(1, 2).(map[string]interface{})["error"])
+}
+
+func TestResolvePoliciesUnauthorized(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewUnauthorized("token expired")
+	})
+	h := newRequestsHandlerWithClock(testr.New(t), client, testGCConfig, &clock.RealClock{})
+	traceflow := map[string]interface{}{
+		"status": map[string]interface{}{
+			"results": []interface{}{map[string]interface{}{
+				"observations": []interface{}{map[string]interface{}{"networkPolicy": "K8sNetworkPolicy:web/default-deny"}},
+			}},
+		},
+	}
+	err := h.ResolvePolicies(t.Context(), client, traceflow)
+	assert.True(t, apierrors.IsUnauthorized(err))
+}
@@ -23,6 +23,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	v1 "antrea.io/antrea-ui/apis/v1"
 	traceflow "antrea.io/antrea-ui/pkg/handlers/traceflow"
 	gomock "github.com/golang/mock/gomock"
 	dynamic "k8s.io/client-go/dynamic"
@@ -51,6 +52,21 @@ func (m *MockRequestsHandler) EXPECT() *MockRequestsHandlerMockRecorder {
 	return m.recorder
 }
 
+// CountRunningRequests mocks base method.
+func (m *MockRequestsHandler) CountRunningRequests(ctx context.Context, username string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRunningRequests", ctx, username)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRunningRequests indicates an expected call of CountRunningRequests.
+func (mr *MockRequestsHandlerMockRecorder) CountRunningRequests(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRunningRequests", reflect.TypeOf((*MockRequestsHandler)(nil).CountRunningRequests), ctx, username)
+}
+
 // CreateRequest mocks base method.
 func (m *MockRequestsHandler) CreateRequest(ctx context.Context, client dynamic.Interface, request *traceflow.Request) (string, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +97,20 @@ func (mr *MockRequestsHandlerMockRecorder) DeleteRequest(ctx, client, requestID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRequest", reflect.TypeOf((*MockRequestsHandler)(nil).DeleteRequest), ctx, client, requestID)
 }
 
+// GCStats mocks base method.
+func (m *MockRequestsHandler) GCStats() v1.TraceflowGCStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GCStats")
+	ret0, _ := ret[0].(v1.TraceflowGCStats)
+	return ret0
+}
+
+// GCStats indicates an expected call of GCStats.
+func (mr *MockRequestsHandlerMockRecorder) GCStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GCStats", reflect.TypeOf((*MockRequestsHandler)(nil).GCStats))
+}
+
 // GetRequestResult mocks base method.
 func (m *MockRequestsHandler) GetRequestResult(ctx context.Context, client dynamic.Interface, requestID string) (map[string]interface{}, bool, error) {
 	m.ctrl.T.Helper()
@@ -96,3 +126,92 @@ func (mr *MockRequestsHandlerMockRecorder) GetRequestResult(ctx, client, request
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestResult", reflect.TypeOf((*MockRequestsHandler)(nil).GetRequestResult), ctx, client, requestID)
 }
+
+// IsDelegatedTo mocks base method.
+func (m *MockRequestsHandler) IsDelegatedTo(ctx context.Context, requestID, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDelegatedTo", ctx, requestID, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDelegatedTo indicates an expected call of IsDelegatedTo.
+func (mr *MockRequestsHandlerMockRecorder) IsDelegatedTo(ctx, requestID, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDelegatedTo", reflect.TypeOf((*MockRequestsHandler)(nil).IsDelegatedTo), ctx, requestID, username)
+}
+
+// ListDelegatedRequests mocks base method.
+func (m *MockRequestsHandler) ListDelegatedRequests(ctx context.Context, username string) ([]v1.TraceflowSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelegatedRequests", ctx, username)
+	ret0, _ := ret[0].([]v1.TraceflowSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelegatedRequests indicates an expected call of ListDelegatedRequests.
+func (mr *MockRequestsHandlerMockRecorder) ListDelegatedRequests(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelegatedRequests", reflect.TypeOf((*MockRequestsHandler)(nil).ListDelegatedRequests), ctx, username)
+}
+
+// ListRequests mocks base method.
+func (m *MockRequestsHandler) ListRequests(ctx context.Context, client dynamic.Interface) ([]v1.TraceflowSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequests", ctx, client)
+	ret0, _ := ret[0].([]v1.TraceflowSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequests indicates an expected call of ListRequests.
+func (mr *MockRequestsHandlerMockRecorder) ListRequests(ctx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequests", reflect.TypeOf((*MockRequestsHandler)(nil).ListRequests), ctx, client)
+}
+
+// ResolvePolicies mocks base method.
+func (m *MockRequestsHandler) ResolvePolicies(ctx context.Context, client dynamic.Interface, traceflow map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePolicies", ctx, client, traceflow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolvePolicies indicates an expected call of ResolvePolicies.
+func (mr *MockRequestsHandlerMockRecorder) ResolvePolicies(ctx, client, traceflow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePolicies", reflect.TypeOf((*MockRequestsHandler)(nil).ResolvePolicies), ctx, client, traceflow)
+}
+
+// SetRequestPinned mocks base method.
+func (m *MockRequestsHandler) SetRequestPinned(ctx context.Context, client dynamic.Interface, requestID string, pinned bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRequestPinned", ctx, client, requestID, pinned)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRequestPinned indicates an expected call of SetRequestPinned.
+func (mr *MockRequestsHandlerMockRecorder) SetRequestPinned(ctx, client, requestID, pinned interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRequestPinned", reflect.TypeOf((*MockRequestsHandler)(nil).SetRequestPinned), ctx, client, requestID, pinned)
+}
+
+// WatchRequest mocks base method.
+func (m *MockRequestsHandler) WatchRequest(ctx context.Context, client dynamic.Interface, requestID string) (<-chan traceflow.RequestUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchRequest", ctx, client, requestID)
+	ret0, _ := ret[0].(<-chan traceflow.RequestUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchRequest indicates an expected call of WatchRequest.
+func (mr *MockRequestsHandlerMockRecorder) WatchRequest(ctx, client, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchRequest", reflect.TypeOf((*MockRequestsHandler)(nil).WatchRequest), ctx, client, requestID)
+}
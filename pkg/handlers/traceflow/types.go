@@ -16,4 +16,20 @@ package traceflow
 
 type Request struct {
 	Object map[string]interface{}
+	// Username and Title are recorded on the Traceflow, so that it can be found again in the
+	// history. Both are informational only.
+	Username string
+	Title    string
+	// Delegated is set when the Traceflow is created by antrea-ui-admin on behalf of Username,
+	// who then reads it through the handler's own client, see IsDelegatedTo.
+	Delegated bool
+}
+
+// RequestUpdate is one state of a watched Traceflow request. Exactly one of Object and Err is set.
+type RequestUpdate struct {
+	Object map[string]interface{}
+	// Done is true when the Traceflow is completed; it is then the last update.
+	Done bool
+	// Err ends the watch.
+	Err error
 }
@@ -0,0 +1,96 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// traceflowAdapter converts Traceflows between the v1beta1 shape, which is the only one callers of
+// the handler see, and the shape of another version.
+type traceflowAdapter struct {
+	// toVersion converts a v1beta1 spec, in place.
+	toVersion func(spec map[string]interface{})
+	// fromVersion converts a Traceflow of the version to the v1beta1 shape, in place.
+	fromVersion func(traceflow map[string]interface{})
+}
+
+// traceflowAdapters are the adapters of the versions whose shape differs from v1beta1, as resolved
+// by crdversions.
+var traceflowAdapters = map[string]traceflowAdapter{
+	"v1alpha1": {
+		toVersion:   v1beta1ToV1alpha1Spec,
+		fromVersion: v1alpha1ToV1beta1,
+	},
+}
+
+// v1beta1ToV1alpha1Spec converts a v1beta1 Traceflow spec to v1alpha1. In v1alpha1, the TCP flags
+// are not a pointer and are omitted when 0, so 0 cannot be asked for: the Antrea Agent then uses
+// its default, SYN.
+func v1beta1ToV1alpha1Spec(spec map[string]interface{}) {
+	if flags, ok, _ := unstructured.NestedInt64(spec, "packet", "transportHeader", "tcp", "flags"); ok && flags == 0 {
+		unstructured.RemoveNestedField(spec, "packet", "transportHeader", "tcp", "flags")
+	}
+}
+
+// v1alpha1ToV1beta1 converts a v1alpha1 Traceflow to v1beta1. In v1alpha1, the IPv4 header of a
+// packet is not a pointer, so it is always set, even for an IPv6 packet: it is dropped from the
+// packets which have an IPv6 header.
+func v1alpha1ToV1beta1(traceflow map[string]interface{}) {
+	for _, packetPath := range [][]string{{"spec", "packet"}, {"status", "capturedPacket"}} {
+		packet, ok, _ := unstructured.NestedMap(traceflow, packetPath...)
+		if !ok {
+			continue
+		}
+		if _, ok := packet["ipv6Header"]; ok {
+			delete(packet, "ipHeader")
+			_ = unstructured.SetNestedMap(traceflow, packet, packetPath...)
+		}
+	}
+	traceflow["apiVersion"] = traceflowGVR.GroupVersion().String()
+}
+
+// specToResolvedVersion returns spec, a v1beta1 Traceflow spec, in the resolved version. spec is
+// not modified.
+func (h *requestsHandler) specToResolvedVersion(spec interface{}) interface{} {
+	adapter, ok := traceflowAdapters[h.traceflowResource().Version]
+	specMap, isMap := spec.(map[string]interface{})
+	if !ok || !isMap {
+		return spec
+	}
+	specMap = copyNestedMaps(specMap)
+	adapter.toVersion(specMap)
+	return specMap
+}
+
+// toV1beta1 converts traceflow, as served by the API server, to v1beta1, in place.
+func toV1beta1(traceflow *unstructured.Unstructured) {
+	if adapter, ok := traceflowAdapters[traceflow.GroupVersionKind().Version]; ok {
+		adapter.fromVersion(traceflow.Object)
+	}
+}
+
+// copyNestedMaps copies m and the maps nested in it, so that the copy can be modified. Other
+// values are shared.
+func copyNestedMaps(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = copyNestedMaps(nested)
+		}
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+func (h *requestsHandler) WatchRequest(ctx context.Context, client dynamic.Interface, requestID string) (<-chan RequestUpdate, error) {
+	tfName := requestID
+	// Getting the object first reports a missing Traceflow (or a rejected credential) to the
+	// caller synchronously, and gives the resourceVersion to watch from.
+	traceflow, err := client.Resource(h.traceflowResource()).Get(ctx, tfName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	updates := make(chan RequestUpdate)
+	go func() {
+		defer close(updates)
+		h.watchTraceflow(ctx, client, traceflow, updates)
+	}()
+	return updates, nil
+}
+
+// watchTraceflow sends traceflow, then its updates, on updates until it completes or ctx is
+// cancelled.
+func (h *requestsHandler) watchTraceflow(ctx context.Context, client dynamic.Interface, traceflow *unstructured.Unstructured, updates chan<- RequestUpdate) {
+	send := func(u RequestUpdate) bool {
+		select {
+		case updates <- u:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	// sendObject returns false when the watch is over, because the Traceflow is completed or
+	// because ctx is cancelled.
+	sendObject := func(tf *unstructured.Unstructured) bool {
+		toV1beta1(tf)
+		done := isCompleted(tf.Object)
+		return send(RequestUpdate{Object: tf.Object, Done: done}) && !done
+	}
+	if !sendObject(traceflow) {
+		return
+	}
+	tfName := traceflow.GetName()
+	resourceVersion := traceflow.GetResourceVersion()
+	for {
+		w, err := client.Resource(h.traceflowResource()).Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", tfName).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			send(RequestUpdate{Err: err})
+			return
+		}
+		result := consumeWatch(ctx, w, sendObject, resourceVersion)
+		w.Stop()
+		if result.err != nil {
+			send(RequestUpdate{Err: result.err})
+			return
+		}
+		if result.over {
+			return
+		}
+		resourceVersion = result.resourceVersion
+		if result.expired {
+			// The version we were watching from is too old: start over from the current
+			// state.
+			tf, err := client.Resource(h.traceflowResource()).Get(ctx, tfName, metav1.GetOptions{})
+			if err != nil {
+				send(RequestUpdate{Err: err})
+				return
+			}
+			if !sendObject(tf) {
+				return
+			}
+			resourceVersion = tf.GetResourceVersion()
+		}
+		// Otherwise the API server closed the watch, which it does after a while: resume from
+		// the last version seen.
+	}
+}
+
+type watchResult struct {
+	// over is true when sendObject returned false or ctx was cancelled.
+	over bool
+	// resourceVersion is the last one seen, to resume watching from.
+	resourceVersion string
+	// expired is true when resourceVersion is too old to resume from.
+	expired bool
+	err     error
+}
+
+// consumeWatch forwards the Traceflows from w to sendObject until sendObject returns false, the
+// watch is closed, or it reports an error.
+func consumeWatch(ctx context.Context, w watch.Interface, sendObject func(*unstructured.Unstructured) bool, resourceVersion string) watchResult {
+	for {
+		select {
+		case <-ctx.Done():
+			return watchResult{over: true}
+		case event, open := <-w.ResultChan():
+			if !open {
+				return watchResult{resourceVersion: resourceVersion}
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				tf, ok := event.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				if !sendObject(tf) {
+					return watchResult{over: true}
+				}
+				resourceVersion = tf.GetResourceVersion()
+			case watch.Deleted:
+				return watchResult{err: fmt.Errorf("traceflow was deleted before completing")}
+			case watch.Error:
+				err := apierrors.FromObject(event.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					return watchResult{resourceVersion: resourceVersion, expired: true}
+				}
+				return watchResult{err: fmt.Errorf("error when watching traceflow: %w", err)}
+			}
+		}
+	}
+}
+
+func isCompleted(object map[string]interface{}) bool {
+	phase, _, _ := unstructured.NestedString(object, "status", "phase")
+	return phase == "Succeeded" || phase == "Failed"
+}
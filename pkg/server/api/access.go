@@ -15,6 +15,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -181,6 +182,21 @@ func (s *Server) namespacesFor(username string, groups []string) ([]string, *err
 	return namespaces, nil
 }
 
+// callerGroups returns the Kubernetes groups of the identity behind ctx, as the API server sees
+// them. A static-admin session gets the groups of the impersonated antrea-ui-admin
+// ServiceAccount.
+func (s *Server) callerGroups(ctx context.Context) ([]string, error) {
+	clientset, err := s.clientFactory.KubernetesClientForRequest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build K8s client for request: %w", err)
+	}
+	ssr, err := clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review caller identity: %w", err)
+	}
+	return ssr.Status.UserInfo.Groups, nil
+}
+
 func (s *Server) AddAccessRoutes(r *gin.RouterGroup) {
 	r.GET("/access-summary", s.authenticate(), s.GetAccessSummary)
 }
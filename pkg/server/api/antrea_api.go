@@ -0,0 +1,155 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+// antreaAPIPathAllowed returns whether p matches one of the allowedPaths patterns.
+func antreaAPIPathAllowed(allowedPaths []string, p string) bool {
+	for _, pattern := range allowedPaths {
+		// The patterns are validated when the config is loaded.
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAntreaAPIRequest returns the path and query of a request to forward to an Antrea API, if its
+// path is allowed. Watches are rejected: the response is buffered, so a watch would never
+// complete.
+func checkAntreaAPIRequest(c *gin.Context, allowedPaths []string) (string, url.Values, *errors.ServerError) {
+	p := c.Param("path")
+	// A path that is not clean could match a pattern and still reach another endpoint.
+	if path.Clean(p) != p {
+		return "", nil, &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Path %q is not clean", p),
+		}
+	}
+	if !antreaAPIPathAllowed(allowedPaths, p) {
+		return "", nil, &errors.ServerError{
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("Path %q is not allowed by this Antrea UI instance", p),
+		}
+	}
+	query := c.Request.URL.Query()
+	if query.Has("watch") {
+		return "", nil, &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: "Watch requests are not supported",
+		}
+	}
+	return p, query, nil
+}
+
+// writeAntreaAPIResponse returns the response of an Antrea API to the client. Error statuses are
+// returned as is, except for 401 and 403, which are handled like for any request made as the
+// caller.
+func (s *Server) writeAntreaAPIResponse(c *gin.Context, statusCode int, body []byte) *errors.ServerError {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return s.upstreamStatusError(c, statusCode, body)
+	}
+	contentType := "text/plain; charset=utf-8"
+	if json.Valid(body) {
+		contentType = "application/json"
+	}
+	c.Data(statusCode, contentType, body)
+	return nil
+}
+
+// GetAntreaControllerAPI handles GET /api/v1/antrea/controller/*path. It forwards the request to
+// the antrea-controller API as the caller, if its path is in the configured allowlist, so that
+// plugins and scripts can use that API without dealing with its CA or port-forwarding.
+func (s *Server) GetAntreaControllerAPI(c *gin.Context) {
+	if sError := func() *errors.ServerError {
+		p, query, sError := checkAntreaAPIRequest(c, s.config.AntreaControllerAllowedPaths)
+		if sError != nil {
+			return sError
+		}
+		// See GetFeatureGates for why c.Request.Context() is used.
+		b, statusCode, err := s.antreaSvcRequestsHandler.Request(c.Request.Context(), "GET", p, query, nil)
+		if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when forwarding request to the Antrea controller",
+				Err:     err,
+			}
+		}
+		return s.writeAntreaAPIResponse(c, statusCode, b)
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to forward request to the Antrea controller", "path", c.Param("path"))
+	}
+}
+
+// GetAntreaAgentAPI handles GET /api/v1/nodes/:node/agent/*path. It forwards the request to the
+// API of the antrea-agent running on the Node as the caller, if its path is in the configured
+// allowlist, e.g. to get the OVS flows of a Pod.
+func (s *Server) GetAntreaAgentAPI(c *gin.Context) {
+	node := c.Param("node")
+	if sError := func() *errors.ServerError {
+		if errs := validation.IsDNS1123Subdomain(node); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid Node name %q: %s", node, strings.Join(errs, "; ")),
+			}
+		}
+		p, query, sError := checkAntreaAPIRequest(c, s.config.AntreaAgentAllowedPaths)
+		if sError != nil {
+			return sError
+		}
+		b, statusCode, err := s.antreaAgentRequestsHandler.Request(c.Request.Context(), node, p, query)
+		if stderrors.Is(err, antreaagent.ErrAgentNotFound) {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("No running antrea-agent found on Node %s", node),
+			}
+		} else if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when forwarding request to antrea-agent",
+				Err:     err,
+			}
+		}
+		return s.writeAntreaAPIResponse(c, statusCode, b)
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to forward request to antrea-agent", "node", node, "path", c.Param("path"))
+	}
+}
+
+func (s *Server) AddAntreaAPIRoutes(r *gin.RouterGroup) {
+	antrea := r.Group("/antrea")
+	antrea.Use(s.authenticate())
+	antrea.GET("/controller/*path", s.GetAntreaControllerAPI)
+	nodes := r.Group("/nodes")
+	nodes.Use(s.authenticate())
+	nodes.GET("/:node/agent/*path", s.GetAntreaAgentAPI)
+}
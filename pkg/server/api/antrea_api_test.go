@@ -0,0 +1,205 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	serverconfig "antrea.io/antrea-ui/pkg/config/server"
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
+)
+
+func setAntreaControllerAllowedPaths(allowedPaths []string) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.AntreaAPI.Controller.AllowedPaths = allowedPaths
+	}
+}
+
+func TestGetAntreaControllerAPI(t *testing.T) {
+	const endpointResponse = `{"effectivePolicies":[]}`
+	testCases := []struct {
+		name                string
+		path                string
+		expectedQuery       url.Values
+		upstreamCode        int
+		upstreamBody        string
+		expectedCode        int
+		expectedContentType string
+	}{
+		{
+			name:                "endpoint query",
+			path:                "/endpoint?namespace=default&pod=pod-x",
+			expectedQuery:       url.Values{"namespace": {"default"}, "pod": {"pod-x"}},
+			upstreamCode:        http.StatusOK,
+			upstreamBody:        endpointResponse,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "NetworkPolicy lookup",
+			path:                "/apis/controlplane.antrea.io/v1beta2/networkpolicies/np-1",
+			expectedQuery:       url.Values{},
+			upstreamCode:        http.StatusNotFound,
+			upstreamBody:        "not found",
+			expectedCode:        http.StatusNotFound,
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:         "forbidden upstream",
+			path:         "/endpoint",
+			upstreamCode: http.StatusForbidden,
+			upstreamBody: "forbidden",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "path not allowed",
+			path:         "/loglevel",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "path not clean",
+			path:         "/apis/controlplane.antrea.io/v1beta2/networkpolicies/../../../../loglevel",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "watch",
+			path:         "/apis/controlplane.antrea.io/v1beta2/networkpolicies?watch=true",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t, setAntreaControllerAllowedPaths(serverconfig.DefaultAntreaControllerAllowedPaths))
+			// httptest.NewRequest would clean the path, so it is set afterwards.
+			req := httptest.NewRequest("GET", "/api/v1/antrea/controller", nil)
+			u, _ := url.Parse("/api/v1/antrea/controller" + tc.path)
+			req.URL = u
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			if tc.upstreamCode != 0 {
+				ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", u.Path[len("/api/v1/antrea/controller"):], gomock.Any(), nil).
+					Return([]byte(tc.upstreamBody), tc.upstreamCode, nil).
+					Do(func(_, _, _ interface{}, query url.Values, _ interface{}) {
+						if tc.expectedQuery != nil {
+							assert.Equal(t, tc.expectedQuery, query)
+						}
+					})
+			}
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+			if tc.expectedContentType != "" {
+				assert.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, tc.upstreamBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func setAntreaAgentAllowedPaths(allowedPaths []string) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.AntreaAPI.Agent.AllowedPaths = allowedPaths
+	}
+}
+
+func TestGetAntreaAgentAPI(t *testing.T) {
+	const ovsFlowsResponse = `[{"flow":"table=0, priority=0 actions=goto_table:1"}]`
+	testCases := []struct {
+		name          string
+		path          string
+		expectedNode  string
+		expectedPath  string
+		expectedQuery url.Values
+		upstreamCode  int
+		upstreamBody  string
+		upstreamErr   error
+		expectedCode  int
+	}{
+		{
+			name:          "Pod flows",
+			path:          "/nodes/node-1/agent/ovsflows?namespace=default&pod=pod-x",
+			expectedNode:  "node-1",
+			expectedPath:  "/ovsflows",
+			expectedQuery: url.Values{"namespace": {"default"}, "pod": {"pod-x"}},
+			upstreamCode:  http.StatusOK,
+			upstreamBody:  ovsFlowsResponse,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:         "unauthorized upstream",
+			path:         "/nodes/node-1/agent/agentinfo",
+			expectedNode: "node-1",
+			expectedPath: "/agentinfo",
+			upstreamCode: http.StatusForbidden,
+			upstreamBody: "forbidden",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "no agent",
+			path:         "/nodes/node-2/agent/agentinfo",
+			expectedNode: "node-2",
+			expectedPath: "/agentinfo",
+			upstreamErr:  fmt.Errorf("%w: node-2", antreaagent.ErrAgentNotFound),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "agent unreachable",
+			path:         "/nodes/node-1/agent/agentinfo",
+			expectedNode: "node-1",
+			expectedPath: "/agentinfo",
+			upstreamErr:  fmt.Errorf("connection refused"),
+			expectedCode: http.StatusBadGateway,
+		},
+		{
+			name:         "path not allowed",
+			path:         "/nodes/node-1/agent/loglevel",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "invalid Node name",
+			path:         "/nodes/Node_1/agent/agentinfo",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t, setAntreaAgentAllowedPaths(serverconfig.DefaultAntreaAgentAllowedPaths))
+			req := httptest.NewRequest("GET", "/api/v1"+tc.path, nil)
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			if tc.expectedNode != "" {
+				ts.antreaAgentRequestsHandler.EXPECT().Request(gomock.Any(), tc.expectedNode, tc.expectedPath, gomock.Any()).
+					Return([]byte(tc.upstreamBody), tc.upstreamCode, tc.upstreamErr).
+					Do(func(_, _, _ interface{}, query url.Values) {
+						if tc.expectedQuery != nil {
+							assert.Equal(t, tc.expectedQuery, query)
+						}
+					})
+			}
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+			if tc.expectedCode == http.StatusOK {
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				assert.Equal(t, tc.upstreamBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -38,7 +38,7 @@ func (s *Server) GetFeatureGates(c *gin.Context) {
 		// session.RequestAuthFrom, which keys off an unexported type. A *gin.Context only
 		// forwards Value() lookups to the request context when Engine.ContextWithFallback is
 		// set, which it is not, so passing c here would lose the identity entirely.
-		b, statusCode, err := s.antreaSvcRequestsHandler.Request(c.Request.Context(), "GET", "/featuregates", nil)
+		b, statusCode, err := s.antreaSvcRequestsHandler.Request(c.Request.Context(), "GET", "/featuregates", nil, nil)
 		if err != nil {
 			return &errors.ServerError{
 				Code: http.StatusInternalServerError,
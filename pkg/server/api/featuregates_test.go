@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -55,8 +56,8 @@ func TestGetFeatureGates(t *testing.T) {
 	// the identity actually made it across. Passing the *gin.Context here instead of the request
 	// context would silently drop it: gin only forwards Value() lookups to the request context
 	// when Engine.ContextWithFallback is set.
-	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/featuregates", nil).
-		DoAndReturn(func(ctx context.Context, _ string, _ string, _ io.Reader) ([]byte, int, error) {
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/featuregates", nil, nil).
+		DoAndReturn(func(ctx context.Context, _ string, _ string, _ url.Values, _ io.Reader) ([]byte, int, error) {
 			ra, ok := session.RequestAuthFrom(ctx)
 			require.True(t, ok, "context passed to the Antrea Service carries no identity")
 			assert.Equal(t, session.ModeAdmin, ra.Mode)
@@ -36,10 +36,17 @@ func buildFrontendSettingsFromConfig(config *serverconfig.Config) *apisv1.Fronte
 		},
 		Features: apisv1.FrontendFeatureSettings{
 			FlowVisibilityEnabled: config.FlowAggregator.Enabled,
+			PacketCaptureEnabled:  config.PacketCapture.FileServer.URL != "",
+			SupportBundleEnabled:  config.SupportBundle.FileServer.URL != "",
 		},
 	}
 }
 
 func (s *Server) FrontendSettings(c *gin.Context) {
-	c.JSON(http.StatusOK, s.frontendSettings)
+	settings := *s.frontendSettings
+	if s.crdResolver != nil {
+		status := s.crdResolver.Status()
+		settings.Antrea = &status
+	}
+	c.JSON(http.StatusOK, &settings)
 }
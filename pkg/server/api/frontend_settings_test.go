@@ -0,0 +1,66 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	crdversionstesting "antrea.io/antrea-ui/pkg/handlers/crdversions/testing"
+)
+
+func TestFrontendSettings(t *testing.T) {
+	getSettings := func(ts *testServer) apisv1.FrontendSettings {
+		req := httptest.NewRequest("GET", "/api/v1/settings", nil)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var settings apisv1.FrontendSettings
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &settings))
+		return settings
+	}
+
+	t.Run("no CRD resolver", func(t *testing.T) {
+		ts := newTestServer(t)
+		settings := getSettings(ts)
+		assert.True(t, settings.Auth.BasicEnabled)
+		assert.Nil(t, settings.Antrea)
+	})
+
+	t.Run("unsupported Antrea version", func(t *testing.T) {
+		ts := newTestServer(t)
+		resolver := crdversionstesting.NewMockResolver(gomock.NewController(t))
+		ts.s.crdResolver = resolver
+		status := apisv1.AntreaAPISettings{
+			Discovered: true,
+			Supported:  false,
+			Message:    "Antrea does not serve traceflows: this Antrea version is not supported",
+			Resources: []apisv1.AntreaResourceVersion{
+				{Resource: "traceflows", Version: "v1beta1"},
+			},
+		}
+		resolver.EXPECT().Status().Return(status)
+		settings := getSettings(ts)
+		require.NotNil(t, settings.Antrea)
+		assert.Equal(t, status, *settings.Antrea)
+	})
+}
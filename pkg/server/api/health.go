@@ -0,0 +1,322 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	antreaHealthHealthy   = "Healthy"
+	antreaHealthDegraded  = "Degraded"
+	antreaHealthUnhealthy = "Unhealthy"
+
+	// Antrea updates the AntreaControllerInfo and the AntreaAgentInfos every minute, so a
+	// heartbeat is stale once 3 updates in a row were missed.
+	antreaHealthStaleHeartbeat = 3 * time.Minute
+	// antreaHealthMaxListedNodes is the number of Nodes named in an issue, so that an issue
+	// stays readable in large clusters.
+	antreaHealthMaxListedNodes = 5
+
+	antreaControllerInfoName = "antrea-controller"
+)
+
+// The fields of AntreaControllerInfo and AntreaAgentInfo used by antrea-ui, copied from
+// https://github.com/antrea-io/antrea/blob/main/pkg/apis/crd/v1beta1/types.go
+type antreaObjectReference struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+type antreaNetworkPolicyControllerInfo struct {
+	NetworkPolicyNum  int32 `json:"networkPolicyNum,omitempty"`
+	AddressGroupNum   int32 `json:"addressGroupNum,omitempty"`
+	AppliedToGroupNum int32 `json:"appliedToGroupNum,omitempty"`
+}
+
+type antreaCondition struct {
+	Type              string      `json:"type,omitempty"`
+	Status            string      `json:"status,omitempty"`
+	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	Reason            string      `json:"reason,omitempty"`
+	Message           string      `json:"message,omitempty"`
+}
+
+type antreaControllerInfo struct {
+	Version                     string                            `json:"version,omitempty"`
+	PodRef                      antreaObjectReference             `json:"podRef,omitempty"`
+	NodeRef                     antreaObjectReference             `json:"nodeRef,omitempty"`
+	NetworkPolicyControllerInfo antreaNetworkPolicyControllerInfo `json:"networkPolicyControllerInfo,omitempty"`
+	ConnectedAgentNum           int32                             `json:"connectedAgentNum,omitempty"`
+	ControllerConditions        []antreaCondition                 `json:"controllerConditions,omitempty"`
+}
+
+type antreaAgentInfo struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Version           string                `json:"version,omitempty"`
+	PodRef            antreaObjectReference `json:"podRef,omitempty"`
+	NodeRef           antreaObjectReference `json:"nodeRef,omitempty"`
+	OVSInfo           struct {
+		Version string `json:"version,omitempty"`
+	} `json:"ovsInfo,omitempty"`
+	NetworkPolicyControllerInfo antreaNetworkPolicyControllerInfo `json:"networkPolicyControllerInfo,omitempty"`
+	LocalPodNum                 int32                             `json:"localPodNum,omitempty"`
+	AgentConditions             []antreaCondition                 `json:"agentConditions,omitempty"`
+}
+
+// antreaConditions converts conditions, and returns the status of the condition of type
+// healthyType, the status of the condition of type connectedType, and the latest heartbeat.
+func antreaConditions(conditions []antreaCondition, healthyType, connectedType string) ([]apisv1.AntreaComponentCondition, bool, bool, time.Time) {
+	result := make([]apisv1.AntreaComponentCondition, 0, len(conditions))
+	var healthy, connected bool
+	var lastHeartbeat time.Time
+	for _, condition := range conditions {
+		result = append(result, apisv1.AntreaComponentCondition{
+			Type:              condition.Type,
+			Status:            condition.Status,
+			LastHeartbeatTime: formatHeartbeat(condition.LastHeartbeatTime.Time),
+			Reason:            condition.Reason,
+			Message:           condition.Message,
+		})
+		isTrue := condition.Status == string(metav1.ConditionTrue)
+		switch condition.Type {
+		case healthyType:
+			healthy = isTrue
+		case connectedType:
+			connected = isTrue
+		}
+		if condition.LastHeartbeatTime.After(lastHeartbeat) {
+			lastHeartbeat = condition.LastHeartbeatTime.Time
+		}
+	}
+	return result, healthy, connected, lastHeartbeat
+}
+
+func formatHeartbeat(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func objectRefString(ref antreaObjectReference) string {
+	if ref.Name == "" {
+		return ""
+	}
+	return ref.Namespace + "/" + ref.Name
+}
+
+func networkPolicyCounts(info antreaNetworkPolicyControllerInfo) apisv1.AntreaNetworkPolicyCounts {
+	return apisv1.AntreaNetworkPolicyCounts{
+		NetworkPolicies: info.NetworkPolicyNum,
+		AddressGroups:   info.AddressGroupNum,
+		AppliedToGroups: info.AppliedToGroupNum,
+	}
+}
+
+// nodesIssue describes a problem shared by nodes, naming at most antreaHealthMaxListedNodes of
+// them.
+func nodesIssue(nodes []string, problem string) string {
+	listed := nodes
+	if len(listed) > antreaHealthMaxListedNodes {
+		listed = listed[:antreaHealthMaxListedNodes]
+	}
+	issue := fmt.Sprintf("%d antrea-agent(s) %s: %s", len(nodes), problem, strings.Join(listed, ", "))
+	if more := len(nodes) - len(listed); more > 0 {
+		issue += fmt.Sprintf(" and %d more", more)
+	}
+	return issue
+}
+
+// buildAntreaHealth aggregates controllerInfo, which is nil when there is no
+// AntreaControllerInfo, and agentInfos into an apisv1.AntreaHealth. Heartbeats older than
+// antreaHealthStaleHeartbeat at now are stale.
+func buildAntreaHealth(controllerInfo *antreaControllerInfo, agentInfos []antreaAgentInfo, now time.Time) *apisv1.AntreaHealth {
+	isStale := func(heartbeat time.Time) bool {
+		return now.Sub(heartbeat) > antreaHealthStaleHeartbeat
+	}
+	health := &apisv1.AntreaHealth{
+		Status:                  antreaHealthHealthy,
+		Issues:                  []string{},
+		Agents:                  make([]apisv1.AntreaAgentHealth, 0, len(agentInfos)),
+		Versions:                make(map[string]int32),
+		StaleHeartbeatThreshold: antreaHealthStaleHeartbeat.String(),
+	}
+
+	var controllerVersion string
+	if controllerInfo == nil {
+		health.Status = antreaHealthUnhealthy
+		health.Issues = append(health.Issues, "The antrea-controller has not reported its status (no AntreaControllerInfo)")
+	} else {
+		conditions, healthy, _, lastHeartbeat := antreaConditions(controllerInfo.ControllerConditions, "ControllerHealthy", "")
+		health.Controller = &apisv1.AntreaControllerHealth{
+			Version:           controllerInfo.Version,
+			Pod:               objectRefString(controllerInfo.PodRef),
+			NodeName:          controllerInfo.NodeRef.Name,
+			Healthy:           healthy,
+			LastHeartbeatTime: formatHeartbeat(lastHeartbeat),
+			StaleHeartbeat:    isStale(lastHeartbeat),
+			ConnectedAgents:   controllerInfo.ConnectedAgentNum,
+			NetworkPolicies:   networkPolicyCounts(controllerInfo.NetworkPolicyControllerInfo),
+			Conditions:        conditions,
+		}
+		controllerVersion = controllerInfo.Version
+		health.Versions[controllerVersion]++
+		if !healthy {
+			health.Status = antreaHealthUnhealthy
+			health.Issues = append(health.Issues, "The antrea-controller is not healthy")
+		}
+		if health.Controller.StaleHeartbeat {
+			health.Status = antreaHealthUnhealthy
+			health.Issues = append(health.Issues, fmt.Sprintf("The antrea-controller has not reported its status for more than %s", antreaHealthStaleHeartbeat))
+		}
+	}
+
+	var unhealthyNodes, disconnectedNodes, staleNodes, skewedNodes []string
+	for idx := range agentInfos {
+		info := &agentInfos[idx]
+		conditions, healthy, connected, lastHeartbeat := antreaConditions(info.AgentConditions, "AgentHealthy", "ControllerConnectionUp")
+		agent := apisv1.AntreaAgentHealth{
+			Name:                info.Name,
+			NodeName:            info.NodeRef.Name,
+			Pod:                 objectRefString(info.PodRef),
+			Version:             info.Version,
+			OVSVersion:          info.OVSInfo.Version,
+			Healthy:             healthy,
+			ControllerConnected: connected,
+			LastHeartbeatTime:   formatHeartbeat(lastHeartbeat),
+			StaleHeartbeat:      isStale(lastHeartbeat),
+			VersionSkew:         controllerInfo != nil && info.Version != controllerVersion,
+			LocalPods:           info.LocalPodNum,
+			NetworkPolicies:     networkPolicyCounts(info.NetworkPolicyControllerInfo),
+			Conditions:          conditions,
+		}
+		if agent.NodeName == "" {
+			// The AntreaAgentInfo is named after the Node.
+			agent.NodeName = info.Name
+		}
+		health.Versions[info.Version]++
+		if healthy {
+			health.HealthyAgents++
+		} else {
+			unhealthyNodes = append(unhealthyNodes, agent.NodeName)
+		}
+		if !connected {
+			disconnectedNodes = append(disconnectedNodes, agent.NodeName)
+		}
+		if agent.StaleHeartbeat {
+			staleNodes = append(staleNodes, agent.NodeName)
+		}
+		if agent.VersionSkew {
+			skewedNodes = append(skewedNodes, agent.NodeName)
+			health.VersionSkew = true
+		}
+		health.Agents = append(health.Agents, agent)
+	}
+	slices.SortFunc(health.Agents, func(a, b apisv1.AntreaAgentHealth) int {
+		return strings.Compare(a.NodeName, b.NodeName)
+	})
+
+	var agentIssues []string
+	if len(agentInfos) == 0 {
+		agentIssues = append(agentIssues, "No antrea-agent has reported its status (no AntreaAgentInfo)")
+	}
+	for _, issue := range []struct {
+		nodes   []string
+		problem string
+	}{
+		{unhealthyNodes, "not healthy"},
+		{disconnectedNodes, "not connected to the antrea-controller"},
+		{staleNodes, fmt.Sprintf("with no status reported for more than %s", antreaHealthStaleHeartbeat)},
+		{skewedNodes, fmt.Sprintf("not running the version of the antrea-controller (%s)", controllerVersion)},
+	} {
+		if len(issue.nodes) > 0 {
+			slices.Sort(issue.nodes)
+			agentIssues = append(agentIssues, nodesIssue(issue.nodes, issue.problem))
+		}
+	}
+	if len(agentIssues) > 0 {
+		health.Issues = append(health.Issues, agentIssues...)
+		if health.Status == antreaHealthHealthy {
+			health.Status = antreaHealthDegraded
+		}
+	}
+	return health
+}
+
+// GetAntreaHealth handles GET /api/v1/health/antrea. The AntreaControllerInfo and the
+// AntreaAgentInfos are read as the caller, and aggregated into an apisv1.AntreaHealth.
+func (s *Server) GetAntreaHealth(c *gin.Context) {
+	var health *apisv1.AntreaHealth
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		ctx := c.Request.Context()
+		var controllerInfo *antreaControllerInfo
+		obj, err := client.Resource(s.crdGVR(crdversions.ResourceAntreaControllerInfos)).Get(ctx, antreaControllerInfoName, metav1.GetOptions{})
+		if err == nil {
+			controllerInfo = &antreaControllerInfo{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, controllerInfo); err != nil {
+				return &errors.ServerError{
+					Code: http.StatusInternalServerError,
+					Err:  fmt.Errorf("invalid AntreaControllerInfo: %w", err),
+				}
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return s.k8sError(c, err, "error when getting AntreaControllerInfo")
+		}
+		list, err := client.Resource(s.crdGVR(crdversions.ResourceAntreaAgentInfos)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return s.k8sError(c, err, "error when listing AntreaAgentInfos")
+		}
+		agentInfos := make([]antreaAgentInfo, len(list.Items))
+		for idx := range list.Items {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[idx].Object, &agentInfos[idx]); err != nil {
+				return &errors.ServerError{
+					Code: http.StatusInternalServerError,
+					Err:  fmt.Errorf("invalid AntreaAgentInfo %s: %w", list.Items[idx].GetName(), err),
+				}
+			}
+		}
+		health = buildAntreaHealth(controllerInfo, agentInfos, time.Now())
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get Antrea health")
+		return
+	}
+	c.JSON(http.StatusOK, health)
+}
+
+func (s *Server) AddHealthRoutes(r *gin.RouterGroup) {
+	r = r.Group("/health")
+	r.Use(s.authenticate())
+	r.GET("/antrea", s.GetAntreaHealth)
+}
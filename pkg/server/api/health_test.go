@@ -0,0 +1,243 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+func testAntreaCondition(conditionType string, status bool, heartbeat time.Time) antreaCondition {
+	condition := antreaCondition{
+		Type:              conditionType,
+		Status:            string(metav1.ConditionFalse),
+		LastHeartbeatTime: metav1.NewTime(heartbeat),
+	}
+	if status {
+		condition.Status = string(metav1.ConditionTrue)
+	}
+	return condition
+}
+
+func testAntreaAgentInfo(node, version string, healthy, connected bool, heartbeat time.Time) antreaAgentInfo {
+	info := antreaAgentInfo{
+		Version: version,
+		NodeRef: antreaObjectReference{Name: node},
+		AgentConditions: []antreaCondition{
+			testAntreaCondition("AgentHealthy", healthy, heartbeat),
+			testAntreaCondition("ControllerConnectionUp", connected, heartbeat),
+		},
+	}
+	info.Name = node
+	return info
+}
+
+func TestBuildAntreaHealth(t *testing.T) {
+	now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	recent := now.Add(-30 * time.Second)
+	old := now.Add(-10 * time.Minute)
+	controller := func(healthy bool, heartbeat time.Time) *antreaControllerInfo {
+		return &antreaControllerInfo{
+			Version:              "v2.2.0",
+			PodRef:               antreaObjectReference{Namespace: "kube-system", Name: "antrea-controller-abc"},
+			NodeRef:              antreaObjectReference{Name: "node-1"},
+			ConnectedAgentNum:    2,
+			ControllerConditions: []antreaCondition{testAntreaCondition("ControllerHealthy", healthy, heartbeat)},
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		controllerInfo      *antreaControllerInfo
+		agentInfos          []antreaAgentInfo
+		expectedStatus      string
+		expectedIssues      []string
+		expectedVersionSkew bool
+	}{
+		{
+			name:           "healthy",
+			controllerInfo: controller(true, recent),
+			agentInfos: []antreaAgentInfo{
+				testAntreaAgentInfo("node-2", "v2.2.0", true, true, recent),
+				testAntreaAgentInfo("node-1", "v2.2.0", true, true, recent),
+			},
+			expectedStatus: "Healthy",
+			expectedIssues: []string{},
+		},
+		{
+			name:           "no controller",
+			controllerInfo: nil,
+			agentInfos: []antreaAgentInfo{
+				testAntreaAgentInfo("node-1", "v2.2.0", true, true, recent),
+			},
+			expectedStatus: "Unhealthy",
+			expectedIssues: []string{"The antrea-controller has not reported its status (no AntreaControllerInfo)"},
+		},
+		{
+			name:           "stale controller",
+			controllerInfo: controller(true, old),
+			agentInfos: []antreaAgentInfo{
+				testAntreaAgentInfo("node-1", "v2.2.0", true, true, recent),
+			},
+			expectedStatus: "Unhealthy",
+			expectedIssues: []string{"The antrea-controller has not reported its status for more than 3m0s"},
+		},
+		{
+			name:           "degraded agents",
+			controllerInfo: controller(true, recent),
+			agentInfos: []antreaAgentInfo{
+				testAntreaAgentInfo("node-1", "v2.2.0", true, true, recent),
+				testAntreaAgentInfo("node-2", "v2.2.0", false, false, old),
+				testAntreaAgentInfo("node-3", "v2.1.0", true, true, recent),
+			},
+			expectedStatus: "Degraded",
+			expectedIssues: []string{
+				"1 antrea-agent(s) not healthy: node-2",
+				"1 antrea-agent(s) not connected to the antrea-controller: node-2",
+				"1 antrea-agent(s) with no status reported for more than 3m0s: node-2",
+				"1 antrea-agent(s) not running the version of the antrea-controller (v2.2.0): node-3",
+			},
+			expectedVersionSkew: true,
+		},
+		{
+			name:           "no agent",
+			controllerInfo: controller(true, recent),
+			expectedStatus: "Degraded",
+			expectedIssues: []string{"No antrea-agent has reported its status (no AntreaAgentInfo)"},
+		},
+		{
+			name:           "many unhealthy agents",
+			controllerInfo: controller(true, recent),
+			agentInfos: []antreaAgentInfo{
+				testAntreaAgentInfo("node-7", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-6", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-5", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-4", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-3", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-2", "v2.2.0", false, true, recent),
+				testAntreaAgentInfo("node-1", "v2.2.0", false, true, recent),
+			},
+			expectedStatus: "Degraded",
+			expectedIssues: []string{"7 antrea-agent(s) not healthy: node-1, node-2, node-3, node-4, node-5 and 2 more"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			health := buildAntreaHealth(tc.controllerInfo, tc.agentInfos, now)
+			assert.Equal(t, tc.expectedStatus, health.Status)
+			assert.Equal(t, tc.expectedIssues, health.Issues)
+			assert.Equal(t, tc.expectedVersionSkew, health.VersionSkew)
+			require.Len(t, health.Agents, len(tc.agentInfos))
+			for idx := 1; idx < len(health.Agents); idx++ {
+				assert.Less(t, health.Agents[idx-1].NodeName, health.Agents[idx].NodeName)
+			}
+		})
+	}
+}
+
+func TestGetAntreaHealth(t *testing.T) {
+	heartbeat := time.Now().UTC().Truncate(time.Second).Format(time.RFC3339)
+	objects := map[string]interface{}{
+		"/apis/crd.antrea.io/v1beta1/antreacontrollerinfos/antrea-controller": map[string]interface{}{
+			"apiVersion": "crd.antrea.io/v1beta1",
+			"kind":       "AntreaControllerInfo",
+			"metadata":   map[string]interface{}{"name": "antrea-controller"},
+			"version":    "v2.2.0",
+			"podRef":     map[string]interface{}{"kind": "Pod", "namespace": "kube-system", "name": "antrea-controller-abc"},
+			"nodeRef":    map[string]interface{}{"kind": "Node", "name": "node-1"},
+			"networkPolicyControllerInfo": map[string]interface{}{
+				"networkPolicyNum": 3, "addressGroupNum": 2, "appliedToGroupNum": 1,
+			},
+			"connectedAgentNum": 1,
+			"controllerConditions": []interface{}{
+				map[string]interface{}{"type": "ControllerHealthy", "status": "True", "lastHeartbeatTime": heartbeat},
+			},
+		},
+		"/apis/crd.antrea.io/v1beta1/antreaagentinfos": map[string]interface{}{
+			"apiVersion": "crd.antrea.io/v1beta1",
+			"kind":       "AntreaAgentInfoList",
+			"metadata":   map[string]interface{}{},
+			"items": []interface{}{
+				map[string]interface{}{
+					"apiVersion":  "crd.antrea.io/v1beta1",
+					"kind":        "AntreaAgentInfo",
+					"metadata":    map[string]interface{}{"name": "node-1"},
+					"version":     "v2.2.0",
+					"podRef":      map[string]interface{}{"kind": "Pod", "namespace": "kube-system", "name": "antrea-agent-xyz"},
+					"nodeRef":     map[string]interface{}{"kind": "Node", "name": "node-1"},
+					"ovsInfo":     map[string]interface{}{"version": "3.1.1", "bridgeName": "br-int"},
+					"localPodNum": 4,
+					"networkPolicyControllerInfo": map[string]interface{}{
+						"networkPolicyNum": 1, "addressGroupNum": 1, "appliedToGroupNum": 1,
+					},
+					"agentConditions": []interface{}{
+						map[string]interface{}{"type": "AgentHealthy", "status": "True", "lastHeartbeatTime": heartbeat},
+						map[string]interface{}{"type": "ControllerConnectionUp", "status": "True", "lastHeartbeatTime": heartbeat},
+					},
+				},
+			},
+		},
+	}
+	ts, fakeAPIServer := newTestServerWithK8sObjects(t, objects)
+
+	req := httptest.NewRequest("GET", "/api/v1/health/antrea", nil)
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var health apisv1.AntreaHealth
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &health))
+	assert.Equal(t, "Healthy", health.Status)
+	assert.Equal(t, map[string]int32{"v2.2.0": 2}, health.Versions)
+	assert.Equal(t, int32(1), health.HealthyAgents)
+	require.NotNil(t, health.Controller)
+	assert.Equal(t, "kube-system/antrea-controller-abc", health.Controller.Pod)
+	assert.Equal(t, heartbeat, health.Controller.LastHeartbeatTime)
+	assert.Equal(t, apisv1.AntreaNetworkPolicyCounts{NetworkPolicies: 3, AddressGroups: 2, AppliedToGroups: 1}, health.Controller.NetworkPolicies)
+	require.Len(t, health.Agents, 1)
+	assert.Equal(t, apisv1.AntreaAgentHealth{
+		Name:                "node-1",
+		NodeName:            "node-1",
+		Pod:                 "kube-system/antrea-agent-xyz",
+		Version:             "v2.2.0",
+		OVSVersion:          "3.1.1",
+		Healthy:             true,
+		ControllerConnected: true,
+		LastHeartbeatTime:   heartbeat,
+		LocalPods:           4,
+		NetworkPolicies:     apisv1.AntreaNetworkPolicyCounts{NetworkPolicies: 1, AddressGroups: 1, AppliedToGroups: 1},
+		Conditions: []apisv1.AntreaComponentCondition{
+			{Type: "AgentHealthy", Status: "True", LastHeartbeatTime: heartbeat},
+			{Type: "ControllerConnectionUp", Status: "True", LastHeartbeatTime: heartbeat},
+		},
+	}, health.Agents[0])
+
+	// the caller is not allowed to read the AntreaControllerInfo
+	fakeAPIServer.forbidden = true
+	req = httptest.NewRequest("GET", "/api/v1/health/antrea", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
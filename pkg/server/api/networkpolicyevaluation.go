@@ -0,0 +1,249 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	// networkPolicyEvaluationPath is served by the antrea-controller since Antrea v1.15.
+	networkPolicyEvaluationPath = "/apis/controlplane.antrea.io/v1beta2/networkpolicyevaluation"
+
+	// Antrea-native policies without a tier are in the Application tier.
+	antreaDefaultTier = "application"
+)
+
+// The NetworkPolicyEvaluation of the controlplane.antrea.io API, copied from
+// https://github.com/antrea-io/antrea/blob/main/pkg/apis/controlplane/v1beta2/types.go
+type networkPolicyEvaluationPodReference struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type networkPolicyEvaluationEntity struct {
+	Pod *networkPolicyEvaluationPodReference `json:"pod,omitempty"`
+}
+
+type networkPolicyEvaluationRequest struct {
+	Source      networkPolicyEvaluationEntity `json:"source"`
+	Destination networkPolicyEvaluationEntity `json:"destination"`
+}
+
+type networkPolicyEvaluation struct {
+	APIVersion string                          `json:"apiVersion"`
+	Kind       string                          `json:"kind"`
+	Request    *networkPolicyEvaluationRequest `json:"request,omitempty"`
+	Response   *struct {
+		NetworkPolicy struct {
+			Type      string `json:"type,omitempty"`
+			Namespace string `json:"namespace,omitempty"`
+			Name      string `json:"name,omitempty"`
+			UID       string `json:"uid,omitempty"`
+		} `json:"networkPolicy"`
+		RuleIndex int32 `json:"ruleIndex"`
+		Rule      struct {
+			Direction string `json:"direction,omitempty"`
+			Name      string `json:"name,omitempty"`
+			Action    string `json:"action,omitempty"`
+		} `json:"rule"`
+	} `json:"response,omitempty"`
+}
+
+func validateNetworkPolicyEvaluationRequest(req *apisv1.NetworkPolicyEvaluationRequest) []string {
+	var errs []string
+	errs = append(errs, validateTraceflowObjectRef("Source", "Pod", req.Source.Namespace, req.Source.Pod, validation.IsDNS1123Subdomain)...)
+	errs = append(errs, validateTraceflowObjectRef("Destination", "Pod", req.Destination.Namespace, req.Destination.Pod, validation.IsDNS1123Subdomain)...)
+	return errs
+}
+
+func networkPolicyEvaluationBody(req *apisv1.NetworkPolicyEvaluationRequest) ([]byte, error) {
+	return json.Marshal(&networkPolicyEvaluation{
+		APIVersion: "controlplane.antrea.io/v1beta2",
+		Kind:       "NetworkPolicyEvaluation",
+		Request: &networkPolicyEvaluationRequest{
+			Source: networkPolicyEvaluationEntity{
+				Pod: &networkPolicyEvaluationPodReference{Namespace: req.Source.Namespace, Name: req.Source.Pod},
+			},
+			Destination: networkPolicyEvaluationEntity{
+				Pod: &networkPolicyEvaluationPodReference{Namespace: req.Destination.Namespace, Name: req.Destination.Pod},
+			},
+		},
+	})
+}
+
+// networkPolicyEvaluationResult translates the NetworkPolicyEvaluation returned by the
+// antrea-controller.
+func networkPolicyEvaluationResult(eval *networkPolicyEvaluation) *apisv1.NetworkPolicyEvaluationResult {
+	if eval.Response == nil {
+		return &apisv1.NetworkPolicyEvaluationResult{}
+	}
+	resp := eval.Response
+	action := resp.Rule.Action
+	if action == "" && resp.NetworkPolicy.Type == crdversions.PolicyTypeK8sNetworkPolicy {
+		// The rules of Kubernetes NetworkPolicies have no action, they can only allow traffic.
+		action = "Allow"
+	}
+	return &apisv1.NetworkPolicyEvaluationResult{
+		Matched: true,
+		Policy: &apisv1.NetworkPolicyEvaluationPolicy{
+			Type:      resp.NetworkPolicy.Type,
+			Namespace: resp.NetworkPolicy.Namespace,
+			Name:      resp.NetworkPolicy.Name,
+			UID:       resp.NetworkPolicy.UID,
+		},
+		Rule: &apisv1.NetworkPolicyEvaluationRule{
+			Index:     resp.RuleIndex,
+			Name:      resp.Rule.Name,
+			Direction: resp.Rule.Direction,
+			Action:    action,
+		},
+	}
+}
+
+// antreaStatusMessage returns the message of a Kubernetes Status returned by the antrea-controller,
+// or the body as is if it is not a Status.
+func antreaStatusMessage(body []byte) string {
+	var status metav1.Status
+	if err := json.Unmarshal(body, &status); err == nil && status.Message != "" {
+		return status.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// policyTier gets the tier of an Antrea-native policy as the caller. The tier is informational,
+// so it is left empty when the policy cannot be read, unless the session is no longer valid.
+func (s *Server) policyTier(c *gin.Context, policy *apisv1.NetworkPolicyEvaluationPolicy) *errors.ServerError {
+	// Only Antrea-native policies have a tier.
+	if policy.Type != crdversions.PolicyTypeAntreaClusterNetworkPolicy && policy.Type != crdversions.PolicyTypeAntreaNetworkPolicy {
+		return nil
+	}
+	gvr, _ := crdversions.PolicyGVR(s.crdResolver, policy.Type)
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return sError
+	}
+	var obj *unstructured.Unstructured
+	var err error
+	if policy.Namespace == "" {
+		obj, err = client.Resource(gvr).Get(c.Request.Context(), policy.Name, metav1.GetOptions{})
+	} else {
+		obj, err = client.Resource(gvr).Namespace(policy.Namespace).Get(c.Request.Context(), policy.Name, metav1.GetOptions{})
+	}
+	if apierrors.IsUnauthorized(err) {
+		return s.k8sError(c, err, "error when getting policy")
+	} else if err != nil {
+		s.logger.V(2).Info("Cannot get tier of policy", "type", policy.Type, "namespace", policy.Namespace, "name", policy.Name, "err", err)
+		return nil
+	}
+	tier, _, _ := unstructured.NestedString(obj.Object, "spec", "tier")
+	if tier == "" {
+		tier = antreaDefaultTier
+	}
+	policy.Tier = tier
+	return nil
+}
+
+// EvaluateNetworkPolicy handles POST /api/v1/networkpolicyevaluation. The body is an
+// apisv1.NetworkPolicyEvaluationRequest. The antrea-controller evaluates it as the caller, and
+// the response is an apisv1.NetworkPolicyEvaluationResult.
+func (s *Server) EvaluateNetworkPolicy(c *gin.Context) {
+	var result *apisv1.NetworkPolicyEvaluationResult
+	if sError := func() *errors.ServerError {
+		var evalRequest apisv1.NetworkPolicyEvaluationRequest
+		if err := c.BindJSON(&evalRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := validateNetworkPolicyEvaluationRequest(&evalRequest); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		body, err := networkPolicyEvaluationBody(&evalRequest)
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when building NetworkPolicyEvaluation: %w", err),
+			}
+		}
+		// See GetFeatureGates for why c.Request.Context() is used.
+		b, statusCode, err := s.antreaSvcRequestsHandler.Request(c.Request.Context(), "POST", networkPolicyEvaluationPath, nil, bytes.NewReader(body))
+		if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when evaluating NetworkPolicies with the antrea-controller",
+				Err:     err,
+			}
+		}
+		switch {
+		case statusCode >= 200 && statusCode < 300:
+		case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+			return s.upstreamStatusError(c, statusCode, b)
+		case statusCode == http.StatusBadRequest, statusCode == http.StatusNotFound:
+			// e.g. a Pod which does not exist, or an Antrea version without the API.
+			return &errors.ServerError{
+				Code:    statusCode,
+				Message: antreaStatusMessage(b),
+			}
+		default:
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when evaluating NetworkPolicies with the antrea-controller",
+				Err:     fmt.Errorf("unexpected status %d from Antrea Service: %s", statusCode, antreaStatusMessage(b)),
+			}
+		}
+		var eval networkPolicyEvaluation
+		if err := json.Unmarshal(b, &eval); err != nil {
+			return &errors.ServerError{
+				Code: http.StatusBadGateway,
+				Err:  fmt.Errorf("unexpected response from Antrea Service: %w", err),
+			}
+		}
+		result = networkPolicyEvaluationResult(&eval)
+		if result.Policy != nil {
+			return s.policyTier(c, result.Policy)
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to evaluate NetworkPolicies")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (s *Server) AddNetworkPolicyEvaluationRoutes(r *gin.RouterGroup) {
+	r = r.Group("/networkpolicyevaluation")
+	r.Use(s.authenticate())
+	r.POST("", s.EvaluateNetworkPolicy)
+}
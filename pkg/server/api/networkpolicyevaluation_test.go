@@ -0,0 +1,224 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+)
+
+var npEvalRequest = apisv1.NetworkPolicyEvaluationRequest{
+	Source:      apisv1.NetworkPolicyEvaluationPod{Namespace: "default", Pod: "client"},
+	Destination: apisv1.NetworkPolicyEvaluationPod{Namespace: "web", Pod: "server"},
+}
+
+func expectNetworkPolicyEvaluation(t *testing.T, ts *testServer, response string, statusCode int) {
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "POST", "/apis/controlplane.antrea.io/v1beta2/networkpolicyevaluation", nil, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _ string, _ url.Values, body io.Reader) ([]byte, int, error) {
+			b, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{
+				"apiVersion": "controlplane.antrea.io/v1beta2",
+				"kind": "NetworkPolicyEvaluation",
+				"request": {
+					"source": {"pod": {"namespace": "default", "name": "client"}},
+					"destination": {"pod": {"namespace": "web", "name": "server"}}
+				}
+			}`, string(b))
+			return []byte(response), statusCode, nil
+		})
+}
+
+func TestEvaluateNetworkPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		objects        map[string]interface{}
+		response       string
+		expectedResult apisv1.NetworkPolicyEvaluationResult
+	}{
+		{
+			name: "Antrea ClusterNetworkPolicy",
+			objects: map[string]interface{}{
+				"/apis/crd.antrea.io/v1beta1/clusternetworkpolicies/deny-web": map[string]interface{}{
+					"apiVersion": "crd.antrea.io/v1beta1",
+					"kind":       "ClusterNetworkPolicy",
+					"metadata":   map[string]interface{}{"name": "deny-web"},
+					"spec":       map[string]interface{}{"tier": "securityops"},
+				},
+			},
+			response: `{"response": {
+				"networkPolicy": {"type": "AntreaClusterNetworkPolicy", "name": "deny-web", "uid": "abc"},
+				"ruleIndex": 1,
+				"rule": {"direction": "In", "name": "drop-client", "action": "Drop"}
+			}}`,
+			expectedResult: apisv1.NetworkPolicyEvaluationResult{
+				Matched: true,
+				Policy: &apisv1.NetworkPolicyEvaluationPolicy{
+					Type: "AntreaClusterNetworkPolicy",
+					Name: "deny-web",
+					UID:  "abc",
+					Tier: "securityops",
+				},
+				Rule: &apisv1.NetworkPolicyEvaluationRule{
+					Index:     1,
+					Name:      "drop-client",
+					Direction: "In",
+					Action:    "Drop",
+				},
+			},
+		},
+		{
+			name: "Antrea NetworkPolicy in the default tier",
+			objects: map[string]interface{}{
+				"/apis/crd.antrea.io/v1beta1/namespaces/web/networkpolicies/allow-client": map[string]interface{}{
+					"apiVersion": "crd.antrea.io/v1beta1",
+					"kind":       "NetworkPolicy",
+					"metadata":   map[string]interface{}{"name": "allow-client", "namespace": "web"},
+					"spec":       map[string]interface{}{},
+				},
+			},
+			response: `{"response": {
+				"networkPolicy": {"type": "AntreaNetworkPolicy", "namespace": "web", "name": "allow-client"},
+				"ruleIndex": 0,
+				"rule": {"direction": "In", "action": "Allow"}
+			}}`,
+			expectedResult: apisv1.NetworkPolicyEvaluationResult{
+				Matched: true,
+				Policy: &apisv1.NetworkPolicyEvaluationPolicy{
+					Type:      "AntreaNetworkPolicy",
+					Namespace: "web",
+					Name:      "allow-client",
+					Tier:      "application",
+				},
+				Rule: &apisv1.NetworkPolicyEvaluationRule{Direction: "In", Action: "Allow"},
+			},
+		},
+		{
+			name: "K8s NetworkPolicy",
+			response: `{"response": {
+				"networkPolicy": {"type": "K8sNetworkPolicy", "namespace": "web", "name": "allow-default"},
+				"ruleIndex": 0,
+				"rule": {"direction": "In"}
+			}}`,
+			expectedResult: apisv1.NetworkPolicyEvaluationResult{
+				Matched: true,
+				Policy: &apisv1.NetworkPolicyEvaluationPolicy{
+					Type:      "K8sNetworkPolicy",
+					Namespace: "web",
+					Name:      "allow-default",
+				},
+				Rule: &apisv1.NetworkPolicyEvaluationRule{Direction: "In", Action: "Allow"},
+			},
+		},
+		{
+			name:           "no rule",
+			response:       `{"request": {}}`,
+			expectedResult: apisv1.NetworkPolicyEvaluationResult{},
+		},
+		{
+			name: "policy not readable",
+			response: `{"response": {
+				"networkPolicy": {"type": "AntreaClusterNetworkPolicy", "name": "hidden"},
+				"ruleIndex": 0,
+				"rule": {"direction": "Out", "name": "pass", "action": "Pass"}
+			}}`,
+			expectedResult: apisv1.NetworkPolicyEvaluationResult{
+				Matched: true,
+				Policy:  &apisv1.NetworkPolicyEvaluationPolicy{Type: "AntreaClusterNetworkPolicy", Name: "hidden"},
+				Rule:    &apisv1.NetworkPolicyEvaluationRule{Direction: "Out", Name: "pass", Action: "Pass"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, _ := newTestServerWithK8sObjects(t, tc.objects)
+			expectNetworkPolicyEvaluation(t, ts, tc.response, http.StatusCreated)
+			req := httptest.NewRequest("POST", "/api/v1/networkpolicyevaluation", bytes.NewReader(mustMarshal(&npEvalRequest)))
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			ts.router.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var result apisv1.NetworkPolicyEvaluationResult
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestEvaluateNetworkPolicyErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statusCode      int
+		response        string
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:            "Pod not found",
+			statusCode:      http.StatusNotFound,
+			response:        `{"kind": "Status", "apiVersion": "v1", "status": "Failure", "message": "pods \"server\" not found", "code": 404}`,
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: `pods \"server\" not found`,
+		},
+		{
+			name:         "forbidden",
+			statusCode:   http.StatusForbidden,
+			response:     "forbidden",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "antrea-controller error",
+			statusCode:   http.StatusInternalServerError,
+			response:     `{"kind": "Status", "apiVersion": "v1", "status": "Failure", "message": "internal error", "code": 500}`,
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			expectNetworkPolicyEvaluation(t, ts, tc.response, tc.statusCode)
+			req := httptest.NewRequest("POST", "/api/v1/networkpolicyevaluation", bytes.NewReader(mustMarshal(&npEvalRequest)))
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedMessage != "" {
+				assert.Contains(t, rr.Body.String(), tc.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicyEvaluationRequestValidation(t *testing.T) {
+	errs := validateNetworkPolicyEvaluationRequest(&apisv1.NetworkPolicyEvaluationRequest{
+		Source:      apisv1.NetworkPolicyEvaluationPod{Pod: "client"},
+		Destination: apisv1.NetworkPolicyEvaluationPod{Namespace: "web"},
+	})
+	require.Len(t, errs, 2)
+	assert.Equal(t, "Source namespace is required for a Pod", errs[0])
+	assert.Contains(t, errs[1], `Invalid destination Pod name ""`)
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	ovsTraceMaxFlowLength = 256
+	ovsTracingPath        = "/ovstracing"
+)
+
+var (
+	// ovsTraceFlowRegexp restricts the additional flow fields to the characters of the flow
+	// syntax of ovs-ofctl which make sense for a packet.
+	ovsTraceFlowRegexp = regexp.MustCompile(`^[A-Za-z0-9_.:/=,+-]*$`)
+	// ovsTraceStepRegexp matches the first line of a step of ofproto/trace, e.g.
+	// " 0. in_port=3, priority 190, cookie 0x1000000000000", or with the name of the table,
+	// "10. SpoofGuard: ip,in_port=3, priority 200, cookie 0x1000000000000". A flow with no
+	// match fields has no match before its priority.
+	ovsTraceStepRegexp = regexp.MustCompile(`^\s*(\d+)\. (?:([A-Za-z][A-Za-z0-9_]*): )?(.*)$`)
+	// ovsTraceFlowSuffixRegexp matches the end of the first line of a step, after its match.
+	ovsTraceFlowSuffixRegexp = regexp.MustCompile(`^(?:(.*), )?priority (\d+)(?:, cookie (0x[0-9a-fA-F]+))?$`)
+)
+
+// validateOVSTraceRequest normalizes req in place and checks it against the rules documented on
+// apisv1.OVSTraceRequest. Like validateTraceflowRequest, it returns every violated rule.
+func validateOVSTraceRequest(req *apisv1.OVSTraceRequest) []string {
+	var errs []string
+	src, dst := &req.Source, &req.Destination
+
+	req.Protocol = strings.ToUpper(req.Protocol)
+	if req.Protocol == "" {
+		req.Protocol = traceflowProtocolTCP
+	}
+	switch req.Protocol {
+	case traceflowProtocolTCP, traceflowProtocolUDP:
+		if req.SourcePort < 0 || req.SourcePort > 65535 {
+			errs = append(errs, "Source port must be between 0 and 65535")
+		}
+		if req.DestinationPort < 0 || req.DestinationPort > 65535 {
+			errs = append(errs, "Destination port must be between 0 and 65535")
+		}
+	case traceflowProtocolICMP:
+		if req.SourcePort != 0 || req.DestinationPort != 0 {
+			errs = append(errs, "Ports can only be set for TCP and UDP")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("Unsupported protocol %q, must be one of TCP, UDP or ICMP", req.Protocol))
+	}
+
+	// The source Pod selects the Node, and the OVS port the packet comes from.
+	errs = append(errs, validateTraceflowObjectRef("Source", "Pod", src.Namespace, src.Pod, validation.IsDNS1123Subdomain)...)
+	switch {
+	case dst.Pod != "" && dst.IP != "":
+		errs = append(errs, "Destination must be either a Pod or an IP address, not both")
+	case dst.Pod != "":
+		errs = append(errs, validateTraceflowObjectRef("Destination", "Pod", dst.Namespace, dst.Pod, validation.IsDNS1123Subdomain)...)
+	case dst.Namespace != "":
+		errs = append(errs, "Destination namespace can only be set with a Pod")
+	}
+	dstV, dstErr := traceflowIPVersion(dst.IP)
+	if dstErr {
+		errs = append(errs, fmt.Sprintf("Invalid destination IP address %q", dst.IP))
+	}
+	if dstV == 4 && req.IPv6 {
+		errs = append(errs, "IPv6 cannot be set with an IPv4 address")
+	}
+	if dstV == 6 {
+		req.IPv6 = true
+	}
+
+	if len(req.Flow) > ovsTraceMaxFlowLength {
+		errs = append(errs, fmt.Sprintf("Flow must be at most %d characters", ovsTraceMaxFlowLength))
+	} else if !ovsTraceFlowRegexp.MatchString(req.Flow) {
+		errs = append(errs, fmt.Sprintf("Invalid flow %q", req.Flow))
+	}
+	return errs
+}
+
+// ovsTraceQuery translates a validated request into the query of the ovstracing API of
+// antrea-agent, as "antctl trace-packet" would send it.
+func ovsTraceQuery(req *apisv1.OVSTraceRequest) url.Values {
+	// ovs-ofctl only knows IPv6 protocols by their own names, e.g. "tcp6".
+	protocol := strings.ToLower(req.Protocol)
+	fields := []string{protocol}
+	if req.IPv6 {
+		fields[0] += "6"
+	}
+	if req.SourcePort > 0 {
+		fields = append(fields, fmt.Sprintf("%s_src=%d", protocol, req.SourcePort))
+	}
+	if req.DestinationPort > 0 {
+		fields = append(fields, fmt.Sprintf("%s_dst=%d", protocol, req.DestinationPort))
+	}
+	if req.Flow != "" {
+		fields = append(fields, req.Flow)
+	}
+
+	query := url.Values{}
+	query.Set("source", req.Source.Namespace+"/"+req.Source.Pod)
+	if req.Destination.Pod != "" {
+		query.Set("destination", req.Destination.Namespace+"/"+req.Destination.Pod)
+	} else if req.Destination.IP != "" {
+		query.Set("destination", req.Destination.IP)
+	}
+	query.Set("flow", strings.Join(fields, ","))
+	if req.IPv6 {
+		query.Set("addressFamily", "6")
+	}
+	return query
+}
+
+// parseOVSTrace parses the output of "ovs-appctl ofproto/trace" into steps. Lines it does not
+// know about are kept as actions of the current step, so that nothing is lost after a
+// recirculation; the raw output is returned as well anyway.
+func parseOVSTrace(output string) *apisv1.OVSTraceResult {
+	result := &apisv1.OVSTraceResult{
+		Output: output,
+		Steps:  []apisv1.OVSTraceStep{},
+	}
+	var step *apisv1.OVSTraceStep
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(line, "Flow: "):
+			// The packet is given again after each recirculation.
+			if result.Flow == "" {
+				result.Flow = strings.TrimPrefix(line, "Flow: ")
+			}
+		case strings.HasPrefix(line, "Final flow: "):
+			result.FinalFlow = strings.TrimPrefix(line, "Final flow: ")
+		case strings.HasPrefix(line, "Megaflow: "):
+			result.Megaflow = strings.TrimPrefix(line, "Megaflow: ")
+		case strings.HasPrefix(line, "Datapath actions: "):
+			result.DatapathActions = strings.TrimPrefix(line, "Datapath actions: ")
+		case strings.HasPrefix(line, "bridge("), strings.Trim(trimmed, "-=") == "":
+		case ovsTraceStepRegexp.MatchString(line):
+			m := ovsTraceStepRegexp.FindStringSubmatch(line)
+			table, _ := strconv.ParseInt(m[1], 10, 32)
+			result.Steps = append(result.Steps, apisv1.OVSTraceStep{
+				Table:     int32(table),
+				TableName: m[2],
+				Match:     m[3],
+				Actions:   []string{},
+			})
+			step = &result.Steps[len(result.Steps)-1]
+			if m := ovsTraceFlowSuffixRegexp.FindStringSubmatch(step.Match); m != nil {
+				priority, _ := strconv.ParseInt(m[2], 10, 32)
+				step.Match, step.Priority, step.Cookie = m[1], int32(priority), m[3]
+			}
+		case step != nil:
+			step.Actions = append(step.Actions, trimmed)
+		}
+	}
+	return result
+}
+
+// sourcePodNode returns the Node of the source Pod of a validated request, which it gets as the
+// caller.
+func (s *Server) sourcePodNode(c *gin.Context, req *apisv1.OVSTraceRequest) (string, *errors.ServerError) {
+	ctx := c.Request.Context()
+	client, err := s.clientFactory.KubernetesClientForRequest(ctx)
+	if err != nil {
+		return "", &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("failed to build K8s client for request: %w", err),
+		}
+	}
+	pod, err := client.CoreV1().Pods(req.Source.Namespace).Get(ctx, req.Source.Pod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Source Pod %s/%s not found", req.Source.Namespace, req.Source.Pod),
+		}
+	} else if err != nil {
+		return "", s.k8sError(c, err, "error when getting source Pod")
+	}
+	if pod.Spec.NodeName == "" {
+		return "", &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Source Pod %s/%s is not scheduled on a Node yet", req.Source.Namespace, req.Source.Pod),
+		}
+	}
+	return pod.Spec.NodeName, nil
+}
+
+// TraceOVSPacket handles POST /api/v1/ovstracing. The body is an apisv1.OVSTraceRequest. The
+// packet is traced by the antrea-agent on the Node of the source Pod, as the caller, and the
+// response is an apisv1.OVSTraceResult.
+func (s *Server) TraceOVSPacket(c *gin.Context) {
+	if sError := func() *errors.ServerError {
+		var traceRequest apisv1.OVSTraceRequest
+		if err := c.BindJSON(&traceRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := validateOVSTraceRequest(&traceRequest); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		node, sError := s.sourcePodNode(c, &traceRequest)
+		if sError != nil {
+			return sError
+		}
+		b, statusCode, err := s.antreaAgentRequestsHandler.Request(c.Request.Context(), node, ovsTracingPath, ovsTraceQuery(&traceRequest))
+		if stderrors.Is(err, antreaagent.ErrAgentNotFound) {
+			return &errors.ServerError{
+				Code:    http.StatusServiceUnavailable,
+				Message: fmt.Sprintf("No running antrea-agent found on Node %s", node),
+			}
+		} else if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when forwarding request to antrea-agent",
+				Err:     err,
+			}
+		}
+		switch statusCode {
+		case http.StatusOK:
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return s.upstreamStatusError(c, statusCode, b)
+		case http.StatusBadRequest, http.StatusNotFound:
+			// e.g. the destination Pod does not exist, or the flow is invalid.
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.TrimSpace(string(b)),
+			}
+		default:
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when tracing packet",
+				Err:     fmt.Errorf("unexpected status %d from antrea-agent: %s", statusCode, strings.TrimSpace(string(b))),
+			}
+		}
+		var response struct {
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal(b, &response); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Invalid response from antrea-agent",
+				Err:     err,
+			}
+		}
+		result := parseOVSTrace(response.Result)
+		result.Node = node
+		c.JSON(http.StatusOK, result)
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to trace packet in OVS")
+	}
+}
+
+func (s *Server) AddOVSTracingRoutes(r *gin.RouterGroup) {
+	r = r.Group("/ovstracing")
+	r.Use(s.authenticate())
+	r.POST("", s.TraceOVSPacket)
+}
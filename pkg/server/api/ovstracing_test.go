@@ -0,0 +1,241 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
+)
+
+const testOVSTraceOutput = `Flow: tcp,in_port=3,vlan_tci=0x0000,dl_src=aa:bb:cc:dd:ee:01,dl_dst=aa:bb:cc:dd:ee:02,nw_src=10.10.0.5,nw_dst=10.10.0.6,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=0,tp_dst=80,tcp_flags=0
+
+bridge("br-int")
+----------------
+ 0. PipelineRootClassifier: priority 0, cookie 0x1000000000000
+    goto_table:Classifier
+ 1. Classifier: in_port=3, priority 190, cookie 0x1030000000000
+    set_field:0x3/0xf->reg0
+    goto_table:SpoofGuard
+ 2. SpoofGuard: ip,in_port=3,dl_src=aa:bb:cc:dd:ee:01,nw_src=10.10.0.5, priority 200, cookie 0x1030000000000
+    goto_table:EgressRule
+14. EgressRule: No match.
+    drop
+
+Final flow: unchanged
+Megaflow: recirc_id=0,eth,ip,in_port=3,nw_frag=no
+Datapath actions: drop
+`
+
+var ovsTraceRequest = apisv1.OVSTraceRequest{
+	Source:          apisv1.OVSTraceSource{Namespace: "default", Pod: "pod-x"},
+	Destination:     apisv1.OVSTraceDestination{Namespace: "default", Pod: "pod-y"},
+	DestinationPort: 80,
+}
+
+func TestTraceOVSPacket(t *testing.T) {
+	agentResponse := mustMarshal(map[string]string{"result": testOVSTraceOutput})
+	testCases := []struct {
+		name          string
+		podNode       string
+		agentCode     int
+		agentBody     []byte
+		agentErr      error
+		expectedCode  int
+		expectedSteps int
+	}{
+		{
+			name:          "traced",
+			podNode:       "node-1",
+			agentCode:     http.StatusOK,
+			agentBody:     agentResponse,
+			expectedCode:  http.StatusOK,
+			expectedSteps: 4,
+		},
+		{
+			name:         "rejected by antrea-agent",
+			podNode:      "node-1",
+			agentCode:    http.StatusNotFound,
+			agentBody:    []byte("destination Pod not found\n"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "forbidden",
+			podNode:      "node-1",
+			agentCode:    http.StatusForbidden,
+			agentBody:    []byte("forbidden"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "no agent",
+			podNode:      "node-1",
+			agentErr:     fmt.Errorf("%w: node-1", antreaagent.ErrAgentNotFound),
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:         "Pod not scheduled",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, fakeAPIServer := newTestServerForTraceflow(t, []string{"pods/default/pod-x"})
+			fakeAPIServer.nodeNames["pods/default/pod-x"] = tc.podNode
+			req := httptest.NewRequest("POST", "/api/v1/ovstracing", bytes.NewReader(mustMarshal(&ovsTraceRequest)))
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			if tc.podNode != "" {
+				ts.antreaAgentRequestsHandler.EXPECT().Request(gomock.Any(), tc.podNode, "/ovstracing", url.Values{
+					"source":      {"default/pod-x"},
+					"destination": {"default/pod-y"},
+					"flow":        {"tcp,tcp_dst=80"},
+				}).Return(tc.agentBody, tc.agentCode, tc.agentErr)
+			}
+			ts.router.ServeHTTP(rr, req)
+			require.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var result apisv1.OVSTraceResult
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+			assert.Equal(t, tc.podNode, result.Node)
+			assert.Len(t, result.Steps, tc.expectedSteps)
+			assert.Equal(t, testOVSTraceOutput, result.Output)
+		})
+	}
+}
+
+func TestTraceOVSPacketSourcePodNotFound(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, nil)
+	req := httptest.NewRequest("POST", "/api/v1/ovstracing", bytes.NewReader(mustMarshal(&ovsTraceRequest)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Source Pod default/pod-x not found")
+}
+
+func TestOVSTraceRequestValidation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       apisv1.OVSTraceRequest
+		expectedError string
+	}{
+		{
+			name:          "no source Pod",
+			request:       apisv1.OVSTraceRequest{Destination: apisv1.OVSTraceDestination{IP: "10.0.0.1"}},
+			expectedError: "Source namespace is required for a Pod",
+		},
+		{
+			name: "destination Pod and IP",
+			request: apisv1.OVSTraceRequest{
+				Source:      apisv1.OVSTraceSource{Namespace: "default", Pod: "pod-x"},
+				Destination: apisv1.OVSTraceDestination{Namespace: "default", Pod: "pod-y", IP: "10.0.0.1"},
+			},
+			expectedError: "Destination must be either a Pod or an IP address, not both",
+		},
+		{
+			name: "ICMP with ports",
+			request: apisv1.OVSTraceRequest{
+				Source:          apisv1.OVSTraceSource{Namespace: "default", Pod: "pod-x"},
+				Protocol:        "icmp",
+				DestinationPort: 80,
+			},
+			expectedError: "Ports can only be set for TCP and UDP",
+		},
+		{
+			name: "invalid flow",
+			request: apisv1.OVSTraceRequest{
+				Source: apisv1.OVSTraceSource{Namespace: "default", Pod: "pod-x"},
+				Flow:   "nw_ttl=1; reboot",
+			},
+			expectedError: `Invalid flow "nw_ttl=1; reboot"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateOVSTraceRequest(&tc.request)
+			assert.Contains(t, errs, tc.expectedError)
+		})
+	}
+}
+
+func TestOVSTraceQuery(t *testing.T) {
+	req := apisv1.OVSTraceRequest{
+		Source:      apisv1.OVSTraceSource{Namespace: "default", Pod: "pod-x"},
+		Destination: apisv1.OVSTraceDestination{IP: "fd00::10"},
+		Protocol:    "udp",
+		SourcePort:  5353,
+		Flow:        "nw_ttl=1",
+	}
+	require.Empty(t, validateOVSTraceRequest(&req))
+	assert.Equal(t, url.Values{
+		"source":        {"default/pod-x"},
+		"destination":   {"fd00::10"},
+		"flow":          {"udp6,udp_src=5353,nw_ttl=1"},
+		"addressFamily": {"6"},
+	}, ovsTraceQuery(&req))
+}
+
+func TestParseOVSTrace(t *testing.T) {
+	result := parseOVSTrace(testOVSTraceOutput)
+	assert.Equal(t, "tcp,in_port=3,vlan_tci=0x0000,dl_src=aa:bb:cc:dd:ee:01,dl_dst=aa:bb:cc:dd:ee:02,nw_src=10.10.0.5,nw_dst=10.10.0.6,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=0,tp_dst=80,tcp_flags=0", result.Flow)
+	assert.Equal(t, []apisv1.OVSTraceStep{
+		{
+			Table:     0,
+			TableName: "PipelineRootClassifier",
+			Match:     "",
+			Priority:  0,
+			Cookie:    "0x1000000000000",
+			Actions:   []string{"goto_table:Classifier"},
+		},
+		{
+			Table:     1,
+			TableName: "Classifier",
+			Match:     "in_port=3",
+			Priority:  190,
+			Cookie:    "0x1030000000000",
+			Actions:   []string{"set_field:0x3/0xf->reg0", "goto_table:SpoofGuard"},
+		},
+		{
+			Table:     2,
+			TableName: "SpoofGuard",
+			Match:     "ip,in_port=3,dl_src=aa:bb:cc:dd:ee:01,nw_src=10.10.0.5",
+			Priority:  200,
+			Cookie:    "0x1030000000000",
+			Actions:   []string{"goto_table:EgressRule"},
+		},
+		{
+			Table:     14,
+			TableName: "EgressRule",
+			Match:     "No match.",
+			Actions:   []string{"drop"},
+		},
+	}, result.Steps)
+	assert.Equal(t, "unchanged", result.FinalFlow)
+	assert.Equal(t, "recirc_id=0,eth,ip,in_port=3,nw_frag=no", result.Megaflow)
+	assert.Equal(t, "drop", result.DatapathActions)
+}
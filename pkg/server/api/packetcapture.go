@@ -0,0 +1,439 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	packetcapturehandler "antrea.io/antrea-ui/pkg/handlers/packetcapture"
+	"antrea.io/antrea-ui/pkg/server/errors"
+	"antrea.io/antrea-ui/pkg/server/ratelimit"
+)
+
+const (
+	packetCaptureDefaultNumber = 10
+	packetCaptureMaxNumber     = 1000
+	packetCaptureMaxTimeout    = 300
+)
+
+// validatePacketCaptureRequest normalizes req in place and checks it against the rules documented
+// on apisv1.PacketCaptureRequest. Like validateTraceflowRequest, it returns every violated rule.
+func validatePacketCaptureRequest(req *apisv1.PacketCaptureRequest) []string {
+	var errs []string
+	src, dst := &req.Source, &req.Destination
+
+	req.Protocol = strings.ToUpper(req.Protocol)
+	if req.Protocol == "" {
+		req.Protocol = traceflowProtocolTCP
+	}
+	switch req.Protocol {
+	case traceflowProtocolTCP, traceflowProtocolUDP:
+		if req.SourcePort < 0 || req.SourcePort > 65535 {
+			errs = append(errs, "Source port must be between 0 and 65535")
+		}
+		if req.DestinationPort < 0 || req.DestinationPort > 65535 {
+			errs = append(errs, "Destination port must be between 0 and 65535")
+		}
+	case traceflowProtocolICMP:
+		if req.SourcePort != 0 || req.DestinationPort != 0 {
+			errs = append(errs, "Ports can only be set for TCP and UDP")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("Unsupported protocol %q, must be one of TCP, UDP or ICMP", req.Protocol))
+	}
+	if req.Number == 0 {
+		req.Number = packetCaptureDefaultNumber
+	}
+	if req.Number < 1 || req.Number > packetCaptureMaxNumber {
+		errs = append(errs, fmt.Sprintf("Number of packets must be between 1 and %d", packetCaptureMaxNumber))
+	}
+	if req.Timeout < 0 || req.Timeout > packetCaptureMaxTimeout {
+		errs = append(errs, fmt.Sprintf("Timeout must be between 1 and %d seconds", packetCaptureMaxTimeout))
+	}
+	if utf8.RuneCountInString(req.Title) > traceflowMaxTitleLength {
+		errs = append(errs, fmt.Sprintf("Title must be at most %d characters", traceflowMaxTitleLength))
+	}
+
+	for _, endpoint := range []struct {
+		side     string
+		endpoint *apisv1.PacketCaptureEndpoint
+	}{{"Source", src}, {"Destination", dst}} {
+		e := endpoint.endpoint
+		switch {
+		case e.Pod != "" && e.IP != "":
+			errs = append(errs, fmt.Sprintf("%s must be either a Pod or an IP address, not both", endpoint.side))
+		case e.Pod != "":
+			errs = append(errs, validateTraceflowObjectRef(endpoint.side, "Pod", e.Namespace, e.Pod, validation.IsDNS1123Subdomain)...)
+		case e.Namespace != "":
+			errs = append(errs, fmt.Sprintf("%s namespace can only be set with a Pod", endpoint.side))
+		}
+	}
+	// Antrea captures on the Node of a Pod, so it needs at least one.
+	if src.Pod == "" && dst.Pod == "" {
+		errs = append(errs, "At least one of source and destination must be a Pod")
+	}
+
+	srcV, srcErr := traceflowIPVersion(src.IP)
+	if srcErr {
+		errs = append(errs, fmt.Sprintf("Invalid source IP address %q", src.IP))
+	}
+	dstV, dstErr := traceflowIPVersion(dst.IP)
+	if dstErr {
+		errs = append(errs, fmt.Sprintf("Invalid destination IP address %q", dst.IP))
+	}
+	if srcV != 0 && dstV != 0 && srcV != dstV {
+		errs = append(errs, "IP version mismatch between source and destination")
+	}
+	if (srcV == 4 || dstV == 4) && req.IPv6 {
+		errs = append(errs, "IPv6 cannot be set with an IPv4 address")
+	}
+	if srcV == 6 || dstV == 6 {
+		req.IPv6 = true
+	}
+	return errs
+}
+
+// packetCaptureSpec translates a validated request into the spec of an Antrea PacketCapture CR.
+// The file server is set by the PacketCapture handler.
+func packetCaptureSpec(req *apisv1.PacketCaptureRequest) map[string]interface{} {
+	endpoint := func(e *apisv1.PacketCaptureEndpoint) map[string]interface{} {
+		if e.Pod != "" {
+			return map[string]interface{}{
+				"pod": map[string]interface{}{"namespace": e.Namespace, "name": e.Pod},
+			}
+		}
+		if e.IP != "" {
+			return map[string]interface{}{"ip": e.IP}
+		}
+		return nil
+	}
+	ports := func() map[string]interface{} {
+		h := map[string]interface{}{}
+		if req.SourcePort > 0 {
+			h["srcPort"] = int64(req.SourcePort)
+		}
+		if req.DestinationPort > 0 {
+			h["dstPort"] = int64(req.DestinationPort)
+		}
+		return h
+	}
+	packet := map[string]interface{}{
+		"ipFamily": "IPv4",
+		"protocol": req.Protocol,
+	}
+	if req.IPv6 {
+		packet["ipFamily"] = "IPv6"
+	}
+	switch req.Protocol {
+	case traceflowProtocolTCP:
+		packet["transportHeader"] = map[string]interface{}{"tcp": ports()}
+	case traceflowProtocolUDP:
+		packet["transportHeader"] = map[string]interface{}{"udp": ports()}
+	case traceflowProtocolICMP:
+		// Antrea only knows the ICMP protocol by name; ICMPv6 is given by number.
+		if req.IPv6 {
+			packet["protocol"] = int64(ipProtocolICMPv6)
+		}
+	}
+
+	spec := map[string]interface{}{
+		"captureConfig": map[string]interface{}{
+			"firstN": map[string]interface{}{"number": int64(req.Number)},
+		},
+		"packet": packet,
+	}
+	if source := endpoint(&req.Source); source != nil {
+		spec["source"] = source
+	}
+	if destination := endpoint(&req.Destination); destination != nil {
+		spec["destination"] = destination
+	}
+	if req.Timeout > 0 {
+		spec["timeout"] = int64(req.Timeout)
+	}
+	return spec
+}
+
+// CreatePacketCaptureRequest handles POST /api/v1/packetcapture. The body is an
+// apisv1.PacketCaptureRequest. The PacketCapture is created as the caller.
+func (s *Server) CreatePacketCaptureRequest(c *gin.Context) {
+	var requestID string
+	if sError := func() *errors.ServerError {
+		var pcRequest apisv1.PacketCaptureRequest
+		if err := c.BindJSON(&pcRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := validatePacketCaptureRequest(&pcRequest); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var username string
+		if ra, ok := session.RequestAuthFrom(c.Request.Context()); ok {
+			username = ra.Username
+		}
+		var err error
+		requestID, err = s.packetCaptureRequestsHandler.CreateRequest(c, client, &packetcapturehandler.Request{
+			Object: map[string]interface{}{
+				"spec": packetCaptureSpec(&pcRequest),
+			},
+			Username: username,
+			Title:    pcRequest.Title,
+		})
+		if err != nil {
+			return s.k8sError(c, err, "error when creating PacketCapture request")
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to create PacketCapture request")
+		return
+	}
+	c.Writer.Header().Add("Access-Control-Expose-Headers", "Location, Retry-After")
+	c.Header("Location", fmt.Sprintf("/api/v1/packetcapture/%s", requestID))
+	c.Header("Retry-After", "2") // 2 seconds
+	c.Status(http.StatusAccepted)
+}
+
+// GetPacketCaptureRequestStatus handles GET /api/v1/packetcapture/:requestId/status, which
+// redirects to /result once the PacketCapture is completed.
+func (s *Server) GetPacketCaptureRequestStatus(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var done bool
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var err error
+		_, done, err = s.packetCaptureRequestsHandler.GetRequestResult(c, client, requestID)
+		if err != nil {
+			return s.k8sError(c, err, "error when getting PacketCapture request status")
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get PacketCapture request status", "requestId", requestID)
+		return
+	}
+	if !done {
+		c.Header("Access-Control-Expose-Headers", "Location, Retry-After")
+		c.Header("Location", fmt.Sprintf("/api/v1/packetcapture/%s/status", requestID))
+		// Captures last longer than Traceflows, so there is no point in polling as often.
+		c.Header("Retry-After", "2") // 2 seconds
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Header("Access-Control-Expose-Headers", "Location")
+	c.Header("Location", fmt.Sprintf("/api/v1/packetcapture/%s/result", requestID))
+	c.Status(http.StatusFound)
+}
+
+// getPacketCaptureRequestResult returns the PacketCapture object of a completed request, as the
+// caller can get it.
+func (s *Server) getPacketCaptureRequestResult(c *gin.Context, requestID string) (map[string]interface{}, *errors.ServerError) {
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return nil, sError
+	}
+	pcResult, done, err := s.packetCaptureRequestsHandler.GetRequestResult(c, client, requestID)
+	if err != nil {
+		return nil, s.k8sError(c, err, "error when getting PacketCapture request result")
+	}
+	if !done {
+		return nil, &errors.ServerError{
+			Code:    http.StatusNotFound,
+			Message: "PacketCapture result not available, call the /status endpoint to check progress",
+		}
+	}
+	return pcResult, nil
+}
+
+// GetPacketCaptureRequestResult handles GET /api/v1/packetcapture/:requestId/result, which
+// returns the PacketCapture object.
+func (s *Server) GetPacketCaptureRequestResult(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var data []byte
+	if sError := func() *errors.ServerError {
+		pcResult, sError := s.getPacketCaptureRequestResult(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		var err error
+		data, err = json.Marshal(pcResult)
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when converting PacketCapture request result to JSON: %w", err),
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get result for PacketCapture request", "requestId", requestID)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// GetPacketCaptureRequestFile handles GET /api/v1/packetcapture/:requestId/pcap. It streams the
+// pcapng file Antrea uploaded to the file server. The PacketCapture is read as the caller first,
+// so only a caller allowed to get it can download its file.
+func (s *Server) GetPacketCaptureRequestFile(c *gin.Context) {
+	requestID := c.Param("requestId")
+	if sError := func() *errors.ServerError {
+		pcResult, sError := s.getPacketCaptureRequestResult(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		f, name, err := s.packetCaptureRequestsHandler.OpenFile(c.Request.Context(), pcResult)
+		switch {
+		case stderrors.Is(err, packetcapturehandler.ErrNoFile):
+			phase, message := packetcapturehandler.Phase(pcResult)
+			if message != "" {
+				phase = fmt.Sprintf("%s: %s", phase, message)
+			}
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("No file is available for this PacketCapture (%s)", phase),
+			}
+		case stderrors.Is(err, fs.ErrNotExist):
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: "The file of this PacketCapture is no longer on the file server",
+			}
+		case err != nil:
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when downloading the file of this PacketCapture from the file server",
+				Err:     err,
+			}
+		}
+		defer f.Close()
+		c.Header("Access-Control-Expose-Headers", "Content-Disposition")
+		// The length is not known without a stat request, so the file is sent chunked.
+		c.DataFromReader(http.StatusOK, -1, "application/vnd.tcpdump.pcap", f, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": name}),
+		})
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get file for PacketCapture request", "requestId", requestID)
+		return
+	}
+}
+
+func (s *Server) DeletePacketCaptureRequest(c *gin.Context) {
+	requestID := c.Param("requestId")
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		ok, err := s.packetCaptureRequestsHandler.DeleteRequest(c, client, requestID)
+		if err != nil {
+			return s.k8sError(c, err, "error when deleting PacketCapture request")
+		}
+		if !ok {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: "PacketCapture request not found",
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to delete PacketCapture request", "requestId", requestID)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ListPacketCaptureRequests handles GET /api/v1/packetcapture, which lists the PacketCaptures
+// created by antrea-ui that the caller can list, most recent first.
+func (s *Server) ListPacketCaptureRequests(c *gin.Context) {
+	var summaries []apisv1.PacketCaptureSummary
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var err error
+		summaries, err = s.packetCaptureRequestsHandler.ListRequests(c, client)
+		if err != nil {
+			return s.k8sError(c, err, "error when listing PacketCapture requests")
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to list PacketCapture requests")
+		return
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+// packetCaptureDisabled answers every /api/v1/packetcapture route when no file server is
+// configured. Like flowStreamDisabled, it is a 501: Antrea needs a file server to upload to.
+func (s *Server) packetCaptureDisabled(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{
+		"error": "PacketCapture is not enabled for this Antrea UI instance (set packetCapture.fileServer.url in the Helm chart).",
+	})
+}
+
+func (s *Server) AddPacketCaptureRoutes(r *gin.RouterGroup) {
+	r = r.Group("/packetcapture")
+	r.Use(s.authenticate())
+	if s.packetCaptureRequestsHandler == nil {
+		r.Use(s.packetCaptureDisabled)
+	}
+	create := []gin.HandlerFunc{s.CreatePacketCaptureRequest}
+	if s.config.MaxPacketCapturesPerHour >= 0 {
+		burstSize := 0
+		if s.config.MaxPacketCapturesPerHour > 0 {
+			burstSize = 5
+		}
+		rateLimiter := ratelimit.NewGlobalRateLimiterOrDie(fmt.Sprintf("%d/h", s.config.MaxPacketCapturesPerHour), burstSize)
+		create = append([]gin.HandlerFunc{ratelimit.Middleware(rateLimiter)}, create...)
+	}
+	r.POST("", create...)
+	r.GET("", s.ListPacketCaptureRequests)
+	r.GET("/:requestId/status", s.GetPacketCaptureRequestStatus)
+	r.GET("/:requestId", func(c *gin.Context) {
+		c.Redirect(http.StatusSeeOther, c.Request.URL.Path+"/status")
+	})
+	r.GET("/:requestId/result", s.GetPacketCaptureRequestResult)
+	r.GET("/:requestId/pcap", s.GetPacketCaptureRequestFile)
+	r.DELETE("/:requestId", s.DeletePacketCaptureRequest)
+}
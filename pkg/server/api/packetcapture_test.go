@@ -0,0 +1,263 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	packetcapturehandler "antrea.io/antrea-ui/pkg/handlers/packetcapture"
+)
+
+var (
+	pcRequest = apisv1.PacketCaptureRequest{
+		Source: apisv1.PacketCaptureEndpoint{
+			Namespace: "default",
+			Pod:       "pod-x",
+		},
+		Destination: apisv1.PacketCaptureEndpoint{
+			IP: "10.0.0.10",
+		},
+		DestinationPort: 80,
+		Title:           "drops to 10.0.0.10",
+	}
+	pcSpec = map[string]interface{}{
+		"source": map[string]interface{}{
+			"pod": map[string]interface{}{"namespace": "default", "name": "pod-x"},
+		},
+		"destination": map[string]interface{}{
+			"ip": "10.0.0.10",
+		},
+		"packet": map[string]interface{}{
+			"ipFamily": "IPv4",
+			"protocol": "TCP",
+			"transportHeader": map[string]interface{}{
+				"tcp": map[string]interface{}{"dstPort": int64(80)},
+			},
+		},
+		"captureConfig": map[string]interface{}{
+			"firstN": map[string]interface{}{"number": int64(10)},
+		},
+	}
+)
+
+func TestPacketCaptureRequest(t *testing.T) {
+	ts := newTestServer(t)
+
+	// create PacketCapture request
+	req := httptest.NewRequest("POST", "/api/v1/packetcapture", bytes.NewReader(mustMarshal(&pcRequest)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	requestID := uuid.NewString()
+	ts.packetCaptureRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &packetcapturehandler.Request{
+		Object:   map[string]interface{}{"spec": pcSpec},
+		Username: "tester",
+		Title:    "drops to 10.0.0.10",
+	}).Return(requestID, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+	url, err := rr.Result().Location()
+	require.NoError(t, err)
+	reqURI := url.RequestURI()
+	statusURI := reqURI + "/status"
+
+	// get status: not ready yet
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.packetCaptureRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(nil, false, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+
+	pcResult := map[string]interface{}{
+		"spec": pcSpec,
+		"status": map[string]interface{}{
+			"numberCaptured": int64(10),
+			"filePath":       "sftp://10.0.0.1/upload/node-1_pc.pcapng",
+		},
+	}
+
+	// get status: ready
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.packetCaptureRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(pcResult, true, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusFound, rr.Code)
+	url, err = rr.Result().Location()
+	require.NoError(t, err)
+	assert.Equal(t, reqURI+"/result", url.RequestURI())
+
+	// get result
+	req = httptest.NewRequest("GET", reqURI+"/result", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.packetCaptureRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(pcResult, true, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, string(mustMarshal(pcResult)), rr.Body.String())
+
+	// get pcap file
+	req = httptest.NewRequest("GET", reqURI+"/pcap", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.packetCaptureRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(pcResult, true, nil)
+	ts.packetCaptureRequestsHandler.EXPECT().OpenFile(gomock.Any(), pcResult).Return(io.NopCloser(bytes.NewReader([]byte("pcapng data"))), "node-1_pc.pcapng", nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "pcapng data", rr.Body.String())
+	assert.Equal(t, `attachment; filename=node-1_pc.pcapng`, rr.Header().Get("Content-Disposition"))
+
+	// delete request
+	req = httptest.NewRequest("DELETE", reqURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.packetCaptureRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), requestID).Return(true, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPacketCaptureRequestFileErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name:         "no file",
+			err:          packetcapturehandler.ErrNoFile,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "file removed",
+			err:          fmt.Errorf("error when opening file: %w", fs.ErrNotExist),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "file server error",
+			err:          fmt.Errorf("connection refused"),
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			requestID := uuid.NewString()
+			pcResult := map[string]interface{}{"spec": pcSpec}
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/packetcapture/%s/pcap", requestID), nil)
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			ts.packetCaptureRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(pcResult, true, nil)
+			ts.packetCaptureRequestsHandler.EXPECT().OpenFile(gomock.Any(), pcResult).Return(nil, "", tc.err)
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestPacketCaptureRequestValidation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       apisv1.PacketCaptureRequest
+		expectedError string
+	}{
+		{
+			name: "no Pod",
+			request: apisv1.PacketCaptureRequest{
+				Source:      apisv1.PacketCaptureEndpoint{IP: "10.0.0.1"},
+				Destination: apisv1.PacketCaptureEndpoint{IP: "10.0.0.2"},
+			},
+			expectedError: "At least one of source and destination must be a Pod",
+		},
+		{
+			name: "too many packets",
+			request: apisv1.PacketCaptureRequest{
+				Source: apisv1.PacketCaptureEndpoint{Namespace: "default", Pod: "pod-x"},
+				Number: 1001,
+			},
+			expectedError: "Number of packets must be between 1 and 1000",
+		},
+		{
+			name: "ICMP with ports",
+			request: apisv1.PacketCaptureRequest{
+				Source:          apisv1.PacketCaptureEndpoint{Namespace: "default", Pod: "pod-x"},
+				Protocol:        "icmp",
+				DestinationPort: 80,
+			},
+			expectedError: "Ports can only be set for TCP and UDP",
+		},
+		{
+			name: "Pod without Namespace",
+			request: apisv1.PacketCaptureRequest{
+				Destination: apisv1.PacketCaptureEndpoint{Pod: "pod-x"},
+			},
+			expectedError: "Destination namespace is required for a Pod",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validatePacketCaptureRequest(&tc.request)
+			assert.Contains(t, errs, tc.expectedError)
+		})
+	}
+}
+
+func TestPacketCaptureSpecIPv6ICMP(t *testing.T) {
+	req := apisv1.PacketCaptureRequest{
+		Source:      apisv1.PacketCaptureEndpoint{Namespace: "default", Pod: "pod-x"},
+		Destination: apisv1.PacketCaptureEndpoint{IP: "fd00::10"},
+		Protocol:    "ICMP",
+	}
+	require.Empty(t, validatePacketCaptureRequest(&req))
+	spec := packetCaptureSpec(&req)
+	assert.Equal(t, map[string]interface{}{
+		"ipFamily": "IPv6",
+		"protocol": int64(ipProtocolICMPv6),
+	}, spec["packet"])
+}
+
+func TestPacketCaptureRequestRateLimiting(t *testing.T) {
+	ts := newTestServer(t, setMaxPacketCapturesPerHour(0))
+	req := httptest.NewRequest("POST", "/api/v1/packetcapture", bytes.NewReader(mustMarshal(&pcRequest)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestPacketCaptureDisabled(t *testing.T) {
+	ts := newTestServer(t)
+	ts.s.packetCaptureRequestsHandler = nil
+	router := gin.New()
+	ts.s.AddPacketCaptureRoutes(router.Group("/api/v1"))
+	req := httptest.NewRequest("GET", "/api/v1/packetcapture", nil)
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotImplemented, rr.Code)
+}
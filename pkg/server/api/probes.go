@@ -0,0 +1,142 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+// runProbe is the probes.Runner: it runs the Traceflow of a connectivity probe as antrea-ui-admin.
+// Probes are configured by the operator, so they do not count against the Traceflow rate limits,
+// which are meant for users.
+func (s *Server) runProbe(ctx context.Context, tfRequest *apisv1.TraceflowRequest) apisv1.ReachabilityCell {
+	if errs := validateTraceflowRequest(tfRequest); len(errs) > 0 {
+		return apisv1.ReachabilityCell{
+			Result: apisv1.ReachabilityError,
+			Reason: strings.Join(errs, "; "),
+		}
+	}
+	if s.traceflowAdminClient == nil {
+		return apisv1.ReachabilityCell{
+			Result: apisv1.ReachabilityError,
+			Reason: "No antrea-ui-admin client for probes",
+		}
+	}
+	// Probes run as antrea-ui-admin, and are not subject to any user's quota.
+	return s.traceReachability(ctx, s.traceflowAdminClient, "", &traceflowQuota{maxPerHour: -1}, tfRequest)
+}
+
+// checkProbesAccess only lets callers who can list Traceflows see the probes: their results name
+// Pods and NetworkPolicies across Namespaces, like the Traceflows themselves.
+func (s *Server) checkProbesAccess(c *gin.Context) *errors.ServerError {
+	ctx := c.Request.Context()
+	if ra, ok := session.RequestAuthFrom(ctx); ok && ra.Mode == session.ModeAdmin {
+		return nil
+	}
+	clientset, err := s.clientFactory.KubernetesClientForRequest(ctx)
+	if err != nil {
+		return &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("failed to build K8s client for request: %w", err),
+		}
+	}
+	allowed, err := reviewAccess(ctx, clientset, &authorizationv1.ResourceAttributes{
+		Verb:     "list",
+		Group:    "crd.antrea.io",
+		Resource: "traceflows",
+	})
+	if err != nil {
+		return s.k8sError(c, err, "error when checking Traceflow permissions")
+	}
+	if !allowed {
+		return &errors.ServerError{
+			Code:    http.StatusForbidden,
+			Message: "Not allowed to list Traceflows, which is required to see probes",
+		}
+	}
+	return nil
+}
+
+// ListProbes handles GET /api/v1/probes. The response is the list of apisv1.ProbeStatus, empty if
+// no probe is configured.
+func (s *Server) ListProbes(c *gin.Context) {
+	if sError := s.checkProbesAccess(c); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to list probes")
+		return
+	}
+	statuses := []apisv1.ProbeStatus{}
+	if s.probeScheduler != nil {
+		statuses = s.probeScheduler.Statuses()
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// GetProbe handles GET /api/v1/probes/:name. The response is an apisv1.ProbeDetails.
+func (s *Server) GetProbe(c *gin.Context) {
+	var details *apisv1.ProbeDetails
+	if sError := func() *errors.ServerError {
+		if sError := s.checkProbesAccess(c); sError != nil {
+			return sError
+		}
+		name := c.Param("name")
+		var ok bool
+		if s.probeScheduler != nil {
+			details, ok = s.probeScheduler.Details(name)
+		}
+		if !ok {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("Probe %s not found", name),
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get probe")
+		return
+	}
+	c.JSON(http.StatusOK, details)
+}
+
+// GetMetrics handles GET /metrics, in the Prometheus text exposition format. It is not
+// authenticated, so that Prometheus can scrape it, and only exposes probe names and results.
+func (s *Server) GetMetrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	if s.probeScheduler == nil {
+		return
+	}
+	if err := s.probeScheduler.WriteMetrics(c.Writer); err != nil {
+		s.logger.Error(err, "Error when writing metrics")
+	}
+}
+
+func (s *Server) AddProbesRoutes(r *gin.RouterGroup) {
+	probes := r.Group("/probes")
+	probes.Use(s.authenticate())
+	probes.GET("", s.ListProbes)
+	probes.GET("/:name", s.GetProbe)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	probestesting "antrea.io/antrea-ui/pkg/handlers/probes/testing"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+func newTestServerForProbes(t *testing.T) (*testServer, *fakeTraceflowK8sAPIServer, *probestesting.MockScheduler) {
+	ts, fakeAPIServer := newTestServerForTraceflow(t, nil)
+	scheduler := probestesting.NewMockScheduler(gomock.NewController(t))
+	ts.s.probeScheduler = scheduler
+	return ts, fakeAPIServer, scheduler
+}
+
+func TestListProbes(t *testing.T) {
+	statuses := []apisv1.ProbeStatus{{Name: "web-to-db", Expect: apisv1.ReachabilityAllowed, Runs: 2, Passed: 1, SuccessRate: 0.5}}
+
+	t.Run("admin", func(t *testing.T) {
+		ts, _, scheduler := newTestServerForProbes(t)
+		scheduler.EXPECT().Statuses().Return(statuses)
+		req := httptest.NewRequest("GET", "/api/v1/probes", nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var got []apisv1.ProbeStatus
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+		assert.Equal(t, statuses, got)
+	})
+
+	t.Run("can list Traceflows", func(t *testing.T) {
+		ts, fakeAPIServer, scheduler := newTestServerForProbes(t)
+		fakeAPIServer.allowed["list traceflows"] = true
+		scheduler.EXPECT().Statuses().Return(statuses)
+		req := httptest.NewRequest("GET", "/api/v1/probes", nil)
+		ts.authorizeRequestAs(req, session.ModeOIDC)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+
+	t.Run("cannot list Traceflows", func(t *testing.T) {
+		ts, _, _ := newTestServerForProbes(t)
+		req := httptest.NewRequest("GET", "/api/v1/probes", nil)
+		ts.authorizeRequestAs(req, session.ModeOIDC)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	})
+
+	t.Run("no probes", func(t *testing.T) {
+		ts := newTestServer(t)
+		req := httptest.NewRequest("GET", "/api/v1/probes", nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.JSONEq(t, "[]", rr.Body.String())
+	})
+}
+
+func TestGetProbe(t *testing.T) {
+	ts, _, scheduler := newTestServerForProbes(t)
+	details := &apisv1.ProbeDetails{
+		ProbeStatus: apisv1.ProbeStatus{Name: "web-to-db"},
+		History: []apisv1.ProbeRun{{
+			Time:             "2026-01-01T00:00:00Z",
+			ReachabilityCell: apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed},
+			Passed:           true,
+		}},
+	}
+	scheduler.EXPECT().Details("web-to-db").Return(details, true)
+	scheduler.EXPECT().Details("unknown").Return(nil, false)
+
+	for name, expectedCode := range map[string]int{"web-to-db": http.StatusOK, "unknown": http.StatusNotFound} {
+		req := httptest.NewRequest("GET", "/api/v1/probes/"+name, nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		require.Equal(t, expectedCode, rr.Code, rr.Body.String())
+		if expectedCode == http.StatusOK {
+			var got apisv1.ProbeDetails
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			assert.Equal(t, *details, got)
+		}
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	ts, _, scheduler := newTestServerForProbes(t)
+	scheduler.EXPECT().WriteMetrics(gomock.Any()).DoAndReturn(func(w io.Writer) error {
+		_, err := w.Write([]byte("antrea_ui_probe_passed{probe=\"web-to-db\"} 1\n"))
+		return err
+	})
+	// Prometheus scrapes without credentials.
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "antrea_ui_probe_passed{probe=\"web-to-db\"} 1\n", rr.Body.String())
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestRunProbe(t *testing.T) {
+	ts := newTestServer(t, setMaxTraceflowsPerHour(1))
+	adminClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	ts.s.traceflowAdminClient = adminClient
+	probeRequest := &apisv1.TraceflowRequest{
+		Source:      apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+		Destination: apisv1.TraceflowDestination{Namespace: "default", Service: "db"},
+		Title:       "Probe web-to-db",
+	}
+	// Probes do not count against the Traceflow rate limit, which only allows one per hour.
+	for _, requestID := range []string{"tf-1", "tf-2"} {
+		ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), adminClient, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ any, request *traceflowhandler.Request) (string, error) {
+				assert.Empty(t, request.Username)
+				assert.Equal(t, "Probe web-to-db", request.Title)
+				return requestID, nil
+			})
+		ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), adminClient, requestID).DoAndReturn(
+			func(context.Context, any, string) (<-chan traceflowhandler.RequestUpdate, error) {
+				updates := make(chan traceflowhandler.RequestUpdate, 1)
+				updates <- traceflowhandler.RequestUpdate{Object: huntResult("Succeeded", ""), Done: true}
+				close(updates)
+				return updates, nil
+			})
+		ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), adminClient, requestID).Return(true, nil)
+		cell := ts.s.runProbe(t.Context(), probeRequest)
+		assert.Equal(t, apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed}, cell)
+	}
+
+	cell := ts.s.runProbe(t.Context(), &apisv1.TraceflowRequest{Source: apisv1.TraceflowSource{IP: "10.0.0.1"}})
+	assert.Equal(t, apisv1.ReachabilityError, cell.Result)
+}
@@ -0,0 +1,494 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	// reachabilityMatrixMaxCells bounds the number of Traceflows of one matrix.
+	reachabilityMatrixMaxCells = 256
+	// reachabilityMatrixConcurrency is the number of Traceflows of a matrix that run at the
+	// same time, so that a large matrix does not flood the Antrea agents.
+	reachabilityMatrixConcurrency = 8
+	// reachabilityMatrixTimeout bounds POST /api/v1/traceflow/matrix. Cells that cannot be
+	// traced by then, e.g. because the Traceflow rate limit is too low for the matrix, are
+	// reported as errors.
+	reachabilityMatrixTimeout = 10 * time.Minute
+	// reachabilityMatrixCleanupTimeout bounds the deletion of each Traceflow of the matrix.
+	reachabilityMatrixCleanupTimeout = 10 * time.Second
+	// reachabilityMatrixRunningRetryInterval is how often a cell waiting for one of the caller's
+	// other Traceflows to complete checks again.
+	reachabilityMatrixRunningRetryInterval = time.Second
+)
+
+// CreateReachabilityMatrix handles POST /api/v1/traceflow/matrix. It resolves the sources and
+// destinations of the apisv1.ReachabilityMatrixRequest to Pods, Services and IPs as the caller,
+// runs one Traceflow per (source, destination) pair, and returns an apisv1.ReachabilityMatrix.
+// The Traceflows are deleted once they are completed.
+func (s *Server) CreateReachabilityMatrix(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), reachabilityMatrixTimeout)
+	defer cancel()
+	var matrix *apisv1.ReachabilityMatrix
+	if sError := func() *errors.ServerError {
+		var req apisv1.ReachabilityMatrixRequest
+		if err := c.BindJSON(&req); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := validateReachabilityMatrixRequest(&req); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		k8sClient, err := s.clientFactory.KubernetesClientForRequest(ctx)
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("failed to build K8s client for request: %w", err),
+			}
+		}
+		var sources []apisv1.TraceflowSource
+		for _, endpoints := range req.Sources {
+			pods, sError := s.selectReachabilityPods(ctx, c, k8sClient, &endpoints)
+			if sError != nil {
+				return sError
+			}
+			for _, pod := range pods {
+				sources = append(sources, apisv1.TraceflowSource{Namespace: pod.namespace, Pod: pod.name})
+			}
+		}
+		var destinations []apisv1.TraceflowDestination
+		for _, endpoints := range req.Destinations {
+			if endpoints.Service != "" || endpoints.IP != "" {
+				destinations = append(destinations, apisv1.TraceflowDestination{
+					Namespace: endpoints.Namespace,
+					Service:   endpoints.Service,
+					IP:        endpoints.IP,
+				})
+				continue
+			}
+			pods, sError := s.selectReachabilityPods(ctx, c, k8sClient, &endpoints)
+			if sError != nil {
+				return sError
+			}
+			for _, pod := range pods {
+				destinations = append(destinations, apisv1.TraceflowDestination{Namespace: pod.namespace, Pod: pod.name})
+			}
+		}
+		sources = dedupReachabilityEndpoints(sources)
+		destinations = dedupReachabilityEndpoints(destinations)
+		if len(sources) == 0 || len(destinations) == 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: "The sources and destinations must each select at least one running Pod or endpoint",
+			}
+		}
+		if cells := len(sources) * len(destinations); cells > reachabilityMatrixMaxCells {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("The matrix has %d cells (%d sources x %d destinations), the maximum is %d", cells, len(sources), len(destinations), reachabilityMatrixMaxCells),
+			}
+		}
+
+		// Every cell is validated as a regular Traceflow request before any Traceflow is
+		// created, so that e.g. an IPv4 destination with IPv6 set fails the whole matrix
+		// right away.
+		tfRequests := make([][]apisv1.TraceflowRequest, len(sources))
+		errs := sets.New[string]()
+		var errList []string
+		for i := range sources {
+			tfRequests[i] = make([]apisv1.TraceflowRequest, len(destinations))
+			for j := range destinations {
+				tfRequests[i][j] = apisv1.TraceflowRequest{
+					Source:          sources[i],
+					Destination:     destinations[j],
+					Protocol:        req.Protocol,
+					DestinationPort: req.DestinationPort,
+					IPv6:            req.IPv6,
+					Timeout:         req.Timeout,
+					Title:           fmt.Sprintf("Reachability matrix: %s to %s", reachabilitySourceName(&sources[i]), reachabilityDestinationName(&destinations[j])),
+				}
+				for _, err := range validateTraceflowRequest(&tfRequests[i][j]) {
+					if !errs.Has(err) {
+						errs.Insert(err)
+						errList = append(errList, err)
+					}
+				}
+			}
+		}
+		if len(errList) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errList, "; "),
+			}
+		}
+
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var username string
+		if ra, ok := session.RequestAuthFrom(c.Request.Context()); ok {
+			username = ra.Username
+		}
+		// The whole matrix must fit in the caller's quota, rather than running until the
+		// quota is exhausted.
+		quota := s.traceflowQuotaFor(ctx)
+		reservations, sError := s.reserveTraceflows(c, &quota, len(sources)*len(destinations))
+		if sError != nil {
+			return sError
+		}
+		cells, created := s.runReachabilityMatrix(ctx, client, username, quota, tfRequests)
+		// Only the Traceflows that were created count against the caller's quota.
+		if reservations != nil {
+			for _, reservation := range reservations[created:] {
+				s.cancelTraceflowReservation(c, reservation)
+			}
+		}
+		matrix = &apisv1.ReachabilityMatrix{
+			Sources:      sources,
+			Destinations: destinations,
+			Cells:        cells,
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to create reachability matrix")
+		return
+	}
+	c.JSON(http.StatusOK, matrix)
+}
+
+// validateReachabilityMatrixRequest checks the endpoint selectors of req. The other fields are
+// validated as part of each cell's Traceflow request.
+func validateReachabilityMatrixRequest(req *apisv1.ReachabilityMatrixRequest) []string {
+	var errs []string
+	validate := func(side string, i int, endpoints *apisv1.ReachabilityEndpoints) {
+		prefix := fmt.Sprintf("%s[%d]", side, i)
+		var kinds []string
+		if endpoints.Pod != "" {
+			kinds = append(kinds, "a Pod")
+		}
+		if endpoints.PodSelector != "" {
+			kinds = append(kinds, "a Pod selector")
+		}
+		if endpoints.Service != "" {
+			kinds = append(kinds, "a Service")
+		}
+		if endpoints.IP != "" {
+			kinds = append(kinds, "an IP address")
+		}
+		if len(kinds) > 1 {
+			errs = append(errs, fmt.Sprintf("%s must be only one of a Pod, a Pod selector, a Service or an IP address, got %s", prefix, strings.Join(kinds, ", ")))
+		}
+		if side == "Sources" && (endpoints.Service != "" || endpoints.IP != "") {
+			errs = append(errs, fmt.Sprintf("%s must select Pods", prefix))
+		}
+		if endpoints.IP != "" {
+			if endpoints.Namespace != "" {
+				errs = append(errs, fmt.Sprintf("%s namespace cannot be set with an IP address", prefix))
+			}
+			return
+		}
+		if endpoints.Namespace == "" {
+			errs = append(errs, fmt.Sprintf("%s namespace is required", prefix))
+		} else if fieldErrs := validation.IsDNS1123Label(endpoints.Namespace); len(fieldErrs) > 0 {
+			errs = append(errs, fmt.Sprintf("Invalid %s namespace %q: %s", prefix, endpoints.Namespace, strings.Join(fieldErrs, "; ")))
+		}
+		if endpoints.PodSelector != "" {
+			if _, err := labels.Parse(endpoints.PodSelector); err != nil {
+				errs = append(errs, fmt.Sprintf("Invalid %s Pod selector %q: %v", prefix, endpoints.PodSelector, err))
+			}
+		}
+	}
+	if len(req.Sources) == 0 {
+		errs = append(errs, "Sources are required")
+	}
+	if len(req.Destinations) == 0 {
+		errs = append(errs, "Destinations are required")
+	}
+	for i := range req.Sources {
+		validate("Sources", i, &req.Sources[i])
+	}
+	for i := range req.Destinations {
+		validate("Destinations", i, &req.Destinations[i])
+	}
+	return errs
+}
+
+type reachabilityPod struct {
+	namespace, name string
+}
+
+// selectReachabilityPods returns the Pods selected by endpoints, which is a Pod or a Pod
+// selector. A Pod is returned as is, like for a single Traceflow; a selector only matches
+// running Pods that are not on the host network, since Antrea cannot trace the others.
+func (s *Server) selectReachabilityPods(ctx context.Context, c *gin.Context, client kubernetes.Interface, endpoints *apisv1.ReachabilityEndpoints) ([]reachabilityPod, *errors.ServerError) {
+	if endpoints.Pod != "" {
+		return []reachabilityPod{{endpoints.Namespace, endpoints.Pod}}, nil
+	}
+	list, err := client.CoreV1().Pods(endpoints.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: endpoints.PodSelector,
+	})
+	if err != nil {
+		return nil, s.k8sError(c, err, "error when listing Pods")
+	}
+	var pods []reachabilityPod
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Spec.HostNetwork {
+			continue
+		}
+		pods = append(pods, reachabilityPod{pod.Namespace, pod.Name})
+	}
+	return pods, nil
+}
+
+func dedupReachabilityEndpoints[T comparable](endpoints []T) []T {
+	seen := sets.New[T]()
+	var result []T
+	for _, e := range endpoints {
+		if !seen.Has(e) {
+			seen.Insert(e)
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func reachabilitySourceName(src *apisv1.TraceflowSource) string {
+	return src.Namespace + "/" + src.Pod
+}
+
+func reachabilityDestinationName(dst *apisv1.TraceflowDestination) string {
+	switch {
+	case dst.Pod != "":
+		return dst.Namespace + "/" + dst.Pod
+	case dst.Service != "":
+		return "Service " + dst.Namespace + "/" + dst.Service
+	default:
+		return dst.IP
+	}
+}
+
+// runReachabilityMatrix runs the validated Traceflow requests, at most
+// reachabilityMatrixConcurrency at a time (or fewer, if the caller's quota allows fewer running
+// Traceflows), and returns their results in the same layout, and the number of Traceflows it
+// created. The caller's hourly budget must have been reserved already.
+func (s *Server) runReachabilityMatrix(ctx context.Context, client dynamic.Interface, username string, quota traceflowQuota, tfRequests [][]apisv1.TraceflowRequest) ([][]apisv1.ReachabilityCell, int) {
+	cells := make([][]apisv1.ReachabilityCell, len(tfRequests))
+	concurrency := reachabilityMatrixConcurrency
+	if quota.maxConcurrent > 0 {
+		concurrency = min(concurrency, quota.maxConcurrent)
+	}
+	sem := make(chan struct{}, concurrency)
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for i := range tfRequests {
+		cells[i] = make([]apisv1.ReachabilityCell, len(tfRequests[i]))
+		for j := range tfRequests[i] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					cells[i][j] = reachabilityCellError("Timed out before the Traceflow could be run")
+					return
+				}
+				defer func() { <-sem }()
+				var ok bool
+				cells[i][j], ok = s.traceReachabilityCell(ctx, client, username, &quota, &tfRequests[i][j])
+				if ok {
+					created.Add(1)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return cells, int(created.Load())
+}
+
+// traceReachabilityCell runs a validated Traceflow request of a matrix, once the global Traceflow
+// limit allows it, see traceReachability. It returns false if the Traceflow was not created.
+func (s *Server) traceReachabilityCell(ctx context.Context, client dynamic.Interface, username string, quota *traceflowQuota, tfRequest *apisv1.TraceflowRequest) (apisv1.ReachabilityCell, bool) {
+	if s.traceflowRateLimiter != nil {
+		if err := s.traceflowRateLimiter.Wait(ctx); err != nil {
+			return reachabilityCellError("Traceflow rate limit exceeded: %v", err), false
+		}
+	}
+	requestID, err := s.createReachabilityTraceflow(ctx, client, quota, reachabilityTraceflowRequest(username, tfRequest))
+	if err != nil {
+		return reachabilityCellError("Error when creating Traceflow: %v", err), false
+	}
+	return s.watchReachabilityTraceflow(ctx, client, requestID), true
+}
+
+// traceReachability runs a validated Traceflow request to completion, deletes it and interprets
+// its result. It waits for the caller to have fewer running Traceflows than their quota allows,
+// but does not apply any rate limit.
+func (s *Server) traceReachability(ctx context.Context, client dynamic.Interface, username string, quota *traceflowQuota, tfRequest *apisv1.TraceflowRequest) apisv1.ReachabilityCell {
+	requestID, err := s.createReachabilityTraceflow(ctx, client, quota, reachabilityTraceflowRequest(username, tfRequest))
+	if err != nil {
+		return reachabilityCellError("Error when creating Traceflow: %v", err)
+	}
+	return s.watchReachabilityTraceflow(ctx, client, requestID)
+}
+
+func reachabilityCellError(format string, args ...interface{}) apisv1.ReachabilityCell {
+	return apisv1.ReachabilityCell{
+		Result: apisv1.ReachabilityError,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+func reachabilityTraceflowRequest(username string, tfRequest *apisv1.TraceflowRequest) *traceflowhandler.Request {
+	return &traceflowhandler.Request{
+		Object: map[string]interface{}{
+			"spec": traceflowSpec(tfRequest, tfRequest.Destination.IP),
+		},
+		Username: username,
+		Title:    tfRequest.Title,
+	}
+}
+
+// watchReachabilityTraceflow waits for Traceflow requestID to complete, deletes it and interprets
+// its result.
+func (s *Server) watchReachabilityTraceflow(ctx context.Context, client dynamic.Interface, requestID string) apisv1.ReachabilityCell {
+	defer func() {
+		// The caller keeps the results: the Traceflows are not worth keeping in the history.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reachabilityMatrixCleanupTimeout)
+		defer cancel()
+		if _, err := s.traceflowRequestsHandler.DeleteRequest(ctx, client, requestID); err != nil {
+			s.logger.Error(err, "Error when deleting reachability Traceflow", "requestId", requestID)
+		}
+	}()
+	updates, err := s.traceflowRequestsHandler.WatchRequest(ctx, client, requestID)
+	if err != nil {
+		return reachabilityCellError("Error when watching Traceflow: %v", err)
+	}
+	for update := range updates {
+		if update.Err != nil {
+			return reachabilityCellError("Error when watching Traceflow: %v", update.Err)
+		}
+		if update.Done {
+			return reachabilityCellForResult(update.Object)
+		}
+	}
+	return reachabilityCellError("Timed out waiting for the Traceflow to complete")
+}
+
+// createReachabilityTraceflow creates the Traceflow for request once the caller has fewer running
+// Traceflows than quota.maxConcurrent, like createTraceflowRequest. Rather than failing, it waits
+// for the caller's other Traceflows, including the ones of the same matrix, to complete.
+func (s *Server) createReachabilityTraceflow(ctx context.Context, client dynamic.Interface, quota *traceflowQuota, request *traceflowhandler.Request) (string, error) {
+	for {
+		requestID, created, err := func() (string, bool, error) {
+			unlock := s.lockTraceflowQuota(quota)
+			defer unlock()
+			if quota.user != "" && quota.maxConcurrent > 0 {
+				running, err := s.traceflowRequestsHandler.CountRunningRequests(ctx, quota.user)
+				if err != nil {
+					return "", false, fmt.Errorf("error when counting running Traceflows: %w", err)
+				}
+				if running >= quota.maxConcurrent {
+					return "", false, nil
+				}
+			}
+			requestID, err := s.traceflowRequestsHandler.CreateRequest(ctx, client, request)
+			return requestID, true, err
+		}()
+		if created || err != nil {
+			return requestID, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("too many running Traceflows: at most %d at a time", quota.maxConcurrent)
+		case <-time.After(reachabilityMatrixRunningRetryInterval):
+		}
+	}
+}
+
+// reachabilityCellForResult interprets a completed Traceflow: the packet was denied if any Node
+// observed it being dropped or rejected.
+func reachabilityCellForResult(traceflow map[string]interface{}) apisv1.ReachabilityCell {
+	phase, _, _ := unstructured.NestedString(traceflow, "status", "phase")
+	if phase != "Succeeded" {
+		reason, _, _ := unstructured.NestedString(traceflow, "status", "reason")
+		return apisv1.ReachabilityCell{
+			Result: apisv1.ReachabilityError,
+			Reason: fmt.Sprintf("Traceflow %s: %s", strings.ToLower(phase), reason),
+		}
+	}
+	results, _, _ := unstructured.NestedSlice(traceflow, "status", "results")
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(result, "node")
+		observations, _, _ := unstructured.NestedSlice(result, "observations")
+		for _, o := range observations {
+			observation, ok := o.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			field := func(name string) string {
+				v, _, _ := unstructured.NestedString(observation, name)
+				return v
+			}
+			if action := field("action"); action == "Dropped" || action == "Rejected" {
+				return apisv1.ReachabilityCell{
+					Result: apisv1.ReachabilityDenied,
+					BlockedBy: &apisv1.ReachabilityBlock{
+						Node:              node,
+						Component:         field("component"),
+						ComponentInfo:     field("componentInfo"),
+						Action:            action,
+						NetworkPolicy:     field("networkPolicy"),
+						NetworkPolicyRule: field("networkPolicyRule"),
+					},
+				}
+			}
+		}
+	}
+	return apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed}
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+func sendReachabilityMatrixRequest(ts *testServer, req *apisv1.ReachabilityMatrixRequest) *httptest.ResponseRecorder {
+	httpReq := httptest.NewRequest("POST", "/api/v1/traceflow/matrix", bytes.NewBuffer(mustMarshal(req)))
+	ts.authorizeRequest(httpReq)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, httpReq)
+	return rr
+}
+
+// expectReachabilityTraceflows makes the Traceflow handler complete every Traceflow with the
+// status returned by result, keyed by "<source Pod> to <destination Pod or IP>".
+func expectReachabilityTraceflows(ts *testServer, result func(key string) map[string]interface{}) {
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ dynamic.Interface, request *traceflowhandler.Request) (string, error) {
+			spec := request.Object["spec"].(map[string]interface{})
+			src := spec["source"].(map[string]interface{})
+			dst := spec["destination"].(map[string]interface{})
+			dstName, ok := dst["pod"]
+			if !ok {
+				dstName = dst["ip"]
+			}
+			return fmt.Sprintf("%s to %s", src["pod"], dstName), nil
+		}).AnyTimes()
+	ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ dynamic.Interface, requestID string) (<-chan traceflowhandler.RequestUpdate, error) {
+			updates := make(chan traceflowhandler.RequestUpdate, 2)
+			updates <- traceflowhandler.RequestUpdate{Object: map[string]interface{}{}}
+			updates <- traceflowhandler.RequestUpdate{Object: map[string]interface{}{"status": result(requestID)}, Done: true}
+			close(updates)
+			return updates, nil
+		}).AnyTimes()
+}
+
+func TestReachabilityMatrix(t *testing.T) {
+	ts, fakeAPIServer := newTestServerForTraceflow(t, []string{"pods/a/client-1", "pods/a/client-2", "pods/a/other", "pods/b/web"})
+	fakeAPIServer.labels["pods/a/client-1"] = map[string]string{"app": "client"}
+	fakeAPIServer.labels["pods/a/client-2"] = map[string]string{"app": "client"}
+	expectReachabilityTraceflows(ts, func(key string) map[string]interface{} {
+		switch key {
+		case "client-2 to web":
+			return map[string]interface{}{
+				"phase": "Succeeded",
+				"results": []interface{}{map[string]interface{}{
+					"node": "node-b",
+					"observations": []interface{}{
+						map[string]interface{}{"component": "Forwarding", "action": "Received"},
+						map[string]interface{}{
+							"component":         "NetworkPolicy",
+							"componentInfo":     "IngressRule",
+							"action":            "Dropped",
+							"networkPolicy":     "AntreaNetworkPolicy:b/deny-client-2",
+							"networkPolicyRule": "deny-all",
+						},
+					},
+				}},
+			}
+		case "client-1 to 10.0.0.1", "client-2 to 10.0.0.1":
+			return map[string]interface{}{"phase": "Failed", "reason": "timeout"}
+		default:
+			return map[string]interface{}{
+				"phase": "Succeeded",
+				"results": []interface{}{map[string]interface{}{
+					"node":         "node-b",
+					"observations": []interface{}{map[string]interface{}{"component": "Forwarding", "action": "Delivered"}},
+				}},
+			}
+		}
+	})
+	ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(4)
+
+	rr := sendReachabilityMatrixRequest(ts, &apisv1.ReachabilityMatrixRequest{
+		Sources: []apisv1.ReachabilityEndpoints{{Namespace: "a", PodSelector: "app=client"}},
+		Destinations: []apisv1.ReachabilityEndpoints{
+			{Namespace: "b", Pod: "web"},
+			{IP: "10.0.0.1"},
+		},
+		DestinationPort: 443,
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var matrix apisv1.ReachabilityMatrix
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matrix))
+	assert.ElementsMatch(t, []apisv1.TraceflowSource{{Namespace: "a", Pod: "client-1"}, {Namespace: "a", Pod: "client-2"}}, matrix.Sources)
+	assert.Equal(t, []apisv1.TraceflowDestination{{Namespace: "b", Pod: "web"}, {IP: "10.0.0.1"}}, matrix.Destinations)
+	require.Len(t, matrix.Cells, 2)
+	for i, src := range matrix.Sources {
+		require.Len(t, matrix.Cells[i], 2)
+		if src.Pod == "client-2" {
+			assert.Equal(t, apisv1.ReachabilityCell{
+				Result: apisv1.ReachabilityDenied,
+				BlockedBy: &apisv1.ReachabilityBlock{
+					Node:              "node-b",
+					Component:         "NetworkPolicy",
+					ComponentInfo:     "IngressRule",
+					Action:            "Dropped",
+					NetworkPolicy:     "AntreaNetworkPolicy:b/deny-client-2",
+					NetworkPolicyRule: "deny-all",
+				},
+			}, matrix.Cells[i][0])
+		} else {
+			assert.Equal(t, apisv1.ReachabilityCell{Result: apisv1.ReachabilityAllowed}, matrix.Cells[i][0])
+		}
+		assert.Equal(t, apisv1.ReachabilityCell{Result: apisv1.ReachabilityError, Reason: "Traceflow failed: timeout"}, matrix.Cells[i][1])
+	}
+}
+
+func TestReachabilityMatrixRateLimited(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, []string{"pods/a/client", "pods/b/web"}, setMaxTraceflowsPerHour(0))
+	rr := sendReachabilityMatrixRequest(ts, &apisv1.ReachabilityMatrixRequest{
+		Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a", Pod: "client"}},
+		Destinations: []apisv1.ReachabilityEndpoints{{Namespace: "b", Pod: "web"}},
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var matrix apisv1.ReachabilityMatrix
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matrix))
+	assert.Equal(t, apisv1.ReachabilityError, matrix.Cells[0][0].Result)
+	assert.Contains(t, matrix.Cells[0][0].Reason, "Traceflow rate limit exceeded")
+}
+
+func TestReachabilityMatrixValidation(t *testing.T) {
+	var manyPods []string
+	for i := 0; i < 17; i++ {
+		manyPods = append(manyPods, fmt.Sprintf("pods/a/client-%d", i))
+	}
+	var manyIPs []apisv1.ReachabilityEndpoints
+	for i := 0; i < 16; i++ {
+		manyIPs = append(manyIPs, apisv1.ReachabilityEndpoints{IP: fmt.Sprintf("10.0.0.%d", i)})
+	}
+	testCases := []struct {
+		name            string
+		req             apisv1.ReachabilityMatrixRequest
+		expectedMessage string
+	}{
+		{
+			name:            "empty",
+			req:             apisv1.ReachabilityMatrixRequest{},
+			expectedMessage: "Sources are required; Destinations are required",
+		},
+		{
+			name: "invalid endpoints",
+			req: apisv1.ReachabilityMatrixRequest{
+				Sources: []apisv1.ReachabilityEndpoints{{Namespace: "a", Service: "web"}, {PodSelector: "app in ("}},
+				Destinations: []apisv1.ReachabilityEndpoints{
+					{Namespace: "b", Pod: "web", PodSelector: "app=web"},
+					{Namespace: "b", IP: "10.0.0.1"},
+				},
+			},
+			expectedMessage: "Sources[0] must select Pods; Sources[1] namespace is required; " +
+				`Invalid Sources[1] Pod selector "app in (": unable to parse requirement: found '', expected: ',', ')' or identifier; ` +
+				"Destinations[0] must be only one of a Pod, a Pod selector, a Service or an IP address, got a Pod, a Pod selector; " +
+				"Destinations[1] namespace cannot be set with an IP address",
+		},
+		{
+			name: "no Pod selected",
+			req: apisv1.ReachabilityMatrixRequest{
+				Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a", PodSelector: "app=none"}},
+				Destinations: []apisv1.ReachabilityEndpoints{{IP: "10.0.0.1"}},
+			},
+			expectedMessage: "The sources and destinations must each select at least one running Pod or endpoint",
+		},
+		{
+			name: "invalid Traceflows",
+			req: apisv1.ReachabilityMatrixRequest{
+				Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a"}},
+				Destinations: []apisv1.ReachabilityEndpoints{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				Protocol:     "SCTP",
+				IPv6:         true,
+			},
+			expectedMessage: `Unsupported protocol "SCTP", must be one of TCP, UDP or ICMP; IPv6 cannot be set with an IPv4 destination`,
+		},
+		{
+			name: "too many cells",
+			req: apisv1.ReachabilityMatrixRequest{
+				Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a"}},
+				Destinations: manyIPs,
+			},
+			expectedMessage: "The matrix has 272 cells (17 sources x 16 destinations), the maximum is 256",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, _ := newTestServerForTraceflow(t, manyPods)
+			rr := sendReachabilityMatrixRequest(ts, &tc.req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, string(mustMarshal(tc.expectedMessage)), rr.Body.String())
+		})
+	}
+}
@@ -15,30 +15,53 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 
 	apisv1 "antrea.io/antrea-ui/apis/v1"
 	serverconfig "antrea.io/antrea-ui/pkg/config/server"
 	accesshandler "antrea.io/antrea-ui/pkg/handlers/access"
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
 	"antrea.io/antrea-ui/pkg/handlers/antreasvc"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
 	"antrea.io/antrea-ui/pkg/handlers/flowstream"
+	"antrea.io/antrea-ui/pkg/handlers/packetcapture"
+	"antrea.io/antrea-ui/pkg/handlers/probes"
+	"antrea.io/antrea-ui/pkg/handlers/supportbundle"
 	"antrea.io/antrea-ui/pkg/handlers/traceflow"
 	"antrea.io/antrea-ui/pkg/k8s"
 	"antrea.io/antrea-ui/pkg/password"
 	"antrea.io/antrea-ui/pkg/plugins"
 	"antrea.io/antrea-ui/pkg/server/authn"
 	"antrea.io/antrea-ui/pkg/server/errors"
+	"antrea.io/antrea-ui/pkg/server/ratelimit"
 	"antrea.io/antrea-ui/pkg/version"
 )
 
 type serverConfig struct {
 	// keep all fields exported, so the config struct can be logged
 	MaxTraceflowsPerHour int
+	TraceflowQuota       serverconfig.TraceflowQuotaConfig
+	TraceflowDelegation  bool
+	TraceflowHunt        serverconfig.TraceflowHuntConfig
+	// MaxPacketCapturesPerHour is negative when PacketCaptures are not rate-limited.
+	MaxPacketCapturesPerHour int
+	// MaxSupportBundlesPerHour is negative when support bundles are not rate-limited.
+	MaxSupportBundlesPerHour int
+	// AntreaControllerAllowedPaths are the patterns of the antrea-controller API paths that
+	// GET /api/v1/antrea/controller/*path forwards.
+	AntreaControllerAllowedPaths []string
+	// AntreaAgentAllowedPaths are the patterns of the antrea-agent API paths that
+	// GET /api/v1/nodes/:node/agent/*path forwards.
+	AntreaAgentAllowedPaths []string
 }
 
 // Options are the dependencies of the API server.
@@ -48,9 +71,13 @@ type Options struct {
 	TraceflowRequestsHandler traceflow.RequestsHandler
 	K8sProxyHandler          http.Handler
 	AntreaSvcRequestsHandler antreasvc.RequestsHandler
-	FlowStreamSubscriber     flowstream.FlowStreamSubscriber
-	PasswordStore            password.Store
-	PluginRegistry           *plugins.Registry
+	// AntreaAgentRequestsHandler forwards requests to the antrea-agent API of a Node.
+	AntreaAgentRequestsHandler antreaagent.RequestsHandler
+	FlowStreamSubscriber       flowstream.FlowStreamSubscriber
+	// FlowMasker, when set, redacts streamed flow records according to the caller's groups.
+	FlowMasker     *flowstream.Masker
+	PasswordStore  password.Store
+	PluginRegistry *plugins.Registry
 	// Authenticator resolves the caller's identity for every protected route.
 	Authenticator *authn.Authenticator
 	// ClientFactory builds Kubernetes clients that act as the caller.
@@ -58,46 +85,106 @@ type Options struct {
 	// AccessResolver answers namespace-discovery and cluster-scope-probe questions for
 	// GET /api/v1/access-summary.
 	AccessResolver accesshandler.Resolver
+	// AdminDynamicClient acts as antrea-ui-admin. It creates and reads delegated Traceflows.
+	AdminDynamicClient dynamic.Interface
+	// ProbeScheduler runs the connectivity probes, if any are configured. The server sets its
+	// runner.
+	ProbeScheduler probes.Scheduler
+	// CRDResolver picks the versions of the Antrea CRDs, and reports them in GET
+	// /api/v1/settings.
+	CRDResolver crdversions.Resolver
+	// PacketCaptureRequestsHandler is nil when no file server is configured for PacketCaptures.
+	PacketCaptureRequestsHandler packetcapture.RequestsHandler
+	// SupportBundleRequestsHandler is nil when no file server is configured for support bundles.
+	SupportBundleRequestsHandler supportbundle.RequestsHandler
 }
 
 type Server struct {
-	logger                   logr.Logger
-	traceflowRequestsHandler traceflow.RequestsHandler
-	k8sProxyHandler          http.Handler
-	antreaSvcRequestsHandler antreasvc.RequestsHandler
-	flowStreamSSEHandler     *flowstream.SSEHandler
-	passwordStore            password.Store
-	authenticator            *authn.Authenticator
-	clientFactory            *k8s.ClientFactory
-	config                   serverConfig
-	frontendSettings         *apisv1.FrontendSettings
-	pluginRegistry           *plugins.Registry
-	accessResolver           accesshandler.Resolver
+	logger                     logr.Logger
+	traceflowRequestsHandler   traceflow.RequestsHandler
+	k8sProxyHandler            http.Handler
+	antreaSvcRequestsHandler   antreasvc.RequestsHandler
+	antreaAgentRequestsHandler antreaagent.RequestsHandler
+	flowStreamSSEHandler       *flowstream.SSEHandler
+	passwordStore              password.Store
+	authenticator              *authn.Authenticator
+	clientFactory              *k8s.ClientFactory
+	config                     serverConfig
+	frontendSettings           *apisv1.FrontendSettings
+	pluginRegistry             *plugins.Registry
+	accessResolver             accesshandler.Resolver
+	// traceflowRateLimiter is shared by POST /api/v1/traceflow and the reachability matrix. It is
+	// nil when Traceflows are not rate-limited.
+	traceflowRateLimiter *ratelimit.GlobalRateLimiter
+	// traceflowUserRateLimiter tracks the hourly budget of every user, see traceflowQuotaFor.
+	traceflowUserRateLimiter *ratelimit.UserRateLimiter
+	// traceflowQuotaLocks holds one lock per user, from counting their running Traceflows to
+	// creating theirs, see lockTraceflowQuota.
+	traceflowQuotaLocks keyedMutex
+	// traceflowAdminClient acts as antrea-ui-admin, see delegateTraceflow.
+	traceflowAdminClient dynamic.Interface
+	// probeScheduler is nil when no probe is configured.
+	probeScheduler probes.Scheduler
+	// crdResolver is nil when the versions of the Antrea CRDs are not discovered.
+	crdResolver crdversions.Resolver
+	// packetCaptureRequestsHandler is nil when PacketCaptures are disabled.
+	packetCaptureRequestsHandler packetcapture.RequestsHandler
+	// supportBundleRequestsHandler is nil when support bundles are disabled.
+	supportBundleRequestsHandler supportbundle.RequestsHandler
+	// lookupIP resolves Traceflow destination FQDNs; it is replaced in tests.
+	lookupIP func(ctx context.Context, network, host string) ([]net.IP, error)
 }
 
 func NewServer(o Options) *Server {
 	c := serverConfig{
-		MaxTraceflowsPerHour: o.Config.Limits.MaxTraceflowsPerHour,
+		MaxTraceflowsPerHour:         o.Config.Limits.MaxTraceflowsPerHour,
+		TraceflowQuota:               o.Config.Limits.TraceflowQuota,
+		TraceflowDelegation:          o.Config.Traceflow.Delegation.Enabled,
+		TraceflowHunt:                o.Config.Traceflow.Hunt,
+		MaxPacketCapturesPerHour:     o.Config.Limits.MaxPacketCapturesPerHour,
+		MaxSupportBundlesPerHour:     o.Config.Limits.MaxSupportBundlesPerHour,
+		AntreaControllerAllowedPaths: o.Config.AntreaAPI.Controller.AllowedPaths,
+		AntreaAgentAllowedPaths:      o.Config.AntreaAPI.Agent.AllowedPaths,
 	}
 	o.Logger.Info("Created API server config", "config", c)
 	var flowSSEHandler *flowstream.SSEHandler
 	if o.FlowStreamSubscriber != nil {
 		flowSSEHandler = flowstream.NewSSEHandler(o.Logger, o.FlowStreamSubscriber)
+		flowSSEHandler.SetStreamLimits(flowstream.StreamLimits{
+			MaxStreams:        o.Config.FlowAggregator.Streams.MaxStreams,
+			MaxStreamsPerUser: o.Config.FlowAggregator.Streams.MaxStreamsPerUser,
+			EvictOldest:       o.Config.FlowAggregator.Streams.EvictOldest,
+		})
 	}
-	return &Server{
-		logger:                   o.Logger,
-		traceflowRequestsHandler: o.TraceflowRequestsHandler,
-		k8sProxyHandler:          o.K8sProxyHandler,
-		antreaSvcRequestsHandler: o.AntreaSvcRequestsHandler,
-		flowStreamSSEHandler:     flowSSEHandler,
-		passwordStore:            o.PasswordStore,
-		authenticator:            o.Authenticator,
-		clientFactory:            o.ClientFactory,
-		config:                   c,
-		frontendSettings:         buildFrontendSettingsFromConfig(o.Config),
-		pluginRegistry:           o.PluginRegistry,
-		accessResolver:           o.AccessResolver,
+	s := &Server{
+		logger:                       o.Logger,
+		traceflowRequestsHandler:     o.TraceflowRequestsHandler,
+		k8sProxyHandler:              o.K8sProxyHandler,
+		antreaSvcRequestsHandler:     o.AntreaSvcRequestsHandler,
+		antreaAgentRequestsHandler:   o.AntreaAgentRequestsHandler,
+		flowStreamSSEHandler:         flowSSEHandler,
+		passwordStore:                o.PasswordStore,
+		authenticator:                o.Authenticator,
+		clientFactory:                o.ClientFactory,
+		config:                       c,
+		frontendSettings:             buildFrontendSettingsFromConfig(o.Config),
+		pluginRegistry:               o.PluginRegistry,
+		accessResolver:               o.AccessResolver,
+		traceflowUserRateLimiter:     ratelimit.NewUserRateLimiterOrDie(traceflowQuotaCacheSize),
+		traceflowAdminClient:         o.AdminDynamicClient,
+		probeScheduler:               o.ProbeScheduler,
+		crdResolver:                  o.CRDResolver,
+		packetCaptureRequestsHandler: o.PacketCaptureRequestsHandler,
+		supportBundleRequestsHandler: o.SupportBundleRequestsHandler,
+		lookupIP:                     net.DefaultResolver.LookupIP,
 	}
+	if flowSSEHandler != nil && o.FlowMasker != nil {
+		flowSSEHandler.EnableMasking(o.FlowMasker, s.callerGroups)
+	}
+	if o.ProbeScheduler != nil {
+		o.ProbeScheduler.SetRunner(s.runProbe)
+	}
+	return s
 }
 
 // authenticate is the middleware protecting every route that acts on the user's behalf. It
@@ -107,6 +194,15 @@ func (s *Server) authenticate() gin.HandlerFunc {
 	return s.authenticator.Middleware()
 }
 
+// crdGVR returns the GroupVersionResource of an Antrea CRD resource, one of the crdversions
+// Resource* constants.
+func (s *Server) crdGVR(resource string) schema.GroupVersionResource {
+	if s.crdResolver != nil {
+		return s.crdResolver.GVR(resource)
+	}
+	return crdversions.DefaultGVR(resource)
+}
+
 //nolint:unused
 func announceDeprecationMiddleware(removalDate time.Time, message string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -123,11 +219,19 @@ func (s *Server) AddRoutes(r *gin.RouterGroup) {
 	apiv1.GET("/settings", s.FrontendSettings)
 	s.AddPluginsRoutes(apiv1)
 	s.AddTraceflowRoutes(apiv1)
+	s.AddPacketCaptureRoutes(apiv1)
+	s.AddSupportBundleRoutes(apiv1)
 	s.AddAccountRoutes(apiv1)
 	s.AddK8sRoutes(apiv1)
 	apiv1.GET("/featuregates", s.authenticate(), s.GetFeatureGates)
+	s.AddHealthRoutes(apiv1)
+	s.AddAntreaAPIRoutes(apiv1)
+	s.AddOVSTracingRoutes(apiv1)
+	s.AddNetworkPolicyEvaluationRoutes(apiv1)
 	s.AddFlowStreamRoutes(apiv1)
 	s.AddAccessRoutes(apiv1)
+	s.AddProbesRoutes(apiv1)
+	r.GET("/metrics", s.GetMetrics)
 }
 
 func (s *Server) AddFlowStreamRoutes(r *gin.RouterGroup) {
@@ -16,6 +16,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -35,7 +36,10 @@ import (
 
 	"antrea.io/antrea-ui/pkg/auth/session"
 	serverconfig "antrea.io/antrea-ui/pkg/config/server"
+	antreaagenthandlertesting "antrea.io/antrea-ui/pkg/handlers/antreaagent/testing"
 	antreasvchandlertesting "antrea.io/antrea-ui/pkg/handlers/antreasvc/testing"
+	packetcapturehandlertesting "antrea.io/antrea-ui/pkg/handlers/packetcapture/testing"
+	supportbundlehandlertesting "antrea.io/antrea-ui/pkg/handlers/supportbundle/testing"
 	traceflowhandlertesting "antrea.io/antrea-ui/pkg/handlers/traceflow/testing"
 	"antrea.io/antrea-ui/pkg/k8s"
 	passwordtesting "antrea.io/antrea-ui/pkg/password/testing"
@@ -66,15 +70,18 @@ func (h *testk8sProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 }
 
 type testServer struct {
-	s                        *Server
-	router                   *gin.Engine
-	traceflowRequestsHandler *traceflowhandlertesting.MockRequestsHandler
-	k8sProxyHandler          *testk8sProxyHandler
-	antreaSvcRequestsHandler *antreasvchandlertesting.MockRequestsHandler
-	passwordStore            *passwordtesting.MockStore
-	sessionStore             session.Store
-	pluginsClientset         *k8sfake.Clientset
-	credentialValidator      *fakeCredentialValidator
+	s                            *Server
+	router                       *gin.Engine
+	traceflowRequestsHandler     *traceflowhandlertesting.MockRequestsHandler
+	packetCaptureRequestsHandler *packetcapturehandlertesting.MockRequestsHandler
+	supportBundleRequestsHandler *supportbundlehandlertesting.MockRequestsHandler
+	k8sProxyHandler              *testk8sProxyHandler
+	antreaSvcRequestsHandler     *antreasvchandlertesting.MockRequestsHandler
+	antreaAgentRequestsHandler   *antreaagenthandlertesting.MockRequestsHandler
+	passwordStore                *passwordtesting.MockStore
+	sessionStore                 session.Store
+	pluginsClientset             *k8sfake.Clientset
+	credentialValidator          *fakeCredentialValidator
 }
 
 // fakeCredentialValidator stands in for the API server's SelfSubjectReview. Bearer tokens have no
@@ -106,23 +113,99 @@ func setMaxTraceflowsPerHour(v int) testServerOptions {
 	}
 }
 
+func setMaxPacketCapturesPerHour(v int) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.Limits.MaxPacketCapturesPerHour = v
+	}
+}
+
+func setMaxSupportBundlesPerHour(v int) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.Limits.MaxSupportBundlesPerHour = v
+	}
+}
+
+func setTraceflowQuota(quota serverconfig.TraceflowQuotaConfig) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.Limits.TraceflowQuota = quota
+	}
+}
+
+func setTraceflowDelegation(enabled bool) testServerOptions {
+	return func(c *serverconfig.Config) {
+		c.Traceflow.Delegation.Enabled = enabled
+	}
+}
+
 func setServerURL(url string) testServerOptions {
 	return func(c *serverconfig.Config) {
 		c.URL = url
 	}
 }
 
+// fakeK8sObjectsAPIServer serves GET requests for fixed objects, indexed by their path. When
+// forbidden is set, every request is denied.
+type fakeK8sObjectsAPIServer struct {
+	*httptest.Server
+	forbidden bool
+}
+
+// newTestServerWithK8sObjects returns a test server whose K8s clients get objects from a fake K8s
+// API server.
+func newTestServerWithK8sObjects(t *testing.T, objects map[string]interface{}, options ...testServerOptions) (*testServer, *fakeK8sObjectsAPIServer) {
+	f := &fakeK8sObjectsAPIServer{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if f.forbidden {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Status",
+				"status":     "Failure",
+				"reason":     "Forbidden",
+				"code":       http.StatusForbidden,
+				"message":    fmt.Sprintf("%s is forbidden", r.URL.Path),
+			})
+			return
+		}
+		obj, ok := objects[r.URL.Path]
+		if r.Method != http.MethodGet || !ok {
+			t.Logf("unexpected request to fake K8s API server: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(obj)
+	}))
+	t.Cleanup(f.Close)
+	ts := newTestServer(t, options...)
+	clientFactory, err := k8s.NewClientFactory(&rest.Config{
+		Host:          f.URL,
+		ContentConfig: rest.ContentConfig{ContentType: "application/json"},
+	}, http.DefaultTransport, session.TransportKeyK8s)
+	require.NoError(t, err)
+	ts.s.clientFactory = clientFactory
+	return ts, f
+}
+
 func newTestServer(t *testing.T, options ...testServerOptions) *testServer {
 	logger := testr.New(t)
 	ctrl := gomock.NewController(t)
 	traceflowRequestsHandler := traceflowhandlertesting.NewMockRequestsHandler(ctrl)
+	packetCaptureRequestsHandler := packetcapturehandlertesting.NewMockRequestsHandler(ctrl)
+	supportBundleRequestsHandler := supportbundlehandlertesting.NewMockRequestsHandler(ctrl)
 	k8sProxyHandler := &testk8sProxyHandler{}
 	antreaSvcRequestsHandler := antreasvchandlertesting.NewMockRequestsHandler(ctrl)
+	antreaAgentRequestsHandler := antreaagenthandlertesting.NewMockRequestsHandler(ctrl)
 	passwordStore := passwordtesting.NewMockStore(ctrl)
 
 	config := &serverconfig.Config{}
 	// disable rate limiting by default
 	config.Limits.MaxTraceflowsPerHour = -1
+	config.Limits.MaxPacketCapturesPerHour = -1
+	config.Limits.MaxSupportBundlesPerHour = -1
+	config.Limits.TraceflowQuota.MaxPerHour = -1
+	config.Traceflow.Hunt.MaxDuration = time.Hour
+	config.Traceflow.Hunt.MaxCaptures = 10
 	config.Auth.Basic.Enabled = true
 	config.Auth.ServiceAccountToken.Enabled = true
 	config.Auth.BearerToken.Enabled = true
@@ -163,29 +246,35 @@ func newTestServer(t *testing.T, options ...testServerOptions) *testServer {
 	require.NoError(t, err)
 
 	s := NewServer(Options{
-		Logger:                   logger,
-		Config:                   config,
-		TraceflowRequestsHandler: traceflowRequestsHandler,
-		K8sProxyHandler:          k8sProxyHandler,
-		AntreaSvcRequestsHandler: antreaSvcRequestsHandler,
-		FlowStreamSubscriber:     nil,
-		PasswordStore:            passwordStore,
-		PluginRegistry:           pluginRegistry,
-		Authenticator:            authenticator,
-		ClientFactory:            clientFactory,
+		Logger:                       logger,
+		Config:                       config,
+		TraceflowRequestsHandler:     traceflowRequestsHandler,
+		K8sProxyHandler:              k8sProxyHandler,
+		AntreaSvcRequestsHandler:     antreaSvcRequestsHandler,
+		AntreaAgentRequestsHandler:   antreaAgentRequestsHandler,
+		FlowStreamSubscriber:         nil,
+		PasswordStore:                passwordStore,
+		PluginRegistry:               pluginRegistry,
+		Authenticator:                authenticator,
+		ClientFactory:                clientFactory,
+		PacketCaptureRequestsHandler: packetCaptureRequestsHandler,
+		SupportBundleRequestsHandler: supportBundleRequestsHandler,
 	})
 	router := gin.Default()
 	s.AddRoutes(&router.RouterGroup)
 	return &testServer{
-		s:                        s,
-		router:                   router,
-		traceflowRequestsHandler: traceflowRequestsHandler,
-		k8sProxyHandler:          k8sProxyHandler,
-		antreaSvcRequestsHandler: antreaSvcRequestsHandler,
-		pluginsClientset:         pluginsClientset,
-		passwordStore:            passwordStore,
-		sessionStore:             sessionStore,
-		credentialValidator:      credentialValidator,
+		s:                            s,
+		router:                       router,
+		traceflowRequestsHandler:     traceflowRequestsHandler,
+		packetCaptureRequestsHandler: packetCaptureRequestsHandler,
+		supportBundleRequestsHandler: supportBundleRequestsHandler,
+		k8sProxyHandler:              k8sProxyHandler,
+		antreaSvcRequestsHandler:     antreaSvcRequestsHandler,
+		antreaAgentRequestsHandler:   antreaAgentRequestsHandler,
+		pluginsClientset:             pluginsClientset,
+		passwordStore:                passwordStore,
+		sessionStore:                 sessionStore,
+		credentialValidator:          credentialValidator,
 	}
 }
 
@@ -224,6 +313,7 @@ func TestAuthorization(t *testing.T) {
 		"GET /api/v1/settings":                true,
 		"GET /api/v1/plugins/index.json":      true,
 		"GET /api/v1/plugins/:name/*filepath": true,
+		"GET /metrics":                        true,
 	}
 	ts := newTestServer(t)
 	for _, routeInfo := range ts.router.Routes() {
@@ -0,0 +1,542 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	supportbundlehandler "antrea.io/antrea-ui/pkg/handlers/supportbundle"
+	"antrea.io/antrea-ui/pkg/server/errors"
+	"antrea.io/antrea-ui/pkg/server/ratelimit"
+)
+
+const (
+	supportBundleMaxNodes = 100
+
+	// The antrea-controller collects its own bundle through the system.antrea.io API, as for
+	// "antctl supportbundle". It keeps a single bundle, named "controller".
+	controllerSupportBundlesPath = "/apis/system.antrea.io/v1beta1/supportbundles"
+	controllerSupportBundlePath  = controllerSupportBundlesPath + "/controller"
+
+	controllerSupportBundleCollecting = "Collecting"
+	controllerSupportBundleCollected  = "Collected"
+	// controllerSupportBundleSuperseded is reported instead of the status of the antrea-controller
+	// bundle when it was requested again, for another request, since.
+	controllerSupportBundleSuperseded = "Superseded"
+)
+
+// supportBundleSinceRegexp matches the relative times accepted by Antrea, e.g. "2h" or "30m".
+var supportBundleSinceRegexp = regexp.MustCompile(`^[1-9][0-9]*[hm]$`)
+
+// validateSupportBundleRequest normalizes req in place and checks it against the rules documented
+// on apisv1.SupportBundleRequest. Like validateTraceflowRequest, it returns every violated rule.
+func validateSupportBundleRequest(req *apisv1.SupportBundleRequest) []string {
+	var errs []string
+	slices.Sort(req.Nodes)
+	req.Nodes = slices.Compact(req.Nodes)
+	if len(req.Nodes) == 0 || len(req.Nodes) > supportBundleMaxNodes {
+		errs = append(errs, fmt.Sprintf("Number of Nodes must be between 1 and %d", supportBundleMaxNodes))
+	}
+	for _, node := range req.Nodes {
+		if fieldErrs := validation.IsDNS1123Subdomain(node); len(fieldErrs) > 0 {
+			errs = append(errs, fmt.Sprintf("Invalid Node name %q: %s", node, strings.Join(fieldErrs, "; ")))
+		}
+	}
+	if req.Since != "" && !supportBundleSinceRegexp.MatchString(req.Since) {
+		errs = append(errs, fmt.Sprintf("Invalid since %q, must be a number of hours or minutes, e.g. 2h or 30m", req.Since))
+	}
+	if utf8.RuneCountInString(req.Title) > traceflowMaxTitleLength {
+		errs = append(errs, fmt.Sprintf("Title must be at most %d characters", traceflowMaxTitleLength))
+	}
+	return errs
+}
+
+// supportBundleSpec translates a validated request into the spec of an Antrea
+// SupportBundleCollection CR. The file server and authentication are set by the handler.
+func supportBundleSpec(req *apisv1.SupportBundleRequest) map[string]interface{} {
+	nodeNames := make([]interface{}, 0, len(req.Nodes))
+	for _, node := range req.Nodes {
+		nodeNames = append(nodeNames, node)
+	}
+	spec := map[string]interface{}{
+		"nodes": map[string]interface{}{"nodeNames": nodeNames},
+	}
+	if req.Since != "" {
+		spec["sinceTime"] = req.Since
+	}
+	return spec
+}
+
+// controllerSupportBundleRequest sends a request for the antrea-controller bundle as the caller,
+// and returns the response body if it succeeded.
+func (s *Server) controllerSupportBundleRequest(c *gin.Context, method, path string, body []byte) ([]byte, *errors.ServerError) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	// See GetFeatureGates for why c.Request.Context() is used.
+	b, statusCode, err := s.antreaSvcRequestsHandler.Request(c.Request.Context(), method, path, nil, bodyReader)
+	if err != nil {
+		return nil, &errors.ServerError{
+			Code:    http.StatusBadGateway,
+			Message: "Error when requesting the antrea-controller bundle",
+			Err:     err,
+		}
+	}
+	if statusCode >= 200 && statusCode < 300 {
+		return b, nil
+	}
+	return nil, s.controllerSupportBundleError(c, statusCode, b)
+}
+
+// controllerSupportBundleError translates an error response of the antrea-controller to a request
+// for its bundle.
+func (s *Server) controllerSupportBundleError(c *gin.Context, statusCode int, b []byte) *errors.ServerError {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return s.upstreamStatusError(c, statusCode, b)
+	case http.StatusNotFound:
+		return &errors.ServerError{
+			Code:    http.StatusNotFound,
+			Message: "The antrea-controller bundle is no longer available",
+		}
+	}
+	return &errors.ServerError{
+		Code:    http.StatusBadGateway,
+		Message: "Error when requesting the antrea-controller bundle",
+		Err:     fmt.Errorf("unexpected status %d from Antrea Service: %s", statusCode, strings.TrimSpace(string(b))),
+	}
+}
+
+// controllerSupportBundle is the part of the antrea-controller bundle used by antrea-ui.
+type controllerSupportBundle struct {
+	// Status is "Collecting", "Collected", or "None" once the bundle is deleted by the
+	// antrea-controller.
+	Status string `json:"status"`
+	// Sum is the checksum of a collected bundle.
+	Sum string `json:"sum"`
+}
+
+// getControllerSupportBundle returns the antrea-controller bundle.
+func (s *Server) getControllerSupportBundle(c *gin.Context) (*controllerSupportBundle, *errors.ServerError) {
+	b, sError := s.controllerSupportBundleRequest(c, "GET", controllerSupportBundlePath, nil)
+	if sError != nil {
+		return nil, sError
+	}
+	var bundle controllerSupportBundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, &errors.ServerError{
+			Code:    http.StatusBadGateway,
+			Message: "Invalid antrea-controller bundle status",
+			Err:     err,
+		}
+	}
+	return &bundle, nil
+}
+
+// controllerSupportBundleStatus returns the status of the antrea-controller bundle for the request
+// of sbc: the status reported by the antrea-controller while the request owns the bundle, and
+// "Superseded" once it no longer does. The antrea-controller only keeps one bundle, so the latest
+// request for it owns it. Once collected, its checksum is recorded on the SupportBundleCollection,
+// so that the request stops owning it when the antrea-controller collects another one, including
+// for "antctl supportbundle". The caller only reads: antrea-ui records the checksum itself, on a
+// SupportBundleCollection the caller got.
+func (s *Server) controllerSupportBundleStatus(c *gin.Context, client dynamic.Interface, requestID string, sbc map[string]interface{}) (string, *errors.ServerError) {
+	bundle, sError := s.getControllerSupportBundle(c)
+	if sError != nil {
+		return "", sError
+	}
+	if sum := supportbundlehandler.ControllerBundleSum(sbc); sum != "" {
+		if bundle.Status != controllerSupportBundleCollected || bundle.Sum != sum {
+			return controllerSupportBundleSuperseded, nil
+		}
+		return bundle.Status, nil
+	}
+	latest, err := s.supportBundleRequestsHandler.LatestControllerRequest(c, client)
+	if err != nil {
+		return "", s.k8sError(c, err, "error when listing SupportBundleCollection requests")
+	}
+	if latest != requestID {
+		return controllerSupportBundleSuperseded, nil
+	}
+	if bundle.Status == controllerSupportBundleCollected {
+		if err := s.supportBundleRequestsHandler.SetControllerBundleSum(c, requestID, bundle.Sum); err != nil {
+			return "", s.k8sError(c, err, "error when updating SupportBundleCollection request")
+		}
+	}
+	return bundle.Status, nil
+}
+
+// CreateSupportBundleRequest handles POST /api/v1/supportbundles. The body is an
+// apisv1.SupportBundleRequest. The SupportBundleCollection is created as the caller, and so is the
+// antrea-controller bundle.
+func (s *Server) CreateSupportBundleRequest(c *gin.Context) {
+	var requestID string
+	if sError := func() *errors.ServerError {
+		var sbRequest apisv1.SupportBundleRequest
+		if err := c.BindJSON(&sbRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := validateSupportBundleRequest(&sbRequest); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var username string
+		if ra, ok := session.RequestAuthFrom(c.Request.Context()); ok {
+			username = ra.Username
+		}
+		var err error
+		requestID, err = s.supportBundleRequestsHandler.CreateRequest(c, client, &supportbundlehandler.Request{
+			Object: map[string]interface{}{
+				"spec": supportBundleSpec(&sbRequest),
+			},
+			Controller: sbRequest.Controller,
+			Username:   username,
+			Title:      sbRequest.Title,
+		})
+		if err != nil {
+			return s.k8sError(c, err, "error when creating SupportBundleCollection request")
+		}
+		// The antrea-controller bundle is only requested once the SupportBundleCollection
+		// exists, as the latest SupportBundleCollection requesting it owns it.
+		if sbRequest.Controller {
+			if sError := s.createControllerSupportBundle(c, sbRequest.Since); sError != nil {
+				// Do not leave behind a request which would never get its bundle.
+				if _, err := s.supportBundleRequestsHandler.DeleteRequest(c, client, requestID); err != nil {
+					s.logger.Error(err, "Failed to delete SupportBundleCollection request", "requestId", requestID)
+				}
+				return sError
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to create SupportBundleCollection request")
+		return
+	}
+	c.Writer.Header().Add("Access-Control-Expose-Headers", "Location, Retry-After")
+	c.Header("Location", fmt.Sprintf("/api/v1/supportbundles/%s", requestID))
+	c.Header("Retry-After", "5") // 5 seconds
+	c.Status(http.StatusAccepted)
+}
+
+// createControllerSupportBundle requests a new antrea-controller bundle as the caller.
+func (s *Server) createControllerSupportBundle(c *gin.Context, since string) *errors.ServerError {
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "system.antrea.io/v1beta1",
+		"kind":       "SupportBundle",
+		"metadata":   map[string]interface{}{"name": "controller"},
+		"since":      since,
+	})
+	if err != nil {
+		return &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("error when building antrea-controller bundle request: %w", err),
+		}
+	}
+	_, sError := s.controllerSupportBundleRequest(c, "POST", controllerSupportBundlesPath, body)
+	return sError
+}
+
+// getSupportBundleRequest returns the summary of a request, as the caller can get its
+// SupportBundleCollection, with the status of the antrea-controller bundle if it was collected,
+// and whether the collection is completed.
+func (s *Server) getSupportBundleRequest(c *gin.Context, requestID string) (*apisv1.SupportBundleSummary, map[string]interface{}, bool, *errors.ServerError) {
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return nil, nil, false, sError
+	}
+	sbc, done, err := s.supportBundleRequestsHandler.GetRequestResult(c, client, requestID)
+	if err != nil {
+		return nil, nil, false, s.k8sError(c, err, "error when getting SupportBundleCollection request")
+	}
+	summary := supportbundlehandler.Summary(sbc)
+	if summary.Controller {
+		summary.ControllerStatus, sError = s.controllerSupportBundleStatus(c, client, requestID, sbc)
+		if sError != nil {
+			return nil, nil, false, sError
+		}
+		// The antrea-controller resets the status of a bundle which it failed to collect.
+		done = done && summary.ControllerStatus != controllerSupportBundleCollecting
+	}
+	return &summary, sbc, done, nil
+}
+
+// GetSupportBundleRequestStatus handles GET /api/v1/supportbundles/:requestId/status, which
+// redirects to /result once the collection is completed.
+func (s *Server) GetSupportBundleRequestStatus(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var done bool
+	if sError := func() *errors.ServerError {
+		var sError *errors.ServerError
+		_, _, done, sError = s.getSupportBundleRequest(c, requestID)
+		return sError
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get SupportBundleCollection request status", "requestId", requestID)
+		return
+	}
+	if !done {
+		c.Header("Access-Control-Expose-Headers", "Location, Retry-After")
+		c.Header("Location", fmt.Sprintf("/api/v1/supportbundles/%s/status", requestID))
+		// Collecting a bundle takes a while.
+		c.Header("Retry-After", "5") // 5 seconds
+		c.Status(http.StatusOK)
+		return
+	}
+	c.Header("Access-Control-Expose-Headers", "Location")
+	c.Header("Location", fmt.Sprintf("/api/v1/supportbundles/%s/result", requestID))
+	c.Status(http.StatusFound)
+}
+
+// GetSupportBundleRequestResult handles GET /api/v1/supportbundles/:requestId/result, which
+// returns the apisv1.SupportBundleSummary of a completed collection.
+func (s *Server) GetSupportBundleRequestResult(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var summary *apisv1.SupportBundleSummary
+	if sError := func() *errors.ServerError {
+		var done bool
+		var sError *errors.ServerError
+		summary, _, done, sError = s.getSupportBundleRequest(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		if !done {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: "SupportBundleCollection result not available, call the /status endpoint to check progress",
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get result for SupportBundleCollection request", "requestId", requestID)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetSupportBundleRequestNodeFile handles GET /api/v1/supportbundles/:requestId/nodes/:node/bundle.
+// It streams the bundle of the Node that Antrea uploaded to the file server. The
+// SupportBundleCollection is read as the caller first, so only a caller allowed to get it can
+// download its bundles.
+func (s *Server) GetSupportBundleRequestNodeFile(c *gin.Context) {
+	requestID := c.Param("requestId")
+	node := c.Param("node")
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		sbc, _, err := s.supportBundleRequestsHandler.GetRequestResult(c, client, requestID)
+		if err != nil {
+			return s.k8sError(c, err, "error when getting SupportBundleCollection request")
+		}
+		f, name, err := s.supportBundleRequestsHandler.OpenFile(c.Request.Context(), sbc, node)
+		switch {
+		case stderrors.Is(err, supportbundlehandler.ErrNoFile):
+			phase, message := supportbundlehandler.Phase(sbc)
+			if message != "" {
+				phase = fmt.Sprintf("%s: %s", phase, message)
+			}
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("No bundle is available for Node %s (%s)", node, phase),
+			}
+		case stderrors.Is(err, fs.ErrNotExist):
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("The bundle of Node %s is not on the file server", node),
+			}
+		case err != nil:
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when downloading the bundle from the file server",
+				Err:     err,
+			}
+		}
+		defer f.Close()
+		c.Header("Access-Control-Expose-Headers", "Content-Disposition")
+		// The length is not known without a stat request, so the file is sent chunked.
+		c.DataFromReader(http.StatusOK, -1, "application/gzip", f, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": name}),
+		})
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get Node bundle for SupportBundleCollection request", "requestId", requestID, "node", node)
+		return
+	}
+}
+
+// GetSupportBundleRequestControllerFile handles
+// GET /api/v1/supportbundles/:requestId/controller/bundle. It streams the antrea-controller bundle
+// as the caller. The antrea-controller only keeps its latest bundle: once it has been requested
+// again for another request, the response is a 410 Gone.
+func (s *Server) GetSupportBundleRequestControllerFile(c *gin.Context) {
+	requestID := c.Param("requestId")
+	if sError := func() *errors.ServerError {
+		summary, _, _, sError := s.getSupportBundleRequest(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		if summary.ControllerStatus == controllerSupportBundleSuperseded {
+			return &errors.ServerError{
+				Code:    http.StatusGone,
+				Message: "The antrea-controller bundle of this request was replaced by a more recent one",
+			}
+		}
+		if !summary.Controller || summary.ControllerStatus != controllerSupportBundleCollected {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: "No antrea-controller bundle is available for this request",
+			}
+		}
+		// See GetFeatureGates for why c.Request.Context() is used.
+		body, statusCode, err := s.antreaSvcRequestsHandler.Stream(c.Request.Context(), "GET", controllerSupportBundlePath+"/download", nil, nil)
+		if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadGateway,
+				Message: "Error when requesting the antrea-controller bundle",
+				Err:     err,
+			}
+		}
+		defer body.Close()
+		if statusCode != http.StatusOK {
+			// Error responses are small.
+			b, _ := io.ReadAll(io.LimitReader(body, 64*1024))
+			return s.controllerSupportBundleError(c, statusCode, b)
+		}
+		c.Header("Access-Control-Expose-Headers", "Content-Disposition")
+		c.DataFromReader(http.StatusOK, -1, "application/gzip", body, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": "controller_" + requestID + ".tar.gz"}),
+		})
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get antrea-controller bundle for SupportBundleCollection request", "requestId", requestID)
+		return
+	}
+}
+
+func (s *Server) DeleteSupportBundleRequest(c *gin.Context) {
+	requestID := c.Param("requestId")
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		ok, err := s.supportBundleRequestsHandler.DeleteRequest(c, client, requestID)
+		if err != nil {
+			return s.k8sError(c, err, "error when deleting SupportBundleCollection request")
+		}
+		if !ok {
+			return &errors.ServerError{
+				Code:    http.StatusNotFound,
+				Message: "SupportBundleCollection request not found",
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to delete SupportBundleCollection request", "requestId", requestID)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// ListSupportBundleRequests handles GET /api/v1/supportbundles, which lists the
+// SupportBundleCollections created by antrea-ui that the caller can list, most recent first.
+func (s *Server) ListSupportBundleRequests(c *gin.Context) {
+	var summaries []apisv1.SupportBundleSummary
+	if sError := func() *errors.ServerError {
+		client, sError := s.dynamicClientFor(c)
+		if sError != nil {
+			return sError
+		}
+		var err error
+		summaries, err = s.supportBundleRequestsHandler.ListRequests(c, client)
+		if err != nil {
+			return s.k8sError(c, err, "error when listing SupportBundleCollection requests")
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to list SupportBundleCollection requests")
+		return
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+// supportBundleDisabled answers every /api/v1/supportbundles route when no file server is
+// configured, like packetCaptureDisabled.
+func (s *Server) supportBundleDisabled(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{
+		"error": "Support bundles are not enabled for this Antrea UI instance (set supportBundle.fileServer.url in the Helm chart).",
+	})
+}
+
+func (s *Server) AddSupportBundleRoutes(r *gin.RouterGroup) {
+	r = r.Group("/supportbundles")
+	r.Use(s.authenticate())
+	if s.supportBundleRequestsHandler == nil {
+		r.Use(s.supportBundleDisabled)
+	}
+	create := []gin.HandlerFunc{s.CreateSupportBundleRequest}
+	if s.config.MaxSupportBundlesPerHour >= 0 {
+		burstSize := 0
+		if s.config.MaxSupportBundlesPerHour > 0 {
+			burstSize = 2
+		}
+		rateLimiter := ratelimit.NewGlobalRateLimiterOrDie(fmt.Sprintf("%d/h", s.config.MaxSupportBundlesPerHour), burstSize)
+		create = append([]gin.HandlerFunc{ratelimit.Middleware(rateLimiter)}, create...)
+	}
+	r.POST("", create...)
+	r.GET("", s.ListSupportBundleRequests)
+	r.GET("/:requestId/status", s.GetSupportBundleRequestStatus)
+	r.GET("/:requestId", func(c *gin.Context) {
+		c.Redirect(http.StatusSeeOther, c.Request.URL.Path+"/status")
+	})
+	r.GET("/:requestId/result", s.GetSupportBundleRequestResult)
+	r.GET("/:requestId/nodes/:node/bundle", s.GetSupportBundleRequestNodeFile)
+	r.GET("/:requestId/controller/bundle", s.GetSupportBundleRequestControllerFile)
+	r.DELETE("/:requestId", s.DeleteSupportBundleRequest)
+}
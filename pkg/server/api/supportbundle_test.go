@@ -0,0 +1,385 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	supportbundlehandler "antrea.io/antrea-ui/pkg/handlers/supportbundle"
+)
+
+var (
+	sbRequest = apisv1.SupportBundleRequest{
+		Nodes: []string{"node-2", "node-1", "node-2"},
+		Since: "2h",
+		Title: "upgrade issue",
+	}
+	sbSpec = map[string]interface{}{
+		"nodes": map[string]interface{}{
+			"nodeNames": []interface{}{"node-1", "node-2"},
+		},
+		"sinceTime": "2h",
+	}
+)
+
+func sbObject(requestID string, controller bool, conditions ...interface{}) map[string]interface{} {
+	annotations := map[string]interface{}{
+		"ui.antrea.io/created-by": "tester",
+		"ui.antrea.io/title":      "upgrade issue",
+	}
+	if controller {
+		annotations["ui.antrea.io/controller-bundle"] = "true"
+	}
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              requestID,
+			"creationTimestamp": "2026-10-01T10:00:00Z",
+			"annotations":       annotations,
+		},
+		"spec": sbSpec,
+		"status": map[string]interface{}{
+			"collectedNodes": int64(2),
+			"desiredNodes":   int64(2),
+			"conditions":     conditions,
+		},
+	}
+}
+
+var sbCompleted = map[string]interface{}{"type": "Completed", "status": "True"}
+
+func TestSupportBundleRequest(t *testing.T) {
+	ts := newTestServer(t)
+
+	// create request
+	req := httptest.NewRequest("POST", "/api/v1/supportbundles", bytes.NewReader(mustMarshal(&sbRequest)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	requestID := uuid.NewString()
+	ts.supportBundleRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &supportbundlehandler.Request{
+		Object:   map[string]interface{}{"spec": sbSpec},
+		Username: "tester",
+		Title:    "upgrade issue",
+	}).Return(requestID, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+	url, err := rr.Result().Location()
+	require.NoError(t, err)
+	reqURI := url.RequestURI()
+	statusURI := reqURI + "/status"
+
+	// get status: not ready yet
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbObject(requestID, false), false, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("Retry-After"))
+
+	sbResult := sbObject(requestID, false, sbCompleted)
+
+	// get status: ready
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusFound, rr.Code)
+	url, err = rr.Result().Location()
+	require.NoError(t, err)
+	assert.Equal(t, reqURI+"/result", url.RequestURI())
+
+	// get result
+	req = httptest.NewRequest("GET", reqURI+"/result", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, string(mustMarshal(&apisv1.SupportBundleSummary{
+		ID:                requestID,
+		Title:             "upgrade issue",
+		CreatedBy:         "tester",
+		CreationTimestamp: "2026-10-01T10:00:00Z",
+		Phase:             "Succeeded",
+		Nodes:             []string{"node-1", "node-2"},
+		CollectedNodes:    2,
+		DesiredNodes:      2,
+	})), rr.Body.String())
+
+	// get Node bundle
+	req = httptest.NewRequest("GET", reqURI+"/nodes/node-1/bundle", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.supportBundleRequestsHandler.EXPECT().OpenFile(gomock.Any(), sbResult, "node-1").Return(io.NopCloser(bytes.NewReader([]byte("bundle data"))), "node-1_"+requestID+".tar.gz", nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "bundle data", rr.Body.String())
+	assert.Equal(t, "application/gzip", rr.Header().Get("Content-Type"))
+	assert.Equal(t, fmt.Sprintf("attachment; filename=node-1_%s.tar.gz", requestID), rr.Header().Get("Content-Disposition"))
+
+	// no antrea-controller bundle was requested
+	req = httptest.NewRequest("GET", reqURI+"/controller/bundle", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// list requests
+	req = httptest.NewRequest("GET", "/api/v1/supportbundles", nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().ListRequests(gomock.Any(), gomock.Any()).Return([]apisv1.SupportBundleSummary{supportbundlehandler.Summary(sbResult)}, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var summaries []apisv1.SupportBundleSummary
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 1)
+	assert.Equal(t, requestID, summaries[0].ID)
+
+	// delete request
+	req = httptest.NewRequest("DELETE", reqURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), requestID).Return(true, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestSupportBundleRequestController(t *testing.T) {
+	ts := newTestServer(t)
+	request := sbRequest
+	request.Controller = true
+	requestID := uuid.NewString()
+
+	// create request: the antrea-controller bundle is requested once the
+	// SupportBundleCollection exists
+	req := httptest.NewRequest("POST", "/api/v1/supportbundles", bytes.NewReader(mustMarshal(&request)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	gomock.InOrder(
+		ts.supportBundleRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &supportbundlehandler.Request{
+			Object:     map[string]interface{}{"spec": sbSpec},
+			Controller: true,
+			Username:   "tester",
+			Title:      "upgrade issue",
+		}).Return(requestID, nil),
+		ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "POST", "/apis/system.antrea.io/v1beta1/supportbundles", nil, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ string, _ url.Values, body io.Reader) ([]byte, int, error) {
+				b, err := io.ReadAll(body)
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"apiVersion":"system.antrea.io/v1beta1","kind":"SupportBundle","metadata":{"name":"controller"},"since":"2h"}`, string(b))
+				return []byte(`{}`), http.StatusCreated, nil
+			}),
+	)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+
+	sbResult := sbObject(requestID, true, sbCompleted)
+	statusURI := fmt.Sprintf("/api/v1/supportbundles/%s/status", requestID)
+
+	// get status: the Node bundles are collected, but not the antrea-controller bundle
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.supportBundleRequestsHandler.EXPECT().LatestControllerRequest(gomock.Any(), gomock.Any()).Return(requestID, nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller", nil, nil).Return([]byte(`{"status":"Collecting"}`), http.StatusOK, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// get status: ready, the bundle is recorded as the one of the request
+	req = httptest.NewRequest("GET", statusURI, nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.supportBundleRequestsHandler.EXPECT().LatestControllerRequest(gomock.Any(), gomock.Any()).Return(requestID, nil)
+	ts.supportBundleRequestsHandler.EXPECT().SetControllerBundleSum(gomock.Any(), requestID, "abc")
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller", nil, nil).Return([]byte(`{"status":"Collected","sum":"abc"}`), http.StatusOK, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusFound, rr.Code)
+
+	sbResult = sbObject(requestID, true, sbCompleted)
+	sbResult["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})["ui.antrea.io/controller-bundle-sum"] = "abc"
+
+	// get antrea-controller bundle
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/supportbundles/%s/controller/bundle", requestID), nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller", nil, nil).Return([]byte(`{"status":"Collected","sum":"abc"}`), http.StatusOK, nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Stream(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller/download", nil, nil).Return(io.NopCloser(bytes.NewReader([]byte("controller data"))), http.StatusOK, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "controller data", rr.Body.String())
+	assert.Equal(t, fmt.Sprintf("attachment; filename=controller_%s.tar.gz", requestID), rr.Header().Get("Content-Disposition"))
+
+	// get antrea-controller bundle: it was collected again since
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/supportbundles/%s/controller/bundle", requestID), nil)
+	ts.authorizeRequest(req)
+	rr = httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller", nil, nil).Return([]byte(`{"status":"Collected","sum":"def"}`), http.StatusOK, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusGone, rr.Code)
+}
+
+func TestSupportBundleRequestControllerSuperseded(t *testing.T) {
+	ts := newTestServer(t)
+	requestID := uuid.NewString()
+	sbResult := sbObject(requestID, true, sbCompleted)
+
+	// Another request asked for the antrea-controller bundle before this one got it: the
+	// collection is done, but without the antrea-controller bundle.
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/supportbundles/%s/result", requestID), nil)
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+	ts.supportBundleRequestsHandler.EXPECT().LatestControllerRequest(gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "GET", "/apis/system.antrea.io/v1beta1/supportbundles/controller", nil, nil).Return([]byte(`{"status":"Collecting"}`), http.StatusOK, nil)
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var summary apisv1.SupportBundleSummary
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+	assert.Equal(t, "Superseded", summary.ControllerStatus)
+}
+
+func TestSupportBundleRequestControllerForbidden(t *testing.T) {
+	ts := newTestServer(t)
+	request := sbRequest
+	request.Controller = true
+	requestID := uuid.NewString()
+	req := httptest.NewRequest("POST", "/api/v1/supportbundles", bytes.NewReader(mustMarshal(&request)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.supportBundleRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(requestID, nil)
+	ts.antreaSvcRequestsHandler.EXPECT().Request(gomock.Any(), "POST", "/apis/system.antrea.io/v1beta1/supportbundles", nil, gomock.Any()).Return([]byte("forbidden"), http.StatusForbidden, nil)
+	// the SupportBundleCollection is deleted
+	ts.supportBundleRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), requestID).Return(true, nil)
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestSupportBundleRequestFileErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{
+			name:         "no file",
+			err:          supportbundlehandler.ErrNoFile,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "file removed",
+			err:          fmt.Errorf("error when opening file: %w", fs.ErrNotExist),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "file server error",
+			err:          fmt.Errorf("connection refused"),
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			requestID := uuid.NewString()
+			sbResult := sbObject(requestID, false, sbCompleted)
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/supportbundles/%s/nodes/node-1/bundle", requestID), nil)
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			ts.supportBundleRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(sbResult, true, nil)
+			ts.supportBundleRequestsHandler.EXPECT().OpenFile(gomock.Any(), sbResult, "node-1").Return(nil, "", tc.err)
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestSupportBundleRequestValidation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       apisv1.SupportBundleRequest
+		expectedError string
+	}{
+		{
+			name:          "no Node",
+			request:       apisv1.SupportBundleRequest{},
+			expectedError: "Number of Nodes must be between 1 and 100",
+		},
+		{
+			name: "invalid Node",
+			request: apisv1.SupportBundleRequest{
+				Nodes: []string{"Node_1"},
+			},
+			expectedError: `Invalid Node name "Node_1": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name: "invalid since",
+			request: apisv1.SupportBundleRequest{
+				Nodes: []string{"node-1"},
+				Since: "1d",
+			},
+			expectedError: `Invalid since "1d", must be a number of hours or minutes, e.g. 2h or 30m`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateSupportBundleRequest(&tc.request)
+			assert.Contains(t, errs, tc.expectedError)
+		})
+	}
+}
+
+func TestSupportBundleRequestRateLimiting(t *testing.T) {
+	ts := newTestServer(t, setMaxSupportBundlesPerHour(0))
+	req := httptest.NewRequest("POST", "/api/v1/supportbundles", bytes.NewReader(mustMarshal(&sbRequest)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestSupportBundleDisabled(t *testing.T) {
+	ts := newTestServer(t)
+	ts.s.supportBundleRequestsHandler = nil
+	router := gin.New()
+	ts.s.AddSupportBundleRoutes(router.Group("/api/v1"))
+	req := httptest.NewRequest("GET", "/api/v1/supportbundles", nil)
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotImplemented, rr.Code)
+}
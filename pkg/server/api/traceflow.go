@@ -15,55 +15,160 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
 	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
 	"antrea.io/antrea-ui/pkg/server/errors"
 	"antrea.io/antrea-ui/pkg/server/ratelimit"
 )
 
+// CreateTraceflowRequest handles POST /api/v1/traceflow. The body is an apisv1.TraceflowRequest,
+// which is validated before any Traceflow CR is created: a malformed request gets a 400 listing
+// every problem, instead of a Traceflow that fails (or traces the wrong thing) in Antrea. The body
+// used to be a Traceflow CR with a "spec": such a body is rejected explicitly, rather than decoded
+// as an empty request.
 func (s *Server) CreateTraceflowRequest(c *gin.Context) {
 	var requestID string
 	if sError := func() *errors.ServerError {
-		var tfRequest map[string]interface{}
-		if err := c.BindJSON(&tfRequest); err != nil {
+		var fields map[string]json.RawMessage
+		if err := c.ShouldBindBodyWith(&fields, binding.JSON); err != nil {
 			return &errors.ServerError{
 				Code:    http.StatusBadRequest,
 				Message: err.Error(),
 			}
 		}
-		client, sError := s.dynamicClientFor(c)
-		if sError != nil {
-			return sError
+		if _, ok := fields["spec"]; ok {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: "The body must be a TraceflowRequest, not a Traceflow CR with a \"spec\": see the Traceflow API documentation for its fields",
+			}
 		}
-		var err error
-		requestID, err = s.traceflowRequestsHandler.CreateRequest(c, client, &traceflowhandler.Request{
-			Object: tfRequest,
-		})
-		if err != nil {
-			return s.k8sError(c, err, "error when creating Traceflow request")
+		var tfRequest apisv1.TraceflowRequest
+		if err := c.ShouldBindBodyWith(&tfRequest, binding.JSON); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
 		}
-		return nil
+		quota := s.traceflowQuotaFor(c.Request.Context())
+		var sError *errors.ServerError
+		requestID, sError = s.submitTraceflowRequest(c, &quota, &tfRequest)
+		return sError
 	}(); sError != nil {
 		errors.HandleError(c, sError)
 		s.LogError(sError, "Failed to create Traceflow request")
 		return
 	}
-	c.Header("Access-Control-Expose-Headers", "Location, Retry-After")
+	setTraceflowAcceptedHeaders(c, requestID)
+	c.Status(http.StatusAccepted)
+}
+
+// submitTraceflowRequest checks tfRequest and creates its Traceflow, within the caller's quota.
+func (s *Server) submitTraceflowRequest(c *gin.Context, quota *traceflowQuota, tfRequest *apisv1.TraceflowRequest) (string, *errors.ServerError) {
+	dstIP, sError := s.checkTraceflowRequest(c, tfRequest)
+	if sError != nil {
+		return "", sError
+	}
+	client, request, sError := s.newTraceflowRequest(c, tfRequest, dstIP)
+	if sError != nil {
+		return "", sError
+	}
+	return s.createTraceflowRequest(c, client, quota, request)
+}
+
+// setTraceflowAcceptedHeaders tells the client where to poll for the status of requestID.
+func setTraceflowAcceptedHeaders(c *gin.Context, requestID string) {
+	c.Writer.Header().Add("Access-Control-Expose-Headers", "Location, Retry-After")
 	c.Header("Location", fmt.Sprintf("/api/v1/traceflow/%s", requestID))
 	c.Header("Retry-After", "2") // 2 seconds
-	c.Status(http.StatusAccepted)
+}
+
+// checkTraceflowRequest validates tfRequest and checks its endpoints. It returns the destination
+// IP, which is resolved if the destination is an FQDN.
+func (s *Server) checkTraceflowRequest(c *gin.Context, tfRequest *apisv1.TraceflowRequest) (string, *errors.ServerError) {
+	if errs := validateTraceflowRequest(tfRequest); len(errs) > 0 {
+		return "", &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: strings.Join(errs, "; "),
+		}
+	}
+	if sError := s.checkTraceflowEndpoints(c, tfRequest); sError != nil {
+		return "", sError
+	}
+	if tfRequest.Destination.FQDN != "" {
+		return s.resolveTraceflowFQDN(c.Request.Context(), tfRequest)
+	}
+	return tfRequest.Destination.IP, nil
+}
+
+// newTraceflowRequest translates a checked tfRequest for the Traceflow handler. It also returns
+// the client to create the Traceflow with, which is antrea-ui-admin's for a delegated Traceflow.
+func (s *Server) newTraceflowRequest(c *gin.Context, tfRequest *apisv1.TraceflowRequest, dstIP string) (dynamic.Interface, *traceflowhandler.Request, *errors.ServerError) {
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return nil, nil, sError
+	}
+	delegated, sError := s.delegateTraceflow(c, tfRequest)
+	if sError != nil {
+		return nil, nil, sError
+	}
+	if delegated {
+		client = s.traceflowAdminClient
+	}
+	var username string
+	if ra, ok := session.RequestAuthFrom(c.Request.Context()); ok {
+		username = ra.Username
+	}
+	return client, &traceflowhandler.Request{
+		Object: map[string]interface{}{
+			"spec": traceflowSpec(tfRequest, dstIP),
+		},
+		Username:  username,
+		Title:     tfRequest.Title,
+		Delegated: delegated,
+	}, nil
+}
+
+// createTraceflowRequest creates the Traceflow for a checked request, if the caller has fewer
+// running Traceflows than their quota allows and some of their hourly budget left. The Traceflow
+// is only taken from the budget once it is created.
+func (s *Server) createTraceflowRequest(c *gin.Context, client dynamic.Interface, quota *traceflowQuota, request *traceflowhandler.Request) (string, *errors.ServerError) {
+	unlock := s.lockTraceflowQuota(quota)
+	defer unlock()
+	if sError := s.checkRunningTraceflows(c, quota); sError != nil {
+		return "", sError
+	}
+	reservation, sError := s.reserveTraceflow(c, quota)
+	if sError != nil {
+		return "", sError
+	}
+	requestID, err := s.traceflowRequestsHandler.CreateRequest(c, client, request)
+	if err != nil {
+		s.cancelTraceflowReservation(c, reservation)
+		return "", s.k8sError(c, err, "error when creating Traceflow request")
+	}
+	return requestID, nil
 }
 
 func (s *Server) GetTraceflowRequestStatus(c *gin.Context) {
 	requestID := c.Param("requestId")
 	var done bool
 	if sError := func() *errors.ServerError {
-		client, sError := s.dynamicClientFor(c)
+		client, sError := s.traceflowClientFor(c, requestID)
 		if sError != nil {
 			return sError
 		}
@@ -90,24 +195,152 @@ func (s *Server) GetTraceflowRequestStatus(c *gin.Context) {
 	c.Status(http.StatusFound)
 }
 
-func (s *Server) GetTraceflowRequestResult(c *gin.Context) {
+const (
+	// traceflowStreamTimeout bounds GET /api/v1/traceflow/:requestId/stream. A Traceflow times
+	// out after at most 120s, so this is only reached if Antrea never updates it.
+	traceflowStreamTimeout = 3 * time.Minute
+	// traceflowStreamKeepAlive is how often an SSE comment is sent while the Traceflow is
+	// running, so that proxies do not close an idle stream.
+	traceflowStreamKeepAlive = 15 * time.Second
+)
+
+// StreamTraceflowRequest handles GET /api/v1/traceflow/:requestId/stream, the push-based
+// alternative to polling /status. It watches the Traceflow as the caller and sends Server-Sent
+// Events:
+//   - "status" (apisv1.TraceflowStatusEvent) right away and then on every phase change;
+//   - "result" (the Traceflow object, as returned by /result) once it is completed;
+//   - "error" (apisv1.TraceflowStreamErrorEvent) if the watch fails or times out.
+//
+// The stream ends after "result" or "error".
+func (s *Server) StreamTraceflowRequest(c *gin.Context) {
 	requestID := c.Param("requestId")
-	var data []byte
+	ctx, cancel := context.WithTimeout(c.Request.Context(), traceflowStreamTimeout)
+	defer cancel()
+	var updates <-chan traceflowhandler.RequestUpdate
 	if sError := func() *errors.ServerError {
-		client, sError := s.dynamicClientFor(c)
+		client, sError := s.traceflowClientFor(c, requestID)
 		if sError != nil {
 			return sError
 		}
-		tfResult, done, err := s.traceflowRequestsHandler.GetRequestResult(c, client, requestID)
+		var err error
+		updates, err = s.traceflowRequestsHandler.WatchRequest(ctx, client, requestID)
 		if err != nil {
-			return s.k8sError(c, err, "error when getting Traceflow request result")
+			return s.k8sError(c, err, "error when watching Traceflow request")
 		}
-		if !done {
-			return &errors.ServerError{
-				Code:    http.StatusNotFound,
-				Message: "Traceflow result not available, call the /status endpoint to check progress",
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to watch Traceflow request", "requestId", requestID)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	sendError := func(message string) {
+		data, err := json.Marshal(apisv1.TraceflowStreamErrorEvent{Message: message})
+		if err == nil {
+			c.SSEvent("error", string(data))
+		}
+	}
+	keepAlive := time.NewTicker(traceflowStreamKeepAlive)
+	defer keepAlive.Stop()
+	var lastStatus *apisv1.TraceflowStatusEvent
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-keepAlive.C:
+			_, err := w.Write([]byte(": keepalive\n\n"))
+			return err == nil
+		case update, ok := <-updates:
+			if !ok {
+				if ctx.Err() == context.DeadlineExceeded {
+					sendError("timed out waiting for the Traceflow to complete")
+				}
+				return false
 			}
+			if update.Err != nil {
+				s.logger.Error(update.Err, "Error when watching Traceflow request", "requestId", requestID)
+				sendError(update.Err.Error())
+				return false
+			}
+			status := apisv1.TraceflowStatusEvent{}
+			status.Phase, _, _ = unstructured.NestedString(update.Object, "status", "phase")
+			status.Reason, _, _ = unstructured.NestedString(update.Object, "status", "reason")
+			if lastStatus == nil || *lastStatus != status {
+				lastStatus = &status
+				data, err := json.Marshal(status)
+				if err != nil {
+					return false
+				}
+				c.SSEvent("status", string(data))
+			}
+			if !update.Done {
+				return true
+			}
+			data, err := json.Marshal(update.Object)
+			if err != nil {
+				sendError("error when converting Traceflow request result to JSON")
+				return false
+			}
+			c.SSEvent("result", string(data))
+			return false
+		}
+	})
+}
+
+// getTraceflowRequestResult returns the Traceflow object of a completed request.
+func (s *Server) getTraceflowRequestResult(c *gin.Context, requestID string) (map[string]interface{}, *errors.ServerError) {
+	client, sError := s.traceflowClientFor(c, requestID)
+	if sError != nil {
+		return nil, sError
+	}
+	tfResult, done, err := s.traceflowRequestsHandler.GetRequestResult(c, client, requestID)
+	if err != nil {
+		return nil, s.k8sError(c, err, "error when getting Traceflow request result")
+	}
+	if !done {
+		return nil, &errors.ServerError{
+			Code:    http.StatusNotFound,
+			Message: "Traceflow result not available, call the /status endpoint to check progress",
 		}
+	}
+	return tfResult, nil
+}
+
+// GetTraceflowRequestResult handles GET /api/v1/traceflow/:requestId/result. With
+// ?resolvePolicies=true, every observation naming a NetworkPolicy also gets the details of that
+// policy, as the caller can see it now.
+func (s *Server) GetTraceflowRequestResult(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var data []byte
+	if sError := func() *errors.ServerError {
+		var resolvePolicies bool
+		if v := c.Query("resolvePolicies"); v != "" {
+			var err error
+			if resolvePolicies, err = strconv.ParseBool(v); err != nil {
+				return &errors.ServerError{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("invalid value for resolvePolicies: %q", v),
+				}
+			}
+		}
+		tfResult, sError := s.getTraceflowRequestResult(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		if resolvePolicies {
+			// Policies are fetched as the caller, even for a delegated Traceflow.
+			client, sError := s.dynamicClientFor(c)
+			if sError != nil {
+				return sError
+			}
+			if err := s.traceflowRequestsHandler.ResolvePolicies(c, client, tfResult); err != nil {
+				return s.k8sError(c, err, "error when resolving NetworkPolicies of Traceflow request")
+			}
+		}
+		var err error
 		data, err = json.Marshal(tfResult)
 		if err != nil {
 			return &errors.ServerError{
@@ -128,7 +361,7 @@ func (s *Server) GetTraceflowRequestResult(c *gin.Context) {
 func (s *Server) DeleteTraceflowRequest(c *gin.Context) {
 	requestID := c.Param("requestId")
 	if sError := func() *errors.ServerError {
-		client, sError := s.dynamicClientFor(c)
+		client, sError := s.traceflowClientFor(c, requestID)
 		if sError != nil {
 			return sError
 		}
@@ -151,24 +384,114 @@ func (s *Server) DeleteTraceflowRequest(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// ListTraceflowRequests handles GET /api/v1/traceflow, which lists the Traceflows created by
+// antrea-ui that the caller can list, and the ones delegated to them, most recent first. With
+// ?mine=true, only the ones the caller created are returned.
+func (s *Server) ListTraceflowRequests(c *gin.Context) {
+	var summaries []apisv1.TraceflowSummary
+	if sError := func() *errors.ServerError {
+		var mine bool
+		if v := c.Query("mine"); v != "" {
+			var err error
+			if mine, err = strconv.ParseBool(v); err != nil {
+				return &errors.ServerError{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("invalid value for mine: %q", v),
+				}
+			}
+		}
+		all, sError := s.listTraceflowRequests(c)
+		if sError != nil {
+			return sError
+		}
+		if !mine {
+			summaries = all
+			return nil
+		}
+		var username string
+		if ra, ok := session.RequestAuthFrom(c.Request.Context()); ok {
+			username = ra.Username
+		}
+		summaries = []apisv1.TraceflowSummary{}
+		for _, summary := range all {
+			if summary.CreatedBy == username {
+				summaries = append(summaries, summary)
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to list Traceflow requests")
+		return
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+// setTraceflowRequestPinned returns the handler for PUT (pinned=true) and DELETE (pinned=false)
+// /api/v1/traceflow/:requestId/pin.
+func (s *Server) setTraceflowRequestPinned(pinned bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.Param("requestId")
+		if sError := func() *errors.ServerError {
+			client, sError := s.traceflowClientFor(c, requestID)
+			if sError != nil {
+				return sError
+			}
+			ok, err := s.traceflowRequestsHandler.SetRequestPinned(c, client, requestID, pinned)
+			if err != nil {
+				return s.k8sError(c, err, "error when updating Traceflow request")
+			}
+			if !ok {
+				return &errors.ServerError{
+					Code:    http.StatusNotFound,
+					Message: "Traceflow request not found",
+				}
+			}
+			return nil
+		}(); sError != nil {
+			errors.HandleError(c, sError)
+			s.LogError(sError, "Failed to pin or unpin Traceflow request", "requestId", requestID, "pinned", pinned)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+}
+
+// GetTraceflowGCStats handles GET /api/v1/traceflow/gc. It describes the garbage collection of
+// the Traceflows created by this antrea-ui install.
+func (s *Server) GetTraceflowGCStats(c *gin.Context) {
+	c.JSON(http.StatusOK, s.traceflowRequestsHandler.GCStats())
+}
+
 func (s *Server) AddTraceflowRoutes(r *gin.RouterGroup) {
 	r = r.Group("/traceflow")
 	r.Use(s.authenticate())
-	createTfHandlers := []gin.HandlerFunc{}
 	if s.config.MaxTraceflowsPerHour >= 0 {
 		burstSize := 0
 		if s.config.MaxTraceflowsPerHour > 0 {
 			burstSize = 10
 		}
-		tfRateLimiter := ratelimit.NewGlobalRateLimiterOrDie(fmt.Sprintf("%d/h", s.config.MaxTraceflowsPerHour), burstSize)
-		createTfHandlers = append(createTfHandlers, ratelimit.Middleware(tfRateLimiter))
+		s.traceflowRateLimiter = ratelimit.NewGlobalRateLimiterOrDie(fmt.Sprintf("%d/h", s.config.MaxTraceflowsPerHour), burstSize)
 	}
-	createTfHandlers = append(createTfHandlers, s.CreateTraceflowRequest)
-	r.POST("", createTfHandlers...)
+	// These are rate-limited by the handlers rather than by a middleware, as the caller's quota
+	// depends on who they are. The matrix is not rate-limited as one request: each of its
+	// Traceflows waits for the rate limiters instead, while a hunt is rate-limited once, however
+	// many times it re-arms.
+	r.POST("", s.CreateTraceflowRequest)
+	r.POST("/matrix", s.CreateReachabilityMatrix)
+	r.POST("/hunt", s.CreateTraceflowHunt)
+	r.POST("/from-flow", s.CreateTraceflowFromFlow)
+	r.GET("", s.ListTraceflowRequests)
+	r.GET("/gc", s.GetTraceflowGCStats)
 	r.GET("/:requestId/status", s.GetTraceflowRequestStatus)
+	r.GET("/:requestId/stream", s.StreamTraceflowRequest)
 	r.GET("/:requestId", func(c *gin.Context) {
 		c.Redirect(http.StatusSeeOther, c.Request.URL.Path+"/status")
 	})
 	r.GET("/:requestId/result", s.GetTraceflowRequestResult)
+	r.GET("/:requestId/diagram", s.GetTraceflowRequestDiagram)
+	r.GET("/:requestId/export", s.ExportTraceflowRequest)
 	r.DELETE("/:requestId", s.DeleteTraceflowRequest)
+	r.PUT("/:requestId/pin", s.setTraceflowRequestPinned(true))
+	r.DELETE("/:requestId/pin", s.setTraceflowRequestPinned(false))
 }
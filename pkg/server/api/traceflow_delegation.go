@@ -0,0 +1,181 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+// traceflowDelegate returns the user that Traceflows may be delegated to for this request, if
+// any. Static-admin sessions already act as antrea-ui-admin and have nothing to gain from it.
+func (s *Server) traceflowDelegate(ctx context.Context) (string, bool) {
+	if !s.config.TraceflowDelegation {
+		return "", false
+	}
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok || ra.Mode == session.ModeAdmin || ra.Username == "" {
+		return "", false
+	}
+	return ra.Username, true
+}
+
+// delegateTraceflow decides whether tfRequest has to be created by antrea-ui-admin on the
+// caller's behalf. That is the case when the caller cannot create Traceflows but can get both the
+// source and the destination Pods: whoever can see both ends of the path may see the path itself.
+//
+// The checks are SelfSubjectAccessReviews made with the caller's own credential, so the API server
+// evaluates them for the caller's identity, and antrea-ui needs no permission to review access
+// for other users.
+func (s *Server) delegateTraceflow(c *gin.Context, tfRequest *apisv1.TraceflowRequest) (bool, *errors.ServerError) {
+	ctx := c.Request.Context()
+	if _, ok := s.traceflowDelegate(ctx); !ok {
+		return false, nil
+	}
+	clientset, err := s.clientFactory.KubernetesClientForRequest(ctx)
+	if err != nil {
+		return false, &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("failed to build K8s client for request: %w", err),
+		}
+	}
+	allowed, err := reviewAccess(ctx, clientset, &authorizationv1.ResourceAttributes{
+		Verb:     "create",
+		Group:    "crd.antrea.io",
+		Resource: "traceflows",
+	})
+	if err != nil {
+		return false, s.k8sError(c, err, "error when checking Traceflow permissions")
+	}
+	if allowed {
+		return false, nil
+	}
+	src, dst := &tfRequest.Source, &tfRequest.Destination
+	if src.Pod == "" || dst.Pod == "" {
+		return false, &errors.ServerError{
+			Code:    http.StatusForbidden,
+			Message: "Not allowed to create Traceflows; Traceflows between two Pods you can get are allowed",
+		}
+	}
+	for _, pod := range []struct{ namespace, name string }{{src.Namespace, src.Pod}, {dst.Namespace, dst.Pod}} {
+		allowed, err := reviewAccess(ctx, clientset, &authorizationv1.ResourceAttributes{
+			Verb:      "get",
+			Resource:  "pods",
+			Namespace: pod.namespace,
+			Name:      pod.name,
+		})
+		if err != nil {
+			return false, s.k8sError(c, err, "error when checking Pod permissions")
+		}
+		if !allowed {
+			return false, &errors.ServerError{
+				Code:    http.StatusForbidden,
+				Message: fmt.Sprintf("Not allowed to create Traceflows, nor to get Pod %s/%s", pod.namespace, pod.name),
+			}
+		}
+	}
+	if s.traceflowAdminClient == nil {
+		return false, &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("no antrea-ui-admin client for delegated Traceflows"),
+		}
+	}
+	return true, nil
+}
+
+func reviewAccess(ctx context.Context, clientset kubernetes.Interface, attributes *authorizationv1.ResourceAttributes) (bool, error) {
+	review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// traceflowClientFor returns the client to access Traceflow requestID with: antrea-ui-admin's if
+// it is a Traceflow delegated to the caller, and the caller's own otherwise.
+func (s *Server) traceflowClientFor(c *gin.Context, requestID string) (dynamic.Interface, *errors.ServerError) {
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return nil, sError
+	}
+	username, ok := s.traceflowDelegate(c.Request.Context())
+	if !ok || s.traceflowAdminClient == nil {
+		return client, nil
+	}
+	delegated, err := s.traceflowRequestsHandler.IsDelegatedTo(c, requestID, username)
+	if err != nil {
+		return nil, &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("error when checking Traceflow delegation: %w", err),
+		}
+	}
+	if delegated {
+		return s.traceflowAdminClient, nil
+	}
+	return client, nil
+}
+
+// listTraceflowRequests lists the Traceflows the caller can list, plus the ones delegated to them.
+// With delegation, a caller who cannot list Traceflows at all still gets their own.
+func (s *Server) listTraceflowRequests(c *gin.Context) ([]apisv1.TraceflowSummary, *errors.ServerError) {
+	client, sError := s.dynamicClientFor(c)
+	if sError != nil {
+		return nil, sError
+	}
+	username, delegation := s.traceflowDelegate(c.Request.Context())
+	summaries, err := s.traceflowRequestsHandler.ListRequests(c, client)
+	if err != nil {
+		if !delegation || !apierrors.IsForbidden(err) {
+			return nil, s.k8sError(c, err, "error when listing Traceflow requests")
+		}
+		summaries = []apisv1.TraceflowSummary{}
+	}
+	if !delegation {
+		return summaries, nil
+	}
+	delegated, err := s.traceflowRequestsHandler.ListDelegatedRequests(c, username)
+	if err != nil {
+		return nil, &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("error when listing delegated Traceflow requests: %w", err),
+		}
+	}
+	listed := make(map[string]bool, len(summaries))
+	for _, summary := range summaries {
+		listed[summary.ID] = true
+	}
+	for _, summary := range delegated {
+		if !listed[summary.ID] {
+			summaries = append(summaries, summary)
+		}
+	}
+	traceflowhandler.SortSummaries(summaries)
+	return summaries, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+func newTestServerForDelegation(t *testing.T, enabled bool) (*testServer, *fakeTraceflowK8sAPIServer, *dynamicfake.FakeDynamicClient) {
+	ts, fakeAPIServer := newTestServerForTraceflow(t, tfObjects, setTraceflowDelegation(enabled))
+	adminClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	ts.s.traceflowAdminClient = adminClient
+	return ts, fakeAPIServer, adminClient
+}
+
+func TestCreateDelegatedTraceflow(t *testing.T) {
+	testCases := []struct {
+		name              string
+		enabled           bool
+		mode              session.Mode
+		request           apisv1.TraceflowRequest
+		allowed           []string
+		expectedCode      int
+		expectedDelegated bool
+	}{
+		{
+			name:         "disabled",
+			mode:         session.ModeOIDC,
+			request:      tfRequest,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "admin session",
+			enabled:      true,
+			mode:         session.ModeAdmin,
+			request:      tfRequest,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "can create Traceflows",
+			enabled:      true,
+			mode:         session.ModeOIDC,
+			request:      tfRequest,
+			allowed:      []string{"create traceflows"},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:              "can get both Pods",
+			enabled:           true,
+			mode:              session.ModeOIDC,
+			request:           tfRequest,
+			allowed:           []string{"get pods default/pod-x", "get pods default/pod-y"},
+			expectedCode:      http.StatusAccepted,
+			expectedDelegated: true,
+		},
+		{
+			name:         "cannot get destination Pod",
+			enabled:      true,
+			mode:         session.ModeOIDC,
+			request:      tfRequest,
+			allowed:      []string{"get pods default/pod-x"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:    "IP destination",
+			enabled: true,
+			mode:    session.ModeOIDC,
+			request: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+				Destination: apisv1.TraceflowDestination{IP: "10.0.0.1"},
+			},
+			allowed:      []string{"get pods default/pod-x"},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, fakeAPIServer, adminClient := newTestServerForDelegation(t, tc.enabled)
+			for _, access := range tc.allowed {
+				fakeAPIServer.allowed[access] = true
+			}
+			if tc.expectedCode == http.StatusAccepted {
+				ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, client any, request *traceflowhandler.Request) (string, error) {
+						assert.Equal(t, tc.expectedDelegated, request.Delegated)
+						assert.Equal(t, tc.expectedDelegated, client == adminClient, "Traceflow should be created by antrea-ui-admin iff it is delegated")
+						assert.Equal(t, "tester", request.Username)
+						return uuid.NewString(), nil
+					})
+			}
+			req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(mustMarshal(tc.request)))
+			ts.authorizeRequestAs(req, tc.mode)
+			rr := httptest.NewRecorder()
+			ts.router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestGetDelegatedTraceflow(t *testing.T) {
+	ts, _, adminClient := newTestServerForDelegation(t, true)
+	requestID := uuid.NewString()
+	otherID := uuid.NewString()
+	ts.traceflowRequestsHandler.EXPECT().IsDelegatedTo(gomock.Any(), requestID, "tester").Return(true, nil)
+	ts.traceflowRequestsHandler.EXPECT().IsDelegatedTo(gomock.Any(), otherID, "tester").Return(false, nil)
+	ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), adminClient, requestID).Return(tf, false, nil)
+	ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Not(adminClient), otherID).Return(nil, false, apierrors.NewForbidden(schema.GroupResource{Group: "crd.antrea.io", Resource: "traceflows"}, otherID, nil))
+
+	for requestID, expectedCode := range map[string]int{requestID: http.StatusOK, otherID: http.StatusForbidden} {
+		req := httptest.NewRequest("GET", "/api/v1/traceflow/"+requestID+"/status", nil)
+		ts.authorizeRequestAs(req, session.ModeOIDC)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		assert.Equal(t, expectedCode, rr.Code, rr.Body.String())
+	}
+}
+
+func TestListDelegatedTraceflows(t *testing.T) {
+	ts, _, _ := newTestServerForDelegation(t, true)
+	ts.traceflowRequestsHandler.EXPECT().ListRequests(gomock.Any(), gomock.Any()).Return(nil, apierrors.NewForbidden(schema.GroupResource{Group: "crd.antrea.io", Resource: "traceflows"}, "", nil))
+	delegated := []apisv1.TraceflowSummary{
+		{ID: "b", CreatedBy: "tester", CreationTimestamp: "2026-01-01T00:00:00Z", Delegated: true},
+		{ID: "a", CreatedBy: "tester", CreationTimestamp: "2026-01-01T01:00:00Z", Delegated: true},
+	}
+	ts.traceflowRequestsHandler.EXPECT().ListDelegatedRequests(gomock.Any(), "tester").Return(delegated, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/traceflow", nil)
+	ts.authorizeRequestAs(req, session.ModeOIDC)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var summaries []apisv1.TraceflowSummary
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summaries))
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "a", summaries[0].ID)
+	assert.Equal(t, "b", summaries[1].ID)
+}
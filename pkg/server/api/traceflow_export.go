@@ -0,0 +1,136 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+// traceflowDiagramFormats maps the format query parameter of
+// GET /api/v1/traceflow/:requestId/diagram to a rendering and its content type.
+var traceflowDiagramFormats = map[string]struct {
+	render      func(*traceflowhandler.Diagram) string
+	contentType string
+}{
+	"svg":     {(*traceflowhandler.Diagram).SVG, "image/svg+xml"},
+	"dot":     {(*traceflowhandler.Diagram).DOT, "text/vnd.graphviz; charset=utf-8"},
+	"mermaid": {(*traceflowhandler.Diagram).Mermaid, "text/plain; charset=utf-8"},
+}
+
+// GetTraceflowRequestDiagram handles GET /api/v1/traceflow/:requestId/diagram. It renders the
+// path of a completed Traceflow as SVG (the default), Graphviz DOT (?format=dot) or Mermaid
+// (?format=mermaid).
+func (s *Server) GetTraceflowRequestDiagram(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var data, contentType string
+	if sError := func() *errors.ServerError {
+		format, ok := traceflowDiagramFormats[c.DefaultQuery("format", "svg")]
+		if !ok {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid format, must be one of svg, dot or mermaid",
+			}
+		}
+		tfResult, sError := s.getTraceflowRequestResult(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		diagram, err := traceflowhandler.NewDiagram(tfResult)
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when building Traceflow diagram: %w", err),
+			}
+		}
+		data, contentType = format.render(diagram), format.contentType
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to get diagram for Traceflow request", "requestId", requestID)
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(data))
+}
+
+// ExportTraceflowRequest handles GET /api/v1/traceflow/:requestId/export. It returns a zip
+// archive with the Traceflow object and its diagram in every format, to be attached to a ticket.
+func (s *Server) ExportTraceflowRequest(c *gin.Context) {
+	requestID := c.Param("requestId")
+	var buf bytes.Buffer
+	if sError := func() *errors.ServerError {
+		tfResult, sError := s.getTraceflowRequestResult(c, requestID)
+		if sError != nil {
+			return sError
+		}
+		diagram, err := traceflowhandler.NewDiagram(tfResult)
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when building Traceflow diagram: %w", err),
+			}
+		}
+		tfJSON, err := json.MarshalIndent(tfResult, "", "  ")
+		if err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when converting Traceflow request result to JSON: %w", err),
+			}
+		}
+		zw := zip.NewWriter(&buf)
+		for _, file := range []struct {
+			name string
+			data []byte
+		}{
+			{"traceflow.json", tfJSON},
+			{"diagram.svg", []byte(diagram.SVG())},
+			{"diagram.dot", []byte(diagram.DOT())},
+			{"diagram.mmd", []byte(diagram.Mermaid())},
+		} {
+			w, err := zw.Create(file.name)
+			if err == nil {
+				_, err = w.Write(file.data)
+			}
+			if err != nil {
+				return &errors.ServerError{
+					Code: http.StatusInternalServerError,
+					Err:  fmt.Errorf("error when writing %s to Traceflow export: %w", file.name, err),
+				}
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return &errors.ServerError{
+				Code: http.StatusInternalServerError,
+				Err:  fmt.Errorf("error when writing Traceflow export: %w", err),
+			}
+		}
+		return nil
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to export Traceflow request", "requestId", requestID)
+		return
+	}
+	c.Header("Access-Control-Expose-Headers", "Content-Disposition")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="traceflow-%s.zip"`, requestID))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+// CreateTraceflowFromFlow handles POST /api/v1/traceflow/from-flow. The body is an
+// apisv1.TraceflowFromFlowRequest. The Traceflow is built from the flow, then created like with
+// POST /api/v1/traceflow, and counts against the same limits. The response is the same, with the
+// apisv1.TraceflowRequest built from the flow as body.
+func (s *Server) CreateTraceflowFromFlow(c *gin.Context) {
+	var tfRequest *apisv1.TraceflowRequest
+	var requestID string
+	if sError := func() *errors.ServerError {
+		var fromFlowRequest apisv1.TraceflowFromFlowRequest
+		if err := c.BindJSON(&fromFlowRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		flow, sError := s.flowForTraceflow(c, &fromFlowRequest)
+		if sError != nil {
+			return sError
+		}
+		var err error
+		tfRequest, err = traceflowRequestForFlow(flow, &fromFlowRequest)
+		if err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		quota := s.traceflowQuotaFor(c.Request.Context())
+		requestID, sError = s.submitTraceflowRequest(c, &quota, tfRequest)
+		return sError
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to create Traceflow request from flow")
+		return
+	}
+	setTraceflowAcceptedHeaders(c, requestID)
+	c.JSON(http.StatusAccepted, tfRequest)
+}
+
+// flowForTraceflow returns the flow of req: the one in the body, or the one the caller recently
+// received with that ID.
+func (s *Server) flowForTraceflow(c *gin.Context, req *apisv1.TraceflowFromFlowRequest) (*apisv1.Flow, *errors.ServerError) {
+	if (req.FlowID == "") == (req.Flow == nil) {
+		return nil, &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: "Exactly one of flowId and flow must be set",
+		}
+	}
+	if req.Flow != nil {
+		return req.Flow, nil
+	}
+	if s.flowStreamSSEHandler == nil {
+		return nil, &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: "Flow Aggregator integration is not enabled, flows cannot be referred to by ID",
+		}
+	}
+	flow, ok := s.flowStreamSSEHandler.RecentFlow(c.Request.Context(), req.FlowID)
+	if !ok {
+		return nil, &errors.ServerError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Flow %s was not recently streamed to you; pass the flow record instead", req.FlowID),
+		}
+	}
+	return flow, nil
+}
+
+// traceflowRequestForFlow builds the Traceflow request reproducing the connection of flow. A flow
+// to a Service is traced to the Service rather than to the endpoint that was picked for it. The
+// source port is only set for live traffic, to match that connection: an injected packet reusing
+// the port of an existing connection would be treated as part of it.
+func traceflowRequestForFlow(flow *apisv1.Flow, req *apisv1.TraceflowFromFlowRequest) (*apisv1.TraceflowRequest, error) {
+	k8s := &flow.K8s
+	tfRequest := &apisv1.TraceflowRequest{
+		IPv6:        flow.IP.Version == apisv1.IPVersionIPv6,
+		LiveTraffic: req.LiveTraffic,
+		DroppedOnly: req.DroppedOnly,
+		Timeout:     req.Timeout,
+		Title:       req.Title,
+	}
+	if tfRequest.Title == "" && flow.ID != "" {
+		tfRequest.Title = "Flow " + flow.ID
+	}
+
+	switch {
+	case k8s.SourcePodName != "":
+		tfRequest.Source = apisv1.TraceflowSource{Namespace: k8s.SourcePodNamespace, Pod: k8s.SourcePodName}
+	case req.LiveTraffic && flow.IP.Source != "":
+		tfRequest.Source = apisv1.TraceflowSource{IP: flow.IP.Source}
+	default:
+		return nil, fmt.Errorf("the flow has no source Pod; a live-traffic Traceflow can trace it from its source IP")
+	}
+
+	var dstPort uint32
+	if namespace, name, ok := serviceFromPortName(k8s.DestinationServicePortName); ok {
+		tfRequest.Destination = apisv1.TraceflowDestination{Namespace: namespace, Service: name}
+		dstPort = k8s.DestinationServicePort
+	} else if k8s.DestinationPodName != "" {
+		tfRequest.Destination = apisv1.TraceflowDestination{Namespace: k8s.DestinationPodNamespace, Pod: k8s.DestinationPodName}
+		dstPort = flow.Transport.DestinationPort
+	} else if flow.IP.Destination != "" {
+		tfRequest.Destination = apisv1.TraceflowDestination{IP: flow.IP.Destination}
+		dstPort = flow.Transport.DestinationPort
+	} else {
+		return nil, fmt.Errorf("the flow has no destination")
+	}
+
+	switch flow.Transport.ProtocolNumber {
+	case 6:
+		tfRequest.Protocol = "TCP"
+	case 17:
+		tfRequest.Protocol = "UDP"
+	case 1, 58:
+		tfRequest.Protocol = "ICMP"
+		return tfRequest, nil
+	default:
+		return nil, fmt.Errorf("protocol %d of the flow is not supported by Traceflow", flow.Transport.ProtocolNumber)
+	}
+	tfRequest.DestinationPort = int32(dstPort)
+	if req.LiveTraffic {
+		tfRequest.SourcePort = int32(flow.Transport.SourcePort)
+	}
+	return tfRequest, nil
+}
+
+// serviceFromPortName parses the destination Service port name of a flow, "<namespace>/<name>"
+// followed by ":<port name>".
+func serviceFromPortName(portName string) (string, string, bool) {
+	namespace, rest, ok := strings.Cut(portName, "/")
+	if !ok || namespace == "" {
+		return "", "", false
+	}
+	name, _, _ := strings.Cut(rest, ":")
+	if name == "" {
+		return "", "", false
+	}
+	return namespace, name, true
+}
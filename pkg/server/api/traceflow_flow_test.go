@@ -0,0 +1,198 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/handlers/flowstream"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+func TestTraceflowRequestForFlow(t *testing.T) {
+	podToService := apisv1.Flow{
+		ID:        "flow-1",
+		IP:        apisv1.FlowIP{Version: apisv1.IPVersionIPv4, Source: "10.0.0.1", Destination: "10.0.1.5"},
+		Transport: apisv1.FlowTransport{ProtocolNumber: 6, SourcePort: 43210, DestinationPort: 8080},
+		K8s: apisv1.FlowKubernetes{
+			SourcePodNamespace:         "default",
+			SourcePodName:              "pod-x",
+			DestinationPodNamespace:    "default",
+			DestinationPodName:         "db-0",
+			DestinationClusterIp:       "10.96.0.10",
+			DestinationServicePort:     5432,
+			DestinationServicePortName: "default/db:postgres",
+		},
+	}
+	toExternal := apisv1.Flow{
+		IP:        apisv1.FlowIP{Version: apisv1.IPVersionIPv6, Source: "fd00::1", Destination: "2001:db8::1"},
+		Transport: apisv1.FlowTransport{ProtocolNumber: 17, SourcePort: 5353, DestinationPort: 53},
+		K8s:       apisv1.FlowKubernetes{SourcePodNamespace: "default", SourcePodName: "pod-x"},
+	}
+	fromExternal := apisv1.Flow{
+		IP:        apisv1.FlowIP{Source: "192.168.1.1", Destination: "10.0.0.1"},
+		Transport: apisv1.FlowTransport{ProtocolNumber: 1},
+		K8s:       apisv1.FlowKubernetes{DestinationPodNamespace: "default", DestinationPodName: "pod-x"},
+	}
+
+	testCases := []struct {
+		name          string
+		flow          apisv1.Flow
+		request       apisv1.TraceflowFromFlowRequest
+		expected      *apisv1.TraceflowRequest
+		expectedError string
+	}{
+		{
+			name: "Pod to Service",
+			flow: podToService,
+			expected: &apisv1.TraceflowRequest{
+				Source:          apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+				Destination:     apisv1.TraceflowDestination{Namespace: "default", Service: "db"},
+				Protocol:        "TCP",
+				DestinationPort: 5432,
+				Title:           "Flow flow-1",
+			},
+		},
+		{
+			name:    "live traffic keeps the source port",
+			flow:    podToService,
+			request: apisv1.TraceflowFromFlowRequest{LiveTraffic: true, Timeout: 60, Title: "Slow queries"},
+			expected: &apisv1.TraceflowRequest{
+				Source:          apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+				Destination:     apisv1.TraceflowDestination{Namespace: "default", Service: "db"},
+				Protocol:        "TCP",
+				SourcePort:      43210,
+				DestinationPort: 5432,
+				LiveTraffic:     true,
+				Timeout:         60,
+				Title:           "Slow queries",
+			},
+		},
+		{
+			name: "to external",
+			flow: toExternal,
+			expected: &apisv1.TraceflowRequest{
+				Source:          apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+				Destination:     apisv1.TraceflowDestination{IP: "2001:db8::1"},
+				Protocol:        "UDP",
+				DestinationPort: 53,
+				IPv6:            true,
+			},
+		},
+		{
+			name:          "from external",
+			flow:          fromExternal,
+			expectedError: "the flow has no source Pod",
+		},
+		{
+			name:    "from external, live traffic",
+			flow:    fromExternal,
+			request: apisv1.TraceflowFromFlowRequest{LiveTraffic: true},
+			expected: &apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "192.168.1.1"},
+				Destination: apisv1.TraceflowDestination{Namespace: "default", Pod: "pod-x"},
+				Protocol:    "ICMP",
+				LiveTraffic: true,
+			},
+		},
+		{
+			name: "unsupported protocol",
+			flow: apisv1.Flow{
+				Transport: apisv1.FlowTransport{ProtocolNumber: 132},
+				K8s:       apisv1.FlowKubernetes{SourcePodNamespace: "default", SourcePodName: "pod-x", DestinationPodNamespace: "default", DestinationPodName: "pod-y"},
+			},
+			expectedError: "protocol 132 of the flow is not supported",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tfRequest, err := traceflowRequestForFlow(&tc.flow, &tc.request)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tfRequest)
+		})
+	}
+}
+
+func TestCreateTraceflowFromFlow(t *testing.T) {
+	flow := apisv1.Flow{
+		ID:        "flow-1",
+		Transport: apisv1.FlowTransport{ProtocolNumber: 6, SourcePort: 43210, DestinationPort: 80},
+		K8s: apisv1.FlowKubernetes{
+			SourcePodNamespace:      "default",
+			SourcePodName:           "pod-x",
+			DestinationPodNamespace: "default",
+			DestinationPodName:      "pod-y",
+		},
+	}
+	post := func(ts *testServer, body apisv1.TraceflowFromFlowRequest) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/traceflow/from-flow", bytes.NewReader(mustMarshal(body)))
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("flow record", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, _ any, request *traceflowhandler.Request) (string, error) {
+				spec := request.Object["spec"].(map[string]interface{})
+				assert.Equal(t, map[string]interface{}{"namespace": "default", "pod": "pod-y"}, spec["destination"])
+				assert.Equal(t, "Flow flow-1", request.Title)
+				return "tf-1", nil
+			})
+		rr := post(ts, apisv1.TraceflowFromFlowRequest{Flow: &flow})
+		require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+		assert.Equal(t, "/api/v1/traceflow/tf-1", rr.Header().Get("Location"))
+		var tfRequest apisv1.TraceflowRequest
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tfRequest))
+		assert.Equal(t, int32(80), tfRequest.DestinationPort)
+	})
+
+	t.Run("flow ID", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		rr := post(ts, apisv1.TraceflowFromFlowRequest{FlowID: "flow-1"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "flow IDs need the Flow Aggregator integration")
+		ts.s.flowStreamSSEHandler = flowstream.NewSSEHandler(testr.New(t), &flowingSubscriber{})
+		rr = post(ts, apisv1.TraceflowFromFlowRequest{FlowID: "flow-1"})
+		assert.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		for _, body := range []apisv1.TraceflowFromFlowRequest{
+			{},
+			{FlowID: "flow-1", Flow: &flow},
+			{Flow: &apisv1.Flow{Transport: apisv1.FlowTransport{ProtocolNumber: 6}}},
+		} {
+			rr := post(ts, body)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	// traceflowHuntGracePeriod is how long a Traceflow of a hunt may run past its timeout,
+	// before the hunt stops waiting for Antrea and re-arms.
+	traceflowHuntGracePeriod = 30 * time.Second
+	// traceflowHuntMinTimeout is the shortest timeout of the Traceflows of a hunt, which bounds
+	// how often a hunt re-arms.
+	traceflowHuntMinTimeout = 60
+	// traceflowHuntDeleteTimeout bounds the deletion of a Traceflow that captured nothing, which
+	// may happen after the client is gone.
+	traceflowHuntDeleteTimeout = 10 * time.Second
+)
+
+// traceflowHunt is a drop hunt in progress. At most one of its Traceflows exists at any time,
+// except for the ones that captured a drop, which are kept.
+type traceflowHunt struct {
+	client      dynamic.Interface
+	tfRequest   apisv1.TraceflowRequest
+	dstIP       string
+	request     traceflowhandler.Request
+	deadline    time.Time
+	maxCaptures int32
+}
+
+var errTraceflowHuntSessionEnded = fmt.Errorf("session is no longer valid, the drop hunt is stopped")
+
+type traceflowHuntEvent struct {
+	name string
+	data interface{}
+}
+
+// CreateTraceflowHunt handles POST /api/v1/traceflow/hunt. The body is an
+// apisv1.TraceflowHuntRequest. The hunt creates a dropped-only live-traffic Traceflow, and creates
+// a new one every time the previous one times out or captures a drop, until it has captured
+// MaxCaptures drops or Duration has elapsed. The response is a stream of Server-Sent Events:
+//   - "armed" (apisv1.TraceflowHuntArmedEvent) every time a Traceflow is created;
+//   - "capture" (apisv1.TraceflowHuntCaptureEvent) every time a drop is captured;
+//   - "end" (apisv1.TraceflowHuntEndEvent) when the hunt is over;
+//   - "error" (apisv1.TraceflowStreamErrorEvent) if a Traceflow fails for another reason than a
+//     timeout, or cannot be created or watched.
+//
+// The stream ends after "end" or "error". The hunt only runs while the client is connected, as it
+// acts as the caller and antrea-ui does not keep their credential once they are gone, and while
+// the caller's session is valid. A hunt counts as one Traceflow against the caller's quota,
+// however many times it re-arms.
+func (s *Server) CreateTraceflowHunt(c *gin.Context) {
+	var hunt *traceflowHunt
+	var requestID string
+	if sError := func() *errors.ServerError {
+		var huntRequest apisv1.TraceflowHuntRequest
+		if err := c.BindJSON(&huntRequest); err != nil {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		if errs := s.validateTraceflowHuntRequest(&huntRequest); len(errs) > 0 {
+			return &errors.ServerError{
+				Code:    http.StatusBadRequest,
+				Message: strings.Join(errs, "; "),
+			}
+		}
+		tfRequest := &huntRequest.TraceflowRequest
+		dstIP, sError := s.checkTraceflowRequest(c, tfRequest)
+		if sError != nil {
+			return sError
+		}
+		hunt = &traceflowHunt{
+			tfRequest:   *tfRequest,
+			dstIP:       dstIP,
+			deadline:    time.Now().Add(time.Duration(huntRequest.Duration) * time.Second),
+			maxCaptures: huntRequest.MaxCaptures,
+		}
+		hunt.tfRequest.Timeout = hunt.armTimeout()
+		client, request, sError := s.newTraceflowRequest(c, &hunt.tfRequest, dstIP)
+		if sError != nil {
+			return sError
+		}
+		hunt.client, hunt.request = client, *request
+		quota := s.traceflowQuotaFor(c.Request.Context())
+		requestID, sError = s.createTraceflowRequest(c, client, &quota, request)
+		return sError
+	}(); sError != nil {
+		errors.HandleError(c, sError)
+		s.LogError(sError, "Failed to create Traceflow hunt")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events := make(chan traceflowHuntEvent)
+	go s.runTraceflowHunt(ctx, hunt, requestID, events)
+	keepAlive := time.NewTicker(traceflowStreamKeepAlive)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-keepAlive.C:
+			if !s.traceflowHuntSessionAlive(ctx) {
+				// Stop the hunt before telling the client, so that it creates nothing more.
+				cancel()
+				data, err := json.Marshal(apisv1.TraceflowStreamErrorEvent{Message: errTraceflowHuntSessionEnded.Error()})
+				if err == nil {
+					c.SSEvent("error", string(data))
+				}
+				return false
+			}
+			_, err := w.Write([]byte(": keepalive\n\n"))
+			return err == nil
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(event.data)
+			if err != nil {
+				return false
+			}
+			c.SSEvent(event.name, string(data))
+			return true
+		}
+	})
+	// Wait for the hunt to delete its last Traceflow.
+	cancel()
+	for range events {
+	}
+}
+
+// validateTraceflowHuntRequest normalizes req in place and checks the fields specific to a hunt.
+// The embedded TraceflowRequest is checked by checkTraceflowRequest.
+func (s *Server) validateTraceflowHuntRequest(req *apisv1.TraceflowHuntRequest) []string {
+	var errs []string
+	req.LiveTraffic = true
+	req.DroppedOnly = true
+	if req.Timeout == 0 {
+		req.Timeout = traceflowMaxTimeout
+	}
+	if req.Timeout < traceflowHuntMinTimeout || req.Timeout > traceflowMaxTimeout {
+		errs = append(errs, fmt.Sprintf("Timeout of a hunt must be between %d and %d seconds", traceflowHuntMinTimeout, traceflowMaxTimeout))
+	}
+	maxDuration := int32(s.config.TraceflowHunt.MaxDuration / time.Second)
+	if req.Duration == 0 {
+		req.Duration = maxDuration
+	}
+	if req.Duration < 0 || req.Duration > maxDuration {
+		errs = append(errs, fmt.Sprintf("Duration must be between 1 and %d seconds", maxDuration))
+	}
+	if req.MaxCaptures == 0 {
+		req.MaxCaptures = 1
+	}
+	if req.MaxCaptures < 0 || int(req.MaxCaptures) > s.config.TraceflowHunt.MaxCaptures {
+		errs = append(errs, fmt.Sprintf("Max captures must be between 1 and %d", s.config.TraceflowHunt.MaxCaptures))
+	}
+	return errs
+}
+
+// armTimeout returns the timeout of the next Traceflow of the hunt, which must not outlive the
+// hunt. Only the last Traceflow of a hunt may be shorter than traceflowHuntMinTimeout.
+func (h *traceflowHunt) armTimeout() int32 {
+	remaining := int32(time.Until(h.deadline).Round(time.Second) / time.Second)
+	return max(1, min(h.tfRequest.Timeout, remaining))
+}
+
+// runTraceflowHunt runs hunt, whose first Traceflow is requestID, and sends its events. It closes
+// events when the hunt is over or ctx is cancelled.
+func (s *Server) runTraceflowHunt(ctx context.Context, hunt *traceflowHunt, requestID string, events chan<- traceflowHuntEvent) {
+	defer close(events)
+	send := func(name string, data interface{}) bool {
+		select {
+		case events <- traceflowHuntEvent{name: name, data: data}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	sendError := func(message string) {
+		send("error", apisv1.TraceflowStreamErrorEvent{Message: message})
+	}
+	var captures int32
+	for {
+		if !send("armed", apisv1.TraceflowHuntArmedEvent{ID: requestID, Captures: captures}) {
+			s.deleteTraceflowHuntArm(ctx, hunt, requestID)
+			return
+		}
+		result, err := s.waitTraceflowHuntArm(ctx, hunt, requestID)
+		if err != nil {
+			s.logger.Error(err, "Error when watching Traceflow of drop hunt", "requestId", requestID)
+			s.deleteTraceflowHuntArm(ctx, hunt, requestID)
+			sendError(err.Error())
+			return
+		}
+		if result == nil {
+			// The Traceflow was still running when the hunt expired, or long after its
+			// timeout.
+			s.deleteTraceflowHuntArm(ctx, hunt, requestID)
+			if ctx.Err() != nil {
+				return
+			}
+		} else if phase, _, _ := unstructured.NestedString(result, "status", "phase"); phase == "Succeeded" {
+			captures++
+			if !send("capture", apisv1.TraceflowHuntCaptureEvent{ID: requestID, Capture: captures, Traceflow: result}) {
+				return
+			}
+			if captures >= hunt.maxCaptures {
+				send("end", apisv1.TraceflowHuntEndEvent{Captures: captures, Reason: "captured"})
+				return
+			}
+		} else {
+			reason, _, _ := unstructured.NestedString(result, "status", "reason")
+			// Antrea fails a live-traffic Traceflow that saw no packet before its timeout.
+			// Any other failure would happen again, so the hunt ends.
+			if !strings.Contains(strings.ToLower(reason), "timeout") {
+				sendError(fmt.Sprintf("Traceflow failed: %s", reason))
+				return
+			}
+			s.deleteTraceflowHuntArm(ctx, hunt, requestID)
+		}
+		if !time.Now().Before(hunt.deadline) {
+			send("end", apisv1.TraceflowHuntEndEvent{Captures: captures, Reason: "expired"})
+			return
+		}
+		if !s.traceflowHuntSessionAlive(ctx) {
+			sendError(errTraceflowHuntSessionEnded.Error())
+			return
+		}
+		hunt.tfRequest.Timeout = hunt.armTimeout()
+		request := hunt.request
+		request.Object = map[string]interface{}{
+			"spec": traceflowSpec(&hunt.tfRequest, hunt.dstIP),
+		}
+		requestID, err = s.traceflowRequestsHandler.CreateRequest(ctx, hunt.client, &request)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error(err, "Error when re-arming drop hunt")
+				sendError(fmt.Sprintf("error when re-arming drop hunt: %v", err))
+			}
+			return
+		}
+	}
+}
+
+// traceflowHuntSessionAlive reports whether the session of the caller of the hunt is still valid,
+// and keeps it alive, see session.RequestAuth.KeepAlive. A hunt acts as the caller for hours, so
+// it must stop once they logged out or their session expired. It fails closed: a hunt whose
+// caller is unknown must not keep running.
+func (s *Server) traceflowHuntSessionAlive(ctx context.Context) bool {
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok {
+		s.logger.Error(errTraceflowHuntSessionEnded, "No identity for drop hunt")
+		return false
+	}
+	return ra.KeepAlive(ctx)
+}
+
+// waitTraceflowHuntArm waits for Traceflow requestID of hunt to complete, and returns it. It
+// returns nil if the Traceflow is not completed by the end of the hunt, or a grace period after
+// its timeout.
+func (s *Server) waitTraceflowHuntArm(ctx context.Context, hunt *traceflowHunt, requestID string) (map[string]interface{}, error) {
+	deadline := time.Now().Add(time.Duration(hunt.tfRequest.Timeout)*time.Second + traceflowHuntGracePeriod)
+	if hunt.deadline.Before(deadline) {
+		deadline = hunt.deadline
+	}
+	armCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	updates, err := s.traceflowRequestsHandler.WatchRequest(armCtx, hunt.client, requestID)
+	if err != nil {
+		if armCtx.Err() != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for update := range updates {
+		if update.Err != nil {
+			if armCtx.Err() != nil {
+				return nil, nil
+			}
+			return nil, update.Err
+		}
+		if update.Done {
+			return update.Object, nil
+		}
+	}
+	return nil, nil
+}
+
+// deleteTraceflowHuntArm deletes a Traceflow of hunt that captured nothing, so that the history
+// only shows captures. It still runs if ctx was cancelled because the client is gone.
+func (s *Server) deleteTraceflowHuntArm(ctx context.Context, hunt *traceflowHunt, requestID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), traceflowHuntDeleteTimeout)
+	defer cancel()
+	if _, err := s.traceflowRequestsHandler.DeleteRequest(ctx, hunt.client, requestID); err != nil {
+		s.logger.Error(err, "Failed to delete Traceflow of drop hunt", "requestId", requestID)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+// openTraceflowHunt starts a hunt on a real server, as gin's Stream needs http.CloseNotifier,
+// and returns its events.
+func openTraceflowHunt(t *testing.T, ts *testServer, huntRequest apisv1.TraceflowHuntRequest) (int, []sseEvent) {
+	srv := httptest.NewServer(ts.router)
+	t.Cleanup(srv.Close)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", srv.URL+"/api/v1/traceflow/hunt", bytes.NewReader(mustMarshal(huntRequest)))
+	require.NoError(t, err)
+	ts.authorizeRequest(req)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(string(body)), "\n\n") {
+		var event sseEvent
+		for _, line := range strings.Split(block, "\n") {
+			if name, ok := strings.CutPrefix(line, "event:"); ok {
+				event.name = name
+			} else if data, ok := strings.CutPrefix(line, "data:"); ok {
+				event.data = data
+			}
+		}
+		events = append(events, event)
+	}
+	return resp.StatusCode, events
+}
+
+// expectHuntArm expects a Traceflow of the hunt to be created, and to complete with result, or
+// to never complete if result is nil.
+func expectHuntArm(ts *testServer, requestID string, result map[string]interface{}) {
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ any, request *traceflowhandler.Request) (string, error) {
+			spec := request.Object["spec"].(map[string]interface{})
+			if spec["liveTraffic"] != true || spec["droppedOnly"] != true {
+				return "", assert.AnError
+			}
+			return requestID, nil
+		})
+	ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), gomock.Any(), requestID).DoAndReturn(
+		func(ctx context.Context, _ any, _ string) (<-chan traceflowhandler.RequestUpdate, error) {
+			updates := make(chan traceflowhandler.RequestUpdate, 1)
+			if result != nil {
+				updates <- traceflowhandler.RequestUpdate{Object: result, Done: true}
+				close(updates)
+			} else {
+				go func() {
+					<-ctx.Done()
+					close(updates)
+				}()
+			}
+			return updates, nil
+		})
+}
+
+func huntResult(phase, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"status": map[string]interface{}{"phase": phase, "reason": reason},
+	}
+}
+
+func TestTraceflowHunt(t *testing.T) {
+	huntRequest := apisv1.TraceflowHuntRequest{
+		TraceflowRequest: apisv1.TraceflowRequest{
+			Source: apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+		},
+		Duration: 60,
+	}
+
+	t.Run("re-arm until captured", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		// tf-1 captures nothing, and is deleted.
+		expectHuntArm(ts, "tf-1", huntResult("Failed", "Traceflow timeout"))
+		ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), "tf-1").Return(true, nil)
+		expectHuntArm(ts, "tf-2", huntResult("Succeeded", ""))
+		expectHuntArm(ts, "tf-3", huntResult("Succeeded", ""))
+		request := huntRequest
+		request.MaxCaptures = 2
+		code, events := openTraceflowHunt(t, ts, request)
+		require.Equal(t, http.StatusOK, code)
+		var names []string
+		for _, event := range events {
+			names = append(names, event.name)
+		}
+		assert.Equal(t, []string{"armed", "armed", "capture", "armed", "capture", "end"}, names)
+		var capture apisv1.TraceflowHuntCaptureEvent
+		require.NoError(t, json.Unmarshal([]byte(events[4].data), &capture))
+		assert.Equal(t, "tf-3", capture.ID)
+		assert.Equal(t, int32(2), capture.Capture)
+		assert.JSONEq(t, `{"captures":2,"reason":"captured"}`, events[5].data)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		expectHuntArm(ts, "tf-1", nil)
+		ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), "tf-1").Return(true, nil)
+		request := huntRequest
+		request.Duration = 1
+		code, events := openTraceflowHunt(t, ts, request)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events, 2)
+		assert.Equal(t, "armed", events[0].name)
+		assert.Equal(t, "end", events[1].name)
+		assert.JSONEq(t, `{"captures":0,"reason":"expired"}`, events[1].data)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		expectHuntArm(ts, "tf-1", huntResult("Failed", "Invalid destination"))
+		code, events := openTraceflowHunt(t, ts, huntRequest)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events, 2)
+		assert.Equal(t, "error", events[1].name)
+		assert.JSONEq(t, `{"message":"Traceflow failed: Invalid destination"}`, events[1].data)
+	})
+
+	t.Run("session ended", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		expectHuntArm(ts, "tf-1", huntResult("Failed", "Traceflow timeout"))
+		// The caller logs out while tf-1 runs: the hunt must not re-arm.
+		ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), "tf-1").DoAndReturn(
+			func(ctx context.Context, _ any, _ string) (bool, error) {
+				ra, ok := session.RequestAuthFrom(ctx)
+				require.True(t, ok)
+				ts.sessionStore.Delete(ra.SessionID())
+				return true, nil
+			})
+		code, events := openTraceflowHunt(t, ts, huntRequest)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, events, 2)
+		assert.Equal(t, "armed", events[0].name)
+		assert.Equal(t, "error", events[1].name)
+		assert.Contains(t, events[1].data, "session is no longer valid")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		for _, request := range []apisv1.TraceflowHuntRequest{
+			{TraceflowRequest: huntRequest.TraceflowRequest, Duration: 3601},
+			{TraceflowRequest: huntRequest.TraceflowRequest, MaxCaptures: 11},
+			{TraceflowRequest: apisv1.TraceflowRequest{Source: huntRequest.Source, Timeout: 1}},
+			{TraceflowRequest: apisv1.TraceflowRequest{Source: apisv1.TraceflowSource{IP: "10.0.0.1"}}},
+		} {
+			code, _ := openTraceflowHunt(t, ts, request)
+			assert.Equal(t, http.StatusBadRequest, code)
+		}
+	})
+}
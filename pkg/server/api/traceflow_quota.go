@@ -0,0 +1,232 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"antrea.io/antrea-ui/pkg/auth/session"
+	"antrea.io/antrea-ui/pkg/server/errors"
+	"antrea.io/antrea-ui/pkg/server/ratelimit"
+)
+
+const (
+	// traceflowQuotaCacheSize bounds the number of users whose hourly budget is tracked. A
+	// user evicted from the cache starts over with a full budget.
+	traceflowQuotaCacheSize = 10000
+	// traceflowConcurrencyRetryAfter is the Retry-After sent when the caller has too many
+	// Traceflows running: a Traceflow completes or times out within that time.
+	traceflowConcurrencyRetryAfter = 10 * time.Second
+)
+
+// traceflowQuota is the quota of one caller, resolved from limits.traceflowQuota.
+type traceflowQuota struct {
+	// user is the identity the quota is tracked under. It is empty for callers that are only
+	// subject to the global limit, see traceflowQuotaFor.
+	user string
+	// maxPerHour is negative when the caller's hourly budget is not limited.
+	maxPerHour int
+	// maxConcurrent is 0 when the caller's running Traceflows are not limited.
+	maxConcurrent int
+}
+
+// traceflowQuotaFor returns the quota of the caller behind ctx: the first override naming one of
+// their groups, or the default one.
+//
+// Static-admin sessions are exempt, for the same reason they are exempt from the per-user session
+// and flow stream caps: everyone using the admin password is the same literal "admin", so a
+// per-user quota would be a single budget shared by all of them.
+func (s *Server) traceflowQuotaFor(ctx context.Context) traceflowQuota {
+	ra, ok := session.RequestAuthFrom(ctx)
+	if !ok || ra.Mode == session.ModeAdmin || ra.Username == "" {
+		return traceflowQuota{maxPerHour: -1}
+	}
+	cfg := &s.config.TraceflowQuota
+	quota := traceflowQuota{
+		user:          ra.Username,
+		maxPerHour:    cfg.MaxPerHour,
+		maxConcurrent: cfg.MaxConcurrent,
+	}
+	// Only look the groups up when they can make a difference: it takes a call to the API
+	// server.
+	if len(cfg.Overrides) == 0 {
+		return quota
+	}
+	groups, err := s.callerGroups(ctx)
+	if err != nil {
+		// Failing the request would make Traceflows depend on the SelfSubjectReview API, when
+		// the default quota is a safe answer.
+		s.logger.Error(err, "Failed to get caller groups, applying the default Traceflow quota", "user", ra.Username)
+		return quota
+	}
+	for _, o := range cfg.Overrides {
+		if slices.ContainsFunc(o.Groups, func(g string) bool { return slices.Contains(groups, g) }) {
+			quota.maxPerHour = o.MaxPerHour
+			quota.maxConcurrent = o.MaxConcurrent
+			break
+		}
+	}
+	return quota
+}
+
+// reserveTraceflow takes one Traceflow from the caller's hourly budget, then from the global
+// one, and sets the RateLimit-* headers describing the caller's budget. The caller's budget is
+// checked first so that a user past their own quota does not use up the global one. It must only
+// be called once the request has been validated, and the returned reservation (nil when the
+// caller's budget is not limited) must be given back with cancelTraceflowReservation if the
+// Traceflow is not created after all.
+func (s *Server) reserveTraceflow(c *gin.Context, quota *traceflowQuota) (*ratelimit.Reservation, *errors.ServerError) {
+	now := time.Now()
+	var reservation *ratelimit.Reservation
+	if quota.maxPerHour >= 0 {
+		reservation = s.traceflowUserRateLimiter.Reserve(now, quota.user, quota.maxPerHour)
+		if !reservation.Allowed {
+			setTraceflowQuotaHeaders(c, &reservation.Status)
+			return nil, &errors.ServerError{
+				Code:    http.StatusTooManyRequests,
+				Message: fmt.Sprintf("Traceflow quota exceeded: at most %d Traceflows per hour", quota.maxPerHour),
+			}
+		}
+	}
+	if s.traceflowRateLimiter != nil && !s.traceflowRateLimiter.Allow(now, c.Request) {
+		// Not the caller's fault: give their Traceflow back.
+		s.cancelTraceflowReservation(c, reservation)
+		return nil, &errors.ServerError{
+			Code:    http.StatusTooManyRequests,
+			Message: "Traceflow rate limit exceeded",
+		}
+	}
+	if reservation != nil {
+		setTraceflowQuotaHeaders(c, &reservation.Status)
+	}
+	return reservation, nil
+}
+
+// cancelTraceflowReservation gives a Traceflow reserved with reserveTraceflow back to the caller's
+// budget, and updates the RateLimit-* headers to match.
+func (s *Server) cancelTraceflowReservation(c *gin.Context, reservation *ratelimit.Reservation) {
+	if reservation == nil {
+		return
+	}
+	reservation.Cancel(time.Now())
+	setTraceflowQuotaHeaders(c, &reservation.Status)
+}
+
+// reserveTraceflows takes n Traceflows at once from the caller's hourly budget, for a request that
+// creates several of them, and sets the RateLimit-* headers. Unlike reserveTraceflow, it does not
+// check the global limit, which the caller must wait for before creating each Traceflow: the
+// global budget is shared by everyone, so a large request waits for it rather than failing. It
+// fails unless the caller's budget allows all n Traceflows. The returned reservations (nil when
+// the caller's budget is not limited) must be given back with cancelTraceflowReservation for the
+// Traceflows that are not created.
+func (s *Server) reserveTraceflows(c *gin.Context, quota *traceflowQuota, n int) ([]*ratelimit.Reservation, *errors.ServerError) {
+	if quota.maxPerHour < 0 {
+		return nil, nil
+	}
+	reservations, status := s.traceflowUserRateLimiter.ReserveN(time.Now(), quota.user, quota.maxPerHour, n)
+	setTraceflowQuotaHeaders(c, &status)
+	if !status.Allowed {
+		return nil, &errors.ServerError{
+			Code:    http.StatusTooManyRequests,
+			Message: fmt.Sprintf("Traceflow quota exceeded: %d Traceflows needed, %d left of at most %d Traceflows per hour", n, status.Remaining, quota.maxPerHour),
+		}
+	}
+	return reservations, nil
+}
+
+func setTraceflowQuotaHeaders(c *gin.Context, status *ratelimit.Status) {
+	status.SetHeaders(c.Writer.Header())
+	c.Writer.Header().Add("Access-Control-Expose-Headers", "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After")
+}
+
+// checkRunningTraceflows fails if the caller already has quota.maxConcurrent Traceflows running.
+// It must be called with the caller's lock from lockTraceflowQuota held, until the new Traceflow
+// is created, so that concurrent requests cannot all see the same count.
+func (s *Server) checkRunningTraceflows(c *gin.Context, quota *traceflowQuota) *errors.ServerError {
+	if quota.user == "" || quota.maxConcurrent == 0 {
+		return nil
+	}
+	running, err := s.traceflowRequestsHandler.CountRunningRequests(c, quota.user)
+	if err != nil {
+		return &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("error when counting running Traceflows: %w", err),
+		}
+	}
+	if running >= quota.maxConcurrent {
+		c.Header("Retry-After", strconv.Itoa(int(traceflowConcurrencyRetryAfter.Seconds())))
+		return &errors.ServerError{
+			Code:    http.StatusTooManyRequests,
+			Message: fmt.Sprintf("Too many running Traceflows: at most %d at a time", quota.maxConcurrent),
+		}
+	}
+	return nil
+}
+
+// lockTraceflowQuota serializes the creation of the caller's Traceflows, from counting the running
+// ones to creating the new one, see checkRunningTraceflows. Only the requests of the same user
+// wait for each other, and only when their running Traceflows are limited. The returned function
+// releases the lock.
+func (s *Server) lockTraceflowQuota(quota *traceflowQuota) func() {
+	if quota.user == "" || quota.maxConcurrent == 0 {
+		return func() {}
+	}
+	return s.traceflowQuotaLocks.lock(quota.user)
+}
+
+// keyedMutex is a set of mutexes indexed by key, which only holds the ones in use.
+type keyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	sync.Mutex
+	// refs is the number of goroutines holding or waiting for the entry.
+	refs int
+}
+
+func (m *keyedMutex) lock(key string) func() {
+	m.mutex.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedMutexEntry)
+	}
+	entry, ok := m.locks[key]
+	if !ok {
+		entry = &keyedMutexEntry{}
+		m.locks[key] = entry
+	}
+	entry.refs++
+	m.mutex.Unlock()
+
+	entry.Lock()
+	return func() {
+		entry.Unlock()
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
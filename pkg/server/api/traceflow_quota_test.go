@@ -0,0 +1,254 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
+	serverconfig "antrea.io/antrea-ui/pkg/config/server"
+)
+
+func sendTraceflowRequestAs(ts *testServer, mode session.Mode) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(tfRequestJSON))
+	ts.authorizeRequestAs(req, mode)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestTraceflowQuotaPerHour(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, tfObjects, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: 2}))
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil).Times(2)
+
+	rr := sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=3600", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"), "Retry-After should be the polling interval")
+	assert.Contains(t, rr.Header().Values("Access-Control-Expose-Headers"), "Location, Retry-After")
+
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	// The next Traceflow is 30 minutes away.
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 1800, retryAfter, 1)
+	assert.Equal(t, string(mustMarshal("Traceflow quota exceeded: at most 2 Traceflows per hour")), rr.Body.String())
+
+	// Static-admin sessions are exempt.
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil)
+	rr = sendTraceflowRequestAs(ts, session.ModeAdmin)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+}
+
+func TestTraceflowQuotaGlobalLimit(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, tfObjects, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: 2}), setMaxTraceflowsPerHour(0))
+	rr := sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, string(mustMarshal("Traceflow rate limit exceeded")), rr.Body.String())
+	assert.Empty(t, rr.Header().Get("Retry-After"))
+	// The global limit is not the user's fault: their Traceflow is given back.
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func TestTraceflowQuotaOverrides(t *testing.T) {
+	ts, fakeAPIServer := newTestServerForTraceflow(t, tfObjects, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{
+		MaxPerHour: 0,
+		Overrides: []serverconfig.TraceflowQuotaOverride{
+			{Groups: []string{"oncall"}, MaxPerHour: 1},
+			{Groups: []string{"sre", "network-admins"}, MaxPerHour: -1},
+		},
+	}))
+
+	rr := sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Limit"))
+
+	fakeAPIServer.groups = []string{"system:authenticated", "network-admins", "oncall"}
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil)
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Limit"), "the first matching override should apply")
+
+	fakeAPIServer.groups = []string{"system:authenticated", "network-admins"}
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil).Times(3)
+	for i := 0; i < 3; i++ {
+		rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestTraceflowQuotaConcurrent(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, tfObjects, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: -1, MaxConcurrent: 2}))
+
+	ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(2, nil)
+	rr := sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("Retry-After"))
+	assert.Equal(t, string(mustMarshal("Too many running Traceflows: at most 2 at a time")), rr.Body.String())
+
+	ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(1, nil)
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil)
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+}
+
+func TestTraceflowQuotaFailedRequests(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, tfObjects, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: 1, MaxConcurrent: 1}))
+
+	// Requests rejected before a Traceflow is created do not count against the caller's budget.
+	req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader([]byte("{")))
+	ts.authorizeRequestAs(req, session.ModeOIDC)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Remaining"))
+
+	req = httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(mustMarshal(&apisv1.TraceflowRequest{})))
+	ts.authorizeRequestAs(req, session.ModeOIDC)
+	rr = httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(1, nil)
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Remaining"))
+
+	// A Traceflow which cannot be created is given back.
+	ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(0, nil)
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("some error"))
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+
+	ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(0, nil)
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.NewString(), nil)
+	rr = sendTraceflowRequestAs(ts, session.ModeOIDC)
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func TestTraceflowQuotaReachabilityMatrix(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, []string{"pods/a/client", "pods/b/web", "pods/b/db"}, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: 2}))
+	runMatrix := func(destinations ...apisv1.ReachabilityEndpoints) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/traceflow/matrix", bytes.NewBuffer(mustMarshal(&apisv1.ReachabilityMatrixRequest{
+			Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a", Pod: "client"}},
+			Destinations: destinations,
+		})))
+		ts.authorizeRequestAs(req, session.ModeOIDC)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+	web := apisv1.ReachabilityEndpoints{Namespace: "b", Pod: "web"}
+	db := apisv1.ReachabilityEndpoints{Namespace: "b", Pod: "db"}
+
+	// Traceflows that cannot be created are given back.
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return("", assert.AnError).Times(2)
+	rr := runMatrix(web, db)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var matrix apisv1.ReachabilityMatrix
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matrix))
+	assert.Equal(t, apisv1.ReachabilityError, matrix.Cells[0][0].Result)
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Remaining"))
+
+	// A matrix only runs if it fits in the quota.
+	expectReachabilityTraceflows(ts, func(string) map[string]interface{} {
+		return map[string]interface{}{"phase": "Succeeded"}
+	})
+	ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	rr = runMatrix(web)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	rr = runMatrix(web, db)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Traceflow quota exceeded")
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
+
+func TestTraceflowQuotaReachabilityMatrixConcurrent(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, []string{"pods/a/client", "pods/b/web"}, setTraceflowQuota(serverconfig.TraceflowQuotaConfig{MaxPerHour: -1, MaxConcurrent: 1}))
+	expectReachabilityTraceflows(ts, func(string) map[string]interface{} {
+		return map[string]interface{}{"phase": "Succeeded"}
+	})
+	ts.traceflowRequestsHandler.EXPECT().DeleteRequest(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	// The cell waits for another Traceflow of the caller to complete, instead of exceeding
+	// their quota.
+	gomock.InOrder(
+		ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(1, nil),
+		ts.traceflowRequestsHandler.EXPECT().CountRunningRequests(gomock.Any(), "tester").Return(0, nil),
+	)
+	req := httptest.NewRequest("POST", "/api/v1/traceflow/matrix", bytes.NewBuffer(mustMarshal(&apisv1.ReachabilityMatrixRequest{
+		Sources:      []apisv1.ReachabilityEndpoints{{Namespace: "a", Pod: "client"}},
+		Destinations: []apisv1.ReachabilityEndpoints{{Namespace: "b", Pod: "web"}},
+	})))
+	ts.authorizeRequestAs(req, session.ModeOIDC)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var matrix apisv1.ReachabilityMatrix
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &matrix))
+	assert.Equal(t, apisv1.ReachabilityAllowed, matrix.Cells[0][0].Result)
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var m keyedMutex
+	unlockAlice := m.lock("alice")
+	// Another user is not blocked.
+	unlockBob := m.lock("bob")
+	unlockBob()
+
+	locked := make(chan struct{})
+	go func() {
+		unlock := m.lock("alice")
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("alice's lock should be held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockAlice()
+	<-locked
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	assert.Empty(t, m.locks, "unused locks should be released")
+}
@@ -0,0 +1,362 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/server/errors"
+)
+
+const (
+	traceflowProtocolTCP  = "TCP"
+	traceflowProtocolUDP  = "UDP"
+	traceflowProtocolICMP = "ICMP"
+
+	traceflowMaxTimeout = 120
+	// traceflowMaxTitleLength keeps the title annotation to something a list can display.
+	traceflowMaxTitleLength = 256
+	// traceflowDefaultTCPFlags is SYN, which is what a client opening a connection sends.
+	traceflowDefaultTCPFlags = 2
+)
+
+// IP protocol numbers used in the Traceflow packet spec.
+const (
+	ipProtocolICMP   = 1
+	ipProtocolTCP    = 6
+	ipProtocolUDP    = 17
+	ipProtocolICMPv6 = 58
+)
+
+// validateTraceflowRequest normalizes req in place and checks it against the rules documented on
+// apisv1.TraceflowRequest. It returns every violated rule rather than only the first one, so that
+// a script author can fix them all at once. It does not talk to the K8s API; see
+// checkTraceflowEndpoints for that.
+func validateTraceflowRequest(req *apisv1.TraceflowRequest) []string {
+	var errs []string
+	src, dst := &req.Source, &req.Destination
+
+	req.Protocol = strings.ToUpper(req.Protocol)
+	if req.Protocol == "" {
+		req.Protocol = traceflowProtocolTCP
+	}
+	switch req.Protocol {
+	case traceflowProtocolTCP, traceflowProtocolUDP:
+		if req.SourcePort < 0 || req.SourcePort > 65535 {
+			errs = append(errs, "Source port must be between 0 and 65535")
+		}
+		if req.DestinationPort < 0 || req.DestinationPort > 65535 {
+			errs = append(errs, "Destination port must be between 0 and 65535")
+		}
+	case traceflowProtocolICMP:
+		if req.SourcePort != 0 || req.DestinationPort != 0 {
+			errs = append(errs, "Ports can only be set for TCP and UDP")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("Unsupported protocol %q, must be one of TCP, UDP or ICMP", req.Protocol))
+	}
+	if req.TCPFlags != nil {
+		if req.Protocol != traceflowProtocolTCP {
+			errs = append(errs, "TCP flags can only be set for TCP")
+		} else if *req.TCPFlags < 0 || *req.TCPFlags > 255 {
+			errs = append(errs, "TCP flags must be between 0 and 255")
+		}
+	}
+	if req.Timeout < 0 || req.Timeout > traceflowMaxTimeout {
+		errs = append(errs, fmt.Sprintf("Timeout must be between 1 and %d seconds", traceflowMaxTimeout))
+	}
+	if req.DroppedOnly && !req.LiveTraffic {
+		errs = append(errs, "Dropped-only requires live traffic")
+	}
+	if utf8.RuneCountInString(req.Title) > traceflowMaxTitleLength {
+		errs = append(errs, fmt.Sprintf("Title must be at most %d characters", traceflowMaxTitleLength))
+	}
+
+	if src.Pod != "" && src.IP != "" {
+		errs = append(errs, "Source must be either a Pod or an IP address, not both")
+	}
+	if src.IP != "" && !req.LiveTraffic {
+		errs = append(errs, "Source must be a Pod for a regular Traceflow")
+	}
+	if src.Pod != "" {
+		errs = append(errs, validateTraceflowObjectRef("Source", "Pod", src.Namespace, src.Pod, validation.IsDNS1123Subdomain)...)
+	} else if src.Namespace != "" {
+		errs = append(errs, "Source namespace can only be set with a Pod")
+	}
+
+	var dstKinds []string
+	for _, kind := range []struct {
+		name  string
+		value string
+	}{{"Pod", dst.Pod}, {"Service", dst.Service}, {"IP", dst.IP}, {"FQDN", dst.FQDN}} {
+		if kind.value != "" {
+			dstKinds = append(dstKinds, kind.name)
+		}
+	}
+	if len(dstKinds) > 1 {
+		errs = append(errs, fmt.Sprintf("Destination must be only one of a Pod, a Service, an IP address or an FQDN, got %s", strings.Join(dstKinds, ", ")))
+	}
+	switch {
+	case dst.Pod != "":
+		errs = append(errs, validateTraceflowObjectRef("Destination", "Pod", dst.Namespace, dst.Pod, validation.IsDNS1123Subdomain)...)
+	case dst.Service != "":
+		errs = append(errs, validateTraceflowObjectRef("Destination", "Service", dst.Namespace, dst.Service, validation.IsDNS1035Label)...)
+	case dst.Namespace != "":
+		errs = append(errs, "Destination namespace can only be set with a Pod or a Service")
+	}
+	if dst.FQDN != "" {
+		if fieldErrs := validation.IsDNS1123Subdomain(strings.TrimSuffix(dst.FQDN, ".")); len(fieldErrs) > 0 {
+			errs = append(errs, fmt.Sprintf("Invalid destination FQDN %q: %s", dst.FQDN, strings.Join(fieldErrs, "; ")))
+		}
+	}
+
+	srcSet := src.Pod != "" || src.IP != ""
+	dstSet := len(dstKinds) > 0
+	if !req.LiveTraffic {
+		if src.Pod == "" && src.IP == "" {
+			errs = append(errs, "Source Pod is required")
+		}
+		if !dstSet {
+			errs = append(errs, "Destination is required")
+		}
+	} else {
+		if !srcSet && !dstSet {
+			errs = append(errs, "At least one of source and destination is required")
+		} else if src.Pod == "" && dst.Pod == "" {
+			errs = append(errs, "At least one of source and destination must be a Pod")
+		}
+	}
+
+	srcV, srcErr := traceflowIPVersion(src.IP)
+	if srcErr {
+		errs = append(errs, fmt.Sprintf("Invalid source IP address %q", src.IP))
+	}
+	dstV, dstErr := traceflowIPVersion(dst.IP)
+	if dstErr {
+		errs = append(errs, fmt.Sprintf("Invalid destination IP address %q", dst.IP))
+	}
+	if srcV != 0 && dstV != 0 && srcV != dstV {
+		errs = append(errs, "IP version mismatch between source and destination")
+	}
+	if srcV == 4 && req.IPv6 {
+		errs = append(errs, "IPv6 cannot be set with an IPv4 source")
+	}
+	if dstV == 4 && req.IPv6 {
+		errs = append(errs, "IPv6 cannot be set with an IPv4 destination")
+	}
+	if srcV == 6 || dstV == 6 {
+		req.IPv6 = true
+	}
+	return errs
+}
+
+func validateTraceflowObjectRef(side, kind, namespace, name string, validateName func(string) []string) []string {
+	var errs []string
+	if namespace == "" {
+		errs = append(errs, fmt.Sprintf("%s namespace is required for a %s", side, kind))
+	} else if fieldErrs := validation.IsDNS1123Label(namespace); len(fieldErrs) > 0 {
+		errs = append(errs, fmt.Sprintf("Invalid %s namespace %q: %s", strings.ToLower(side), namespace, strings.Join(fieldErrs, "; ")))
+	}
+	if fieldErrs := validateName(name); len(fieldErrs) > 0 {
+		errs = append(errs, fmt.Sprintf("Invalid %s %s name %q: %s", strings.ToLower(side), kind, name, strings.Join(fieldErrs, "; ")))
+	}
+	return errs
+}
+
+// traceflowIPVersion returns 4 or 6 for a valid address, 0 for an empty one, and invalid=true
+// otherwise.
+func traceflowIPVersion(s string) (version int, invalid bool) {
+	if s == "" {
+		return 0, false
+	}
+	ip := net.ParseIP(s)
+	switch {
+	case ip == nil:
+		return 0, true
+	case ip.To4() != nil:
+		return 4, false
+	default:
+		return 6, false
+	}
+}
+
+// checkTraceflowEndpoints verifies, as the caller, that the Pods and Service a validated request
+// refers to exist, so that a typo is reported right away rather than as a failed Traceflow. A
+// caller who may not read them can still run the Traceflow: Antrea does not require it, so a 403
+// skips the check.
+func (s *Server) checkTraceflowEndpoints(c *gin.Context, req *apisv1.TraceflowRequest) *errors.ServerError {
+	ctx := c.Request.Context()
+	client, err := s.clientFactory.KubernetesClientForRequest(ctx)
+	if err != nil {
+		return &errors.ServerError{
+			Code: http.StatusInternalServerError,
+			Err:  fmt.Errorf("failed to build K8s client for request: %w", err),
+		}
+	}
+	type objectRef struct {
+		side, kind, namespace, name string
+		get                         func(context.Context, kubernetes.Interface, string, string) error
+	}
+	getPod := func(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+		_, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+	getService := func(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+		_, err := client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+	var refs []objectRef
+	if req.Source.Pod != "" {
+		refs = append(refs, objectRef{"Source", "Pod", req.Source.Namespace, req.Source.Pod, getPod})
+	}
+	if req.Destination.Pod != "" {
+		refs = append(refs, objectRef{"Destination", "Pod", req.Destination.Namespace, req.Destination.Pod, getPod})
+	}
+	if req.Destination.Service != "" {
+		refs = append(refs, objectRef{"Destination", "Service", req.Destination.Namespace, req.Destination.Service, getService})
+	}
+	var notFound []string
+	for _, ref := range refs {
+		err := ref.get(ctx, client, ref.namespace, ref.name)
+		switch {
+		case err == nil, apierrors.IsForbidden(err):
+		case apierrors.IsNotFound(err):
+			notFound = append(notFound, fmt.Sprintf("%s %s %s/%s not found", ref.side, ref.kind, ref.namespace, ref.name))
+		default:
+			return s.k8sError(c, err, fmt.Sprintf("error when checking %s %s", strings.ToLower(ref.side), ref.kind))
+		}
+	}
+	if len(notFound) > 0 {
+		return &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: strings.Join(notFound, "; "),
+		}
+	}
+	return nil
+}
+
+// resolveTraceflowFQDN resolves the destination FQDN of a validated request to an address of the
+// request's IP family. Antrea only traces to IP addresses, so the name is resolved once, here, and
+// the Traceflow targets the first address returned.
+func (s *Server) resolveTraceflowFQDN(ctx context.Context, req *apisv1.TraceflowRequest) (string, *errors.ServerError) {
+	network, family := "ip4", "IPv4"
+	if req.IPv6 {
+		network, family = "ip6", "IPv6"
+	}
+	ips, err := s.lookupIP(ctx, network, req.Destination.FQDN)
+	if err != nil || len(ips) == 0 {
+		message := fmt.Sprintf("Cannot resolve destination FQDN %q to an %s address", req.Destination.FQDN, family)
+		if err != nil {
+			message = fmt.Sprintf("%s: %v", message, err)
+		}
+		return "", &errors.ServerError{
+			Code:    http.StatusBadRequest,
+			Message: message,
+		}
+	}
+	return ips[0].String(), nil
+}
+
+// traceflowSpec translates a validated request into the spec of an Antrea Traceflow CR.
+// dstIP is the destination address, taken from the request or resolved from its FQDN.
+func traceflowSpec(req *apisv1.TraceflowRequest, dstIP string) map[string]interface{} {
+	source := map[string]interface{}{}
+	if req.Source.Pod != "" {
+		source["namespace"] = req.Source.Namespace
+		source["pod"] = req.Source.Pod
+	} else if req.Source.IP != "" {
+		source["ip"] = req.Source.IP
+	}
+	destination := map[string]interface{}{}
+	switch {
+	case req.Destination.Pod != "":
+		destination["namespace"] = req.Destination.Namespace
+		destination["pod"] = req.Destination.Pod
+	case req.Destination.Service != "":
+		destination["namespace"] = req.Destination.Namespace
+		destination["service"] = req.Destination.Service
+	case dstIP != "":
+		destination["ip"] = dstIP
+	}
+
+	ports := func() map[string]interface{} {
+		h := map[string]interface{}{}
+		if req.SourcePort > 0 {
+			h["srcPort"] = int64(req.SourcePort)
+		}
+		if req.DestinationPort > 0 {
+			h["dstPort"] = int64(req.DestinationPort)
+		}
+		return h
+	}
+	var protocol int64
+	transportHeader := map[string]interface{}{}
+	switch req.Protocol {
+	case traceflowProtocolTCP:
+		protocol = ipProtocolTCP
+		tcp := ports()
+		if req.TCPFlags != nil {
+			tcp["flags"] = int64(*req.TCPFlags)
+		} else if !req.LiveTraffic {
+			tcp["flags"] = int64(traceflowDefaultTCPFlags)
+		}
+		transportHeader["tcp"] = tcp
+	case traceflowProtocolUDP:
+		protocol = ipProtocolUDP
+		transportHeader["udp"] = ports()
+	case traceflowProtocolICMP:
+		protocol = ipProtocolICMP
+		if req.IPv6 {
+			protocol = ipProtocolICMPv6
+		}
+		transportHeader["icmp"] = map[string]interface{}{}
+	}
+	packet := map[string]interface{}{
+		"transportHeader": transportHeader,
+	}
+	if req.IPv6 {
+		packet["ipv6Header"] = map[string]interface{}{"nextHeader": protocol}
+	} else {
+		packet["ipHeader"] = map[string]interface{}{"protocol": protocol}
+	}
+
+	spec := map[string]interface{}{
+		"source":      source,
+		"destination": destination,
+		"packet":      packet,
+	}
+	if req.LiveTraffic {
+		spec["liveTraffic"] = true
+	}
+	if req.DroppedOnly {
+		spec["droppedOnly"] = true
+	}
+	if req.Timeout > 0 {
+		spec["timeout"] = int64(req.Timeout)
+	}
+	return spec
+}
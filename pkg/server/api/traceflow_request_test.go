@@ -0,0 +1,329 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+)
+
+func TestValidateTraceflowRequest(t *testing.T) {
+	podX := apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"}
+	podY := apisv1.TraceflowDestination{Namespace: "default", Pod: "pod-y"}
+	testCases := []struct {
+		name         string
+		req          apisv1.TraceflowRequest
+		expectedErrs []string
+	}{
+		{
+			name: "Pod to Pod",
+			req:  apisv1.TraceflowRequest{Source: podX, Destination: podY},
+		},
+		{
+			name: "live traffic from an IP",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "10.0.0.1"},
+				Destination: podY,
+				LiveTraffic: true,
+				DroppedOnly: true,
+			},
+		},
+		{
+			name: "live traffic to a Pod only",
+			req:  apisv1.TraceflowRequest{Destination: podY, LiveTraffic: true},
+		},
+		{
+			name:         "missing endpoints",
+			req:          apisv1.TraceflowRequest{},
+			expectedErrs: []string{"Source Pod is required", "Destination is required"},
+		},
+		{
+			name:         "missing endpoints for live traffic",
+			req:          apisv1.TraceflowRequest{LiveTraffic: true},
+			expectedErrs: []string{"At least one of source and destination is required"},
+		},
+		{
+			name: "no Pod for live traffic",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "10.0.0.1"},
+				Destination: apisv1.TraceflowDestination{IP: "10.0.0.2"},
+				LiveTraffic: true,
+			},
+			expectedErrs: []string{"At least one of source and destination must be a Pod"},
+		},
+		{
+			name: "IP source for a regular Traceflow",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "10.0.0.1"},
+				Destination: podY,
+			},
+			expectedErrs: []string{"Source must be a Pod for a regular Traceflow"},
+		},
+		{
+			name: "invalid names",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{Pod: "pod-x"},
+				Destination: apisv1.TraceflowDestination{Namespace: "Default", Service: "1svc"},
+			},
+			expectedErrs: []string{
+				"Source namespace is required for a Pod",
+				`Invalid destination namespace "Default": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+				`Invalid destination Service name "1svc": a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')`,
+			},
+		},
+		{
+			name: "several destinations",
+			req: apisv1.TraceflowRequest{
+				Source:      podX,
+				Destination: apisv1.TraceflowDestination{Namespace: "default", Pod: "pod-y", IP: "10.0.0.2"},
+			},
+			expectedErrs: []string{"Destination must be only one of a Pod, a Service, an IP address or an FQDN, got Pod, IP"},
+		},
+		{
+			name: "invalid packet",
+			req: apisv1.TraceflowRequest{
+				Source:          podX,
+				Destination:     podY,
+				Protocol:        "icmp",
+				DestinationPort: 80,
+				TCPFlags:        ptr.To[int32](2),
+				Timeout:         121,
+				DroppedOnly:     true,
+			},
+			expectedErrs: []string{
+				"Ports can only be set for TCP and UDP",
+				"TCP flags can only be set for TCP",
+				"Timeout must be between 1 and 120 seconds",
+				"Dropped-only requires live traffic",
+			},
+		},
+		{
+			name: "out of range values",
+			req: apisv1.TraceflowRequest{
+				Source:          podX,
+				Destination:     podY,
+				SourcePort:      -1,
+				DestinationPort: 65536,
+				TCPFlags:        ptr.To[int32](256),
+			},
+			expectedErrs: []string{
+				"Source port must be between 0 and 65535",
+				"Destination port must be between 0 and 65535",
+				"TCP flags must be between 0 and 255",
+			},
+		},
+		{
+			name:         "unsupported protocol",
+			req:          apisv1.TraceflowRequest{Source: podX, Destination: podY, Protocol: "SCTP"},
+			expectedErrs: []string{`Unsupported protocol "SCTP", must be one of TCP, UDP or ICMP`},
+		},
+		{
+			name: "IP versions",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "fd00::1"},
+				Destination: apisv1.TraceflowDestination{IP: "10.0.0.2"},
+				IPv6:        true,
+				LiveTraffic: true,
+			},
+			expectedErrs: []string{
+				"At least one of source and destination must be a Pod",
+				"IP version mismatch between source and destination",
+				"IPv6 cannot be set with an IPv4 destination",
+			},
+		},
+		{
+			name: "invalid IP",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "10.0.0"},
+				Destination: podY,
+				LiveTraffic: true,
+			},
+			expectedErrs: []string{`Invalid source IP address "10.0.0"`},
+		},
+		{
+			name: "invalid FQDN",
+			req: apisv1.TraceflowRequest{
+				Source:      podX,
+				Destination: apisv1.TraceflowDestination{FQDN: "-example.com"},
+			},
+			expectedErrs: []string{`Invalid destination FQDN "-example.com": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedErrs, validateTraceflowRequest(&tc.req))
+		})
+	}
+}
+
+func TestTraceflowSpec(t *testing.T) {
+	podX := apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"}
+	testCases := []struct {
+		name         string
+		req          apisv1.TraceflowRequest
+		expectedSpec map[string]interface{}
+	}{
+		{
+			name: "UDP to a Service",
+			req: apisv1.TraceflowRequest{
+				Source:          podX,
+				Destination:     apisv1.TraceflowDestination{Namespace: "kube-system", Service: "kube-dns"},
+				Protocol:        "udp",
+				DestinationPort: 53,
+				Timeout:         10,
+			},
+			expectedSpec: map[string]interface{}{
+				"source":      map[string]interface{}{"namespace": "default", "pod": "pod-x"},
+				"destination": map[string]interface{}{"namespace": "kube-system", "service": "kube-dns"},
+				"packet": map[string]interface{}{
+					"ipHeader":        map[string]interface{}{"protocol": int64(17)},
+					"transportHeader": map[string]interface{}{"udp": map[string]interface{}{"dstPort": int64(53)}},
+				},
+				"timeout": int64(10),
+			},
+		},
+		{
+			name: "live ICMPv6 from an IP",
+			req: apisv1.TraceflowRequest{
+				Source:      apisv1.TraceflowSource{IP: "fd00::1"},
+				Destination: apisv1.TraceflowDestination{Namespace: "default", Pod: "pod-y"},
+				Protocol:    "ICMP",
+				LiveTraffic: true,
+				DroppedOnly: true,
+			},
+			expectedSpec: map[string]interface{}{
+				"source":      map[string]interface{}{"ip": "fd00::1"},
+				"destination": map[string]interface{}{"namespace": "default", "pod": "pod-y"},
+				"packet": map[string]interface{}{
+					"ipv6Header":      map[string]interface{}{"nextHeader": int64(58)},
+					"transportHeader": map[string]interface{}{"icmp": map[string]interface{}{}},
+				},
+				"liveTraffic": true,
+				"droppedOnly": true,
+			},
+		},
+		{
+			name: "live TCP matches any flags by default",
+			req: apisv1.TraceflowRequest{
+				Source:      podX,
+				LiveTraffic: true,
+			},
+			expectedSpec: map[string]interface{}{
+				"source":      map[string]interface{}{"namespace": "default", "pod": "pod-x"},
+				"destination": map[string]interface{}{},
+				"packet": map[string]interface{}{
+					"ipHeader":        map[string]interface{}{"protocol": int64(6)},
+					"transportHeader": map[string]interface{}{"tcp": map[string]interface{}{}},
+				},
+				"liveTraffic": true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Empty(t, validateTraceflowRequest(&tc.req))
+			assert.Equal(t, tc.expectedSpec, traceflowSpec(&tc.req, tc.req.Destination.IP))
+		})
+	}
+}
+
+func TestCreateTraceflowRequestValidation(t *testing.T) {
+	sendRequest := func(ts *testServer, tfReq *apisv1.TraceflowRequest) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(mustMarshal(tfReq)))
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		rr := sendRequest(ts, &apisv1.TraceflowRequest{Source: tfRequest.Source})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, `"Destination is required"`, rr.Body.String())
+	})
+
+	t.Run("Traceflow CR body", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(mustMarshal(tf)))
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "The body must be a TraceflowRequest")
+	})
+
+	t.Run("missing objects", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, []string{"pods/default/pod-x"})
+		rr := sendRequest(ts, &tfRequest)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, `"Destination Pod default/pod-y not found"`, rr.Body.String())
+	})
+
+	t.Run("objects the caller cannot get", func(t *testing.T) {
+		ts, fakeAPIServer := newTestServerForTraceflow(t, []string{"pods/default/pod-x"})
+		fakeAPIServer.forbidden["pods/default/pod-y"] = true
+		ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &traceflowhandler.Request{
+			Object:   tf,
+			Username: "tester",
+		}).Return(uuid.NewString(), nil)
+		rr := sendRequest(ts, &tfRequest)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+	})
+
+	t.Run("FQDN", func(t *testing.T) {
+		ts, _ := newTestServerForTraceflow(t, tfObjects)
+		ts.s.lookupIP = func(_ context.Context, network, host string) ([]net.IP, error) {
+			if network == "ip4" && host == "example.com" {
+				return []net.IP{net.ParseIP("192.0.2.10")}, nil
+			}
+			return nil, fmt.Errorf("no such host")
+		}
+		spec := traceflowSpec(&apisv1.TraceflowRequest{Source: tfRequest.Source, Protocol: "TCP"}, "192.0.2.10")
+		ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &traceflowhandler.Request{
+			Object:   map[string]interface{}{"spec": spec},
+			Username: "tester",
+		}).Return(uuid.NewString(), nil)
+		rr := sendRequest(ts, &apisv1.TraceflowRequest{
+			Source:      tfRequest.Source,
+			Destination: apisv1.TraceflowDestination{FQDN: "example.com"},
+		})
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+
+		rr = sendRequest(ts, &apisv1.TraceflowRequest{
+			Source:      tfRequest.Source,
+			Destination: apisv1.TraceflowDestination{FQDN: "example.com"},
+			IPv6:        true,
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, `"Cannot resolve destination FQDN \"example.com\" to an IPv6 address: no such host"`, rr.Body.String())
+	})
+}
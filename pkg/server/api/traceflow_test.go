@@ -15,10 +15,15 @@
 package api
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,8 +31,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 
+	apisv1 "antrea.io/antrea-ui/apis/v1"
+	"antrea.io/antrea-ui/pkg/auth/session"
 	traceflowhandler "antrea.io/antrea-ui/pkg/handlers/traceflow"
+	"antrea.io/antrea-ui/pkg/k8s"
 )
 
 func mustMarshal(obj interface{}) []byte {
@@ -38,36 +52,189 @@ func mustMarshal(obj interface{}) []byte {
 	return b
 }
 
+// fakeTraceflowK8sAPIServer answers the Pod and Service GETs made to check the endpoints of a
+// Traceflow request.
+type fakeTraceflowK8sAPIServer struct {
+	*httptest.Server
+	// objects holds the paths of existing objects, e.g. "pods/default/pod-x".
+	objects map[string]bool
+	// forbidden holds the paths of objects the caller may not get.
+	forbidden map[string]bool
+	// labels holds the labels of objects, which lists can select. Listed Pods are running.
+	labels map[string]map[string]string
+	// nodeNames holds the Nodes of Pods, returned in their spec.
+	nodeNames map[string]string
+	// groups are the caller's groups, as returned by a SelfSubjectReview.
+	groups []string
+	// allowed holds the access the caller has, as answered by a SelfSubjectAccessReview, e.g.
+	// "create traceflows" or "get pods default/pod-x".
+	allowed map[string]bool
+}
+
+func newFakeTraceflowK8sAPIServer(t *testing.T, objects ...string) *fakeTraceflowK8sAPIServer {
+	f := &fakeTraceflowK8sAPIServer{
+		objects:   map[string]bool{},
+		forbidden: map[string]bool{},
+		labels:    map[string]map[string]string{},
+		nodeNames: map[string]string{},
+		allowed:   map[string]bool{},
+	}
+	for _, o := range objects {
+		f.objects[o] = true
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/selfsubjectreviews") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"apiVersion": "authentication.k8s.io/v1",
+				"kind":       "SelfSubjectReview",
+				"status": map[string]interface{}{
+					"userInfo": map[string]interface{}{"groups": f.groups},
+				},
+			})
+			return
+		}
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/selfsubjectaccessreviews") {
+			var review authorizationv1.SelfSubjectAccessReview
+			if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Spec.ResourceAttributes == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			attrs := review.Spec.ResourceAttributes
+			key := attrs.Verb + " " + attrs.Resource
+			if attrs.Name != "" {
+				key += " " + attrs.Namespace + "/" + attrs.Name
+			}
+			review.Status.Allowed = f.allowed[key]
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(review)
+			return
+		}
+		// /api/v1/namespaces/<ns>/<resource>/<name>, or /api/v1/namespaces/<ns>/<resource> for
+		// a list.
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/"), "/")
+		if r.Method == http.MethodGet && len(parts) == 2 {
+			f.serveList(w, r, parts[0], parts[1])
+			return
+		}
+		if r.Method != http.MethodGet || len(parts) != 3 {
+			t.Logf("unexpected request to fake K8s API server: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		namespace, resource, name := parts[0], parts[1], parts[2]
+		key := resource + "/" + namespace + "/" + name
+		gr := schema.GroupResource{Resource: resource}
+		var status *apierrors.StatusError
+		switch {
+		case f.forbidden[key]:
+			status = apierrors.NewForbidden(gr, name, fmt.Errorf("access denied"))
+		case !f.objects[key]:
+			status = apierrors.NewNotFound(gr, name)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if status != nil {
+			w.WriteHeader(int(status.ErrStatus.Code))
+			_ = json.NewEncoder(w).Encode(status.ErrStatus)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"apiVersion": "v1",
+			"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+			"spec":       map[string]interface{}{"nodeName": f.nodeNames[key]},
+		})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeTraceflowK8sAPIServer) serveList(w http.ResponseWriter, r *http.Request, namespace, resource string) {
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	items := []interface{}{}
+	for key := range f.objects {
+		parts := strings.Split(key, "/")
+		if parts[0] != resource || parts[1] != namespace || !selector.Matches(labels.Set(f.labels[key])) {
+			continue
+		}
+		items = append(items, map[string]interface{}{
+			"metadata": map[string]interface{}{"namespace": namespace, "name": parts[2], "labels": f.labels[key]},
+			"status":   map[string]interface{}{"phase": "Running"},
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	// Only Pods are listed in tests.
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "PodList",
+		"metadata":   map[string]interface{}{},
+		"items":      items,
+	})
+}
+
+// newTestServerForTraceflow builds a Server whose ClientFactory talks to a fake K8s API server in
+// which the given objects exist.
+func newTestServerForTraceflow(t *testing.T, objects []string, options ...testServerOptions) (*testServer, *fakeTraceflowK8sAPIServer) {
+	ts := newTestServer(t, options...)
+	fakeAPIServer := newFakeTraceflowK8sAPIServer(t, objects...)
+	clientFactory, err := k8s.NewClientFactory(&rest.Config{
+		Host:          fakeAPIServer.URL,
+		ContentConfig: rest.ContentConfig{ContentType: "application/json"},
+	}, http.DefaultTransport, session.TransportKeyK8s)
+	require.NoError(t, err)
+	ts.s.clientFactory = clientFactory
+	return ts, fakeAPIServer
+}
+
 var (
+	tfRequest = apisv1.TraceflowRequest{
+		Source:      apisv1.TraceflowSource{Namespace: "default", Pod: "pod-x"},
+		Destination: apisv1.TraceflowDestination{Namespace: "default", Pod: "pod-y"},
+	}
+	tfObjects = []string{"pods/default/pod-x", "pods/default/pod-y"}
+
+	tfRequestJSON = mustMarshal(tfRequest)
+
+	// tf is the Traceflow that tfRequest translates to.
 	tf = map[string]interface{}{
 		"spec": map[string]interface{}{
 			"source": map[string]interface{}{
 				"namespace": "default",
-				"pod":       "podX",
+				"pod":       "pod-x",
 			},
 			"destination": map[string]interface{}{
 				"namespace": "default",
-				"pod":       "podY",
+				"pod":       "pod-y",
+			},
+			"packet": map[string]interface{}{
+				"ipHeader": map[string]interface{}{"protocol": int64(6)},
+				"transportHeader": map[string]interface{}{
+					"tcp": map[string]interface{}{"flags": int64(2)},
+				},
 			},
 		},
 	}
-
-	tfJSON = mustMarshal(tf)
 )
 
 func TestTraceflowRequest(t *testing.T) {
-	ts := newTestServer(t)
+	ts, _ := newTestServerForTraceflow(t, tfObjects)
 
 	// create traceflow request
-	req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(tfJSON))
+	req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(tfRequestJSON))
 	ts.authorizeRequest(req)
 	rr := httptest.NewRecorder()
 	requestID := uuid.NewString()
 	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &traceflowhandler.Request{
-		Object: tf,
+		Object:   tf,
+		Username: "tester",
 	}).Return(requestID, nil)
 	ts.router.ServeHTTP(rr, req)
-	require.Equal(t, http.StatusAccepted, rr.Code)
+	require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
 	resp := rr.Result()
 	url, err := resp.Location()
 	require.NoError(t, err)
@@ -124,9 +291,7 @@ func TestTraceflowRequest(t *testing.T) {
 	ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(tfResult, true, nil)
 	ts.router.ServeHTTP(rr, req)
 	require.Equal(t, http.StatusOK, rr.Code)
-	var result map[string]interface{}
-	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
-	assert.Equal(t, tfResult, result)
+	assert.JSONEq(t, string(mustMarshal(tfResult)), rr.Body.String())
 
 	// delete request
 	req = httptest.NewRequest("DELETE", reqURI, nil)
@@ -139,7 +304,7 @@ func TestTraceflowRequest(t *testing.T) {
 
 func TestTraceflowRequestRateLimiting(t *testing.T) {
 	sendRequest := func(ts *testServer) *httptest.ResponseRecorder {
-		req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(tfJSON))
+		req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(tfRequestJSON))
 		rr := httptest.NewRecorder()
 		ts.authorizeRequest(req)
 		ts.router.ServeHTTP(rr, req)
@@ -147,15 +312,16 @@ func TestTraceflowRequestRateLimiting(t *testing.T) {
 	}
 
 	t.Run("0/s", func(t *testing.T) {
-		ts := newTestServer(t, setMaxTraceflowsPerHour(0))
+		ts, _ := newTestServerForTraceflow(t, tfObjects, setMaxTraceflowsPerHour(0))
 		rr := sendRequest(ts)
 		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
 	})
 
 	t.Run("5/s", func(t *testing.T) {
-		ts := newTestServer(t, setMaxTraceflowsPerHour(5*3600))
+		ts, _ := newTestServerForTraceflow(t, tfObjects, setMaxTraceflowsPerHour(5*3600))
 		ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &traceflowhandler.Request{
-			Object: tf,
+			Object:   tf,
+			Username: "tester",
 		}).Return(uuid.NewString(), nil).AnyTimes()
 		rr := sendRequest(ts)
 		assert.Equal(t, http.StatusAccepted, rr.Code)
@@ -169,3 +335,315 @@ func TestTraceflowRequestRateLimiting(t *testing.T) {
 		}, time.Second, 100*time.Millisecond)
 	})
 }
+
+func TestTraceflowRequestTitle(t *testing.T) {
+	ts, _ := newTestServerForTraceflow(t, tfObjects)
+	tfReq := tfRequest
+	tfReq.Title = "checking the web tier"
+	ts.traceflowRequestsHandler.EXPECT().CreateRequest(gomock.Any(), gomock.Any(), &traceflowhandler.Request{
+		Object:   tf,
+		Username: "tester",
+		Title:    "checking the web tier",
+	}).Return(uuid.NewString(), nil)
+	req := httptest.NewRequest("POST", "/api/v1/traceflow", bytes.NewReader(mustMarshal(tfReq)))
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+}
+
+func TestTraceflowRequestResultResolvePolicies(t *testing.T) {
+	requestID := uuid.NewString()
+	sendRequest := func(ts *testServer, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/traceflow/%s/result%s", requestID, query), nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+	result := func() map[string]interface{} {
+		return map[string]interface{}{
+			"status": map[string]interface{}{
+				"phase": "Succeeded",
+				"results": []interface{}{map[string]interface{}{
+					"observations": []interface{}{map[string]interface{}{"networkPolicy": "K8sNetworkPolicy:web/default-deny"}},
+				}},
+			},
+		}
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		ts := newTestServer(t)
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(result(), true, nil)
+		ts.traceflowRequestsHandler.EXPECT().ResolvePolicies(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ dynamic.Interface, traceflow map[string]interface{}) error {
+				observation := traceflow["status"].(map[string]interface{})["results"].([]interface{})[0].(map[string]interface{})["observations"].([]interface{})[0].(map[string]interface{})
+				observation["policyDetails"] = map[string]interface{}{"kind": "K8sNetworkPolicy", "changedSinceTraceflow": true}
+				return nil
+			})
+		rr := sendRequest(ts, "?resolvePolicies=true")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"policyDetails":{"changedSinceTraceflow":true,"kind":"K8sNetworkPolicy"}`)
+	})
+
+	t.Run("not resolved", func(t *testing.T) {
+		ts := newTestServer(t)
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(result(), true, nil)
+		rr := sendRequest(ts, "?resolvePolicies=false")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, string(mustMarshal(result())), rr.Body.String())
+	})
+
+	t.Run("session expired", func(t *testing.T) {
+		ts := newTestServer(t)
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(result(), true, nil)
+		ts.traceflowRequestsHandler.EXPECT().ResolvePolicies(gomock.Any(), gomock.Any(), gomock.Any()).Return(apierrors.NewUnauthorized("token expired"))
+		rr := sendRequest(ts, "?resolvePolicies=true")
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		ts := newTestServer(t)
+		rr := sendRequest(ts, "?resolvePolicies=maybe")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestGetTraceflowGCStats(t *testing.T) {
+	ts := newTestServer(t)
+	stats := apisv1.TraceflowGCStats{
+		InstanceID:     "antrea-ui",
+		ExpiryTimeout:  3600,
+		Period:         60,
+		Runs:           3,
+		LastRun:        "2026-10-18T10:00:00Z",
+		LastRunDeleted: 1,
+		TotalDeleted:   4,
+	}
+	ts.traceflowRequestsHandler.EXPECT().GCStats().Return(stats)
+	req := httptest.NewRequest("GET", "/api/v1/traceflow/gc", nil)
+	ts.authorizeRequest(req)
+	rr := httptest.NewRecorder()
+	ts.router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, string(mustMarshal(stats)), rr.Body.String())
+}
+
+func TestListTraceflowRequests(t *testing.T) {
+	summaries := []apisv1.TraceflowSummary{
+		{ID: "tf-2", CreatedBy: "bob", CreationTimestamp: "2026-10-18T10:00:00Z"},
+		{ID: "tf-1", CreatedBy: "tester", Title: "checking the web tier", CreationTimestamp: "2026-10-17T10:00:00Z", Pinned: true},
+	}
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIDs  []string
+	}{
+		{
+			name:         "all",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []string{"tf-2", "tf-1"},
+		},
+		{
+			name:         "mine",
+			query:        "?mine=true",
+			expectedCode: http.StatusOK,
+			expectedIDs:  []string{"tf-1"},
+		},
+		{
+			name:         "invalid filter",
+			query:        "?mine=maybe",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			if tc.expectedCode == http.StatusOK {
+				ts.traceflowRequestsHandler.EXPECT().ListRequests(gomock.Any(), gomock.Any()).Return(summaries, nil)
+			}
+			req := httptest.NewRequest("GET", "/api/v1/traceflow"+tc.query, nil)
+			ts.authorizeRequest(req)
+			rr := httptest.NewRecorder()
+			ts.router.ServeHTTP(rr, req)
+			require.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var result []apisv1.TraceflowSummary
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+			ids := []string{}
+			for _, summary := range result {
+				ids = append(ids, summary.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestPinTraceflowRequest(t *testing.T) {
+	ts := newTestServer(t)
+	requestID := uuid.NewString()
+	sendRequest := func(method string, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/traceflow/"+id+"/pin", nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	ts.traceflowRequestsHandler.EXPECT().SetRequestPinned(gomock.Any(), gomock.Any(), requestID, true).Return(true, nil)
+	assert.Equal(t, http.StatusOK, sendRequest("PUT", requestID).Code)
+	ts.traceflowRequestsHandler.EXPECT().SetRequestPinned(gomock.Any(), gomock.Any(), requestID, false).Return(true, nil)
+	assert.Equal(t, http.StatusOK, sendRequest("DELETE", requestID).Code)
+	ts.traceflowRequestsHandler.EXPECT().SetRequestPinned(gomock.Any(), gomock.Any(), "missing", true).Return(false, nil)
+	assert.Equal(t, http.StatusNotFound, sendRequest("PUT", "missing").Code)
+}
+
+func TestStreamTraceflowRequest(t *testing.T) {
+	// httptest.ResponseRecorder does not implement http.CloseNotifier, which gin's Stream needs,
+	// so the stream is read from a real server.
+	openStream := func(t *testing.T, ts *testServer, requestID string) *http.Response {
+		srv := httptest.NewServer(ts.router)
+		t.Cleanup(srv.Close)
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		t.Cleanup(cancel)
+		req, err := http.NewRequestWithContext(ctx, "GET", srv.URL+"/api/v1/traceflow/"+requestID+"/stream", nil)
+		require.NoError(t, err)
+		ts.authorizeRequest(req)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	withPhase := func(phase string) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "foo"},
+			"status":   map[string]interface{}{"phase": phase},
+		}
+	}
+
+	t.Run("result", func(t *testing.T) {
+		ts := newTestServer(t)
+		requestID := uuid.NewString()
+		updates := make(chan traceflowhandler.RequestUpdate, 4)
+		updates <- traceflowhandler.RequestUpdate{Object: withPhase("")}
+		updates <- traceflowhandler.RequestUpdate{Object: withPhase("Running")}
+		// Same phase: no new status event.
+		updates <- traceflowhandler.RequestUpdate{Object: withPhase("Running")}
+		updates <- traceflowhandler.RequestUpdate{Object: withPhase("Succeeded"), Done: true}
+		ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), gomock.Any(), requestID).Return((<-chan traceflowhandler.RequestUpdate)(updates), nil)
+		resp := openStream(t, ts, requestID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			`event:status`, `data:{"phase":""}`, ``,
+			`event:status`, `data:{"phase":"Running"}`, ``,
+			`event:status`, `data:{"phase":"Succeeded"}`, ``,
+			`event:result`, `data:` + string(mustMarshal(withPhase("Succeeded"))), ``,
+		}, "\n")+"\n", string(body))
+	})
+
+	t.Run("watch error", func(t *testing.T) {
+		ts := newTestServer(t)
+		requestID := uuid.NewString()
+		updates := make(chan traceflowhandler.RequestUpdate, 2)
+		updates <- traceflowhandler.RequestUpdate{Object: withPhase("Running")}
+		updates <- traceflowhandler.RequestUpdate{Err: fmt.Errorf("traceflow was deleted before completing")}
+		ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), gomock.Any(), requestID).Return((<-chan traceflowhandler.RequestUpdate)(updates), nil)
+		resp := openStream(t, ts, requestID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "event:error\ndata:{\"message\":\"traceflow was deleted before completing\"}\n")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ts := newTestServer(t)
+		requestID := uuid.NewString()
+		ts.traceflowRequestsHandler.EXPECT().WatchRequest(gomock.Any(), gomock.Any(), requestID).Return(nil, apierrors.NewNotFound(schema.GroupResource{Group: "crd.antrea.io", Resource: "traceflows"}, requestID))
+		resp := openStream(t, ts, requestID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestTraceflowRequestDiagramAndExport(t *testing.T) {
+	ts := newTestServer(t)
+	requestID := uuid.NewString()
+	tfResult := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": requestID},
+		"spec":     tf["spec"],
+		"status": map[string]interface{}{
+			"phase": "Succeeded",
+			"results": []interface{}{map[string]interface{}{
+				"node": "node-a",
+				"observations": []interface{}{
+					map[string]interface{}{"component": "SpoofGuard", "action": "Forwarded"},
+					map[string]interface{}{"component": "Forwarding", "componentInfo": "Output", "action": "Delivered"},
+				},
+			}},
+		},
+	}
+	sendRequest := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/traceflow/"+requestID+path, nil)
+		ts.authorizeRequest(req)
+		rr := httptest.NewRecorder()
+		ts.router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("diagram", func(t *testing.T) {
+		for _, tc := range []struct {
+			query               string
+			expectedContentType string
+			expectedContent     string
+		}{
+			{"", "image/svg+xml", "<svg "},
+			{"?format=svg", "image/svg+xml", ">node-a</text>"},
+			{"?format=dot", "text/vnd.graphviz; charset=utf-8", "digraph tf {"},
+			{"?format=mermaid", "text/plain; charset=utf-8", "flowchart TD"},
+		} {
+			ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(tfResult, true, nil)
+			rr := sendRequest("/diagram" + tc.query)
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"))
+			assert.Contains(t, rr.Body.String(), tc.expectedContent)
+		}
+		rr := sendRequest("/diagram?format=png")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("not completed", func(t *testing.T) {
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(tf, false, nil)
+		assert.Equal(t, http.StatusNotFound, sendRequest("/diagram").Code)
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(tf, false, nil)
+		assert.Equal(t, http.StatusNotFound, sendRequest("/export").Code)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		ts.traceflowRequestsHandler.EXPECT().GetRequestResult(gomock.Any(), gomock.Any(), requestID).Return(tfResult, true, nil)
+		rr := sendRequest("/export")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+		assert.Equal(t, fmt.Sprintf(`attachment; filename="traceflow-%s.zip"`, requestID), rr.Header().Get("Content-Disposition"))
+		zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, f := range zr.File {
+			r, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			files[f.Name] = string(data)
+		}
+		assert.Len(t, files, 4)
+		assert.JSONEq(t, string(mustMarshal(tfResult)), files["traceflow.json"])
+		assert.Contains(t, files["diagram.svg"], "<svg ")
+		assert.Contains(t, files["diagram.dot"], "digraph tf {")
+		assert.Contains(t, files["diagram.mmd"], "flowchart TD")
+	})
+}
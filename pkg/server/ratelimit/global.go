@@ -15,6 +15,7 @@
 package ratelimit
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -47,3 +48,9 @@ func NewGlobalRateLimiterOrDie(rate string, burstSize int) *GlobalRateLimiter {
 func (l *GlobalRateLimiter) Allow(t time.Time, req *http.Request) bool {
 	return l.rl.AllowN(t, 1)
 }
+
+// Wait blocks until a request is allowed. It returns an error right away if ctx would be done
+// before then, e.g. because its deadline is too close, or if no request is ever allowed.
+func (l *GlobalRateLimiter) Wait(ctx context.Context) error {
+	return l.rl.Wait(ctx)
+}
@@ -15,6 +15,7 @@
 package ratelimit
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -45,3 +46,18 @@ func TestGlobalRateLimiter(t *testing.T) {
 	clock.SetTime(start.Add(25 * time.Minute))
 	assert.True(t, testAllow())
 }
+
+func TestGlobalRateLimiterWait(t *testing.T) {
+	rl, err := NewGlobalRateLimiter("3/h", 2)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(t.Context(), time.Minute)
+	defer cancel()
+	assert.NoError(t, rl.Wait(ctx))
+	assert.NoError(t, rl.Wait(ctx))
+	// The next token is 20 minutes away, past the deadline: this fails without blocking.
+	assert.Error(t, rl.Wait(ctx))
+
+	rl, err = NewGlobalRateLimiter("0/h", 0)
+	require.NoError(t, err)
+	assert.Error(t, rl.Wait(ctx))
+}
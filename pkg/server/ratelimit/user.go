@@ -0,0 +1,202 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// UserRateLimiter gives every user their own hourly budget, so that no user can use up a limit
+// shared by everyone. A budget of perHour requests is a token bucket of that size, refilled over
+// the hour: a user who has been idle for an hour can make perHour requests right away. Unlike
+// ClientRateLimiter, the budget is chosen per call, as it may depend on who the user is. To avoid
+// unbounded memory usage, the buckets are kept in a LRU cache with a pre-determined size.
+type UserRateLimiter struct {
+	cache *lru.Cache[userKey, *rate.Limiter]
+}
+
+// userKey includes the budget, so that a user whose budget changes, e.g. because they joined a
+// group with a different one, starts over with a full bucket of the new size.
+type userKey struct {
+	user    string
+	perHour int
+}
+
+func NewUserRateLimiter(maxSize int) (*UserRateLimiter, error) {
+	cache, err := lru.New[userKey, *rate.Limiter](maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing LRU user cache: %w", err)
+	}
+	return &UserRateLimiter{
+		cache: cache,
+	}, nil
+}
+
+func NewUserRateLimiterOrDie(maxSize int) *UserRateLimiter {
+	l, err := NewUserRateLimiter(maxSize)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func (l *UserRateLimiter) limiter(user string, perHour int) *rate.Limiter {
+	key := userKey{user: user, perHour: perHour}
+	rl, ok := l.cache.Get(key)
+	if !ok {
+		rl = rate.NewLimiter(rate.Limit(float64(perHour)/3600.), perHour)
+		previous, ok, _ := l.cache.PeekOrAdd(key, rl)
+		if ok {
+			rl = previous
+		}
+	}
+	return rl
+}
+
+// Status describes a user's budget, as of a call to Reserve.
+type Status struct {
+	// Allowed is true if the request was taken from the budget.
+	Allowed bool
+	// Limit is the size of the budget, in requests per hour.
+	Limit int
+	// Remaining is the number of requests the user can still make right away.
+	Remaining int
+	// Reset is how long until the budget is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, when this one was not.
+	RetryAfter time.Duration
+}
+
+// SetHeaders sets the RateLimit-* headers (from the IETF draft "RateLimit header fields for
+// HTTP") describing s, and Retry-After when the request was not allowed because of this budget.
+func (s *Status) SetHeaders(h http.Header) {
+	h.Set("RateLimit-Limit", strconv.Itoa(s.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(s.Remaining))
+	h.Set("RateLimit-Reset", ceilSeconds(s.Reset))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=3600", s.Limit))
+	if !s.Allowed && s.RetryAfter > 0 {
+		h.Set("Retry-After", ceilSeconds(s.RetryAfter))
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// Reservation is one request taken from a user's budget. It can be given back with Cancel, if
+// the request ends up not being served for another reason.
+type Reservation struct {
+	Status
+	rl *rate.Limiter
+	r  *rate.Reservation
+	// at is the time the request was reserved at.
+	at time.Time
+}
+
+// Cancel gives the request back to the user's budget at time t, and updates the Status to match.
+// It does nothing if the request was not allowed in the first place.
+func (r *Reservation) Cancel(t time.Time) {
+	if !r.Allowed {
+		return
+	}
+	// rate.Reservation only gives tokens back until the time it was meant to act at, which is
+	// the time of the reservation for a request allowed right away: cancel it as of that time.
+	// The request may be cancelled long after that, e.g. after it failed.
+	r.r.CancelAt(r.at)
+	r.Allowed = false
+	r.setBudget(t)
+}
+
+func (r *Reservation) setBudget(t time.Time) {
+	r.Status.setBudget(r.rl, t)
+}
+
+func (s *Status) setBudget(rl *rate.Limiter, t time.Time) {
+	tokens := rl.TokensAt(t)
+	s.Remaining = max(0, int(math.Floor(tokens)))
+	s.Reset = time.Duration((float64(s.Limit) - tokens) / float64(s.Limit) * float64(time.Hour))
+}
+
+// Reserve takes one request from the budget of perHour requests of user, if it allows one at
+// time t.
+func (l *UserRateLimiter) Reserve(t time.Time, user string, perHour int) *Reservation {
+	rl := l.limiter(user, perHour)
+	r := rl.ReserveN(t, 1)
+	res := &Reservation{
+		Status: Status{Limit: perHour},
+		rl:     rl,
+		r:      r,
+		at:     t,
+	}
+	if !r.OK() {
+		// The budget is 0: no request is ever allowed.
+		res.RetryAfter = time.Hour
+		res.Reset = time.Hour
+		return res
+	}
+	if delay := r.DelayFrom(t); delay > 0 {
+		r.CancelAt(t)
+		res.RetryAfter = delay
+	} else {
+		res.Allowed = true
+	}
+	res.setBudget(t)
+	return res
+}
+
+// ReserveN takes n requests from the budget of perHour requests of user, if it allows all of them
+// at time t. They are taken as n Reservations, so that each one can be given back on its own.
+// When the budget does not allow all of them, none is taken, and the returned Status tells when
+// it will, if n is not more than perHour.
+func (l *UserRateLimiter) ReserveN(t time.Time, user string, perHour int, n int) ([]*Reservation, Status) {
+	rl := l.limiter(user, perHour)
+	status := Status{Limit: perHour}
+	// Check the budget first: a request which is not allowed still moves the limiter, and
+	// giving back the ones taken before it would then not restore the budget exactly.
+	if tokens := rl.TokensAt(t); tokens < float64(n) {
+		if perHour == 0 {
+			status.RetryAfter = time.Hour
+			status.Reset = time.Hour
+			return nil, status
+		}
+		status.setBudget(rl, t)
+		if n <= perHour {
+			status.RetryAfter = time.Duration((float64(n) - tokens) / float64(perHour) * float64(time.Hour))
+		}
+		return nil, status
+	}
+	reservations := make([]*Reservation, 0, n)
+	for range n {
+		res := l.Reserve(t, user, perHour)
+		if !res.Allowed {
+			// Another request of the user took from the budget in the meantime.
+			for _, r := range reservations {
+				r.Cancel(t)
+			}
+			return nil, res.Status
+		}
+		reservations = append(reservations, res)
+	}
+	status.Allowed = true
+	status.setBudget(rl, t)
+	return reservations, status
+}
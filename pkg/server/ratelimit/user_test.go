@@ -0,0 +1,107 @@
+// Copyright 2026 Antrea Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRateLimiter(t *testing.T) {
+	start := time.Now()
+	rl, err := NewUserRateLimiter(10)
+	require.NoError(t, err)
+
+	r := rl.Reserve(start, "alice", 3)
+	assert.Equal(t, Status{Allowed: true, Limit: 3, Remaining: 2, Reset: 20 * time.Minute}, r.Status)
+	r = rl.Reserve(start, "alice", 3)
+	assert.True(t, r.Allowed)
+	r = rl.Reserve(start, "alice", 3)
+	assert.Equal(t, Status{Allowed: true, Limit: 3, Remaining: 0, Reset: time.Hour}, r.Status)
+	r = rl.Reserve(start, "alice", 3)
+	assert.Equal(t, Status{Allowed: false, Limit: 3, Remaining: 0, Reset: time.Hour, RetryAfter: 20 * time.Minute}, r.Status)
+
+	// Every user has their own budget.
+	assert.True(t, rl.Reserve(start, "bob", 3).Allowed)
+	// A user whose budget changes gets a full bucket of the new size.
+	assert.True(t, rl.Reserve(start, "alice", 1).Allowed)
+	assert.False(t, rl.Reserve(start, "alice", 1).Allowed)
+
+	// We get 1 token every 20 minutes.
+	assert.False(t, rl.Reserve(start.Add(15*time.Minute), "alice", 3).Allowed)
+	r = rl.Reserve(start.Add(25*time.Minute), "alice", 3)
+	assert.True(t, r.Allowed)
+	// A cancelled request is given back.
+	r.Cancel(start.Add(25 * time.Minute))
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 1, r.Remaining)
+	assert.True(t, rl.Reserve(start.Add(25*time.Minute), "alice", 3).Allowed)
+	assert.False(t, rl.Reserve(start.Add(25*time.Minute), "alice", 3).Allowed)
+	// Including when it is cancelled after it was reserved.
+	r = rl.Reserve(start, "dave", 1)
+	assert.True(t, r.Allowed)
+	r.Cancel(start.Add(time.Second))
+	assert.Equal(t, 1, r.Remaining)
+	assert.True(t, rl.Reserve(start.Add(time.Second), "dave", 1).Allowed)
+
+	r = rl.Reserve(start, "carol", 0)
+	assert.Equal(t, Status{Allowed: false, Limit: 0, Remaining: 0, Reset: time.Hour, RetryAfter: time.Hour}, r.Status)
+}
+
+func TestUserRateLimiterReserveN(t *testing.T) {
+	start := time.Now()
+	rl, err := NewUserRateLimiter(10)
+	require.NoError(t, err)
+
+	reservations, status := rl.ReserveN(start, "alice", 3, 2)
+	require.Len(t, reservations, 2)
+	assert.Equal(t, Status{Allowed: true, Limit: 3, Remaining: 1, Reset: 40 * time.Minute}, status)
+	// The budget does not allow 2 more: none is taken.
+	reservations2, status := rl.ReserveN(start, "alice", 3, 2)
+	assert.Nil(t, reservations2)
+	assert.Equal(t, Status{Allowed: false, Limit: 3, Remaining: 1, Reset: 40 * time.Minute, RetryAfter: 20 * time.Minute}, status)
+	// Every request can be given back on its own.
+	reservations[0].Cancel(start)
+	reservations2, _ = rl.ReserveN(start, "alice", 3, 2)
+	assert.Len(t, reservations2, 2)
+
+	// More than the budget is never allowed.
+	_, status = rl.ReserveN(start, "bob", 3, 4)
+	assert.Equal(t, Status{Allowed: false, Limit: 3, Remaining: 3, Reset: 0}, status)
+	_, status = rl.ReserveN(start, "carol", 0, 1)
+	assert.Equal(t, Status{Allowed: false, Limit: 0, Remaining: 0, Reset: time.Hour, RetryAfter: time.Hour}, status)
+}
+
+func TestStatusSetHeaders(t *testing.T) {
+	h := http.Header{}
+	s := Status{Allowed: true, Limit: 10, Remaining: 4, Reset: 2500 * time.Millisecond}
+	s.SetHeaders(h)
+	assert.Equal(t, http.Header{
+		"Ratelimit-Limit":     []string{"10"},
+		"Ratelimit-Remaining": []string{"4"},
+		"Ratelimit-Reset":     []string{"3"},
+		"Ratelimit-Policy":    []string{"10;w=3600"},
+	}, h)
+
+	h = http.Header{}
+	s = Status{Limit: 10, Reset: time.Hour, RetryAfter: 6 * time.Minute}
+	s.SetHeaders(h)
+	assert.Equal(t, "0", h.Get("RateLimit-Remaining"))
+	assert.Equal(t, "360", h.Get("Retry-After"))
+}
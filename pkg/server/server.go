@@ -19,12 +19,18 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
+	"k8s.io/client-go/dynamic"
 
 	"antrea.io/antrea-ui/pkg/auth/session"
 	serverconfig "antrea.io/antrea-ui/pkg/config/server"
 	accesshandler "antrea.io/antrea-ui/pkg/handlers/access"
+	"antrea.io/antrea-ui/pkg/handlers/antreaagent"
 	"antrea.io/antrea-ui/pkg/handlers/antreasvc"
+	"antrea.io/antrea-ui/pkg/handlers/crdversions"
 	"antrea.io/antrea-ui/pkg/handlers/flowstream"
+	"antrea.io/antrea-ui/pkg/handlers/packetcapture"
+	"antrea.io/antrea-ui/pkg/handlers/probes"
+	"antrea.io/antrea-ui/pkg/handlers/supportbundle"
 	"antrea.io/antrea-ui/pkg/handlers/traceflow"
 	"antrea.io/antrea-ui/pkg/k8s"
 	"antrea.io/antrea-ui/pkg/password"
@@ -55,8 +61,12 @@ type Options struct {
 	TraceflowRequestsHandler traceflow.RequestsHandler
 	K8sProxyHandler          http.Handler
 	AntreaSvcRequestsHandler antreasvc.RequestsHandler
-	FlowStreamSubscriber     flowstream.FlowStreamSubscriber
-	PasswordStore            password.Store
+	// AntreaAgentRequestsHandler forwards requests to the antrea-agent API of a Node.
+	AntreaAgentRequestsHandler antreaagent.RequestsHandler
+	FlowStreamSubscriber       flowstream.FlowStreamSubscriber
+	// FlowMasker, when set, redacts streamed flow records according to the caller's groups.
+	FlowMasker    *flowstream.Masker
+	PasswordStore password.Store
 	// SessionStore holds every logged-in user's Kubernetes credential, in memory only.
 	SessionStore session.Store
 	// ClientFactory builds Kubernetes clients that act as the caller, and validates a
@@ -69,6 +79,16 @@ type Options struct {
 	// AccessResolver answers namespace-discovery and cluster-scope-probe questions for
 	// GET /api/v1/access-summary.
 	AccessResolver accesshandler.Resolver
+	// AdminDynamicClient acts as antrea-ui-admin. It creates and reads delegated Traceflows.
+	AdminDynamicClient dynamic.Interface
+	// ProbeScheduler runs the connectivity probes, if any are configured.
+	ProbeScheduler probes.Scheduler
+	// CRDResolver picks the versions of the Antrea CRDs.
+	CRDResolver crdversions.Resolver
+	// PacketCaptureRequestsHandler is nil when PacketCaptures are disabled.
+	PacketCaptureRequestsHandler packetcapture.RequestsHandler
+	// SupportBundleRequestsHandler is nil when support bundles are disabled.
+	SupportBundleRequestsHandler supportbundle.RequestsHandler
 }
 
 type Server struct {
@@ -104,17 +124,24 @@ func NewServer(o Options) (*Server, error) {
 		logger: o.Logger,
 		config: c,
 		apiServer: api.NewServer(api.Options{
-			Logger:                   o.Logger,
-			Config:                   o.Config,
-			TraceflowRequestsHandler: o.TraceflowRequestsHandler,
-			K8sProxyHandler:          o.K8sProxyHandler,
-			AntreaSvcRequestsHandler: o.AntreaSvcRequestsHandler,
-			FlowStreamSubscriber:     o.FlowStreamSubscriber,
-			PasswordStore:            o.PasswordStore,
-			PluginRegistry:           o.PluginRegistry,
-			Authenticator:            authenticator,
-			ClientFactory:            o.ClientFactory,
-			AccessResolver:           o.AccessResolver,
+			Logger:                       o.Logger,
+			Config:                       o.Config,
+			TraceflowRequestsHandler:     o.TraceflowRequestsHandler,
+			K8sProxyHandler:              o.K8sProxyHandler,
+			AntreaSvcRequestsHandler:     o.AntreaSvcRequestsHandler,
+			AntreaAgentRequestsHandler:   o.AntreaAgentRequestsHandler,
+			FlowStreamSubscriber:         o.FlowStreamSubscriber,
+			FlowMasker:                   o.FlowMasker,
+			PasswordStore:                o.PasswordStore,
+			PluginRegistry:               o.PluginRegistry,
+			Authenticator:                authenticator,
+			ClientFactory:                o.ClientFactory,
+			AccessResolver:               o.AccessResolver,
+			AdminDynamicClient:           o.AdminDynamicClient,
+			ProbeScheduler:               o.ProbeScheduler,
+			CRDResolver:                  o.CRDResolver,
+			PacketCaptureRequestsHandler: o.PacketCaptureRequestsHandler,
+			SupportBundleRequestsHandler: o.SupportBundleRequestsHandler,
 		}),
 		passwordStore: o.PasswordStore,
 		sessionStore:  o.SessionStore,
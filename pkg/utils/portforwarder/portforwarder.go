@@ -68,7 +68,8 @@ func NewPortForwarder(
 // Run is recommended over Start / Stop
 func (p *PortForwarder) Run(stopCh <-chan struct{}, portCh chan<- int) error {
 	readyCh := make(chan struct{})
-	errCh := make(chan error)
+	// Buffered, so that ForwardPorts can return after Run has returned on stopCh.
+	errCh := make(chan error, 1)
 
 	url := p.clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -94,7 +95,7 @@ func (p *PortForwarder) Run(stopCh <-chan struct{}, portCh chan<- int) error {
 		p.listenAddress,
 	}
 
-	pf, err := portforward.NewOnAddresses(dialer, addresses, ports, p.stopCh, readyCh, io.Discard, io.Discard)
+	pf, err := portforward.NewOnAddresses(dialer, addresses, ports, stopCh, readyCh, io.Discard, io.Discard)
 	if err != nil {
 		return fmt.Errorf("port forward request failed: %w", err)
 	}
@@ -126,13 +127,12 @@ func (p *PortForwarder) Run(stopCh <-chan struct{}, portCh chan<- int) error {
 
 // Start Port Forwarding channel
 func (p *PortForwarder) Start() (int, error) {
-	stopCh := make(chan struct{})
 	p.stopCh = make(chan struct{})
 	portCh := make(chan int)
 	errCh := make(chan error)
 
 	go func() {
-		errCh <- p.Run(stopCh, portCh)
+		errCh <- p.Run(p.stopCh, portCh)
 	}()
 
 	select {